@@ -36,6 +36,7 @@ require (
 	github.com/theckman/yacspin v0.13.12
 	golang.org/x/crypto v0.12.0
 	golang.org/x/exp v0.0.0-20230522175609-2e198f4a06a1
+	gopkg.in/yaml.v2 v2.4.0
 )
 
 require (
@@ -58,5 +59,4 @@ require (
 	github.com/ulikunitz/xz v0.5.10 // indirect
 	github.com/xi2/xz v0.0.0-20171230120015-48954b6210f8 // indirect
 	golang.org/x/net v0.10.0 // indirect
-	gopkg.in/yaml.v2 v2.4.0 // indirect
 )
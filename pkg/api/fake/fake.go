@@ -0,0 +1,231 @@
+// Package fake provides an in-memory fake of the Fastly API, so whole CLI
+// workflows can be exercised offline (e.g. in CI) by pointing the CLI at it
+// with the --endpoint flag or the FASTLY_API_ENDPOINT environment variable.
+//
+// The fake implements the service, version, backend, domain, dictionary,
+// package, config store and KV store endpoints used by the CLI. State is held
+// in memory and lost when the server stops. Authentication isn't checked.
+package fake
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Server is an http.Handler implementing the fake API.
+type Server struct {
+	mu           sync.Mutex
+	configStores map[string]*configStore
+	kvStores     map[string]*kvStore
+	routes       []route
+	services     map[string]*service
+}
+
+// New returns a Server with no services or stores.
+func New() *Server {
+	s := &Server{
+		configStores: make(map[string]*configStore),
+		kvStores:     make(map[string]*kvStore),
+		services:     make(map[string]*service),
+	}
+	s.registerServiceRoutes()
+	s.registerStoreRoutes()
+	return s
+}
+
+// ServeHTTP implements the http.Handler interface.
+//
+// Requests are handled one at a time, so handlers can access the state
+// without further locking.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	segments := splitPath(r.URL.EscapedPath())
+
+	var methodMismatch bool
+	for _, rt := range s.routes {
+		p, ok := rt.match(segments)
+		if !ok {
+			continue
+		}
+		if rt.method != r.Method {
+			methodMismatch = true
+			continue
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		rt.handler(w, r, p)
+		return
+	}
+
+	if methodMismatch {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed", "%s %s isn't supported", r.Method, r.URL.Path)
+		return
+	}
+	writeError(w, http.StatusNotFound, "Record not found", "%s %s isn't implemented by the fake API", r.Method, r.URL.Path)
+}
+
+// handler handles a request, with the parameters captured from its path.
+type handler func(w http.ResponseWriter, r *http.Request, p params)
+
+// params are the values of the :name segments of a route's pattern.
+type params map[string]string
+
+// route maps a method and path pattern to a handler.
+type route struct {
+	method   string
+	segments []string
+	handler  handler
+}
+
+// handle registers a handler for a method and path pattern, where segments
+// starting with a colon (e.g. /service/:service_id) match any value.
+func (s *Server) handle(method, pattern string, h handler) {
+	s.routes = append(s.routes, route{
+		method:   method,
+		segments: splitPath(pattern),
+		handler:  h,
+	})
+}
+
+// match reports whether the path segments match the route, returning the
+// captured parameters.
+func (rt route) match(segments []string) (params, bool) {
+	if len(segments) != len(rt.segments) {
+		return nil, false
+	}
+	p := make(params)
+	for i, seg := range rt.segments {
+		if strings.HasPrefix(seg, ":") {
+			p[seg[1:]] = unescape(segments[i])
+			continue
+		}
+		if seg != segments[i] {
+			return nil, false
+		}
+	}
+	return p, true
+}
+
+// splitPath splits a URL path into its segments.
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+// unescape decodes a path segment, returning it as is if it's malformed.
+func unescape(segment string) string {
+	if v, err := url.PathUnescape(segment); err == nil {
+		return v
+	}
+	return segment
+}
+
+// record is a resource as returned by the API.
+type record map[string]any
+
+// clone returns a shallow copy of the record.
+func (r record) clone() record {
+	c := make(record, len(r))
+	for k, v := range r {
+		c[k] = v
+	}
+	return c
+}
+
+// setForm copies the form values of the request into the record.
+func (r record) setForm(req *http.Request, exclude ...string) {
+	_ = req.ParseForm()
+	for k, v := range req.PostForm {
+		if len(v) == 0 || contains(exclude, k) {
+			continue
+		}
+		r[k] = v[0]
+	}
+}
+
+// collection is a set of records keyed by name.
+type collection map[string]record
+
+// list returns the records ordered by name.
+func (c collection) list() []record {
+	l := make([]record, 0, len(c))
+	for _, name := range sortedKeys(c) {
+		l = append(l, c[name])
+	}
+	return l
+}
+
+// clone returns a copy of the collection, with the given fields of each
+// record overwritten.
+func (c collection) clone(fields record) collection {
+	n := make(collection, len(c))
+	for name, r := range c {
+		r = r.clone()
+		for k, v := range fields {
+			r[k] = v
+		}
+		n[name] = r
+	}
+	return n
+}
+
+// status is the response of endpoints that don't return a resource.
+var status = record{"status": "ok"}
+
+// writeJSON writes the value as a JSON response.
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error in the format of the API's legacy errors.
+func writeError(w http.ResponseWriter, code int, msg, format string, args ...any) {
+	writeJSON(w, code, record{
+		"msg":    msg,
+		"detail": fmt.Sprintf(format, args...),
+	})
+}
+
+// now returns the current time in the format used by the API.
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+// idChars are the characters of generated IDs.
+const idChars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// newID returns a random ID in the format used by the API.
+func newID() string {
+	b := make([]byte, 22)
+	_, _ = rand.Read(b)
+	for i := range b {
+		b[i] = idChars[int(b[i])%len(idChars)]
+	}
+	return string(b)
+}
+
+// contains reports whether the list contains the value.
+func contains(l []string, v string) bool {
+	for _, s := range l {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys returns the keys of the map in order.
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
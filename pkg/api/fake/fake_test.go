@@ -0,0 +1,251 @@
+package fake_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha512"
+	"fmt"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/fastly/go-fastly/v8/fastly"
+
+	"github.com/fastly/cli/pkg/api/fake"
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+// TestWorkflow runs a sequence of CLI commands against the fake API, with each
+// command relying on the state left by the previous ones.
+func TestWorkflow(t *testing.T) {
+	srv := httptest.NewServer(fake.New())
+	defer srv.Close()
+
+	ids := make(map[string]string)
+	for _, step := range []struct {
+		args       string
+		capture    string // name of the ID captured by the first group of wantID.
+		wantID     string
+		wantError  string
+		wantOutput []string
+	}{
+		{
+			args:    "service create --name example --type wasm",
+			capture: "service",
+			wantID:  `Created service (\S+)`,
+		},
+		{
+			args:       "backend create --service-id {service} --version 1 --name origin --address example.com --port 443",
+			wantOutput: []string{"Created backend origin (service {service} version 1)"},
+		},
+		{
+			args:       "domain create --service-id {service} --version 1 --name www.example.com",
+			wantOutput: []string{"Created domain www.example.com (service {service} version 1)"},
+		},
+		{
+			args:    "dictionary create --service-id {service} --version 1 --name settings",
+			capture: "dictionary",
+			wantID:  `\(id (\S+), service`,
+		},
+		{
+			args:       "dictionary-entry create --service-id {service} --dictionary-id {dictionary} --key colour --value blue",
+			wantOutput: []string{"Created dictionary item colour"},
+		},
+		{
+			args:       "service-version activate --service-id {service} --version 1",
+			wantOutput: []string{"Activated service {service} version 1"},
+		},
+		{
+			args:      "backend create --service-id {service} --version 1 --name other --address example.org",
+			wantError: "service version 1 is not editable",
+		},
+		{
+			args:       "backend create --service-id {service} --version active --autoclone --name other --address example.org",
+			wantOutput: []string{"Created backend other (service {service} version 2)"},
+		},
+		{
+			args:       "backend list --service-id {service} --version 2",
+			wantOutput: []string{"origin", "example.com", "other", "example.org"},
+		},
+		{
+			args:       "service-version list --service-id {service}",
+			wantOutput: []string{"1       true", "2       false"},
+		},
+		{
+			args:       "dictionary-entry list --service-id {service} --dictionary-id {dictionary}",
+			wantOutput: []string{"Item Key: colour", "Item Value: blue"},
+		},
+		{
+			args:    "config-store create --name flags",
+			capture: "config-store",
+			wantID:  `Created Config Store 'flags' \((\S+)\)`,
+		},
+		{
+			args:       "config-store-entry create --store-id {config-store} --key canary --value 10",
+			wantOutput: []string{"Created key 'canary'"},
+		},
+		{
+			args:       "config-store-entry update --store-id {config-store} --key canary --value 20",
+			wantOutput: []string{"Updated config store item canary"},
+		},
+		{
+			args:       "config-store-entry describe --store-id {config-store} --key canary",
+			wantOutput: []string{"Value: 20"},
+		},
+		{
+			args:    "kv-store create --name assets",
+			capture: "kv-store",
+			wantID:  `Created KV Store 'assets' \((\S+)\)`,
+		},
+		{
+			args:       "kv-store-entry create --store-id {kv-store} --key hello --value world",
+			wantOutput: []string{"Created key 'hello'"},
+		},
+		{
+			args:       "kv-store-entry describe --store-id {kv-store} --key hello",
+			wantOutput: []string{"world"},
+		},
+		{
+			args:       "kv-store-entry list --store-id {kv-store}",
+			wantOutput: []string{"hello"},
+		},
+		{
+			args:       "service list",
+			wantOutput: []string{"example", "{service}", "wasm"},
+		},
+		{
+			args:       "service search --name example",
+			wantOutput: []string{"ID: {service}", "Name: example"},
+		},
+		{
+			args:      "service search --name missing",
+			wantError: "Cannot find service 'missing'",
+		},
+		{
+			args:       "backend list --service-name example --version 2",
+			wantOutput: []string{"origin", "other"},
+		},
+	} {
+		args := expand(step.args, ids)
+		var stdout bytes.Buffer
+		opts := testutil.NewRunOpts(testutil.Args(fmt.Sprintf("--token 123 --endpoint %s %s", srv.URL, args)), &stdout)
+		opts.APIClient = app.FastlyAPIClient
+		err := app.Run(opts)
+
+		testutil.AssertErrorContains(t, err, step.wantError)
+		if err != nil {
+			if step.wantError == "" {
+				t.Fatalf("%s: %s", args, stdout.String())
+			}
+			continue
+		}
+		for _, want := range step.wantOutput {
+			testutil.AssertStringContains(t, stdout.String(), expand(want, ids))
+		}
+		if step.capture != "" {
+			m := regexp.MustCompile(step.wantID).FindStringSubmatch(stdout.String())
+			if m == nil {
+				t.Fatalf("%s: no match for %s in output:\n%s", args, step.wantID, stdout.String())
+			}
+			ids[step.capture] = m[1]
+		}
+	}
+}
+
+// TestPackage validates a package can be uploaded to an editable version, and
+// that the metadata matches what `compute deploy` compares against.
+func TestPackage(t *testing.T) {
+	srv := httptest.NewServer(fake.New())
+	defer srv.Close()
+
+	client, err := fastly.NewClientForEndpoint("123", srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	service, err := client.CreateService(&fastly.CreateServiceInput{
+		Name: fastly.String("example"),
+		Type: fastly.String("wasm"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.GetPackage(&fastly.GetPackageInput{ServiceID: service.ID, ServiceVersion: 1})
+	testutil.AssertErrorContains(t, err, "404 - Not Found")
+
+	pkg, files := testPackage(t, [][2]string{
+		{"package/bin/main.wasm", "wasm"},
+		{"package/fastly.toml", "name = 'example'"},
+	})
+	p, err := client.UpdatePackage(&fastly.UpdatePackageInput{
+		ServiceID:      service.ID,
+		ServiceVersion: 1,
+		PackageContent: pkg,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertEqual(t, files, p.Metadata.FilesHash)
+	testutil.AssertEqual(t, fmt.Sprintf("%x", sha512.Sum512(pkg)), p.Metadata.HashSum)
+
+	if _, err = client.ActivateVersion(&fastly.ActivateVersionInput{ServiceID: service.ID, ServiceVersion: 1}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.UpdatePackage(&fastly.UpdatePackageInput{
+		ServiceID:      service.ID,
+		ServiceVersion: 1,
+		PackageContent: pkg,
+	})
+	testutil.AssertErrorContains(t, err, "Version locked")
+
+	v, err := client.CloneVersion(&fastly.CloneVersionInput{ServiceID: service.ID, ServiceVersion: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err = client.GetPackage(&fastly.GetPackageInput{ServiceID: service.ID, ServiceVersion: v.Number})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertEqual(t, files, p.Metadata.FilesHash)
+}
+
+// expand replaces the {name} placeholders with the captured IDs.
+func expand(s string, ids map[string]string) string {
+	for name, id := range ids {
+		s = strings.ReplaceAll(s, "{"+name+"}", id)
+	}
+	return s
+}
+
+// testPackage returns a package containing the files (name and content pairs,
+// in order of name), along with the hash of the files calculated the same way
+// as `compute deploy`.
+func testPackage(t *testing.T, files [][2]string) ([]byte, string) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	h := sha512.New()
+	for _, f := range files {
+		name, content := f[0], f[1]
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+		h.Write([]byte(content))
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes(), fmt.Sprintf("%x", h.Sum(nil))
+}
@@ -0,0 +1,702 @@
+package fake
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha512"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// maxPackageSize is the largest package the fake accepts.
+const maxPackageSize = 100 << 20
+
+// service is a service and its versions.
+type service struct {
+	record record
+	// dictionaryItems are keyed by dictionary ID, as dictionary items aren't
+	// versioned.
+	dictionaryItems map[string]collection
+	versions        []*version
+}
+
+// version is a service version and the resources it configures.
+type version struct {
+	record       record
+	backends     collection
+	dictionaries collection
+	domains      collection
+	pkg          record // nil until a package is uploaded.
+}
+
+// number returns the version number.
+func (v *version) number() int {
+	return v.record["number"].(int)
+}
+
+// editable reports whether the version can be modified.
+func (v *version) editable() bool {
+	return !v.record["active"].(bool) && !v.record["locked"].(bool)
+}
+
+// newVersion returns an empty version of the service.
+func (sv *service) newVersion() *version {
+	ts := now()
+	v := &version{
+		record: record{
+			"active":     false,
+			"comment":    "",
+			"created_at": ts,
+			"deployed":   false,
+			"locked":     false,
+			"number":     len(sv.versions) + 1,
+			"service_id": sv.record["id"],
+			"staging":    false,
+			"testing":    false,
+			"updated_at": ts,
+		},
+		backends:     make(collection),
+		dictionaries: make(collection),
+		domains:      make(collection),
+	}
+	sv.versions = append(sv.versions, v)
+	return v
+}
+
+// activeVersion returns the number of the active version (or zero if there
+// isn't one).
+func (sv *service) activeVersion() int {
+	for _, v := range sv.versions {
+		if v.record["active"].(bool) {
+			return v.number()
+		}
+	}
+	return 0
+}
+
+// summary returns the service as returned by the list endpoint.
+func (sv *service) summary() record {
+	r := sv.record.clone()
+	r["version"] = sv.activeVersion()
+	r["versions"] = sv.versionRecords()
+	return r
+}
+
+// versionRecords returns the version records of the service.
+func (sv *service) versionRecords() []record {
+	l := make([]record, 0, len(sv.versions))
+	for _, v := range sv.versions {
+		l = append(l, v.record)
+	}
+	return l
+}
+
+// registerServiceRoutes registers the service and versioned resource routes.
+func (s *Server) registerServiceRoutes() {
+	s.handle(http.MethodGet, "/service", s.listServices)
+	s.handle(http.MethodPost, "/service", s.createService)
+	// NOTE: Registered before /service/:service_id, as the first matching
+	// route handles the request.
+	s.handle(http.MethodGet, "/service/search", s.searchService)
+	s.handle(http.MethodGet, "/service/:service_id", s.getService)
+	s.handle(http.MethodPut, "/service/:service_id", s.updateService)
+	s.handle(http.MethodDelete, "/service/:service_id", s.deleteService)
+	s.handle(http.MethodGet, "/service/:service_id/details", s.getServiceDetails)
+
+	s.handle(http.MethodGet, "/service/:service_id/version", s.listVersions)
+	s.handle(http.MethodPost, "/service/:service_id/version", s.createVersion)
+	s.handle(http.MethodGet, "/service/:service_id/version/:version", s.getVersion)
+	s.handle(http.MethodPut, "/service/:service_id/version/:version", s.updateVersion)
+	s.handle(http.MethodPut, "/service/:service_id/version/:version/activate", s.activateVersion)
+	s.handle(http.MethodPut, "/service/:service_id/version/:version/deactivate", s.deactivateVersion)
+	s.handle(http.MethodPut, "/service/:service_id/version/:version/clone", s.cloneVersion)
+	s.handle(http.MethodPut, "/service/:service_id/version/:version/lock", s.lockVersion)
+	s.handle(http.MethodGet, "/service/:service_id/version/:version/validate", s.validateVersion)
+
+	s.handle(http.MethodGet, "/service/:service_id/version/:version/package", s.getPackage)
+	s.handle(http.MethodPut, "/service/:service_id/version/:version/package", s.updatePackage)
+
+	resources := map[string]func(v *version) collection{
+		"backend":    func(v *version) collection { return v.backends },
+		"dictionary": func(v *version) collection { return v.dictionaries },
+		"domain":     func(v *version) collection { return v.domains },
+	}
+	for resource, get := range resources {
+		path := fmt.Sprintf("/service/:service_id/version/:version/%s", resource)
+		s.handle(http.MethodGet, path, s.listResources(get))
+		s.handle(http.MethodPost, path, s.createResource(resource, get))
+		s.handle(http.MethodGet, path+"/:name", s.getResource(resource, get))
+		s.handle(http.MethodPut, path+"/:name", s.updateResource(resource, get))
+		s.handle(http.MethodDelete, path+"/:name", s.deleteResource(resource, get))
+	}
+
+	s.handle(http.MethodGet, "/service/:service_id/dictionary/:dictionary_id/items", s.listDictionaryItems)
+	s.handle(http.MethodPatch, "/service/:service_id/dictionary/:dictionary_id/items", s.batchDictionaryItems)
+	s.handle(http.MethodPost, "/service/:service_id/dictionary/:dictionary_id/item", s.createDictionaryItem)
+	s.handle(http.MethodGet, "/service/:service_id/dictionary/:dictionary_id/item/:key", s.getDictionaryItem)
+	s.handle(http.MethodPut, "/service/:service_id/dictionary/:dictionary_id/item/:key", s.updateDictionaryItem)
+	s.handle(http.MethodDelete, "/service/:service_id/dictionary/:dictionary_id/item/:key", s.deleteDictionaryItem)
+}
+
+// lookupService returns the service identified by the request path, writing
+// an error if it doesn't exist.
+func (s *Server) lookupService(w http.ResponseWriter, p params) (*service, bool) {
+	sv, ok := s.services[p["service_id"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Record not found", "Cannot find service '%s'", p["service_id"])
+	}
+	return sv, ok
+}
+
+// lookupVersion returns the service and version identified by the request
+// path, writing an error if either doesn't exist.
+func (s *Server) lookupVersion(w http.ResponseWriter, p params) (*service, *version, bool) {
+	sv, ok := s.lookupService(w, p)
+	if !ok {
+		return nil, nil, false
+	}
+	n, err := strconv.Atoi(p["version"])
+	if err != nil || n < 1 || n > len(sv.versions) {
+		writeError(w, http.StatusNotFound, "Record not found", "Cannot find service version '%s'", p["version"])
+		return nil, nil, false
+	}
+	return sv, sv.versions[n-1], true
+}
+
+// lookupEditableVersion is lookupVersion for requests that modify the
+// version, writing an error if the version is active or locked.
+func (s *Server) lookupEditableVersion(w http.ResponseWriter, p params) (*service, *version, bool) {
+	sv, v, ok := s.lookupVersion(w, p)
+	if ok && !v.editable() {
+		writeError(w, http.StatusBadRequest, "Version locked", "Version %d of service '%s' is locked and can't be modified", v.number(), sv.record["id"])
+		return nil, nil, false
+	}
+	return sv, v, ok
+}
+
+func (s *Server) listServices(w http.ResponseWriter, _ *http.Request, _ params) {
+	l := make([]record, 0, len(s.services))
+	for _, id := range sortedKeys(s.services) {
+		l = append(l, s.services[id].summary())
+	}
+	sort.SliceStable(l, func(i, j int) bool {
+		return l[i]["name"].(string) < l[j]["name"].(string)
+	})
+	writeJSON(w, http.StatusOK, l)
+}
+
+func (s *Server) createService(w http.ResponseWriter, r *http.Request, _ params) {
+	ts := now()
+	sv := &service{
+		record: record{
+			"comment":     "",
+			"created_at":  ts,
+			"customer_id": "fake",
+			"id":          newID(),
+			"type":        "vcl",
+			"updated_at":  ts,
+		},
+		dictionaryItems: make(map[string]collection),
+	}
+	sv.record.setForm(r)
+	if sv.record["name"] == nil || sv.record["name"] == "" {
+		writeError(w, http.StatusBadRequest, "Bad request", "Name is required")
+		return
+	}
+	for _, other := range s.services {
+		if other.record["name"] == sv.record["name"] {
+			writeError(w, http.StatusConflict, "Duplicate record", "A service named '%s' already exists", sv.record["name"])
+			return
+		}
+	}
+	sv.newVersion()
+	s.services[sv.record["id"].(string)] = sv
+	writeJSON(w, http.StatusOK, sv.summary())
+}
+
+func (s *Server) getService(w http.ResponseWriter, _ *http.Request, p params) {
+	if sv, ok := s.lookupService(w, p); ok {
+		r := sv.record.clone()
+		r["versions"] = sv.versionRecords()
+		writeJSON(w, http.StatusOK, r)
+	}
+}
+
+func (s *Server) searchService(w http.ResponseWriter, r *http.Request, _ params) {
+	name := r.URL.Query().Get("name")
+	for _, id := range sortedKeys(s.services) {
+		sv := s.services[id]
+		if sv.record["name"] == name {
+			s.getService(w, r, params{"service_id": id})
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Record not found", "Cannot find service '%s'", name)
+}
+
+func (s *Server) getServiceDetails(w http.ResponseWriter, _ *http.Request, p params) {
+	sv, ok := s.lookupService(w, p)
+	if !ok {
+		return
+	}
+	r := sv.record.clone()
+	r["versions"] = sv.versionRecords()
+	r["version"] = sv.versions[len(sv.versions)-1].record
+	if n := sv.activeVersion(); n > 0 {
+		r["active_version"] = sv.versions[n-1].record
+		r["version"] = sv.versions[n-1].record
+	}
+	writeJSON(w, http.StatusOK, r)
+}
+
+func (s *Server) updateService(w http.ResponseWriter, r *http.Request, p params) {
+	if sv, ok := s.lookupService(w, p); ok {
+		sv.record.setForm(r, "id", "type")
+		sv.record["updated_at"] = now()
+		writeJSON(w, http.StatusOK, sv.summary())
+	}
+}
+
+func (s *Server) deleteService(w http.ResponseWriter, _ *http.Request, p params) {
+	sv, ok := s.lookupService(w, p)
+	if !ok {
+		return
+	}
+	if n := sv.activeVersion(); n > 0 {
+		writeError(w, http.StatusBadRequest, "Bad request", "Service version %d is active and must be deactivated before the service can be deleted", n)
+		return
+	}
+	delete(s.services, p["service_id"])
+	writeJSON(w, http.StatusOK, status)
+}
+
+func (s *Server) listVersions(w http.ResponseWriter, _ *http.Request, p params) {
+	if sv, ok := s.lookupService(w, p); ok {
+		writeJSON(w, http.StatusOK, sv.versionRecords())
+	}
+}
+
+func (s *Server) createVersion(w http.ResponseWriter, r *http.Request, p params) {
+	if sv, ok := s.lookupService(w, p); ok {
+		v := sv.newVersion()
+		v.record.setForm(r, "number", "service_id", "active", "locked")
+		writeJSON(w, http.StatusOK, v.record)
+	}
+}
+
+func (s *Server) getVersion(w http.ResponseWriter, _ *http.Request, p params) {
+	if _, v, ok := s.lookupVersion(w, p); ok {
+		writeJSON(w, http.StatusOK, v.record)
+	}
+}
+
+func (s *Server) updateVersion(w http.ResponseWriter, r *http.Request, p params) {
+	// NOTE: The comment of a locked version can still be updated.
+	if _, v, ok := s.lookupVersion(w, p); ok {
+		v.record.setForm(r, "number", "service_id", "active", "locked")
+		v.record["updated_at"] = now()
+		writeJSON(w, http.StatusOK, v.record)
+	}
+}
+
+func (s *Server) activateVersion(w http.ResponseWriter, _ *http.Request, p params) {
+	sv, v, ok := s.lookupVersion(w, p)
+	if !ok {
+		return
+	}
+	for _, other := range sv.versions {
+		other.record["active"] = false
+	}
+	v.record["active"] = true
+	v.record["deployed"] = true
+	v.record["locked"] = true
+	v.record["updated_at"] = now()
+	writeJSON(w, http.StatusOK, v.record)
+}
+
+func (s *Server) deactivateVersion(w http.ResponseWriter, _ *http.Request, p params) {
+	if _, v, ok := s.lookupVersion(w, p); ok {
+		v.record["active"] = false
+		v.record["updated_at"] = now()
+		writeJSON(w, http.StatusOK, v.record)
+	}
+}
+
+func (s *Server) lockVersion(w http.ResponseWriter, _ *http.Request, p params) {
+	if _, v, ok := s.lookupVersion(w, p); ok {
+		v.record["locked"] = true
+		v.record["updated_at"] = now()
+		writeJSON(w, http.StatusOK, v.record)
+	}
+}
+
+func (s *Server) cloneVersion(w http.ResponseWriter, _ *http.Request, p params) {
+	sv, v, ok := s.lookupVersion(w, p)
+	if !ok {
+		return
+	}
+	c := sv.newVersion()
+	c.record["comment"] = v.record["comment"]
+	fields := record{"version": c.number()}
+	c.backends = v.backends.clone(fields)
+	c.dictionaries = v.dictionaries.clone(fields)
+	c.domains = v.domains.clone(fields)
+	if v.pkg != nil {
+		c.pkg = v.pkg.clone()
+		c.pkg["version"] = c.number()
+	}
+	writeJSON(w, http.StatusOK, c.record)
+}
+
+func (s *Server) validateVersion(w http.ResponseWriter, _ *http.Request, p params) {
+	if _, _, ok := s.lookupVersion(w, p); ok {
+		writeJSON(w, http.StatusOK, record{"status": "ok", "msg": nil, "errors": []string{}})
+	}
+}
+
+func (s *Server) getPackage(w http.ResponseWriter, _ *http.Request, p params) {
+	_, v, ok := s.lookupVersion(w, p)
+	if !ok {
+		return
+	}
+	if v.pkg == nil {
+		writeError(w, http.StatusNotFound, "Record not found", "No package has been uploaded to version %d", v.number())
+		return
+	}
+	writeJSON(w, http.StatusOK, v.pkg)
+}
+
+func (s *Server) updatePackage(w http.ResponseWriter, r *http.Request, p params) {
+	sv, v, ok := s.lookupEditableVersion(w, p)
+	if !ok {
+		return
+	}
+	if err := r.ParseMultipartForm(maxPackageSize); err != nil {
+		writeError(w, http.StatusBadRequest, "Bad request", "Error parsing the package upload: %s", err)
+		return
+	}
+	f, _, err := r.FormFile("package")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Bad request", "Missing package: %s", err)
+		return
+	}
+	defer f.Close() // #nosec G307
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Bad request", "Error reading the package: %s", err)
+		return
+	}
+	filesHash, err := filesHash(data)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Bad request", "Invalid package: %s", err)
+		return
+	}
+
+	ts := now()
+	v.pkg = record{
+		"created_at": ts,
+		"id":         newID(),
+		"metadata": record{
+			"files_hash": filesHash,
+			"hashsum":    fmt.Sprintf("%x", sha512.Sum512(data)),
+			"size":       len(data),
+		},
+		"service_id": sv.record["id"],
+		"updated_at": ts,
+		"version":    v.number(),
+	}
+	writeJSON(w, http.StatusOK, v.pkg)
+}
+
+// filesHash returns the hash of the files within a package, calculated the
+// same way as the CLI (the contents of each file, in order of file name).
+func filesHash(pkg []byte) (string, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(pkg))
+	if err != nil {
+		return "", err
+	}
+	contents := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if contents[hdr.Name], err = io.ReadAll(tr); err != nil {
+			return "", err
+		}
+	}
+
+	h := sha512.New()
+	for _, name := range sortedKeys(contents) {
+		_, _ = h.Write(contents[name])
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// listResources handles listing the resources of a version.
+func (s *Server) listResources(get func(v *version) collection) handler {
+	return func(w http.ResponseWriter, _ *http.Request, p params) {
+		if _, v, ok := s.lookupVersion(w, p); ok {
+			writeJSON(w, http.StatusOK, get(v).list())
+		}
+	}
+}
+
+// createResource handles creating a resource within a version.
+func (s *Server) createResource(resource string, get func(v *version) collection) handler {
+	return func(w http.ResponseWriter, r *http.Request, p params) {
+		sv, v, ok := s.lookupEditableVersion(w, p)
+		if !ok {
+			return
+		}
+		ts := now()
+		rec := record{
+			"created_at": ts,
+			"service_id": sv.record["id"],
+			"updated_at": ts,
+			"version":    v.number(),
+		}
+		rec.setForm(r, "service_id", "version")
+		name, _ := rec["name"].(string)
+		if name == "" {
+			writeError(w, http.StatusBadRequest, "Bad request", "Name is required")
+			return
+		}
+		c := get(v)
+		if _, exists := c[name]; exists {
+			writeError(w, http.StatusConflict, "Duplicate record", "A %s named '%s' already exists in version %d", resource, name, v.number())
+			return
+		}
+		if resource == "dictionary" {
+			rec["id"] = newID()
+			sv.dictionaryItems[rec["id"].(string)] = make(collection)
+		}
+		c[name] = rec
+		writeJSON(w, http.StatusOK, rec)
+	}
+}
+
+// getResource handles describing a resource of a version.
+func (s *Server) getResource(resource string, get func(v *version) collection) handler {
+	return func(w http.ResponseWriter, _ *http.Request, p params) {
+		if _, v, ok := s.lookupVersion(w, p); ok {
+			if rec, ok := lookupResource(w, resource, get(v), p["name"]); ok {
+				writeJSON(w, http.StatusOK, rec)
+			}
+		}
+	}
+}
+
+// updateResource handles updating (and renaming) a resource of a version.
+func (s *Server) updateResource(resource string, get func(v *version) collection) handler {
+	return func(w http.ResponseWriter, r *http.Request, p params) {
+		_, v, ok := s.lookupEditableVersion(w, p)
+		if !ok {
+			return
+		}
+		c := get(v)
+		rec, ok := lookupResource(w, resource, c, p["name"])
+		if !ok {
+			return
+		}
+		rec.setForm(r, "id", "service_id", "version")
+		rec["updated_at"] = now()
+		if name, _ := rec["name"].(string); name != p["name"] {
+			delete(c, p["name"])
+			c[name] = rec
+		}
+		writeJSON(w, http.StatusOK, rec)
+	}
+}
+
+// deleteResource handles deleting a resource of a version.
+func (s *Server) deleteResource(resource string, get func(v *version) collection) handler {
+	return func(w http.ResponseWriter, _ *http.Request, p params) {
+		_, v, ok := s.lookupEditableVersion(w, p)
+		if !ok {
+			return
+		}
+		c := get(v)
+		if _, ok := lookupResource(w, resource, c, p["name"]); ok {
+			delete(c, p["name"])
+			writeJSON(w, http.StatusOK, status)
+		}
+	}
+}
+
+// lookupResource returns the named resource, writing an error if it doesn't
+// exist.
+func lookupResource(w http.ResponseWriter, resource string, c collection, name string) (record, bool) {
+	rec, ok := c[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Record not found", "Cannot find %s '%s'", resource, name)
+	}
+	return rec, ok
+}
+
+// lookupDictionaryItems returns the items of the dictionary identified by the
+// request path, writing an error if it doesn't exist.
+func (s *Server) lookupDictionaryItems(w http.ResponseWriter, p params) (collection, bool) {
+	sv, ok := s.lookupService(w, p)
+	if !ok {
+		return nil, false
+	}
+	items, ok := sv.dictionaryItems[p["dictionary_id"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Record not found", "Cannot find dictionary '%s'", p["dictionary_id"])
+	}
+	return items, ok
+}
+
+// newDictionaryItem returns a dictionary item record.
+func newDictionaryItem(p params, key, value string) record {
+	ts := now()
+	return record{
+		"created_at":    ts,
+		"dictionary_id": p["dictionary_id"],
+		"item_key":      key,
+		"item_value":    value,
+		"service_id":    p["service_id"],
+		"updated_at":    ts,
+	}
+}
+
+func (s *Server) listDictionaryItems(w http.ResponseWriter, _ *http.Request, p params) {
+	if items, ok := s.lookupDictionaryItems(w, p); ok {
+		writeJSON(w, http.StatusOK, items.list())
+	}
+}
+
+func (s *Server) createDictionaryItem(w http.ResponseWriter, r *http.Request, p params) {
+	items, ok := s.lookupDictionaryItems(w, p)
+	if !ok {
+		return
+	}
+	key, value := r.PostFormValue("item_key"), r.PostFormValue("item_value")
+	if key == "" {
+		writeError(w, http.StatusBadRequest, "Bad request", "Item key is required")
+		return
+	}
+	items[key] = newDictionaryItem(p, key, value)
+	writeJSON(w, http.StatusOK, items[key])
+}
+
+func (s *Server) getDictionaryItem(w http.ResponseWriter, _ *http.Request, p params) {
+	if items, ok := s.lookupDictionaryItems(w, p); ok {
+		if rec, ok := lookupResource(w, "dictionary item", items, p["key"]); ok {
+			writeJSON(w, http.StatusOK, rec)
+		}
+	}
+}
+
+func (s *Server) updateDictionaryItem(w http.ResponseWriter, r *http.Request, p params) {
+	items, ok := s.lookupDictionaryItems(w, p)
+	if !ok {
+		return
+	}
+	rec, ok := items[p["key"]]
+	if !ok {
+		rec = newDictionaryItem(p, p["key"], "")
+		items[p["key"]] = rec
+	}
+	rec["item_value"] = r.PostFormValue("item_value")
+	rec["updated_at"] = now()
+	writeJSON(w, http.StatusOK, rec)
+}
+
+func (s *Server) deleteDictionaryItem(w http.ResponseWriter, _ *http.Request, p params) {
+	if items, ok := s.lookupDictionaryItems(w, p); ok {
+		if _, ok := lookupResource(w, "dictionary item", items, p["key"]); ok {
+			delete(items, p["key"])
+			writeJSON(w, http.StatusOK, status)
+		}
+	}
+}
+
+// batchItems is the body of a batch modification of dictionary or config
+// store items.
+type batchItems struct {
+	Items []struct {
+		Key   string `json:"item_key"`
+		Op    string `json:"op"`
+		Value string `json:"item_value"`
+	} `json:"items"`
+}
+
+// apply applies the batch operations to the items, using newItem to create
+// items that don't exist. If any operation fails, none are applied.
+func (b batchItems) apply(items collection, newItem func(key, value string) record) error {
+	batch := items.clone(nil)
+	if err := b.applyTo(batch, newItem); err != nil {
+		return err
+	}
+	for key := range items {
+		delete(items, key)
+	}
+	for key, rec := range batch {
+		items[key] = rec
+	}
+	return nil
+}
+
+func (b batchItems) applyTo(items collection, newItem func(key, value string) record) error {
+	for _, item := range b.Items {
+		rec, exists := items[item.Key]
+		switch item.Op {
+		case "create":
+			if exists {
+				return fmt.Errorf("item '%s' already exists", item.Key)
+			}
+			items[item.Key] = newItem(item.Key, item.Value)
+		case "update", "upsert":
+			if !exists {
+				if item.Op == "update" {
+					return fmt.Errorf("item '%s' doesn't exist", item.Key)
+				}
+				items[item.Key] = newItem(item.Key, item.Value)
+				continue
+			}
+			rec["item_value"] = item.Value
+			rec["updated_at"] = now()
+		case "delete":
+			delete(items, item.Key)
+		default:
+			return fmt.Errorf("invalid operation '%s'", item.Op)
+		}
+	}
+	return nil
+}
+
+func (s *Server) batchDictionaryItems(w http.ResponseWriter, r *http.Request, p params) {
+	items, ok := s.lookupDictionaryItems(w, p)
+	if !ok {
+		return
+	}
+	var b batchItems
+	if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+		writeError(w, http.StatusBadRequest, "Bad request", "Error parsing the batch: %s", err)
+		return
+	}
+	err := b.apply(items, func(key, value string) record {
+		return newDictionaryItem(p, key, value)
+	})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Bad request", "%s", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
@@ -0,0 +1,384 @@
+package fake
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// maxKVValueSize is the largest KV store value the fake accepts.
+const maxKVValueSize = 25 << 20
+
+// configStore is a config store and its items.
+type configStore struct {
+	record record
+	items  collection
+}
+
+// kvStore is a KV store and its key/value pairs.
+type kvStore struct {
+	record record
+	values map[string][]byte
+}
+
+// registerStoreRoutes registers the config store and KV store routes.
+func (s *Server) registerStoreRoutes() {
+	s.handle(http.MethodGet, "/resources/stores/config", s.listConfigStores)
+	s.handle(http.MethodPost, "/resources/stores/config", s.createConfigStore)
+	s.handle(http.MethodGet, "/resources/stores/config/:store_id", s.getConfigStore)
+	s.handle(http.MethodPut, "/resources/stores/config/:store_id", s.updateConfigStore)
+	s.handle(http.MethodDelete, "/resources/stores/config/:store_id", s.deleteConfigStore)
+	s.handle(http.MethodGet, "/resources/stores/config/:store_id/info", s.getConfigStoreInfo)
+	s.handle(http.MethodGet, "/resources/stores/config/:store_id/services", s.listConfigStoreServices)
+	s.handle(http.MethodGet, "/resources/stores/config/:store_id/items", s.listConfigStoreItems)
+	s.handle(http.MethodPatch, "/resources/stores/config/:store_id/items", s.batchConfigStoreItems)
+	s.handle(http.MethodPost, "/resources/stores/config/:store_id/item", s.createConfigStoreItem)
+	s.handle(http.MethodGet, "/resources/stores/config/:store_id/item/:key", s.getConfigStoreItem)
+	s.handle(http.MethodPut, "/resources/stores/config/:store_id/item/:key", s.updateConfigStoreItem(true))
+	s.handle(http.MethodPatch, "/resources/stores/config/:store_id/item/:key", s.updateConfigStoreItem(false))
+	s.handle(http.MethodDelete, "/resources/stores/config/:store_id/item/:key", s.deleteConfigStoreItem)
+
+	s.handle(http.MethodGet, "/resources/stores/kv", s.listKVStores)
+	s.handle(http.MethodPost, "/resources/stores/kv", s.createKVStore)
+	s.handle(http.MethodGet, "/resources/stores/kv/:store_id", s.getKVStore)
+	s.handle(http.MethodDelete, "/resources/stores/kv/:store_id", s.deleteKVStore)
+	s.handle(http.MethodPut, "/resources/stores/kv/:store_id/batch", s.batchKVStoreKeys)
+	s.handle(http.MethodGet, "/resources/stores/kv/:store_id/keys", s.listKVStoreKeys)
+	s.handle(http.MethodGet, "/resources/stores/kv/:store_id/keys/:key", s.getKVStoreKey)
+	s.handle(http.MethodPut, "/resources/stores/kv/:store_id/keys/:key", s.insertKVStoreKey)
+	s.handle(http.MethodDelete, "/resources/stores/kv/:store_id/keys/:key", s.deleteKVStoreKey)
+}
+
+// newStore returns the record of a new config or KV store, writing an error
+// if the name is missing or already used by a store of the same kind.
+func newStore(w http.ResponseWriter, name string, exists func(name string) bool) (record, bool) {
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "Bad request", "Name is required")
+		return nil, false
+	}
+	if exists(name) {
+		writeError(w, http.StatusConflict, "Duplicate record", "A store named '%s' already exists", name)
+		return nil, false
+	}
+	ts := now()
+	return record{
+		"created_at": ts,
+		"id":         newID(),
+		"name":       name,
+		"updated_at": ts,
+	}, true
+}
+
+// lookupConfigStore returns the config store identified by the request path,
+// writing an error if it doesn't exist.
+func (s *Server) lookupConfigStore(w http.ResponseWriter, p params) (*configStore, bool) {
+	cs, ok := s.configStores[p["store_id"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Record not found", "Cannot find config store '%s'", p["store_id"])
+	}
+	return cs, ok
+}
+
+// newConfigStoreItem returns a config store item record.
+func newConfigStoreItem(storeID, key, value string) record {
+	ts := now()
+	return record{
+		"created_at": ts,
+		"item_key":   key,
+		"item_value": value,
+		"store_id":   storeID,
+		"updated_at": ts,
+	}
+}
+
+func (s *Server) listConfigStores(w http.ResponseWriter, _ *http.Request, _ params) {
+	l := make([]record, 0, len(s.configStores))
+	for _, id := range sortedKeys(s.configStores) {
+		l = append(l, s.configStores[id].record)
+	}
+	writeJSON(w, http.StatusOK, l)
+}
+
+func (s *Server) createConfigStore(w http.ResponseWriter, r *http.Request, _ params) {
+	rec, ok := newStore(w, r.PostFormValue("name"), func(name string) bool {
+		for _, cs := range s.configStores {
+			if cs.record["name"] == name {
+				return true
+			}
+		}
+		return false
+	})
+	if !ok {
+		return
+	}
+	s.configStores[rec["id"].(string)] = &configStore{record: rec, items: make(collection)}
+	writeJSON(w, http.StatusOK, rec)
+}
+
+func (s *Server) getConfigStore(w http.ResponseWriter, _ *http.Request, p params) {
+	if cs, ok := s.lookupConfigStore(w, p); ok {
+		writeJSON(w, http.StatusOK, cs.record)
+	}
+}
+
+func (s *Server) updateConfigStore(w http.ResponseWriter, r *http.Request, p params) {
+	if cs, ok := s.lookupConfigStore(w, p); ok {
+		cs.record.setForm(r, "id")
+		cs.record["updated_at"] = now()
+		writeJSON(w, http.StatusOK, cs.record)
+	}
+}
+
+func (s *Server) deleteConfigStore(w http.ResponseWriter, _ *http.Request, p params) {
+	if _, ok := s.lookupConfigStore(w, p); ok {
+		delete(s.configStores, p["store_id"])
+		writeJSON(w, http.StatusOK, status)
+	}
+}
+
+func (s *Server) getConfigStoreInfo(w http.ResponseWriter, _ *http.Request, p params) {
+	if cs, ok := s.lookupConfigStore(w, p); ok {
+		writeJSON(w, http.StatusOK, record{"item_count": len(cs.items)})
+	}
+}
+
+// NOTE: Resource links aren't implemented, so stores aren't linked to any
+// services.
+func (s *Server) listConfigStoreServices(w http.ResponseWriter, _ *http.Request, p params) {
+	if _, ok := s.lookupConfigStore(w, p); ok {
+		writeJSON(w, http.StatusOK, []record{})
+	}
+}
+
+func (s *Server) listConfigStoreItems(w http.ResponseWriter, _ *http.Request, p params) {
+	if cs, ok := s.lookupConfigStore(w, p); ok {
+		writeJSON(w, http.StatusOK, cs.items.list())
+	}
+}
+
+func (s *Server) createConfigStoreItem(w http.ResponseWriter, r *http.Request, p params) {
+	cs, ok := s.lookupConfigStore(w, p)
+	if !ok {
+		return
+	}
+	key := r.PostFormValue("item_key")
+	if key == "" {
+		writeError(w, http.StatusBadRequest, "Bad request", "Item key is required")
+		return
+	}
+	if _, exists := cs.items[key]; exists {
+		writeError(w, http.StatusConflict, "Duplicate record", "Item '%s' already exists", key)
+		return
+	}
+	cs.items[key] = newConfigStoreItem(p["store_id"], key, r.PostFormValue("item_value"))
+	writeJSON(w, http.StatusOK, cs.items[key])
+}
+
+func (s *Server) getConfigStoreItem(w http.ResponseWriter, _ *http.Request, p params) {
+	if cs, ok := s.lookupConfigStore(w, p); ok {
+		if rec, ok := lookupResource(w, "config store item", cs.items, p["key"]); ok {
+			writeJSON(w, http.StatusOK, rec)
+		}
+	}
+}
+
+// updateConfigStoreItem handles updating a config store item, creating it if
+// it doesn't exist and upsert is set.
+func (s *Server) updateConfigStoreItem(upsert bool) handler {
+	return func(w http.ResponseWriter, r *http.Request, p params) {
+		cs, ok := s.lookupConfigStore(w, p)
+		if !ok {
+			return
+		}
+		rec, exists := cs.items[p["key"]]
+		if !exists {
+			if !upsert {
+				writeError(w, http.StatusNotFound, "Record not found", "Cannot find config store item '%s'", p["key"])
+				return
+			}
+			rec = newConfigStoreItem(p["store_id"], p["key"], "")
+			cs.items[p["key"]] = rec
+		}
+		rec["item_value"] = r.PostFormValue("item_value")
+		rec["updated_at"] = now()
+		writeJSON(w, http.StatusOK, rec)
+	}
+}
+
+func (s *Server) deleteConfigStoreItem(w http.ResponseWriter, _ *http.Request, p params) {
+	if cs, ok := s.lookupConfigStore(w, p); ok {
+		if _, ok := lookupResource(w, "config store item", cs.items, p["key"]); ok {
+			delete(cs.items, p["key"])
+			writeJSON(w, http.StatusOK, status)
+		}
+	}
+}
+
+func (s *Server) batchConfigStoreItems(w http.ResponseWriter, r *http.Request, p params) {
+	cs, ok := s.lookupConfigStore(w, p)
+	if !ok {
+		return
+	}
+	var b batchItems
+	if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+		writeError(w, http.StatusBadRequest, "Bad request", "Error parsing the batch: %s", err)
+		return
+	}
+	err := b.apply(cs.items, func(key, value string) record {
+		return newConfigStoreItem(p["store_id"], key, value)
+	})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Bad request", "%s", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+// lookupKVStore returns the KV store identified by the request path, writing
+// an error if it doesn't exist.
+func (s *Server) lookupKVStore(w http.ResponseWriter, p params) (*kvStore, bool) {
+	kv, ok := s.kvStores[p["store_id"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Record not found", "Cannot find kv store '%s'", p["store_id"])
+	}
+	return kv, ok
+}
+
+// NOTE: Every store and key is returned in a single page, so the responses of
+// the KV store list endpoints have no next_cursor.
+func (s *Server) listKVStores(w http.ResponseWriter, _ *http.Request, _ params) {
+	l := make([]record, 0, len(s.kvStores))
+	for _, id := range sortedKeys(s.kvStores) {
+		l = append(l, s.kvStores[id].record)
+	}
+	writeJSON(w, http.StatusOK, record{"data": l, "meta": record{}})
+}
+
+func (s *Server) createKVStore(w http.ResponseWriter, r *http.Request, _ params) {
+	var input struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Bad request", "Error parsing the request: %s", err)
+		return
+	}
+	rec, ok := newStore(w, input.Name, func(name string) bool {
+		for _, kv := range s.kvStores {
+			if kv.record["name"] == name {
+				return true
+			}
+		}
+		return false
+	})
+	if !ok {
+		return
+	}
+	s.kvStores[rec["id"].(string)] = &kvStore{record: rec, values: make(map[string][]byte)}
+	writeJSON(w, http.StatusCreated, rec)
+}
+
+func (s *Server) getKVStore(w http.ResponseWriter, _ *http.Request, p params) {
+	if kv, ok := s.lookupKVStore(w, p); ok {
+		writeJSON(w, http.StatusOK, kv.record)
+	}
+}
+
+func (s *Server) deleteKVStore(w http.ResponseWriter, _ *http.Request, p params) {
+	if _, ok := s.lookupKVStore(w, p); ok {
+		delete(s.kvStores, p["store_id"])
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *Server) listKVStoreKeys(w http.ResponseWriter, _ *http.Request, p params) {
+	if kv, ok := s.lookupKVStore(w, p); ok {
+		writeJSON(w, http.StatusOK, record{"data": sortedKeys(kv.values), "meta": record{}})
+	}
+}
+
+func (s *Server) getKVStoreKey(w http.ResponseWriter, _ *http.Request, p params) {
+	kv, ok := s.lookupKVStore(w, p)
+	if !ok {
+		return
+	}
+	v, ok := kv.values[p["key"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Record not found", "Cannot find key '%s'", p["key"])
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	_, _ = w.Write(v)
+}
+
+func (s *Server) insertKVStoreKey(w http.ResponseWriter, r *http.Request, p params) {
+	kv, ok := s.lookupKVStore(w, p)
+	if !ok {
+		return
+	}
+	v, err := io.ReadAll(io.LimitReader(r.Body, maxKVValueSize+1))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Bad request", "Error reading the value: %s", err)
+		return
+	}
+	if len(v) > maxKVValueSize {
+		writeError(w, http.StatusRequestEntityTooLarge, "Bad request", "The value of key '%s' is too large", p["key"])
+		return
+	}
+	kv.values[p["key"]] = v
+	writeJSON(w, http.StatusOK, status)
+}
+
+func (s *Server) deleteKVStoreKey(w http.ResponseWriter, _ *http.Request, p params) {
+	kv, ok := s.lookupKVStore(w, p)
+	if !ok {
+		return
+	}
+	if _, ok := kv.values[p["key"]]; !ok {
+		writeError(w, http.StatusNotFound, "Record not found", "Cannot find key '%s'", p["key"])
+		return
+	}
+	delete(kv.values, p["key"])
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// batchKVStoreKeys handles a newline delimited stream of JSON objects, each
+// containing a key and a base64 encoded value. If any line is invalid, none
+// of the keys are inserted.
+func (s *Server) batchKVStoreKeys(w http.ResponseWriter, r *http.Request, p params) {
+	kv, ok := s.lookupKVStore(w, p)
+	if !ok {
+		return
+	}
+	values := make(map[string][]byte)
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxKVValueSize*2)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || entry.Key == "" {
+			writeError(w, http.StatusBadRequest, "Bad request", "Invalid entry on line %d", line)
+			return
+		}
+		v, err := base64.StdEncoding.DecodeString(entry.Value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Bad request", "Invalid base64 value on line %d: %s", line, err)
+			return
+		}
+		values[entry.Key] = v
+	}
+	if err := scanner.Err(); err != nil {
+		writeError(w, http.StatusBadRequest, "Bad request", "Error reading the batch: %s", err)
+		return
+	}
+	for k, v := range values {
+		kv.values[k] = v
+	}
+	writeJSON(w, http.StatusOK, status)
+}
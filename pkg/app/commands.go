@@ -8,13 +8,20 @@ import (
 	"github.com/fastly/cli/pkg/commands/aclentry"
 	"github.com/fastly/cli/pkg/commands/authtoken"
 	"github.com/fastly/cli/pkg/commands/backend"
+	"github.com/fastly/cli/pkg/commands/cachesetting"
 	"github.com/fastly/cli/pkg/commands/compute"
+	"github.com/fastly/cli/pkg/commands/condition"
 	"github.com/fastly/cli/pkg/commands/config"
 	"github.com/fastly/cli/pkg/commands/configstore"
 	"github.com/fastly/cli/pkg/commands/configstoreentry"
+	"github.com/fastly/cli/pkg/commands/dev"
 	"github.com/fastly/cli/pkg/commands/dictionary"
 	"github.com/fastly/cli/pkg/commands/dictionaryentry"
+	"github.com/fastly/cli/pkg/commands/director"
+	directorBackend "github.com/fastly/cli/pkg/commands/director/backend"
 	"github.com/fastly/cli/pkg/commands/domain"
+	"github.com/fastly/cli/pkg/commands/gzip"
+	"github.com/fastly/cli/pkg/commands/header"
 	"github.com/fastly/cli/pkg/commands/healthcheck"
 	"github.com/fastly/cli/pkg/commands/ip"
 	"github.com/fastly/cli/pkg/commands/kvstore"
@@ -47,11 +54,15 @@ import (
 	"github.com/fastly/cli/pkg/commands/logging/sumologic"
 	"github.com/fastly/cli/pkg/commands/logging/syslog"
 	"github.com/fastly/cli/pkg/commands/logtail"
+	"github.com/fastly/cli/pkg/commands/pool"
+	"github.com/fastly/cli/pkg/commands/pool/server"
 	"github.com/fastly/cli/pkg/commands/pop"
 	"github.com/fastly/cli/pkg/commands/profile"
 	"github.com/fastly/cli/pkg/commands/purge"
 	"github.com/fastly/cli/pkg/commands/ratelimit"
+	"github.com/fastly/cli/pkg/commands/requestsetting"
 	"github.com/fastly/cli/pkg/commands/resourcelink"
+	"github.com/fastly/cli/pkg/commands/responseobject"
 	"github.com/fastly/cli/pkg/commands/secretstore"
 	"github.com/fastly/cli/pkg/commands/secretstoreentry"
 	"github.com/fastly/cli/pkg/commands/service"
@@ -108,17 +119,32 @@ func defineCommands(
 	backendDescribe := backend.NewDescribeCommand(backendCmdRoot.CmdClause, g, m)
 	backendList := backend.NewListCommand(backendCmdRoot.CmdClause, g, m)
 	backendUpdate := backend.NewUpdateCommand(backendCmdRoot.CmdClause, g, m)
+	cacheSettingCmdRoot := cachesetting.NewRootCommand(app, g)
+	cacheSettingCreate := cachesetting.NewCreateCommand(cacheSettingCmdRoot.CmdClause, g, m)
+	cacheSettingDelete := cachesetting.NewDeleteCommand(cacheSettingCmdRoot.CmdClause, g, m)
+	cacheSettingDescribe := cachesetting.NewDescribeCommand(cacheSettingCmdRoot.CmdClause, g, m)
+	cacheSettingList := cachesetting.NewListCommand(cacheSettingCmdRoot.CmdClause, g, m)
+	cacheSettingUpdate := cachesetting.NewUpdateCommand(cacheSettingCmdRoot.CmdClause, g, m)
 	computeCmdRoot := compute.NewRootCommand(app, g)
 	computeBuild := compute.NewBuildCommand(computeCmdRoot.CmdClause, g, m)
 	computeDeploy := compute.NewDeployCommand(computeCmdRoot.CmdClause, g, m)
 	computeHashFiles := compute.NewHashFilesCommand(computeCmdRoot.CmdClause, g, computeBuild, m)
 	computeHashsum := compute.NewHashsumCommand(computeCmdRoot.CmdClause, g, computeBuild, m)
 	computeInit := compute.NewInitCommand(computeCmdRoot.CmdClause, g, m)
+	computeLocalStores := compute.NewLocalStoresRootCommand(computeCmdRoot.CmdClause, g)
+	computeLocalStoresPull := compute.NewLocalStoresPullCommand(computeLocalStores.CmdClause, g, m)
 	computePack := compute.NewPackCommand(computeCmdRoot.CmdClause, g, m)
 	computePublish := compute.NewPublishCommand(computeCmdRoot.CmdClause, g, computeBuild, computeDeploy, m)
 	computeServe := compute.NewServeCommand(computeCmdRoot.CmdClause, g, computeBuild, opts.Versioners.Viceroy, m)
+	computeTest := compute.NewTestCommand(computeCmdRoot.CmdClause, g, computeBuild, opts.Versioners.Viceroy, m)
 	computeUpdate := compute.NewUpdateCommand(computeCmdRoot.CmdClause, g, m)
 	computeValidate := compute.NewValidateCommand(computeCmdRoot.CmdClause, g, m)
+	conditionCmdRoot := condition.NewRootCommand(app, g)
+	conditionCreate := condition.NewCreateCommand(conditionCmdRoot.CmdClause, g, m)
+	conditionDelete := condition.NewDeleteCommand(conditionCmdRoot.CmdClause, g, m)
+	conditionDescribe := condition.NewDescribeCommand(conditionCmdRoot.CmdClause, g, m)
+	conditionList := condition.NewListCommand(conditionCmdRoot.CmdClause, g, m)
+	conditionUpdate := condition.NewUpdateCommand(conditionCmdRoot.CmdClause, g, m)
 	configCmdRoot := config.NewRootCommand(app, g)
 	configstoreCmdRoot := configstore.NewRootCommand(app, g)
 	configstoreCreate := configstore.NewCreateCommand(configstoreCmdRoot.CmdClause, g, m)
@@ -133,6 +159,8 @@ func defineCommands(
 	configstoreentryDescribe := configstoreentry.NewDescribeCommand(configstoreentryCmdRoot.CmdClause, g, m)
 	configstoreentryList := configstoreentry.NewListCommand(configstoreentryCmdRoot.CmdClause, g, m)
 	configstoreentryUpdate := configstoreentry.NewUpdateCommand(configstoreentryCmdRoot.CmdClause, g, m)
+	devCmdRoot := dev.NewRootCommand(app, g)
+	devAPIServer := dev.NewAPIServerCommand(devCmdRoot.CmdClause, g)
 	dictionaryCmdRoot := dictionary.NewRootCommand(app, g)
 	dictionaryCreate := dictionary.NewCreateCommand(dictionaryCmdRoot.CmdClause, g, m)
 	dictionaryDelete := dictionary.NewDeleteCommand(dictionaryCmdRoot.CmdClause, g, m)
@@ -145,6 +173,15 @@ func defineCommands(
 	dictionaryEntryUpdate := dictionaryentry.NewUpdateCommand(dictionaryEntryCmdRoot.CmdClause, g, m)
 	dictionaryList := dictionary.NewListCommand(dictionaryCmdRoot.CmdClause, g, m)
 	dictionaryUpdate := dictionary.NewUpdateCommand(dictionaryCmdRoot.CmdClause, g, m)
+	directorCmdRoot := director.NewRootCommand(app, g)
+	directorCreate := director.NewCreateCommand(directorCmdRoot.CmdClause, g, m)
+	directorDelete := director.NewDeleteCommand(directorCmdRoot.CmdClause, g, m)
+	directorDescribe := director.NewDescribeCommand(directorCmdRoot.CmdClause, g, m)
+	directorList := director.NewListCommand(directorCmdRoot.CmdClause, g, m)
+	directorUpdate := director.NewUpdateCommand(directorCmdRoot.CmdClause, g, m)
+	directorBackendCmdRoot := directorBackend.NewRootCommand(directorCmdRoot.CmdClause, g)
+	directorBackendAttach := directorBackend.NewAttachCommand(directorBackendCmdRoot.CmdClause, g, m)
+	directorBackendDetach := directorBackend.NewDetachCommand(directorBackendCmdRoot.CmdClause, g, m)
 	domainCmdRoot := domain.NewRootCommand(app, g)
 	domainCreate := domain.NewCreateCommand(domainCmdRoot.CmdClause, g, m)
 	domainDelete := domain.NewDeleteCommand(domainCmdRoot.CmdClause, g, m)
@@ -152,6 +189,18 @@ func defineCommands(
 	domainList := domain.NewListCommand(domainCmdRoot.CmdClause, g, m)
 	domainUpdate := domain.NewUpdateCommand(domainCmdRoot.CmdClause, g, m)
 	domainValidate := domain.NewValidateCommand(domainCmdRoot.CmdClause, g, m)
+	gzipCmdRoot := gzip.NewRootCommand(app, g)
+	gzipCreate := gzip.NewCreateCommand(gzipCmdRoot.CmdClause, g, m)
+	gzipDelete := gzip.NewDeleteCommand(gzipCmdRoot.CmdClause, g, m)
+	gzipDescribe := gzip.NewDescribeCommand(gzipCmdRoot.CmdClause, g, m)
+	gzipList := gzip.NewListCommand(gzipCmdRoot.CmdClause, g, m)
+	gzipUpdate := gzip.NewUpdateCommand(gzipCmdRoot.CmdClause, g, m)
+	headerCmdRoot := header.NewRootCommand(app, g)
+	headerCreate := header.NewCreateCommand(headerCmdRoot.CmdClause, g, m)
+	headerDelete := header.NewDeleteCommand(headerCmdRoot.CmdClause, g, m)
+	headerDescribe := header.NewDescribeCommand(headerCmdRoot.CmdClause, g, m)
+	headerList := header.NewListCommand(headerCmdRoot.CmdClause, g, m)
+	headerUpdate := header.NewUpdateCommand(headerCmdRoot.CmdClause, g, m)
 	healthcheckCmdRoot := healthcheck.NewRootCommand(app, g)
 	healthcheckCreate := healthcheck.NewCreateCommand(healthcheckCmdRoot.CmdClause, g, m)
 	healthcheckDelete := healthcheck.NewDeleteCommand(healthcheckCmdRoot.CmdClause, g, m)
@@ -170,6 +219,7 @@ func defineCommands(
 	kvstoreentryDescribe := kvstoreentry.NewDescribeCommand(kvstoreentryCmdRoot.CmdClause, g, m)
 	kvstoreentryList := kvstoreentry.NewListCommand(kvstoreentryCmdRoot.CmdClause, g, m)
 	logtailCmdRoot := logtail.NewRootCommand(app, g, m)
+	logtailReplay := logtail.NewReplayCommand(logtailCmdRoot, g)
 	loggingCmdRoot := logging.NewRootCommand(app, g)
 	loggingAzureblobCmdRoot := azureblob.NewRootCommand(loggingCmdRoot.CmdClause, g)
 	loggingAzureblobCreate := azureblob.NewCreateCommand(loggingAzureblobCmdRoot.CmdClause, g, m)
@@ -327,6 +377,18 @@ func defineCommands(
 	loggingSyslogDescribe := syslog.NewDescribeCommand(loggingSyslogCmdRoot.CmdClause, g, m)
 	loggingSyslogList := syslog.NewListCommand(loggingSyslogCmdRoot.CmdClause, g, m)
 	loggingSyslogUpdate := syslog.NewUpdateCommand(loggingSyslogCmdRoot.CmdClause, g, m)
+	poolCmdRoot := pool.NewRootCommand(app, g)
+	poolCreate := pool.NewCreateCommand(poolCmdRoot.CmdClause, g, m)
+	poolDelete := pool.NewDeleteCommand(poolCmdRoot.CmdClause, g, m)
+	poolDescribe := pool.NewDescribeCommand(poolCmdRoot.CmdClause, g, m)
+	poolList := pool.NewListCommand(poolCmdRoot.CmdClause, g, m)
+	poolUpdate := pool.NewUpdateCommand(poolCmdRoot.CmdClause, g, m)
+	poolServerCmdRoot := server.NewRootCommand(poolCmdRoot.CmdClause, g)
+	poolServerCreate := server.NewCreateCommand(poolServerCmdRoot.CmdClause, g, m)
+	poolServerDelete := server.NewDeleteCommand(poolServerCmdRoot.CmdClause, g, m)
+	poolServerDescribe := server.NewDescribeCommand(poolServerCmdRoot.CmdClause, g, m)
+	poolServerList := server.NewListCommand(poolServerCmdRoot.CmdClause, g, m)
+	poolServerUpdate := server.NewUpdateCommand(poolServerCmdRoot.CmdClause, g, m)
 	popCmdRoot := pop.NewRootCommand(app, g)
 	profileCmdRoot := profile.NewRootCommand(app, g)
 	profileCreate := profile.NewCreateCommand(profileCmdRoot.CmdClause, profile.APIClientFactory(opts.APIClient), g)
@@ -342,12 +404,24 @@ func defineCommands(
 	rateLimitDescribe := ratelimit.NewDescribeCommand(rateLimitCmdRoot.CmdClause, g, m)
 	rateLimitList := ratelimit.NewListCommand(rateLimitCmdRoot.CmdClause, g, m)
 	rateLimitUpdate := ratelimit.NewUpdateCommand(rateLimitCmdRoot.CmdClause, g, m)
+	requestSettingCmdRoot := requestsetting.NewRootCommand(app, g)
+	requestSettingCreate := requestsetting.NewCreateCommand(requestSettingCmdRoot.CmdClause, g, m)
+	requestSettingDelete := requestsetting.NewDeleteCommand(requestSettingCmdRoot.CmdClause, g, m)
+	requestSettingDescribe := requestsetting.NewDescribeCommand(requestSettingCmdRoot.CmdClause, g, m)
+	requestSettingList := requestsetting.NewListCommand(requestSettingCmdRoot.CmdClause, g, m)
+	requestSettingUpdate := requestsetting.NewUpdateCommand(requestSettingCmdRoot.CmdClause, g, m)
 	resourcelinkCmdRoot := resourcelink.NewRootCommand(app, g)
 	resourcelinkCreate := resourcelink.NewCreateCommand(resourcelinkCmdRoot.CmdClause, g, m)
 	resourcelinkDelete := resourcelink.NewDeleteCommand(resourcelinkCmdRoot.CmdClause, g, m)
 	resourcelinkDescribe := resourcelink.NewDescribeCommand(resourcelinkCmdRoot.CmdClause, g, m)
 	resourcelinkList := resourcelink.NewListCommand(resourcelinkCmdRoot.CmdClause, g, m)
 	resourcelinkUpdate := resourcelink.NewUpdateCommand(resourcelinkCmdRoot.CmdClause, g, m)
+	responseObjectCmdRoot := responseobject.NewRootCommand(app, g)
+	responseObjectCreate := responseobject.NewCreateCommand(responseObjectCmdRoot.CmdClause, g, m)
+	responseObjectDelete := responseobject.NewDeleteCommand(responseObjectCmdRoot.CmdClause, g, m)
+	responseObjectDescribe := responseobject.NewDescribeCommand(responseObjectCmdRoot.CmdClause, g, m)
+	responseObjectList := responseobject.NewListCommand(responseObjectCmdRoot.CmdClause, g, m)
+	responseObjectUpdate := responseobject.NewUpdateCommand(responseObjectCmdRoot.CmdClause, g, m)
 	secretstoreCmdRoot := secretstore.NewRootCommand(app, g)
 	secretstoreCreate := secretstore.NewCreateCommand(secretstoreCmdRoot.CmdClause, g, m)
 	secretstoreDescribe := secretstore.NewDescribeCommand(secretstoreCmdRoot.CmdClause, g, m)
@@ -359,9 +433,11 @@ func defineCommands(
 	secretstoreentryDelete := secretstoreentry.NewDeleteCommand(secretstoreentryCmdRoot.CmdClause, g, m)
 	secretstoreentryList := secretstoreentry.NewListCommand(secretstoreentryCmdRoot.CmdClause, g, m)
 	serviceCmdRoot := service.NewRootCommand(app, g)
+	serviceApply := service.NewApplyCommand(serviceCmdRoot.CmdClause, g, m)
 	serviceCreate := service.NewCreateCommand(serviceCmdRoot.CmdClause, g)
 	serviceDelete := service.NewDeleteCommand(serviceCmdRoot.CmdClause, g, m)
 	serviceDescribe := service.NewDescribeCommand(serviceCmdRoot.CmdClause, g, m)
+	serviceExport := service.NewExportCommand(serviceCmdRoot.CmdClause, g, m)
 	serviceList := service.NewListCommand(serviceCmdRoot.CmdClause, g)
 	serviceSearch := service.NewSearchCommand(serviceCmdRoot.CmdClause, g, m)
 	serviceUpdate := service.NewUpdateCommand(serviceCmdRoot.CmdClause, g, m)
@@ -375,10 +451,12 @@ func defineCommands(
 	serviceVersionActivate := serviceversion.NewActivateCommand(serviceVersionCmdRoot.CmdClause, g, m)
 	serviceVersionClone := serviceversion.NewCloneCommand(serviceVersionCmdRoot.CmdClause, g, m)
 	serviceVersionDeactivate := serviceversion.NewDeactivateCommand(serviceVersionCmdRoot.CmdClause, g, m)
+	serviceVersionDiff := serviceversion.NewDiffCommand(serviceVersionCmdRoot.CmdClause, g, m)
 	serviceVersionList := serviceversion.NewListCommand(serviceVersionCmdRoot.CmdClause, g, m)
 	serviceVersionLock := serviceversion.NewLockCommand(serviceVersionCmdRoot.CmdClause, g, m)
 	serviceVersionUpdate := serviceversion.NewUpdateCommand(serviceVersionCmdRoot.CmdClause, g, m)
 	statsCmdRoot := stats.NewRootCommand(app, g)
+	statsExporter := stats.NewExporterCommand(statsCmdRoot.CmdClause, g, m)
 	statsHistorical := stats.NewHistoricalCommand(statsCmdRoot.CmdClause, g, m)
 	statsRealtime := stats.NewRealtimeCommand(statsCmdRoot.CmdClause, g, m)
 	statsRegions := stats.NewRegionsCommand(statsCmdRoot.CmdClause, g)
@@ -466,17 +544,32 @@ func defineCommands(
 		backendDescribe,
 		backendList,
 		backendUpdate,
+		cacheSettingCmdRoot,
+		cacheSettingCreate,
+		cacheSettingDelete,
+		cacheSettingDescribe,
+		cacheSettingList,
+		cacheSettingUpdate,
 		computeBuild,
 		computeCmdRoot,
 		computeDeploy,
 		computeHashFiles,
 		computeHashsum,
 		computeInit,
+		computeLocalStores,
+		computeLocalStoresPull,
 		computePack,
 		computePublish,
 		computeServe,
+		computeTest,
 		computeUpdate,
 		computeValidate,
+		conditionCmdRoot,
+		conditionCreate,
+		conditionDelete,
+		conditionDescribe,
+		conditionList,
+		conditionUpdate,
 		configCmdRoot,
 		configstoreCmdRoot,
 		configstoreCreate,
@@ -491,6 +584,8 @@ func defineCommands(
 		configstoreentryDescribe,
 		configstoreentryList,
 		configstoreentryUpdate,
+		devCmdRoot,
+		devAPIServer,
 		dictionaryCmdRoot,
 		dictionaryCreate,
 		dictionaryDelete,
@@ -503,6 +598,15 @@ func defineCommands(
 		dictionaryEntryUpdate,
 		dictionaryList,
 		dictionaryUpdate,
+		directorBackendAttach,
+		directorBackendCmdRoot,
+		directorBackendDetach,
+		directorCmdRoot,
+		directorCreate,
+		directorDelete,
+		directorDescribe,
+		directorList,
+		directorUpdate,
 		domainCmdRoot,
 		domainCreate,
 		domainDelete,
@@ -510,6 +614,18 @@ func defineCommands(
 		domainList,
 		domainUpdate,
 		domainValidate,
+		gzipCmdRoot,
+		gzipCreate,
+		gzipDelete,
+		gzipDescribe,
+		gzipList,
+		gzipUpdate,
+		headerCmdRoot,
+		headerCreate,
+		headerDelete,
+		headerDescribe,
+		headerList,
+		headerUpdate,
 		healthcheckCmdRoot,
 		healthcheckCreate,
 		healthcheckDelete,
@@ -526,6 +642,7 @@ func defineCommands(
 		kvstoreentryDescribe,
 		kvstoreentryList,
 		logtailCmdRoot,
+		logtailReplay,
 		loggingAzureblobCmdRoot,
 		loggingAzureblobCreate,
 		loggingAzureblobDelete,
@@ -683,6 +800,18 @@ func defineCommands(
 		loggingSyslogDescribe,
 		loggingSyslogList,
 		loggingSyslogUpdate,
+		poolCmdRoot,
+		poolCreate,
+		poolDelete,
+		poolDescribe,
+		poolList,
+		poolUpdate,
+		poolServerCmdRoot,
+		poolServerCreate,
+		poolServerDelete,
+		poolServerDescribe,
+		poolServerList,
+		poolServerUpdate,
 		popCmdRoot,
 		profileCmdRoot,
 		profileCreate,
@@ -698,12 +827,24 @@ func defineCommands(
 		rateLimitDescribe,
 		rateLimitList,
 		rateLimitUpdate,
+		requestSettingCmdRoot,
+		requestSettingCreate,
+		requestSettingDelete,
+		requestSettingDescribe,
+		requestSettingList,
+		requestSettingUpdate,
 		resourcelinkCmdRoot,
 		resourcelinkCreate,
 		resourcelinkDelete,
 		resourcelinkDescribe,
 		resourcelinkList,
 		resourcelinkUpdate,
+		responseObjectCmdRoot,
+		responseObjectCreate,
+		responseObjectDelete,
+		responseObjectDescribe,
+		responseObjectList,
+		responseObjectUpdate,
 		secretstoreCreate,
 		secretstoreDescribe,
 		secretstoreDelete,
@@ -713,9 +854,11 @@ func defineCommands(
 		secretstoreentryDelete,
 		secretstoreentryList,
 		serviceCmdRoot,
+		serviceApply,
 		serviceCreate,
 		serviceDelete,
 		serviceDescribe,
+		serviceExport,
 		serviceList,
 		serviceSearch,
 		serviceUpdate,
@@ -729,10 +872,12 @@ func defineCommands(
 		serviceVersionClone,
 		serviceVersionCmdRoot,
 		serviceVersionDeactivate,
+		serviceVersionDiff,
 		serviceVersionList,
 		serviceVersionLock,
 		serviceVersionUpdate,
 		statsCmdRoot,
+		statsExporter,
 		statsHistorical,
 		statsRealtime,
 		statsRegions,
@@ -10,6 +10,7 @@ import (
 	"github.com/fastly/kingpin"
 
 	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/cmd"
 	"github.com/fastly/cli/pkg/commands/update"
 	"github.com/fastly/cli/pkg/commands/version"
 	"github.com/fastly/cli/pkg/config"
@@ -31,6 +32,13 @@ func FastlyAPIClient(token, endpoint string) (api.Interface, error) {
 	return client, err
 }
 
+// FastlyRTSClient is a RTSClientFactory that returns a real Fastly realtime
+// stats client using the provided token.
+func FastlyRTSClient(token string) (api.RealtimeStatsInterface, error) {
+	client, err := fastly.NewRealtimeStatsClientForEndpoint(token, fastly.DefaultRealtimeStatsEndpoint)
+	return client, err
+}
+
 // Run constructs the application including all of the subcommands, parses the
 // args, invokes the client factory with the token to create a Fastly API
 // client, and executes the chosen command, using the provided io.Reader and
@@ -90,10 +98,14 @@ func Run(opts RunOpts) error {
 	app.Flag("verbose", "Verbose logging").Short('v').BoolVar(&g.Flags.Verbose)
 
 	commands := defineCommands(app, &g, *opts.Manifest, opts)
+	registerFormatFlag(app, &g, commands)
 	command, name, err := processCommandInput(opts, app, &g, commands)
 	if err != nil {
 		return err
 	}
+	if o, ok := command.(cmd.StructuredOutput); ok && g.Flags.Format != "" {
+		o.SetFormat(g.Flags.Format)
+	}
 	// We short-circuit the execution for specific cases:
 	//
 	// - cmd.ArgsIsHelpJSON() == true
@@ -166,7 +178,11 @@ func Run(opts RunOpts) error {
 
 	// NOTE: We return error immediately so there's no issue assigning to global.
 	// nosemgrep
-	g.RTSClient, err = fastly.NewRealtimeStatsClientForEndpoint(token, fastly.DefaultRealtimeStatsEndpoint)
+	rtsClient := opts.RTSClient
+	if rtsClient == nil {
+		rtsClient = FastlyRTSClient
+	}
+	g.RTSClient, err = rtsClient(token)
 	if err != nil {
 		g.ErrLog.Add(err)
 		return fmt.Errorf("error constructing Fastly realtime stats client: %w", err)
@@ -184,6 +200,29 @@ func Run(opts RunOpts) error {
 	return command.Exec(opts.Stdin, opts.Stdout)
 }
 
+// registerFormatFlag defines the global --format flag.
+//
+// NOTE: The flag isn't defined on the app, like the other global flags, as
+// some commands (e.g. the logging endpoints) define a --format flag of their
+// own and kingpin doesn't allow a subcommand to redefine a global flag.
+// Instead it's defined on every command that supports structured output.
+func registerFormatFlag(app *kingpin.Application, g *global.Data, commands []cmd.Command) {
+	for _, command := range commands {
+		if _, ok := command.(cmd.StructuredOutput); !ok {
+			continue
+		}
+		var clause *kingpin.CmdClause
+		for i, name := range strings.Fields(command.Name()) {
+			if i == 0 {
+				clause = app.GetCommand(name)
+			} else {
+				clause = clause.GetCommand(name)
+			}
+		}
+		clause.Flag("format", "Output format (json, yaml, csv, table)").HintOptions(text.Formats...).EnumVar(&g.Flags.Format, text.Formats...)
+	}
+}
+
 // RunOpts represent arguments to Run()
 type RunOpts struct {
 	APIClient  APIClientFactory
@@ -194,6 +233,7 @@ type RunOpts struct {
 	ErrLog     fsterr.LogInterface
 	HTTPClient api.HTTPClient
 	Manifest   *manifest.Data
+	RTSClient  RTSClientFactory
 	Stdin      io.Reader
 	Stdout     io.Writer
 	Versioners Versioners
@@ -206,6 +246,11 @@ type RunOpts struct {
 // interface via MockClient.
 type APIClientFactory func(token, endpoint string) (api.Interface, error)
 
+// RTSClientFactory creates a Fastly realtime stats client (modeled as an
+// api.RealtimeStatsInterface) from a user-provided API token. When unset, the
+// Run helper defaults to FastlyRTSClient.
+type RTSClientFactory func(token string) (api.RealtimeStatsInterface, error)
+
 // Versioners represents all supported versioner types.
 type Versioners struct {
 	CLI     github.AssetVersioner
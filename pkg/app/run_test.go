@@ -61,24 +61,33 @@ acl
 acl-entry
 auth-token
 backend
+cache-setting
 compute
+condition
 config
 config-store
 config-store-entry
+dev
 dictionary
 dictionary-entry
+director
 domain
+gzip
+header
 healthcheck
 ip-list
 kv-store
 kv-store-entry
 log-tail
 logging
+pool
 pops
 profile
 purge
 rate-limit
+request-setting
 resource-link
+response-object
 secret-store
 secret-store-entry
 service
@@ -1,4 +1,5 @@
-// Package check provides functions for validating installed binaries.
+// Package check provides functions for validating installed binaries and the
+// availability of services.
 package check
 
 import (
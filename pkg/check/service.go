@@ -0,0 +1,127 @@
+package check
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// ErrServiceUnavailable indicates the service didn't become available before
+// the availability check timed out.
+var ErrServiceUnavailable = errors.New("service not yet available")
+
+// ValidStatusCode checks the status is a valid status code.
+// e.g. >= 100 and <= 999
+func ValidStatusCode(status int) bool {
+	if status >= 100 && status <= 999 {
+		return true
+	}
+	return false
+}
+
+// ServiceAvailability pings the service URL, every interval, until either
+// there is a non-500 (or whatever expected status code is given) or the
+// timeout is reached.
+//
+// The returned status is the last status code received, and ErrServiceUnavailable
+// is returned if the timeout is reached.
+func ServiceAvailability(
+	serviceURL string,
+	httpClient api.HTTPClient,
+	expectedStatusCode int,
+	timeout time.Duration,
+	interval time.Duration,
+	spinner text.Spinner,
+) (status int, err error) {
+	end := time.Now().Add(timeout)
+	timer := time.After(timeout)
+	ticker := time.NewTicker(interval)
+	defer func() { ticker.Stop() }()
+
+	err = spinner.Start()
+	if err != nil {
+		return 0, err
+	}
+	msg := "Checking service availability"
+	spinner.Message(msg + generateTimeout(time.Until(end)))
+
+	// Keep trying until we're timed out, got a result or got an error
+	for {
+		select {
+		case <-timer:
+			returnedStatus := fmt.Sprintf(" (status: %d)", status)
+			spinner.StopFailMessage(msg + returnedStatus)
+			spinErr := spinner.StopFail()
+			if spinErr != nil {
+				return status, spinErr
+			}
+			return status, ErrServiceUnavailable
+		case t := <-ticker.C:
+			var (
+				ok  bool
+				err error
+			)
+			// We overwrite the `status` variable in the parent scope (defined in the
+			// return arguments list) so it can be used as part of both the timeout
+			// and success scenarios.
+			ok, status, err = PingServiceURL(serviceURL, httpClient, expectedStatusCode)
+			if err != nil {
+				returnedStatus := fmt.Sprintf(" (status: %d)", status)
+				spinner.StopFailMessage(msg + returnedStatus)
+				spinErr := spinner.StopFail()
+				if spinErr != nil {
+					return status, spinErr
+				}
+				return status, err
+			} else if ok {
+				returnedStatus := fmt.Sprintf(" (status: %d)", status)
+				spinner.StopMessage(msg + returnedStatus)
+				return status, spinner.Stop()
+			}
+			// Service not available, and no error, so jump back to top of loop
+			spinner.Message(msg + generateTimeout(end.Sub(t)))
+		}
+	}
+}
+
+// generateTimeout inserts a dynamically generated message on each tick.
+// It notifies the user what's happening and how long is left on the timer.
+func generateTimeout(d time.Duration) string {
+	remaining := fmt.Sprintf("timeout: %v", d.Round(time.Second))
+	return fmt.Sprintf(" (app deploying across Fastly's global network | %s)...", remaining)
+}
+
+// PingServiceURL indicates if the service returned a non-5xx response (or
+// whatever the user defined as the expected status code), which should help
+// signify if the service is generally available.
+func PingServiceURL(serviceURL string, httpClient api.HTTPClient, expectedStatusCode int) (ok bool, status int, err error) {
+	req, err := http.NewRequest("GET", serviceURL, nil)
+	if err != nil {
+		return false, 0, err
+	}
+
+	// gosec flagged this:
+	// G107 (CWE-88): Potential HTTP request made with variable url
+	// Disabling as we trust the source of the variable.
+	// #nosec
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return false, 0, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	// We check for the user's defined status code expectation.
+	// Otherwise we'll default to checking for a non-500.
+	if ValidStatusCode(expectedStatusCode) && resp.StatusCode == expectedStatusCode {
+		return true, resp.StatusCode, nil
+	} else if resp.StatusCode < http.StatusInternalServerError {
+		return true, resp.StatusCode, nil
+	}
+	return false, resp.StatusCode, nil
+}
@@ -5,10 +5,24 @@ var (
 	FlagCustomerIDName = "customer-id"
 	// FlagCustomerIDDesc is the flag description.
 	FlagCustomerIDDesc = "Alphanumeric string identifying the customer (falls back to FASTLY_CUSTOMER_ID)"
+	// FlagFieldsName is the flag name.
+	FlagFieldsName = "fields"
+	// FlagFieldsDesc is the flag description.
+	FlagFieldsDesc = "Comma separated list of fields to output for each item (e.g. name,address,port)"
+	// FlagFilterName is the flag name.
+	FlagFilterName = "filter"
+	// FlagFilterDesc is the flag description.
+	FlagFilterDesc = "Only output items matching the expression (e.g. 'port==443', 'name=~^www && port!=80')"
 	// FlagJSONName is the flag name.
 	FlagJSONName = "json"
 	// FlagJSONDesc is the flag description.
 	FlagJSONDesc = "Render output as JSON"
+	// FlagServicesName is the flag name.
+	FlagServicesName = "services"
+	// FlagServicesDesc is the flag description.
+	FlagServicesDesc = "Run against every matching service: a name glob (e.g. 'shop-*'), a tag in the service comment (tag:production) or a file of service IDs (file:ids.txt), comma separated"
+	// FlagServicesRemediation is the remediation for an invalid --services selector.
+	FlagServicesRemediation = "The --services flag accepts a comma separated list of name globs (e.g. 'shop-*'), tags (e.g. 'tag:production') and files of service IDs (e.g. 'file:ids.txt')."
 	// FlagServiceIDName is the flag name.
 	FlagServiceIDName = "service-id"
 	// FlagServiceIDDesc is the flag description.
@@ -0,0 +1,396 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v2"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// SelectFields filters the items of a list by the --filter expression and
+// then reduces each item to the comma separated list of --fields.
+//
+// Field names are matched case-insensitively, ignoring any underscores and
+// hyphens (e.g. `service_id` matches `ServiceID`), and nested fields can be
+// selected with a dot separated path (e.g. `shield.name`).
+//
+// A filter expression compares fields with a value, e.g. `port==443`, using
+// the operators ==, !=, <, <=, >, >=, =~ (regular expression match) and !~.
+// Comparisons can be combined with && and || and grouped with parentheses.
+// Values containing spaces or operators must be quoted.
+func SelectFields(value any, filter, fields string) (any, error) {
+	v, err := text.Decode(value)
+	if err != nil {
+		return nil, err
+	}
+	l, ok := v.([]any)
+	if !ok {
+		l = []any{v}
+	}
+
+	if filter != "" {
+		expr, err := parseFilter(filter)
+		if err != nil {
+			return nil, fsterr.RemediationError{
+				Inner:       fmt.Errorf("error parsing --filter: %w", err),
+				Remediation: "Filter expressions compare a field with a value (e.g. 'port==443' or \"name=~^www\\.\") and can be combined with && and ||.",
+			}
+		}
+		seen := make(map[string]bool)
+		filtered := []any{}
+		for _, item := range l {
+			if expr.match(item, seen) {
+				filtered = append(filtered, item)
+			}
+		}
+		for _, name := range expr.fields(nil) {
+			if !seen[name] && len(l) > 0 {
+				return nil, unknownField(name, "--filter", l)
+			}
+		}
+		l = filtered
+	}
+
+	if fields != "" {
+		names := strings.Split(fields, ",")
+		for i, name := range names {
+			names[i] = strings.TrimSpace(name)
+		}
+		for _, name := range names {
+			var found bool
+			for _, item := range l {
+				if _, found = lookupField(item, name); found {
+					break
+				}
+			}
+			if !found && len(l) > 0 {
+				return nil, unknownField(name, "--fields", l)
+			}
+		}
+		for i, item := range l {
+			o := make(text.Object, 0, len(names))
+			for _, name := range names {
+				v, _ := lookupField(item, name)
+				o = append(o, yaml.MapItem{Key: fieldName(item, name), Value: v})
+			}
+			l[i] = o
+		}
+	}
+	return l, nil
+}
+
+// unknownField returns an error describing a field that doesn't exist on any
+// of the listed items.
+func unknownField(name, flag string, l []any) error {
+	var available []string
+	if o, ok := l[0].(text.Object); ok {
+		for _, item := range o {
+			available = append(available, fmt.Sprint(item.Key))
+		}
+	} else {
+		available = append(available, "value")
+	}
+	return fsterr.RemediationError{
+		Inner:       fmt.Errorf("error parsing %s: unknown field '%s'", flag, name),
+		Remediation: fmt.Sprintf("Available fields: %s", strings.Join(available, ", ")),
+	}
+}
+
+// normalizeField returns the form of a field name used for matching.
+func normalizeField(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+}
+
+// lookupField returns the value of the (dot separated) field of an item.
+//
+// NOTE: Items that aren't objects (e.g. a list of names) have a single field
+// called `value`.
+func lookupField(item any, name string) (any, bool) {
+	o, ok := item.(text.Object)
+	if !ok {
+		return item, normalizeField(name) == "value"
+	}
+	segs := strings.Split(name, ".")
+	for _, seg := range segs {
+		var found bool
+		for _, field := range o {
+			if normalizeField(fmt.Sprint(field.Key)) == normalizeField(seg) {
+				item, found = field.Value, true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+		if o, ok = item.(text.Object); !ok {
+			o = nil
+		}
+	}
+	return item, true
+}
+
+// fieldName returns the name the selected field is rendered with, preferring
+// the name used by the item (e.g. `Address` rather than `address`).
+func fieldName(item any, name string) string {
+	if o, ok := item.(text.Object); ok && !strings.Contains(name, ".") {
+		for _, field := range o {
+			if normalizeField(fmt.Sprint(field.Key)) == normalizeField(name) {
+				return fmt.Sprint(field.Key)
+			}
+		}
+	}
+	return name
+}
+
+// filterExpr is a parsed --filter expression.
+type filterExpr interface {
+	// match reports whether the item matches the expression, recording the
+	// fields found on the item in seen.
+	match(item any, seen map[string]bool) bool
+	// fields appends the names of the fields referenced by the expression.
+	fields(names []string) []string
+}
+
+// filterLogical combines two expressions with && or ||.
+type filterLogical struct {
+	and         bool
+	left, right filterExpr
+}
+
+func (e filterLogical) match(item any, seen map[string]bool) bool {
+	// NOTE: Both sides are evaluated so every referenced field is recorded.
+	l, r := e.left.match(item, seen), e.right.match(item, seen)
+	if e.and {
+		return l && r
+	}
+	return l || r
+}
+
+func (e filterLogical) fields(names []string) []string {
+	return e.right.fields(e.left.fields(names))
+}
+
+// filterComparison compares a field with a value.
+type filterComparison struct {
+	field string
+	op    string
+	value string
+	re    *regexp.Regexp
+}
+
+func (e filterComparison) match(item any, seen map[string]bool) bool {
+	v, ok := lookupField(item, e.field)
+	if ok {
+		seen[e.field] = true
+	}
+	s := fieldText(v)
+
+	switch e.op {
+	case "=~":
+		return e.re.MatchString(s)
+	case "!~":
+		return !e.re.MatchString(s)
+	}
+
+	cmp := strings.Compare(s, e.value)
+	if a, ok := fieldNumber(v); ok {
+		if b, err := strconv.ParseFloat(e.value, 64); err == nil {
+			switch {
+			case a < b:
+				cmp = -1
+			case a > b:
+				cmp = 1
+			default:
+				cmp = 0
+			}
+		}
+	}
+
+	switch e.op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	}
+	return cmp >= 0 // ">="
+}
+
+func (e filterComparison) fields(names []string) []string {
+	return append(names, e.field)
+}
+
+// fieldText returns the text a field value is compared as.
+func fieldText(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+// fieldNumber returns the numeric value of a field, if it is a number.
+func fieldNumber(v any) (float64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// filterOperators are the comparison operators, longest first so they're
+// matched greedily.
+var filterOperators = []string{"==", "!=", "<=", ">=", "=~", "!~", "<", ">"}
+
+// filterParser is a recursive descent parser for --filter expressions:
+//
+//	expr       = and { "||" and }
+//	and        = term { "&&" term }
+//	term       = "(" expr ")" | comparison
+//	comparison = word operator word
+type filterParser struct {
+	input string
+	pos   int
+}
+
+// parseFilter parses a --filter expression.
+func parseFilter(s string) (filterExpr, error) {
+	p := &filterParser{input: s}
+	e, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpace(); p.pos < len(p.input) {
+		return nil, fmt.Errorf("unexpected '%s' at position %d", p.input[p.pos:], p.pos+1)
+	}
+	return e, nil
+}
+
+func (p *filterParser) parseExpr() (filterExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = filterLogical{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterExpr, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume("&&") {
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = filterLogical{and: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseTerm() (filterExpr, error) {
+	if p.consume("(") {
+		e, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume(")") {
+			return nil, fmt.Errorf("missing ')' at position %d", p.pos+1)
+		}
+		return e, nil
+	}
+
+	field, err := p.parseWord("field name")
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	var op string
+	for _, o := range filterOperators {
+		if p.consume(o) {
+			op = o
+			break
+		}
+	}
+	if op == "" {
+		return nil, fmt.Errorf("expected an operator (%s) after '%s' at position %d", strings.Join(filterOperators, " "), field, p.pos+1)
+	}
+	value, err := p.parseWord("value")
+	if err != nil {
+		return nil, err
+	}
+
+	e := filterComparison{field: field, op: op, value: value}
+	if op == "=~" || op == "!~" {
+		if e.re, err = regexp.Compile(value); err != nil {
+			return nil, fmt.Errorf("invalid regular expression '%s': %w", value, err)
+		}
+	}
+	return e, nil
+}
+
+// parseWord parses a bare or quoted word.
+func (p *filterParser) parseWord(what string) (string, error) {
+	p.skipSpace()
+	if p.pos >= len(p.input) {
+		return "", fmt.Errorf("expected a %s at the end of the expression", what)
+	}
+	if q := p.input[p.pos]; q == '\'' || q == '"' {
+		end := strings.IndexByte(p.input[p.pos+1:], q)
+		if end < 0 {
+			return "", fmt.Errorf("unterminated quote at position %d", p.pos+1)
+		}
+		w := p.input[p.pos+1 : p.pos+1+end]
+		p.pos += end + 2
+		return w, nil
+	}
+	start := p.pos
+	for p.pos < len(p.input) && !strings.ContainsRune("=!<>&|()'\"", rune(p.input[p.pos])) && !unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+	if p.pos == start {
+		return "", fmt.Errorf("expected a %s at position %d", what, p.pos+1)
+	}
+	return p.input[start:p.pos], nil
+}
+
+// consume skips any whitespace and then the token, if it's next.
+func (p *filterParser) consume(token string) bool {
+	p.skipSpace()
+	if strings.HasPrefix(p.input[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) skipSpace() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
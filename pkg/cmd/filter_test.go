@@ -0,0 +1,117 @@
+package cmd_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/cli/pkg/text"
+)
+
+type filterFixture struct {
+	Name      string
+	Address   string
+	Port      int
+	ServiceID string
+	Shield    *filterShield
+}
+
+type filterShield struct {
+	Name string
+}
+
+var filterFixtures = []filterFixture{
+	{Name: "www", Address: "www.example.com", Port: 443, ServiceID: "123", Shield: &filterShield{Name: "london"}},
+	{Name: "api", Address: "api.example.com", Port: 8443, ServiceID: "123"},
+	{Name: "legacy", Address: "10.0.0.1", Port: 80, ServiceID: "456"},
+}
+
+func TestSelectFields(t *testing.T) {
+	for _, testcase := range []struct {
+		name       string
+		filter     string
+		fields     string
+		wantOutput string
+		wantError  string
+	}{
+		{
+			name:       "fields",
+			fields:     "name, port",
+			wantOutput: "Name,Port\nwww,443\napi,8443\nlegacy,80\n",
+		},
+		{
+			name:       "normalized and nested fields",
+			fields:     "service_id,shield.name",
+			wantOutput: "ServiceID,shield.name\n123,london\n123,\n456,\n",
+		},
+		{
+			name:       "numeric comparison",
+			filter:     "port==443",
+			fields:     "name",
+			wantOutput: "Name\nwww\n",
+		},
+		{
+			name:       "numeric ordering",
+			filter:     "port>=443 && port<8443",
+			fields:     "name",
+			wantOutput: "Name\nwww\n",
+		},
+		{
+			name:       "logical or and regular expression",
+			filter:     `name=~"^(www|leg)" || (service_id!=123 && port<100)`,
+			fields:     "name",
+			wantOutput: "Name\nwww\nlegacy\n",
+		},
+		{
+			name:       "negated regular expression",
+			filter:     `address!~example\.com$`,
+			fields:     "address",
+			wantOutput: "Address\n10.0.0.1\n",
+		},
+		{
+			name:       "quoted value",
+			filter:     "address=='api.example.com'",
+			fields:     "name",
+			wantOutput: "Name\napi\n",
+		},
+		{
+			name:      "unknown field",
+			fields:    "name,hostname",
+			wantError: "error parsing --fields: unknown field 'hostname'",
+		},
+		{
+			name:      "unknown filter field",
+			filter:    "hostname==www",
+			wantError: "error parsing --filter: unknown field 'hostname'",
+		},
+		{
+			name:      "missing operator",
+			filter:    "port 443",
+			wantError: "error parsing --filter: expected an operator",
+		},
+		{
+			name:      "missing value",
+			filter:    "port==",
+			wantError: "error parsing --filter: expected a value",
+		},
+		{
+			name:      "unbalanced parentheses",
+			filter:    "(port==443",
+			wantError: "error parsing --filter: missing ')'",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			v, err := cmd.SelectFields(filterFixtures, testcase.filter, testcase.fields)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			if err != nil {
+				return
+			}
+			var buf bytes.Buffer
+			if err := text.Render(&buf, text.FormatCSV, v); err != nil {
+				t.Fatal(err)
+			}
+			testutil.AssertString(t, testcase.wantOutput, buf.String())
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -325,10 +324,23 @@ func IsCompletion(args []string) bool {
 	return found
 }
 
+// StructuredOutput is implemented by commands whose output can be rendered
+// in any of the formats accepted by the global --format flag (see app.Run).
+type StructuredOutput interface {
+	SetFormat(format string)
+}
+
 // JSONOutput is a helper for adding a `--json` flag and encoding
 // values to JSON. It can be embedded into command structs.
+//
+// It also implements StructuredOutput, so values are encoded in the format
+// selected by the global --format flag, and list commands can register the
+// --fields and --filter flags to select what is output.
 type JSONOutput struct {
-	Enabled bool // Set via flag.
+	Enabled bool   // Set via flag.
+	Fields  string // Set via flag.
+	Filter  string // Set via flag.
+	Format  string // Set via the global --format flag.
 }
 
 // JSONFlag creates a flag for enabling JSON output.
@@ -341,14 +353,63 @@ func (j *JSONOutput) JSONFlag() BoolFlagOpts {
 	}
 }
 
+// FieldsFlag creates a flag for selecting the fields of each listed item.
+func (j *JSONOutput) FieldsFlag() StringFlagOpts {
+	return StringFlagOpts{
+		Name:        FlagFieldsName,
+		Description: FlagFieldsDesc,
+		Dst:         &j.Fields,
+	}
+}
+
+// FilterFlag creates a flag for filtering the listed items.
+func (j *JSONOutput) FilterFlag() StringFlagOpts {
+	return StringFlagOpts{
+		Name:        FlagFilterName,
+		Description: FlagFilterDesc,
+		Dst:         &j.Filter,
+	}
+}
+
+// SetFormat implements StructuredOutput. Any format other than the default
+// table format enables structured output.
+func (j *JSONOutput) SetFormat(format string) {
+	j.Format = format
+	if format != text.FormatTable {
+		j.Enabled = true
+	}
+}
+
 // WriteJSON checks whether the enabled flag is set or not. If set,
-// then the given value is written as JSON to out. Otherwise, false is returned.
+// then the given value is written to out in the selected format (JSON unless
+// the global --format flag says otherwise). Otherwise, false is returned.
+//
+// When --fields or --filter is set the value is always written (see
+// SelectFields), as a table unless structured output is enabled.
 func (j *JSONOutput) WriteJSON(out io.Writer, value any) (bool, error) {
-	if !j.Enabled {
+	selected := j.Fields != "" || j.Filter != ""
+	if !j.Enabled && !selected {
 		return false, nil
 	}
 
-	enc := json.NewEncoder(out)
-	enc.SetIndent("", "  ")
-	return true, enc.Encode(value)
+	if selected {
+		var err error
+		if value, err = SelectFields(value, j.Filter, j.Fields); err != nil {
+			return true, err
+		}
+	}
+	return true, text.Render(out, j.outputFormat(), value)
+}
+
+// outputFormat returns the format values are written in: the generic table
+// unless structured output is enabled, in which case JSON unless the global
+// --format flag says otherwise.
+func (j *JSONOutput) outputFormat() string {
+	if !j.Enabled {
+		return text.FormatTable
+	}
+	if j.Format != "" && j.Format != text.FormatTable {
+		return j.Format
+	}
+	return text.FormatJSON
 }
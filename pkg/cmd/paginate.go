@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"io"
+	"sync"
+
+	"github.com/fastly/cli/pkg/text"
+)
+
+const (
+	// MaxConcurrentPages is the number of pages FetchPages requests
+	// concurrently.
+	MaxConcurrentPages = 4
+	// MaxPerPage is the number of results requested per page when streaming
+	// (the maximum allowed by the paginated API endpoints).
+	MaxPerPage = 100
+)
+
+// Pagination is a helper for adding the `--all` and `--limit` flags to list
+// commands whose results are paginated. It can be embedded into command
+// structs.
+type Pagination struct {
+	All   bool // Set via flag.
+	Limit int  // Set via flag.
+}
+
+// AllFlag creates a flag for streaming every page of results.
+func (p *Pagination) AllFlag() BoolFlagOpts {
+	return BoolFlagOpts{
+		Name:        "all",
+		Description: "Fetch every page of results, printing each page as it arrives",
+		Dst:         &p.All,
+	}
+}
+
+// LimitFlag creates a flag for capping the number of streamed results.
+func (p *Pagination) LimitFlag() IntFlagOpts {
+	return IntFlagOpts{
+		Name:        "limit",
+		Description: "Maximum number of results to print (implies --all)",
+		Dst:         &p.Limit,
+	}
+}
+
+// MaxResultsFlag creates a flag for capping the number of streamed results,
+// for commands whose --limit flag is already the page size (see the
+// package-level LimitFlag).
+func (p *Pagination) MaxResultsFlag() IntFlagOpts {
+	return IntFlagOpts{
+		Name:        "max-results",
+		Description: "Maximum number of results to print (implies --all)",
+		Dst:         &p.Limit,
+	}
+}
+
+// Streaming indicates if the results should be streamed a page at a time.
+func (p *Pagination) Streaming() bool {
+	return p.All || p.Limit > 0
+}
+
+// FetchPages fetches every page of a list, passing each page to emit, in
+// order, as soon as it has been fetched. At most limit items are emitted
+// (unless limit is zero).
+//
+// The first page is fetched with first, which also returns the number of
+// pages remaining after it. The remaining pages are then fetched with page
+// (numbered from 1), up to MaxConcurrentPages at a time. Any pages still being
+// fetched when an error occurs (or the limit is reached) are waited for before
+// returning.
+func FetchPages[T any](limit int, first func() ([]T, int, error), page func(n int) ([]T, error), emit func([]T) error) error {
+	send := limitedEmit(limit, emit)
+
+	items, remaining, err := first()
+	if err != nil {
+		return err
+	}
+	if done, err := send(items); done || err != nil {
+		return err
+	}
+
+	type result struct {
+		items []T
+		err   error
+	}
+	results := make([]chan result, remaining)
+	for i := range results {
+		results[i] = make(chan result, 1)
+	}
+
+	// The semaphore bounds the number of pages that are being fetched (or have
+	// been fetched but not yet emitted).
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, MaxConcurrentPages)
+	stop := make(chan struct{})
+	defer func() {
+		close(stop)
+		wg.Wait()
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := range results {
+			select {
+			case sem <- struct{}{}:
+			case <-stop:
+				return
+			}
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				items, err := page(i + 1)
+				results[i] <- result{items, err}
+			}(i)
+		}
+	}()
+
+	for i := range results {
+		r := <-results[i]
+		<-sem
+		if r.err != nil {
+			return r.err
+		}
+		if done, err := send(r.items); done || err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FetchNumberedPages fetches every page of a list whose number of pages isn't
+// known up front, passing each page to emit as soon as it has been fetched.
+// At most limit items are emitted (unless limit is zero).
+//
+// The pages are fetched with page (numbered from 0) one at a time, until a
+// page has fewer than perPage items.
+func FetchNumberedPages[T any](limit, perPage int, page func(n int) ([]T, error), emit func([]T) error) error {
+	send := limitedEmit(limit, emit)
+	for n := 0; ; n++ {
+		items, err := page(n)
+		if err != nil {
+			return err
+		}
+		if done, err := send(items); done || err != nil {
+			return err
+		}
+		if len(items) < perPage {
+			return nil
+		}
+	}
+}
+
+// FetchCursorPages fetches every page of a list identified by a cursor,
+// passing each page to emit as soon as it has been fetched. At most limit
+// items are emitted (unless limit is zero).
+//
+// The pages are fetched with page one at a time (as each page's cursor is
+// returned with the previous page), starting from cursor, until no next
+// cursor is returned.
+func FetchCursorPages[T any](limit int, cursor string, page func(cursor string) ([]T, string, error), emit func([]T) error) error {
+	send := limitedEmit(limit, emit)
+	for {
+		items, next, err := page(cursor)
+		if err != nil {
+			return err
+		}
+		if done, err := send(items); done || err != nil || next == "" {
+			return err
+		}
+		cursor = next
+	}
+}
+
+// limitedEmit returns a function that passes items to emit until limit items
+// have been emitted (unless limit is zero), reporting when that happens.
+func limitedEmit[T any](limit int, emit func([]T) error) func([]T) (bool, error) {
+	var count int
+	return func(items []T) (bool, error) {
+		done := limit > 0 && count+len(items) >= limit
+		if done {
+			items = items[:limit-count]
+		}
+		count += len(items)
+		return done, emit(items)
+	}
+}
+
+// PageWriter writes a list a page at a time, honouring the --json, --format,
+// --fields and --filter flags.
+//
+// NOTE: The default text output is buffered until every page has been
+// fetched, so the columns of a table line up across pages. The other formats
+// are written as each page arrives.
+type PageWriter[T any] struct {
+	fields   string
+	filter   string
+	items    []T
+	print    func(items []T)
+	renderer *text.ListRenderer // nil when printing the default text output.
+}
+
+// NewPageWriter returns a PageWriter that writes to out.
+//
+// The print function writes the items in the command's default text format.
+func NewPageWriter[T any](out io.Writer, j *JSONOutput, print func(items []T)) *PageWriter[T] {
+	w := &PageWriter[T]{
+		fields: j.Fields,
+		filter: j.Filter,
+		print:  print,
+	}
+	if j.Enabled || j.Fields != "" || j.Filter != "" {
+		w.renderer = text.NewListRenderer(out, j.outputFormat())
+	}
+	return w
+}
+
+// Write writes a page of items.
+func (w *PageWriter[T]) Write(items []T) error {
+	if w.renderer == nil {
+		w.items = append(w.items, items...)
+		return nil
+	}
+
+	var v any = items
+	if w.fields != "" || w.filter != "" {
+		var err error
+		if v, err = SelectFields(items, w.filter, w.fields); err != nil {
+			return err
+		}
+	}
+	return w.renderer.Render(v)
+}
+
+// Close completes the output once every page has been written.
+func (w *PageWriter[T]) Close() error {
+	if w.renderer == nil {
+		w.print(w.items)
+		return nil
+	}
+	return w.renderer.Close()
+}
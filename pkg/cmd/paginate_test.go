@@ -0,0 +1,165 @@
+package cmd_test
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestFetchPages(t *testing.T) {
+	const pages = 10
+
+	for _, testcase := range []struct {
+		name      string
+		limit     int
+		failPage  int
+		wantItems []int
+		wantError string
+	}{
+		{
+			name:      "every page in order",
+			wantItems: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19},
+		},
+		{
+			name:      "limit",
+			limit:     5,
+			wantItems: []int{0, 1, 2, 3, 4},
+		},
+		{
+			name:      "limit on a page boundary",
+			limit:     4,
+			wantItems: []int{0, 1, 2, 3},
+		},
+		{
+			name:      "error",
+			failPage:  3,
+			wantItems: []int{0, 1, 2, 3, 4, 5},
+			wantError: "page 3 failed",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			var inflight, maxInflight int32
+
+			// Each page has two items, with later pages returning sooner.
+			first := func() ([]int, int, error) {
+				return []int{0, 1}, pages - 1, nil
+			}
+			page := func(n int) ([]int, error) {
+				cur := atomic.AddInt32(&inflight, 1)
+				defer atomic.AddInt32(&inflight, -1)
+				for {
+					m := atomic.LoadInt32(&maxInflight)
+					if cur <= m || atomic.CompareAndSwapInt32(&maxInflight, m, cur) {
+						break
+					}
+				}
+				time.Sleep(time.Duration(pages-n) * time.Millisecond)
+				if n == testcase.failPage {
+					return nil, errors.New("page 3 failed")
+				}
+				return []int{n * 2, n*2 + 1}, nil
+			}
+
+			var items []int
+			err := cmd.FetchPages(testcase.limit, first, page, func(page []int) error {
+				items = append(items, page...)
+				return nil
+			})
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			if diff := cmp.Diff(testcase.wantItems, items); diff != "" {
+				t.Fatalf("unexpected items (-want +got):\n%s", diff)
+			}
+			if m := atomic.LoadInt32(&maxInflight); m > cmd.MaxConcurrentPages {
+				t.Fatalf("fetched %d pages concurrently, want at most %d", m, cmd.MaxConcurrentPages)
+			}
+		})
+	}
+}
+
+func TestFetchNumberedPages(t *testing.T) {
+	// Three pages of two items, and a short last page.
+	page := func(n int) ([]int, error) {
+		if n == 3 {
+			return []int{6}, nil
+		}
+		return []int{n * 2, n*2 + 1}, nil
+	}
+
+	for _, testcase := range []struct {
+		name      string
+		limit     int
+		wantItems []int
+	}{
+		{
+			name:      "every page until a short page",
+			wantItems: []int{0, 1, 2, 3, 4, 5, 6},
+		},
+		{
+			name:      "limit",
+			limit:     3,
+			wantItems: []int{0, 1, 2},
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			var items []int
+			err := cmd.FetchNumberedPages(testcase.limit, 2, page, func(page []int) error {
+				items = append(items, page...)
+				return nil
+			})
+			testutil.AssertNoError(t, err)
+			if diff := cmp.Diff(testcase.wantItems, items); diff != "" {
+				t.Fatalf("unexpected items (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFetchCursorPages(t *testing.T) {
+	cursors := map[string]string{"": "a", "a": "b", "b": ""}
+	var fetched []string
+	page := func(cursor string) ([]string, string, error) {
+		fetched = append(fetched, cursor)
+		return []string{cursor + "1", cursor + "2"}, cursors[cursor], nil
+	}
+
+	for _, testcase := range []struct {
+		name        string
+		limit       int
+		wantItems   []string
+		wantFetched []string
+	}{
+		{
+			name:        "every page until there's no cursor",
+			wantItems:   []string{"1", "2", "a1", "a2", "b1", "b2"},
+			wantFetched: []string{"", "a", "b"},
+		},
+		{
+			name:        "limit",
+			limit:       3,
+			wantItems:   []string{"1", "2", "a1"},
+			wantFetched: []string{"", "a"},
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			fetched = nil
+			var items []string
+			err := cmd.FetchCursorPages(testcase.limit, "", page, func(page []string) error {
+				items = append(items, page...)
+				return nil
+			})
+			testutil.AssertNoError(t, err)
+			if diff := cmp.Diff(testcase.wantItems, items); diff != "" {
+				t.Fatalf("unexpected items (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(testcase.wantFetched, fetched); diff != "" {
+				t.Fatalf("unexpected cursors fetched (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,327 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/fastly/go-fastly/v8/fastly"
+
+	"github.com/fastly/cli/pkg/api"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// MaxConcurrentServices is the number of services ForEachService runs against
+// concurrently.
+const MaxConcurrentServices = 8
+
+// ServiceSelector is a helper for adding the `--services` flag to commands
+// that can be run against many services at once. It can be embedded into
+// command structs.
+type ServiceSelector struct {
+	Services string // Set via flag.
+}
+
+// ServicesFlag creates a flag for selecting multiple services.
+func (s *ServiceSelector) ServicesFlag() StringFlagOpts {
+	return StringFlagOpts{
+		Name:        FlagServicesName,
+		Description: FlagServicesDesc,
+		Dst:         &s.Services,
+	}
+}
+
+// SelectingServices indicates if the command should be run against the
+// services matching the --services selector.
+func (s *ServiceSelector) SelectingServices() bool {
+	return s.Services != ""
+}
+
+// SelectedService is a service matched by a --services selector.
+type SelectedService struct {
+	ID   string
+	Name string
+}
+
+// String returns the name and ID of the service.
+func (s SelectedService) String() string {
+	if s.Name == "" {
+		return s.ID
+	}
+	return fmt.Sprintf("%s (%s)", s.Name, s.ID)
+}
+
+// SelectServices returns the services matching the --services selector, in the
+// order they're listed by the API (followed by any IDs read from a file).
+//
+// The selector is a comma separated list of terms, with a service selected if
+// it matches any of them:
+//
+//	shop-*          a glob matched against the service name (name:shop-* also works)
+//	tag:production  services whose comment contains the tag #production
+//	file:ids.txt    the service IDs in the file, one per line (@ids.txt also works)
+//
+// Blank lines and lines starting with # are ignored in a file of IDs.
+func SelectServices(selector string, client api.Interface) ([]SelectedService, error) {
+	var (
+		globs, tags []string
+		ids         []string
+	)
+	for _, term := range strings.Split(selector, ",") {
+		term = strings.TrimSpace(term)
+		switch {
+		case term == "":
+			continue
+		case strings.HasPrefix(term, "tag:"):
+			tags = append(tags, strings.TrimPrefix(strings.TrimPrefix(term, "tag:"), "#"))
+		case strings.HasPrefix(term, "file:"), strings.HasPrefix(term, "@"):
+			l, err := readServiceIDs(strings.TrimPrefix(strings.TrimPrefix(term, "file:"), "@"))
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, l...)
+		default:
+			glob := strings.TrimPrefix(term, "name:")
+			if _, err := path.Match(glob, ""); err != nil {
+				return nil, fsterr.RemediationError{
+					Inner:       fmt.Errorf("error parsing --%s: invalid glob '%s': %w", FlagServicesName, glob, err),
+					Remediation: FlagServicesRemediation,
+				}
+			}
+			globs = append(globs, glob)
+		}
+	}
+	if len(globs) == 0 && len(tags) == 0 && len(ids) == 0 {
+		return nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("error parsing --%s: no services selected", FlagServicesName),
+			Remediation: FlagServicesRemediation,
+		}
+	}
+
+	var selected []SelectedService
+	seen := make(map[string]bool)
+	add := func(s SelectedService) {
+		if !seen[s.ID] {
+			seen[s.ID] = true
+			selected = append(selected, s)
+		}
+	}
+
+	if len(globs) > 0 || len(tags) > 0 {
+		names := make(map[string]string)
+		paginator := client.NewListServicesPaginator(&fastly.ListServicesInput{})
+		for paginator.HasNext() {
+			data, err := paginator.GetNext()
+			if err != nil {
+				return nil, fmt.Errorf("error listing services: %w", err)
+			}
+			for _, s := range data {
+				names[s.ID] = s.Name
+				if matchService(s, globs, tags) {
+					add(SelectedService{ID: s.ID, Name: s.Name})
+				}
+			}
+		}
+		for _, id := range ids {
+			add(SelectedService{ID: id, Name: names[id]})
+		}
+	} else {
+		for _, id := range ids {
+			add(SelectedService{ID: id})
+		}
+	}
+
+	if len(selected) == 0 {
+		return nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("no services match --%s '%s'", FlagServicesName, selector),
+			Remediation: "Run `fastly service list` to see the available services.",
+		}
+	}
+	return selected, nil
+}
+
+// matchService reports whether the service name matches any of the globs or
+// its comment contains any of the tags.
+func matchService(s *fastly.Service, globs, tags []string) bool {
+	for _, glob := range globs {
+		if ok, _ := path.Match(glob, s.Name); ok {
+			return true
+		}
+	}
+	words := strings.FieldsFunc(s.Comment, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("#-_", r)
+	})
+	for _, word := range words {
+		for _, tag := range tags {
+			if strings.EqualFold(word, "#"+tag) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// readServiceIDs reads a file of service IDs.
+func readServiceIDs(fpath string) ([]string, error) {
+	path, err := filepath.Abs(fpath)
+	if err != nil {
+		return nil, err
+	}
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as we trust the source of the fpath variable.
+	/* #nosec */
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("error reading service IDs: %w", err),
+			Remediation: "Check the file exists and contains one service ID per line.",
+		}
+	}
+	defer f.Close() // #nosec G307
+
+	var ids []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ids = append(ids, line)
+	}
+	return ids, scanner.Err()
+}
+
+// ServiceResult is the outcome of running a command against a service.
+type ServiceResult struct {
+	Service SelectedService
+	Err     error
+}
+
+// ForEachService runs fn against each of the services, up to
+// MaxConcurrentServices at a time, without stopping if any of them fail.
+//
+// The output of each service is buffered and written to out (under a heading
+// naming the service) in the order the services were given, so the output of
+// concurrent services isn't interleaved.
+func ForEachService(out io.Writer, services []SelectedService, fn func(s SelectedService, out io.Writer) error) []ServiceResult {
+	type result struct {
+		buf bytes.Buffer
+		err error
+	}
+	results := make([]chan *result, len(services))
+	for i := range results {
+		results[i] = make(chan *result, 1)
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, MaxConcurrentServices)
+	for i, s := range services {
+		wg.Add(1)
+		go func(i int, s SelectedService) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() {
+				<-sem
+			}()
+			r := &result{}
+			r.err = fn(s, &r.buf)
+			results[i] <- r
+		}(i, s)
+	}
+
+	summary := make([]ServiceResult, len(services))
+	for i, s := range services {
+		r := <-results[i]
+		if r.err != nil {
+			text.Error(&r.buf, "%s", r.err)
+		}
+		// Separate the output of each service with a blank line.
+		if !bytes.HasSuffix(r.buf.Bytes(), []byte("\n\n")) {
+			text.Break(&r.buf)
+		}
+		text.Output(out, "%s", text.Bold(fmt.Sprintf("Service: %s", s)))
+		_, _ = r.buf.WriteTo(out)
+		summary[i] = ServiceResult{Service: s, Err: r.err}
+	}
+	wg.Wait()
+	return summary
+}
+
+// RunServices runs fn against each of the services (with the index of the
+// service), up to MaxConcurrentServices at a time, without stopping if any of
+// them fail. It's an alternative to ForEachService for commands whose output
+// is combined once every service has completed.
+func RunServices(services []SelectedService, fn func(i int, s SelectedService) error) []ServiceResult {
+	results := make([]ServiceResult, len(services))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, MaxConcurrentServices)
+	for i, s := range services {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, s SelectedService) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i] = ServiceResult{Service: s, Err: fn(i, s)}
+		}(i, s)
+	}
+	wg.Wait()
+	return results
+}
+
+// PrintServiceSummary writes the result of running a command against each
+// service, returning an error if it failed for any of them.
+func PrintServiceSummary(out io.Writer, results []ServiceResult) error {
+	t := text.NewTable(out)
+	t.AddHeader("SERVICE ID", "NAME", "RESULT")
+	for _, r := range results {
+		result := "ok"
+		if r.Err != nil {
+			result = "failed: " + firstLine(r.Err)
+		}
+		t.AddLine(r.Service.ID, r.Service.Name, result)
+	}
+	t.Print()
+	return servicesError(results, false)
+}
+
+// ServicesError returns an error describing the services that failed (or nil
+// if none of them did), for commands that don't print a summary.
+func ServicesError(results []ServiceResult) error {
+	return servicesError(results, true)
+}
+
+func servicesError(results []ServiceResult, detail bool) error {
+	var failed []string
+	for _, r := range results {
+		if r.Err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %s", r.Service, firstLine(r.Err)))
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	err := fmt.Errorf("%d of %d services failed", len(failed), len(results))
+	if detail {
+		err = fmt.Errorf("%w (%s)", err, strings.Join(failed, "; "))
+	}
+	return fsterr.RemediationError{
+		Inner:       err,
+		Remediation: "Check the errors reported for each service and rerun the command for the services that failed.",
+	}
+}
+
+// firstLine returns the first line of the error message.
+func firstLine(err error) string {
+	return strings.SplitN(err.Error(), "\n", 2)[0]
+}
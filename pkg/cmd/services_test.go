@@ -0,0 +1,152 @@
+package cmd_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fastly/go-fastly/v8/fastly"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestSelectServices(t *testing.T) {
+	dir := t.TempDir()
+	ids := filepath.Join(dir, "ids.txt")
+	if err := os.WriteFile(ids, []byte("# production\nsvc-3\n\nsvc-9\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, testcase := range []struct {
+		name      string
+		selector  string
+		wantIDs   []string
+		wantError string
+	}{
+		{
+			name:     "name glob",
+			selector: "shop-*",
+			wantIDs:  []string{"svc-1", "svc-2"},
+		},
+		{
+			name:     "tag",
+			selector: "tag:production",
+			wantIDs:  []string{"svc-1", "svc-3"},
+		},
+		{
+			name:     "file of IDs",
+			selector: "file:" + ids,
+			wantIDs:  []string{"svc-3", "svc-9"},
+		},
+		{
+			name:     "union without duplicates",
+			selector: "tag:production, name:shop-eu, @" + ids,
+			wantIDs:  []string{"svc-1", "svc-2", "svc-3", "svc-9"},
+		},
+		{
+			name:      "no matches",
+			selector:  "blog-*",
+			wantError: "no services match --services 'blog-*'",
+		},
+		{
+			name:      "invalid glob",
+			selector:  "shop-[",
+			wantError: "invalid glob 'shop-['",
+		},
+		{
+			name:      "missing file",
+			selector:  "file:" + filepath.Join(dir, "missing.txt"),
+			wantError: "error reading service IDs",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			client := mock.API{
+				NewListServicesPaginatorFn: func(i *fastly.ListServicesInput) fastly.PaginatorServices {
+					return &servicesPaginator{pages: [][]*fastly.Service{
+						{
+							{ID: "svc-1", Name: "shop-us", Comment: "Storefront #production"},
+							{ID: "svc-2", Name: "shop-eu", Comment: "#staging"},
+						},
+						{
+							{ID: "svc-3", Name: "api", Comment: "Public API (#Production)."},
+						},
+					}}
+				},
+			}
+			services, err := cmd.SelectServices(testcase.selector, client)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			var got []string
+			for _, s := range services {
+				got = append(got, s.ID)
+			}
+			if diff := cmp.Diff(testcase.wantIDs, got); diff != "" {
+				t.Fatalf("unexpected services (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestForEachService(t *testing.T) {
+	var services []cmd.SelectedService
+	for i := 0; i < cmd.MaxConcurrentServices*2; i++ {
+		services = append(services, cmd.SelectedService{ID: fmt.Sprintf("svc-%d", i), Name: fmt.Sprintf("site-%d", i)})
+	}
+
+	var buf bytes.Buffer
+	results := cmd.ForEachService(&buf, services, func(s cmd.SelectedService, out io.Writer) error {
+		if s.ID == "svc-3" {
+			return errors.New("purge failed\nwith detail")
+		}
+		fmt.Fprintf(out, "purged %s\n", s.ID)
+		return nil
+	})
+	out := buf.String()
+
+	// The output of each service is written in order, under a heading.
+	last := -1
+	for _, s := range services {
+		i := strings.Index(out, fmt.Sprintf("Service: %s (%s)\n", s.Name, s.ID))
+		if i <= last {
+			t.Fatalf("output of %s is out of order:\n%s", s.ID, out)
+		}
+		last = i
+	}
+	testutil.AssertStringContains(t, out, "purged svc-4\n")
+	testutil.AssertStringContains(t, out, "ERROR: purge failed")
+
+	buf.Reset()
+	err := cmd.PrintServiceSummary(&buf, results)
+	testutil.AssertErrorContains(t, err, "1 of 16 services failed")
+	testutil.AssertStringContains(t, buf.String(), "svc-3       site-3   failed: purge failed\n")
+	testutil.AssertStringContains(t, buf.String(), "svc-4       site-4   ok\n")
+
+	err = cmd.ServicesError(results)
+	testutil.AssertErrorContains(t, err, "1 of 16 services failed (site-3 (svc-3): purge failed)")
+}
+
+// servicesPaginator returns the given pages of services.
+type servicesPaginator struct {
+	pages [][]*fastly.Service
+}
+
+func (p *servicesPaginator) HasNext() bool {
+	return len(p.pages) > 0
+}
+
+func (p *servicesPaginator) Remaining() int {
+	return len(p.pages)
+}
+
+func (p *servicesPaginator) GetNext() ([]*fastly.Service, error) {
+	page := p.pages[0]
+	p.pages = p.pages[1:]
+	return page, nil
+}
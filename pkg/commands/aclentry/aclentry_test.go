@@ -251,6 +251,23 @@ func (p *mockACLPaginator) GetNext() (as []*fastly.ACLEntry, err error) {
 	return as, err
 }
 
+// listACLEntriesPage returns the entry for the requested page (only page 2
+// is expected, as the first page is fetched using mockACLPaginator).
+func listACLEntriesPage(i *fastly.ListACLEntriesInput) ([]*fastly.ACLEntry, error) {
+	if i.Page != 2 {
+		return nil, testutil.Err
+	}
+	return []*fastly.ACLEntry{
+		{
+			ACLID:     "123",
+			ID:        "789",
+			IP:        "127.0.0.2",
+			Negated:   true,
+			ServiceID: "123",
+		},
+	}, nil
+}
+
 func TestACLEntryList(t *testing.T) {
 	args := testutil.Args
 	scenarios := []testutil.TestScenario{
@@ -310,6 +327,28 @@ func TestACLEntryList(t *testing.T) {
 			Args:       args("acl-entry list --acl-id 123 --page 2 --per-page 1 --service-id 123"),
 			WantOutput: listACLEntriesOutputPageTwo,
 		},
+		{
+			Name: "validate --all fetches the remaining pages",
+			API: mock.API{
+				NewListACLEntriesPaginatorFn: func(i *fastly.ListACLEntriesInput) fastly.PaginatorACLEntries {
+					return &mockACLPaginator{numOfPages: i.PerPage, maxPages: 2}
+				},
+				ListACLEntriesFn: listACLEntriesPage,
+			},
+			Args:       args("acl-entry list --acl-id 123 --all --fields id,ip --format csv --service-id 123"),
+			WantOutput: "ID,IP\n456,127.0.0.1\n789,127.0.0.2\n",
+		},
+		{
+			Name: "validate --limit stops after the given number of entries",
+			API: mock.API{
+				NewListACLEntriesPaginatorFn: func(i *fastly.ListACLEntriesInput) fastly.PaginatorACLEntries {
+					return &mockACLPaginator{numOfPages: i.PerPage, maxPages: 2}
+				},
+				ListACLEntriesFn: listACLEntriesPage,
+			},
+			Args:       args("acl-entry list --acl-id 123 --limit 1 --service-id 123"),
+			WantOutput: "SERVICE ID  ID   IP         SUBNET  NEGATED\n123         456  127.0.0.1  0       false\n",
+		},
 		{
 			Name: "validate --verbose flag",
 			API: mock.API{
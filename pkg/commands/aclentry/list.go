@@ -26,6 +26,9 @@ func NewListCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Lis
 	c.CmdClause.Flag("acl-id", "Alphanumeric string identifying a ACL").Required().StringVar(&c.aclID)
 
 	// Optional.
+	c.RegisterFlagBool(c.AllFlag())  // --all
+	c.RegisterFlag(c.FieldsFlag())   // --fields
+	c.RegisterFlag(c.FilterFlag())   // --filter
 	c.RegisterFlagBool(c.JSONFlag()) // --json
 	c.RegisterFlag(cmd.StringFlagOpts{
 		Name:        cmd.FlagServiceIDName,
@@ -41,6 +44,7 @@ func NewListCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Lis
 	})
 
 	c.CmdClause.Flag("direction", "Direction in which to sort results").Default(cmd.PaginationDirection[0]).HintOptions(cmd.PaginationDirection...).EnumVar(&c.direction, cmd.PaginationDirection...)
+	c.RegisterFlagInt(c.LimitFlag()) // --limit
 	c.CmdClause.Flag("page", "Page number of data set to fetch").IntVar(&c.page)
 	c.CmdClause.Flag("per-page", "Number of records per page").IntVar(&c.perPage)
 	c.CmdClause.Flag("sort", "Field on which to sort").Default("created").StringVar(&c.sort)
@@ -52,6 +56,7 @@ func NewListCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Lis
 type ListCommand struct {
 	cmd.Base
 	cmd.JSONOutput
+	cmd.Pagination
 
 	aclID       string
 	direction   string
@@ -77,6 +82,9 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 	}
 
 	input := c.constructInput(serviceID)
+	if c.Streaming() {
+		return c.stream(out, input)
+	}
 	paginator := c.Globals.APIClient.NewListACLEntriesPaginator(input)
 
 	// TODO: Use generics support in go 1.18 to replace this almost identical
@@ -110,6 +118,51 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 	return nil
 }
 
+// stream prints the entries a page at a time as they're fetched, fetching
+// the pages after the first concurrently.
+func (c *ListCommand) stream(out io.Writer, input *fastly.ListACLEntriesInput) error {
+	if input.PerPage <= 0 {
+		input.PerPage = cmd.MaxPerPage
+	}
+	start := input.Page
+	if start <= 0 {
+		start = 1
+	}
+
+	w := cmd.NewPageWriter(out, &c.JSONOutput, func(as []*fastly.ACLEntry) {
+		if c.Globals.Verbose() {
+			c.printVerbose(out, as)
+			return
+		}
+		_ = c.printSummary(out, as)
+	})
+
+	first := func() ([]*fastly.ACLEntry, int, error) {
+		var (
+			as        []*fastly.ACLEntry
+			err       error
+			paginator = c.Globals.APIClient.NewListACLEntriesPaginator(input)
+		)
+		if paginator.HasNext() {
+			as, err = paginator.GetNext()
+		}
+		return as, paginator.Remaining(), err
+	}
+	page := func(n int) ([]*fastly.ACLEntry, error) {
+		i := *input
+		i.Page = start + n
+		return c.Globals.APIClient.ListACLEntries(&i)
+	}
+	if err := cmd.FetchPages(c.Limit, first, page, w.Write); err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"ACL ID":     c.aclID,
+			"Service ID": input.ServiceID,
+		})
+		return err
+	}
+	return w.Close()
+}
+
 // constructInput transforms values parsed from CLI flags into an object to be used by the API client library.
 func (c *ListCommand) constructInput(serviceID string) *fastly.ListACLEntriesInput {
 	var input fastly.ListACLEntriesInput
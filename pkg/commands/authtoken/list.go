@@ -30,6 +30,8 @@ func NewListCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Lis
 		Dst:         &c.customerID.Value,
 		Action:      c.customerID.Set,
 	})
+	c.RegisterFlag(c.FieldsFlag())   // --fields
+	c.RegisterFlag(c.FilterFlag())   // --filter
 	c.RegisterFlagBool(c.JSONFlag()) // --json
 	return &c
 }
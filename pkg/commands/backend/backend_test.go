@@ -150,6 +150,38 @@ func TestBackendList(t *testing.T) {
 			},
 			WantOutput: listBackendsJSONOutput,
 		},
+		{
+			Args: args("backend list --service-id 123 --version 1 --format json"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				ListBackendsFn: listBackendsOK,
+			},
+			WantOutput: listBackendsJSONOutput,
+		},
+		{
+			Args: args("backend list --service-id 123 --version 1 --fields name,port --filter port==443"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				ListBackendsFn: listBackendsOK,
+			},
+			WantOutput: "Name         Port\nexample.com  443\n",
+		},
+		{
+			Args: args("backend list --service-id 123 --version 1 --fields name,address --format csv"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				ListBackendsFn: listBackendsOK,
+			},
+			WantOutput: "Name,Address\ntest.com,www.test.com\nexample.com,www.example.com\n",
+		},
+		{
+			Args: args("backend list --service-id 123 --version 1 --format yaml --verbose"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				ListBackendsFn: listBackendsOK,
+			},
+			WantError: fsterr.ErrInvalidVerboseJSONCombo.Error(),
+		},
 		{
 			Args: args("backend list --service-id 123 --version 1 --json --verbose"),
 			API: mock.API{
@@ -14,6 +14,7 @@ import (
 // UpdateCommand calls the Fastly API to update backends.
 type UpdateCommand struct {
 	cmd.Base
+	cmd.ServiceSelector
 	manifest       manifest.Data
 	serviceName    cmd.OptionalServiceNameID
 	serviceVersion cmd.OptionalServiceVersion
@@ -97,6 +98,7 @@ func NewUpdateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *U
 		Description: cmd.FlagServiceDesc,
 		Dst:         &c.serviceName.Value,
 	})
+	c.RegisterFlag(c.ServicesFlag()) // --services
 	c.CmdClause.Flag("shield", "The shield POP designated to reduce inbound load on this origin by serving the cached data to the rest of the network").Action(c.Shield.Set).StringVar(&c.Shield.Value)
 	c.CmdClause.Flag("ssl-ca-cert", "CA certificate attached to origin").Action(c.SSLCACert.Set).StringVar(&c.SSLCACert.Value)
 	c.CmdClause.Flag("ssl-cert-hostname", "Overrides ssl_hostname, but only for cert verification. Does not affect SNI at all.").Action(c.SSLCertHostname.Set).StringVar(&c.SSLCertHostname.Value)
@@ -112,12 +114,37 @@ func NewUpdateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *U
 
 // Exec invokes the application logic for the command.
 func (c *UpdateCommand) Exec(_ io.Reader, out io.Writer) error {
+	if !c.SelectingServices() {
+		return c.update(c.manifest, c.serviceName, out)
+	}
+	if c.manifest.Flag.ServiceID != "" || c.serviceName.WasSet {
+		return errors.ErrInvalidServicesCombo
+	}
+
+	services, err := cmd.SelectServices(c.Services, c.Globals.APIClient)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Services": c.Services,
+		})
+		return err
+	}
+	results := cmd.ForEachService(out, services, func(s cmd.SelectedService, out io.Writer) error {
+		m := c.manifest
+		m.Flag.ServiceID = s.ID
+		return c.update(m, cmd.OptionalServiceNameID{}, out)
+	})
+	return cmd.PrintServiceSummary(out, results)
+}
+
+// update updates the backend on the service identified by the manifest data or
+// service name.
+func (c *UpdateCommand) update(m manifest.Data, serviceName cmd.OptionalServiceNameID, out io.Writer) error {
 	serviceID, serviceVersion, err := cmd.ServiceDetails(cmd.ServiceDetailsOpts{
 		AutoCloneFlag:      c.autoClone,
 		APIClient:          c.Globals.APIClient,
-		Manifest:           c.manifest,
+		Manifest:           m,
 		Out:                out,
-		ServiceNameFlag:    c.serviceName,
+		ServiceNameFlag:    serviceName,
 		ServiceVersionFlag: c.serviceVersion,
 		VerboseMode:        c.Globals.Flags.Verbose,
 	})
@@ -0,0 +1,279 @@
+package cachesetting_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fastly/go-fastly/v8/fastly"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestCacheSettingCreate(t *testing.T) {
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name:      "validate --action is a known cache setting action",
+			Args:      args("cache-setting create --service-id 123 --version 1 --name images --action lookup"),
+			WantError: "error parsing arguments: enum value must be one of cache,pass,restart, got 'lookup'",
+		},
+		{
+			Name: "validate CreateCacheSetting API error",
+			Args: args("cache-setting create --service-id 123 --version 1 --name images --autoclone"),
+			API: mock.API{
+				ListVersionsFn:       testutil.ListVersions,
+				CloneVersionFn:       testutil.CloneVersionResult(4),
+				CreateCacheSettingFn: createCacheSettingError,
+			},
+			WantError: errTest.Error(),
+		},
+		{
+			Name: "validate the cache setting is created from the flags",
+			Args: args("cache-setting create --service-id 123 --version 1 --name images --autoclone --action cache --cache-condition is-image --stale-ttl 3600 --ttl 86400"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CloneVersionFn: testutil.CloneVersionResult(4),
+				CreateCacheSettingFn: createCacheSettingWith(fastly.CreateCacheSettingInput{
+					ServiceID:      "123",
+					ServiceVersion: 4,
+					Name:           fastly.String("images"),
+					Action:         fastly.CacheSettingActionPtr(fastly.CacheSettingActionCache),
+					CacheCondition: fastly.String("is-image"),
+					StaleTTL:       fastly.Int(3600),
+					TTL:            fastly.Int(86400),
+				}),
+			},
+			WantOutput: "Created cache setting images (service 123 version 4)",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+		})
+	}
+}
+
+func TestCacheSettingList(t *testing.T) {
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Args: args("cache-setting list --service-id 123 --version 1"),
+			API: mock.API{
+				ListVersionsFn:      testutil.ListVersions,
+				ListCacheSettingsFn: listCacheSettingsOK,
+			},
+			WantOutput: listCacheSettingsShortOutput,
+		},
+		{
+			Args: args("cache-setting list --service-id 123 --version 1 --verbose"),
+			API: mock.API{
+				ListVersionsFn:      testutil.ListVersions,
+				ListCacheSettingsFn: listCacheSettingsOK,
+			},
+			WantOutput: listCacheSettingsVerboseOutput,
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(strings.Join(testcase.Args, " "), func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertString(t, testcase.WantOutput, stdout.String())
+		})
+	}
+}
+
+func TestCacheSettingDescribe(t *testing.T) {
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("cache-setting describe --service-id 123 --version 1 --name images"), &stdout)
+	opts.APIClient = mock.APIClient(mock.API{
+		ListVersionsFn:    testutil.ListVersions,
+		GetCacheSettingFn: getCacheSettingOK,
+	})
+	err := app.Run(opts)
+	testutil.AssertNoError(t, err)
+	testutil.AssertString(t, describeCacheSettingOutput, stdout.String())
+}
+
+func TestCacheSettingUpdate(t *testing.T) {
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name:      "validate missing --name flag",
+			Args:      args("cache-setting update --service-id 123 --version 1 --ttl 60"),
+			WantError: "error parsing arguments: required flag --name not provided",
+		},
+		{
+			Name:      "validate --action is a known cache setting action",
+			Args:      args("cache-setting update --service-id 123 --version 1 --name images --action lookup"),
+			WantError: "error parsing arguments: enum value must be one of cache,pass,restart, got 'lookup'",
+		},
+		// NOTE: An explicit --ttl 0 must still be sent so a TTL can be cleared.
+		{
+			Name: "validate a zero TTL is sent alongside the new action",
+			Args: args("cache-setting update --service-id 123 --version 1 --name images --action pass --ttl 0 --autoclone"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CloneVersionFn: testutil.CloneVersionResult(4),
+				UpdateCacheSettingFn: updateCacheSettingWith(fastly.UpdateCacheSettingInput{
+					ServiceID:      "123",
+					ServiceVersion: 4,
+					Name:           "images",
+					Action:         fastly.CacheSettingActionPass,
+					TTL:            fastly.Int(0),
+				}),
+			},
+			WantOutput: "Updated cache setting images (service 123 version 4)",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+		})
+	}
+}
+
+func TestCacheSettingDelete(t *testing.T) {
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("cache-setting delete --service-id 123 --version 1 --name images --autoclone"), &stdout)
+	opts.APIClient = mock.APIClient(mock.API{
+		ListVersionsFn: testutil.ListVersions,
+		CloneVersionFn: testutil.CloneVersionResult(4),
+		DeleteCacheSettingFn: func(i *fastly.DeleteCacheSettingInput) error {
+			if i.Name != "images" || i.ServiceVersion != 4 {
+				return errTest
+			}
+			return nil
+		},
+	})
+	err := app.Run(opts)
+	testutil.AssertNoError(t, err)
+	testutil.AssertStringContains(t, stdout.String(), "Deleted cache setting images (service 123 version 4)")
+}
+
+var errTest = errors.New("fixture error")
+
+func createCacheSettingWith(want fastly.CreateCacheSettingInput) func(*fastly.CreateCacheSettingInput) (*fastly.CacheSetting, error) {
+	return func(i *fastly.CreateCacheSettingInput) (*fastly.CacheSetting, error) {
+		if !reflect.DeepEqual(*i, want) {
+			return nil, fmt.Errorf("unexpected input: %+v", *i)
+		}
+		return &fastly.CacheSetting{
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+			Name:           *i.Name,
+		}, nil
+	}
+}
+
+func createCacheSettingError(i *fastly.CreateCacheSettingInput) (*fastly.CacheSetting, error) {
+	return nil, errTest
+}
+
+func listCacheSettingsOK(i *fastly.ListCacheSettingsInput) ([]*fastly.CacheSetting, error) {
+	return []*fastly.CacheSetting{
+		{
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+			Name:           "images",
+			Action:         fastly.CacheSettingActionCache,
+			TTL:            86400,
+			StaleTTL:       3600,
+			CacheCondition: "is-image",
+		},
+		{
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+			Name:           "no-cache",
+			Action:         fastly.CacheSettingActionPass,
+			CacheCondition: "is-private",
+		},
+	}, nil
+}
+
+var listCacheSettingsShortOutput = strings.TrimSpace(`
+SERVICE  VERSION  NAME      ACTION  TTL    STALE TTL
+123      1        images    cache   86400  3600
+123      1        no-cache  pass    0      0
+`) + "\n"
+
+var listCacheSettingsVerboseOutput = strings.Join([]string{
+	"Fastly API token not provided",
+	"Fastly API endpoint: https://api.fastly.com",
+	"",
+	"Service ID (via --service-id): 123",
+	"",
+	"Version: 1",
+	"	Cache setting 1/2",
+	"		Name: images",
+	"		Action: cache",
+	"		TTL: 86400",
+	"		Stale TTL: 3600",
+	"		Cache condition: is-image",
+	"	Cache setting 2/2",
+	"		Name: no-cache",
+	"		Action: pass",
+	"		TTL: 0",
+	"		Stale TTL: 0",
+	"		Cache condition: is-private",
+}, "\n") + "\n\n"
+
+func getCacheSettingOK(i *fastly.GetCacheSettingInput) (*fastly.CacheSetting, error) {
+	return &fastly.CacheSetting{
+		ServiceID:      i.ServiceID,
+		ServiceVersion: i.ServiceVersion,
+		Name:           "images",
+		Action:         fastly.CacheSettingActionCache,
+		TTL:            86400,
+		StaleTTL:       3600,
+		CacheCondition: "is-image",
+	}, nil
+}
+
+var describeCacheSettingOutput = "\n" + strings.Join([]string{
+	"Service ID: 123",
+	"Version: 1",
+	"Name: images",
+	"Action: cache",
+	"TTL: 86400",
+	"Stale TTL: 3600",
+	"Cache condition: is-image",
+}, "\n") + "\n"
+
+func updateCacheSettingWith(want fastly.UpdateCacheSettingInput) func(*fastly.UpdateCacheSettingInput) (*fastly.CacheSetting, error) {
+	return func(i *fastly.UpdateCacheSettingInput) (*fastly.CacheSetting, error) {
+		if !reflect.DeepEqual(*i, want) {
+			return nil, fmt.Errorf("unexpected input: %+v", *i)
+		}
+		name := i.Name
+		if i.NewName != nil {
+			name = *i.NewName
+		}
+		return &fastly.CacheSetting{
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+			Name:           name,
+		}, nil
+	}
+}
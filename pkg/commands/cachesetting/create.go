@@ -0,0 +1,133 @@
+package cachesetting
+
+import (
+	"io"
+
+	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v8/fastly"
+)
+
+// cacheSettingActions are the accepted values for the --action flag.
+var cacheSettingActions = []string{
+	string(fastly.CacheSettingActionCache),
+	string(fastly.CacheSettingActionPass),
+	string(fastly.CacheSettingActionRestart),
+}
+
+// CreateCommand calls the Fastly API to create cache settings.
+type CreateCommand struct {
+	cmd.Base
+	manifest manifest.Data
+
+	// Required.
+	serviceVersion cmd.OptionalServiceVersion
+
+	// Optional.
+	action         cmd.OptionalString
+	autoClone      cmd.OptionalAutoClone
+	cacheCondition cmd.OptionalString
+	name           cmd.OptionalString
+	serviceName    cmd.OptionalServiceNameID
+	staleTTL       cmd.OptionalInt
+	ttl            cmd.OptionalInt
+}
+
+// NewCreateCommand returns a usable command registered under the parent.
+func NewCreateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *CreateCommand {
+	c := CreateCommand{
+		Base: cmd.Base{
+			Globals: g,
+		},
+		manifest: m,
+	}
+	c.CmdClause = parent.Command("create", "Create a cache setting on a Fastly service version").Alias("add")
+
+	// Required.
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagVersionName,
+		Description: cmd.FlagVersionDesc,
+		Dst:         &c.serviceVersion.Value,
+		Required:    true,
+	})
+
+	// Optional.
+	c.RegisterAutoCloneFlag(cmd.AutoCloneFlagOpts{
+		Action: c.autoClone.Set,
+		Dst:    &c.autoClone.Value,
+	})
+	c.CmdClause.Flag("action", "The action to take when the cache condition is met (cache, pass, restart)").Action(c.action.Set).HintOptions(cacheSettingActions...).EnumVar(&c.action.Value, cacheSettingActions...)
+	c.CmdClause.Flag("cache-condition", "Name of the cache condition controlling when this configuration applies").Action(c.cacheCondition.Set).StringVar(&c.cacheCondition.Value)
+	c.CmdClause.Flag("name", "Name of the cache setting").Short('n').Action(c.name.Set).StringVar(&c.name.Value)
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	c.CmdClause.Flag("stale-ttl", "Maximum time in seconds to continue to use a stale version of the object if future requests to your backend server fail").Action(c.staleTTL.Set).IntVar(&c.staleTTL.Value)
+	c.CmdClause.Flag("ttl", "Maximum time in seconds to consider the object fresh in the cache").Action(c.ttl.Set).IntVar(&c.ttl.Value)
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
+	serviceID, serviceVersion, err := cmd.ServiceDetails(cmd.ServiceDetailsOpts{
+		AutoCloneFlag:      c.autoClone,
+		APIClient:          c.Globals.APIClient,
+		Manifest:           c.manifest,
+		Out:                out,
+		ServiceNameFlag:    c.serviceName,
+		ServiceVersionFlag: c.serviceVersion,
+		VerboseMode:        c.Globals.Flags.Verbose,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": errors.ServiceVersion(serviceVersion),
+		})
+		return err
+	}
+
+	input := fastly.CreateCacheSettingInput{
+		ServiceID:      serviceID,
+		ServiceVersion: serviceVersion.Number,
+	}
+	if c.name.WasSet {
+		input.Name = &c.name.Value
+	}
+	if c.action.WasSet {
+		action := fastly.CacheSettingAction(c.action.Value)
+		input.Action = &action
+	}
+	if c.cacheCondition.WasSet {
+		input.CacheCondition = &c.cacheCondition.Value
+	}
+	if c.staleTTL.WasSet {
+		input.StaleTTL = &c.staleTTL.Value
+	}
+	if c.ttl.WasSet {
+		input.TTL = &c.ttl.Value
+	}
+
+	o, err := c.Globals.APIClient.CreateCacheSetting(&input)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": serviceVersion.Number,
+		})
+		return err
+	}
+
+	text.Success(out, "Created cache setting %s (service %s version %d)", o.Name, o.ServiceID, o.ServiceVersion)
+	return nil
+}
@@ -1,17 +1,20 @@
 package compute
 
 import (
+	"archive/tar"
 	"bufio"
-	"crypto/rand"
+	"compress/gzip"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/kennygrant/sanitize"
-	"github.com/mholt/archiver/v3"
 
 	"github.com/fastly/cli/pkg/cmd"
 	fsterr "github.com/fastly/cli/pkg/errors"
@@ -33,6 +36,7 @@ type Flags struct {
 	IncludeSrc  bool
 	Lang        string
 	PackageName string
+	Provenance  bool
 	Timeout     int
 }
 
@@ -55,9 +59,11 @@ func NewBuildCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Bu
 
 	// NOTE: when updating these flags, be sure to update the composite commands:
 	// `compute publish` and `compute serve`.
+	c.CmdClause.Flag("env", "The environment configuration to use (e.g. stage)").StringVar(&c.Manifest.Flag.Env)
 	c.CmdClause.Flag("include-source", "Include source code in built package").BoolVar(&c.Flags.IncludeSrc)
 	c.CmdClause.Flag("language", "Language type").StringVar(&c.Flags.Lang)
 	c.CmdClause.Flag("package-name", "Package name").StringVar(&c.Flags.PackageName)
+	c.CmdClause.Flag("provenance", fmt.Sprintf("Write a provenance attestation of the package (to pkg/<package>.tar.gz%s), verified by `compute deploy`", ProvenanceSuffix)).BoolVar(&c.Flags.Provenance)
 	c.CmdClause.Flag("timeout", "Timeout, in seconds, for the build compilation step").IntVar(&c.Flags.Timeout)
 
 	return &c
@@ -65,6 +71,8 @@ func NewBuildCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Bu
 
 // Exec implements the command interface.
 func (c *BuildCommand) Exec(in io.Reader, out io.Writer) (err error) {
+	started := time.Now()
+
 	// We'll restore this at the end to print a final successful build output.
 	originalOut := out
 
@@ -95,6 +103,10 @@ func (c *BuildCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		if errors.Is(err, os.ErrNotExist) {
 			err = fsterr.ErrReadingManifest
 		}
+	} else {
+		err = c.Manifest.ApplyEnv()
+	}
+	if err != nil {
 		c.Globals.ErrLog.Add(err)
 
 		spinner.StopFailMessage(msg)
@@ -142,7 +154,7 @@ func (c *BuildCommand) Exec(in io.Reader, out io.Writer) (err error) {
 	msg = "Identifying toolchain"
 	spinner.Message(msg + "...")
 
-	toolchain, err := toolchain(c)
+	toolchain, err := toolchain(c.Flags.Lang, c.Manifest.File.Language)
 	if err != nil {
 		spinner.StopFailMessage(msg)
 		spinErr := spinner.StopFail()
@@ -200,7 +212,36 @@ func (c *BuildCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		return err
 	}
 
-	err = CreatePackageArchive(files, dest)
+	// NOTE: When an environment is selected the package contains the manifest
+	// with its overlay applied, as `compute pack` does.
+	sources := map[string]string{}
+	if c.Manifest.Env() != "" {
+		tmpDir, err := os.MkdirTemp("", "fastly-build")
+		if err != nil {
+			spinner.StopFailMessage(msg)
+			spinErr := spinner.StopFail()
+			if spinErr != nil {
+				return spinErr
+			}
+			return fmt.Errorf("error creating temporary directory: %w", err)
+		}
+		defer os.RemoveAll(tmpDir)
+
+		sources[manifest.Filename] = filepath.Join(tmpDir, manifest.Filename)
+		err = c.Manifest.File.Write(sources[manifest.Filename])
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+
+			spinner.StopFailMessage(msg)
+			spinErr := spinner.StopFail()
+			if spinErr != nil {
+				return spinErr
+			}
+			return fmt.Errorf("error writing %s: %w", manifest.Filename, err)
+		}
+	}
+
+	err = createPackageArchive(files, sources, dest)
 	if err != nil {
 		c.Globals.ErrLog.AddWithContext(err, map[string]any{
 			"Files":       files,
@@ -222,8 +263,56 @@ func (c *BuildCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		return err
 	}
 
+	var attestation string
+	if c.Flags.Provenance {
+		err = spinner.Start()
+		if err != nil {
+			return err
+		}
+		msg = "Writing provenance attestation"
+		spinner.Message(msg + "...")
+
+		params := provenanceParameters{
+			Environment:   c.Manifest.Flag.Env,
+			IncludeSource: c.Flags.IncludeSrc,
+			Language:      toolchain,
+			PackageName:   packageName,
+		}
+		manifestPath := manifest.Filename
+		if src, ok := sources[manifest.Filename]; ok {
+			manifestPath = src
+		}
+		attestation, err = writeProvenance(dest, manifestPath, params, toolchainVersions(toolchain), started)
+		if err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Destination": dest,
+			})
+
+			spinner.StopFailMessage(msg)
+			spinErr := spinner.StopFail()
+			if spinErr != nil {
+				return spinErr
+			}
+
+			return fmt.Errorf("error writing provenance attestation: %w", err)
+		}
+
+		spinner.StopMessage(msg)
+		err = spinner.Stop()
+		if err != nil {
+			return err
+		}
+	} else if err = removeProvenance(dest); err != nil {
+		// NOTE: An attestation of a previous build would no longer match.
+		c.Globals.ErrLog.Add(err)
+		return fmt.Errorf("error removing the provenance attestation of the previous build: %w", err)
+	}
+
 	out = originalOut
 	text.Success(out, "Built package (%s)", dest)
+	if attestation != "" {
+		text.Info(out, "Provenance attestation written to %s", attestation)
+	}
 	return nil
 }
 
@@ -293,14 +382,14 @@ func packageName(c *BuildCommand) (string, error) {
 // It prioritises the --language flag over the manifest field.
 // Will error if neither are provided.
 // Lastly, it will normalise with a trim and lowercase.
-func toolchain(c *BuildCommand) (string, error) {
+func toolchain(flag, manifestLanguage string) (string, error) {
 	var toolchain string
 
 	switch {
-	case c.Flags.Lang != "":
-		toolchain = c.Flags.Lang
-	case c.Manifest.File.Language != "":
-		toolchain = c.Manifest.File.Language
+	case flag != "":
+		toolchain = flag
+	case manifestLanguage != "":
+		toolchain = manifestLanguage
 	default:
 		return "", fmt.Errorf("language cannot be empty, please provide a language")
 	}
@@ -405,48 +494,125 @@ func binDir(c *BuildCommand) error {
 // CreatePackageArchive packages build artifacts as a Fastly package.
 // The package must be a GZipped Tar archive.
 //
-// Due to a behavior of archiver.Archive() which recursively writes all files in
-// a provided directory to the archive we first copy our input files to a
-// temporary directory to ensure only the specified files are included and not
-// any in the directory which may be ignored.
+// The archive is reproducible, so the same files produce the same package on
+// any machine: entries are sorted by name, ownership is dropped, permissions
+// are normalised and timestamps are set to SOURCE_DATE_EPOCH (or the Unix
+// epoch when it isn't set).
 func CreatePackageArchive(files []string, destination string) error {
-	// Create temporary directory to copy files into.
-	p := make([]byte, 8)
-	n, err := rand.Read(p)
-	if err != nil {
-		return fmt.Errorf("error creating temporary directory: %w", err)
-	}
+	return createPackageArchive(files, nil, destination)
+}
 
-	tmpDir := filepath.Join(
-		os.TempDir(),
-		fmt.Sprintf("fastly-build-%x", p[:n]),
-	)
+// createPackageArchive packages the files as CreatePackageArchive does, reading
+// the content of any file with an entry in sources from that path instead.
+func createPackageArchive(files []string, sources map[string]string, destination string) (err error) {
+	// NOTE: All files are placed within a top-level directory named after the
+	// package, along with an entry for each of their parent directories.
+	root := FileNameWithoutExtension(destination)
+	entries := map[string]string{root + "/": ""}
+	for _, src := range files {
+		name := path.Join(root, filepath.ToSlash(filepath.Clean(src)))
+		entries[name] = src
+		if override, ok := sources[src]; ok {
+			entries[name] = override
+		}
+		for dir := path.Dir(name); dir != root && dir != "."; dir = path.Dir(dir) {
+			entries[dir+"/"] = ""
+		}
+	}
 
-	if err := os.MkdirAll(tmpDir, 0o700); err != nil {
-		return fmt.Errorf("error creating temporary directory: %w", err)
+	modTime, err := archiveModTime()
+	if err != nil {
+		return err
 	}
-	defer os.RemoveAll(tmpDir)
 
-	// Create implicit top-level directory within temp which will become the
-	// root of the archive. This replaces the `tar.ImplicitTopLevelFolder`
-	// behavior.
-	dir := filepath.Join(tmpDir, FileNameWithoutExtension(destination))
-	if err := os.Mkdir(dir, 0o700); err != nil {
-		return fmt.Errorf("error creating temporary directory: %w", err)
+	if err := filesystem.MakeDirectoryIfNotExists(filepath.Dir(destination)); err != nil {
+		return fmt.Errorf("error creating package directory: %w", err)
+	}
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as the destination is determined by our own package.
+	/* #nosec */
+	f, err := os.Create(destination)
+	if err != nil {
+		return err
 	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
 
-	for _, src := range files {
-		dst := filepath.Join(dir, src)
-		if err = filesystem.CopyFile(src, dst); err != nil {
-			return fmt.Errorf("error copying file: %w", err)
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	for _, name := range sortedKeys(entries) {
+		hdr := &tar.Header{
+			Name:    name,
+			ModTime: modTime,
+		}
+		src := entries[name]
+		if src == "" {
+			hdr.Typeflag = tar.TypeDir
+			hdr.Mode = 0o755
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := addArchiveFile(tw, hdr, src); err != nil {
+			return fmt.Errorf("error adding %s to package: %w", src, err)
 		}
 	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
 
-	tar := archiver.NewTarGz()
-	tar.OverwriteExisting = true //
-	tar.MkdirAll = true          // make destination directory if it doesn't exist
+// addArchiveFile writes the file at src to the archive.
+func addArchiveFile(tw *tar.Writer, hdr *tar.Header, src string) error {
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as we need to read the files that make up the package.
+	/* #nosec */
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close() // #nosec G307
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	hdr.Typeflag = tar.TypeReg
+	hdr.Size = info.Size()
+	hdr.Mode = 0o644
+	if info.Mode()&0o111 != 0 {
+		hdr.Mode = 0o755
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
 
-	return tar.Archive([]string{dir}, destination)
+// archiveModTime returns the timestamp given to every entry of a package.
+//
+// https://reproducible-builds.org/docs/source-date-epoch/
+func archiveModTime() (time.Time, error) {
+	epoch := os.Getenv("SOURCE_DATE_EPOCH")
+	if epoch == "" {
+		return time.Unix(0, 0), nil
+	}
+	seconds, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		return time.Time{}, fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid SOURCE_DATE_EPOCH '%s': %w", epoch, err),
+			Remediation: "Set SOURCE_DATE_EPOCH to a Unix timestamp (in seconds), or unset it.",
+		}
+	}
+	return time.Unix(seconds, 0), nil
 }
 
 // FileNameWithoutExtension returns a filename with its extension stripped.
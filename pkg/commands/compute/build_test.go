@@ -1,12 +1,18 @@
 package compute_test
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/fastly/cli/pkg/app"
 	"github.com/fastly/cli/pkg/commands/compute"
@@ -14,6 +20,7 @@ import (
 	"github.com/fastly/cli/pkg/manifest"
 	"github.com/fastly/cli/pkg/testutil"
 	"github.com/fastly/cli/pkg/threadsafe"
+	"github.com/mholt/archiver/v3"
 )
 
 func TestBuildRust(t *testing.T) {
@@ -706,6 +713,7 @@ func TestBuildOther(t *testing.T) {
 	for _, testcase := range []struct {
 		args                 []string
 		dontWantOutput       []string
+		envManifest          string
 		fastlyManifest       string
 		name                 string
 		stdin                string
@@ -780,6 +788,34 @@ func TestBuildOther(t *testing.T) {
 			},
 			wantError: "exit status 1", // because we have to trigger an error to see the post_build output
 		},
+		{
+			name: "environment overlay replaces scripts",
+			args: args("compute build --auto-yes --env stage --language other --verbose"),
+			fastlyManifest: `
+			manifest_version = 2
+			name = "test"
+			[scripts]
+			build = "ls ./bin"
+      post_build = "echo doing a post build"`,
+			envManifest: `
+			[scripts]
+			post_build = "echo doing a stage post build"`,
+			wantOutput: []string{
+				"doing a stage post build",
+				"Built package",
+			},
+		},
+		{
+			name: "missing environment overlay",
+			args: args("compute build --env prod --language other"),
+			fastlyManifest: `
+			manifest_version = 2
+			name = "test"
+			[scripts]
+			build = "ls ./bin"`,
+			wantError:            "error reading fastly.prod.toml",
+			wantRemediationError: "Create a fastly.prod.toml manifest",
+		},
 	} {
 		t.Run(testcase.name, func(t *testing.T) {
 			if testcase.fastlyManifest != "" {
@@ -787,6 +823,11 @@ func TestBuildOther(t *testing.T) {
 					t.Fatal(err)
 				}
 			}
+			if testcase.envManifest != "" {
+				if err := os.WriteFile(filepath.Join(rootdir, manifest.EnvFilename("stage")), []byte(testcase.envManifest), 0o777); err != nil {
+					t.Fatal(err)
+				}
+			}
 
 			var stdout threadsafe.Buffer
 			opts := testutil.NewRunOpts(testcase.args, &stdout)
@@ -806,3 +847,193 @@ func TestBuildOther(t *testing.T) {
 		})
 	}
 }
+
+// TestBuildProvenance validates that packages are reproducible, and that the
+// provenance attestation written by `compute build --provenance` is verified
+// by `compute deploy`.
+func TestBuildProvenance(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the build script requires a POSIX shell")
+	}
+
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootdir := testutil.NewEnv(testutil.EnvOpts{
+		T: t,
+		Copy: []testutil.FileIO{
+			{Src: "./testdata/main.wasm", Dst: "bin/main.wasm"},
+		},
+		Write: []testutil.FileIO{
+			{Src: "manifest_version = 2\nname = \"test\"\nlanguage = \"other\"\n[scripts]\nbuild = \"ls ./bin\"\n", Dst: manifest.Filename},
+		},
+	})
+	defer os.RemoveAll(rootdir)
+	if err := os.Chdir(rootdir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(pwd)
+
+	pkgPath := filepath.Join("pkg", "test.tar.gz")
+	attestationPath := pkgPath + compute.ProvenanceSuffix
+	run := func(args string) (string, error) {
+		var stdout threadsafe.Buffer
+		opts := testutil.NewRunOpts(testutil.Args(args), &stdout)
+		err := app.Run(opts)
+		return stdout.String(), err
+	}
+	build := func(args string) []byte {
+		t.Helper()
+		if out, err := run(strings.TrimSpace("compute build --auto-yes " + args)); err != nil {
+			t.Fatalf("unexpected error: %v\n%s", err, out)
+		}
+		pkg, err := os.ReadFile(pkgPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pkg
+	}
+
+	// The same files produce the same package, regardless of their timestamps.
+	first := build("")
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join("bin", "main.wasm"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	if second := build(""); !bytes.Equal(first, second) {
+		t.Fatal("building the same files produced different packages")
+	}
+
+	build("--provenance")
+	data, err := os.ReadFile(attestationPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var attestation struct {
+		Type    string `json:"_type"`
+		Subject []struct {
+			Name   string            `json:"name"`
+			Digest map[string]string `json:"digest"`
+		} `json:"subject"`
+		Predicate struct {
+			BuildDefinition struct {
+				ExternalParameters struct {
+					Language string `json:"language"`
+					Manifest string `json:"manifest"`
+				} `json:"externalParameters"`
+				ResolvedDependencies []struct {
+					Name string `json:"name"`
+				} `json:"resolvedDependencies"`
+			} `json:"buildDefinition"`
+		} `json:"predicate"`
+	}
+	if err := json.Unmarshal(data, &attestation); err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertEqual(t, "https://in-toto.io/Statement/v1", attestation.Type)
+	testutil.AssertEqual(t, "test.tar.gz", attestation.Subject[0].Name)
+	testutil.AssertEqual(t, fmt.Sprintf("%x", sha256.Sum256(first)), attestation.Subject[0].Digest["sha256"])
+	testutil.AssertEqual(t, "other", attestation.Predicate.BuildDefinition.ExternalParameters.Language)
+	testutil.AssertStringContains(t, attestation.Predicate.BuildDefinition.ExternalParameters.Manifest, "name = \"test\"")
+	var files []string
+	for _, f := range attestation.Predicate.BuildDefinition.ResolvedDependencies {
+		files = append(files, f.Name)
+	}
+	testutil.AssertEqual(t, []string{"test/bin/main.wasm", "test/fastly.toml"}, files)
+
+	out, err := run("compute deploy --token 123 --plan --non-interactive --verbose")
+	testutil.AssertNoError(t, err)
+	testutil.AssertStringContains(t, out, "Verified the package against its provenance attestation (pkg/test.tar.gz.intoto.json)")
+
+	// A build without --provenance removes the attestation of the previous
+	// build, so it's restored to simulate a package that no longer matches.
+	if err := os.WriteFile(filepath.Join("bin", "main.wasm"), []byte("\x00asm\x01\x00\x00\x00\x00"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	build("")
+	if _, err := os.Stat(attestationPath); !os.IsNotExist(err) {
+		t.Fatalf("the attestation of the previous build wasn't removed: %v", err)
+	}
+	if err := os.WriteFile(attestationPath, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = run("compute deploy --token 123 --plan --non-interactive")
+	testutil.AssertErrorContains(t, err, "the package pkg/test.tar.gz doesn't match its provenance attestation")
+	testutil.AssertRemediationErrorContains(t, err, "fastly compute build --provenance")
+}
+
+// TestBuildEnv validates that `compute build --env` packages (and attests to)
+// the manifest with the environment's overlay applied.
+func TestBuildEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the build script requires a POSIX shell")
+	}
+
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootdir := testutil.NewEnv(testutil.EnvOpts{
+		T: t,
+		Copy: []testutil.FileIO{
+			{Src: "./testdata/main.wasm", Dst: "bin/main.wasm"},
+		},
+		Write: []testutil.FileIO{
+			{Src: "manifest_version = 2\nname = \"test\"\nlanguage = \"other\"\nservice_id = \"123\"\n[scripts]\nbuild = \"ls ./bin\"\n", Dst: manifest.Filename},
+			{Src: `service_id = "stage-456"`, Dst: manifest.EnvFilename("stage")},
+		},
+	})
+	defer os.RemoveAll(rootdir)
+	if err := os.Chdir(rootdir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(pwd)
+
+	var stdout threadsafe.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("compute build --auto-yes --env stage --provenance"), &stdout)
+	if err := app.Run(opts); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, stdout.String())
+	}
+
+	var packaged string
+	if err := archiver.Walk(filepath.Join("pkg", "test.tar.gz"), func(f archiver.File) error {
+		if f.Name() != manifest.Filename {
+			return nil
+		}
+		b, err := io.ReadAll(f)
+		packaged = string(b)
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertStringContains(t, packaged, `service_id = "stage-456"`)
+	testutil.AssertStringDoesntContain(t, packaged, `service_id = "123"`)
+
+	data, err := os.ReadFile(filepath.Join("pkg", "test.tar.gz") + compute.ProvenanceSuffix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var attestation struct {
+		Predicate struct {
+			BuildDefinition struct {
+				ExternalParameters struct {
+					Environment string `json:"environment"`
+					Manifest    string `json:"manifest"`
+				} `json:"externalParameters"`
+			} `json:"buildDefinition"`
+		} `json:"predicate"`
+	}
+	if err := json.Unmarshal(data, &attestation); err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertEqual(t, "stage", attestation.Predicate.BuildDefinition.ExternalParameters.Environment)
+	testutil.AssertEqual(t, packaged, attestation.Predicate.BuildDefinition.ExternalParameters.Manifest)
+
+	// The project's manifest is left as it was.
+	data, err = os.ReadFile(manifest.Filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertStringContains(t, string(data), `service_id = "123"`)
+}
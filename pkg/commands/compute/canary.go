@@ -0,0 +1,318 @@
+package compute
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fastly/go-fastly/v8/fastly"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/cli/pkg/undo"
+)
+
+// CanaryKey is the config store item holding the percentage of traffic the
+// primary service should route to the canary service.
+const CanaryKey = "canary_percent"
+
+// canaryRemediation explains how a service splits traffic with a canary.
+var canaryRemediation = fmt.Sprintf("A canary deploy uploads the package to a second Compute@Edge service (--canary-service-id) and sets the '%s' item of a config store linked to the primary service (--canary-config-store). The package running on the primary service must read that item and forward the given percentage of requests to the canary service.", CanaryKey)
+
+// errCanaryInterrupted means the rollout was stopped by a SIGINT or SIGTERM.
+var errCanaryInterrupted = errors.New("canary aborted: interrupted")
+
+// canary deploys the package to the canary service, routes an increasing share
+// of the primary service's traffic to it and, once the share reaches 100%,
+// promotes the package to the primary service.
+//
+// Whilst traffic is routed to the canary, its realtime stats are watched and if
+// the 5xx error rate exceeds --canary-max-error-rate the rollout is aborted and
+// all traffic is routed back to the primary service. The same happens if the
+// canary service receives no requests during a step, or the rollout is
+// interrupted.
+func (c *DeployCommand) canary(source manifest.Source, serviceID, pkgPath string, out io.Writer) (err error) {
+	percent, step, maxErrorRate, err := c.canaryFlags(source)
+	if err != nil {
+		return err
+	}
+
+	apiClient := c.Globals.APIClient
+	if c.ServiceName.WasSet {
+		serviceID, err = c.ServiceName.Parse(apiClient)
+		if err != nil {
+			return err
+		}
+	}
+	if c.CanaryServiceID == serviceID {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("error parsing --canary-service-id: service %s is the primary service", serviceID),
+			Remediation: "Provide the ID of a second service to deploy the canary package to. " + canaryRemediation,
+		}
+	}
+
+	storeID, err := c.canaryConfigStore(serviceID)
+	if err != nil {
+		return err
+	}
+
+	spinner, err := text.NewSpinner(out)
+	if err != nil {
+		return err
+	}
+
+	text.Info(out, "Deploying the package to the canary service %s", c.CanaryServiceID)
+	if err = c.deployVersion(c.CanaryServiceID, cmd.OptionalServiceVersion{}, pkgPath, spinner, out); err != nil {
+		return err
+	}
+
+	// If the rollout fails at any point, all traffic is routed back to the
+	// primary service.
+	undoStack := undo.NewStack()
+	undoStack.Push(func() error {
+		text.Info(out, "Routing all traffic back to service %s", serviceID)
+		return setCanaryPercent(c, storeID, 0)
+	})
+	defer func(errLog fsterr.LogInterface) {
+		if err != nil {
+			errLog.Add(err)
+		}
+		undoStack.RunIfError(out, err)
+	}(c.Globals.ErrLog)
+
+	// NOTE: Interrupting the rollout mustn't leave traffic routed to the canary,
+	// so the signals are handled until the rollout completes or is undone.
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+
+	for percent < 100 {
+		if err = setCanaryPercent(c, storeID, percent); err != nil {
+			return err
+		}
+		text.Output(out, "Routing %d%% of traffic to the canary service, watching its error rate for %s...", percent, c.CanaryInterval)
+
+		var requests, failed uint64
+		requests, failed, err = watchCanary(c, c.CanaryServiceID, c.CanaryInterval, sigs)
+		if err != nil {
+			return err
+		}
+		if requests == 0 {
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("canary aborted: no requests were received by the canary service"),
+				Remediation: fmt.Sprintf("Check the package running on service %s forwards traffic to the canary service, or increase --canary-interval. %s", serviceID, canaryRemediation),
+			}
+		}
+		rate := float64(failed) / float64(requests) * 100
+		text.Output(out, "Canary error rate: %.2f%% (%d of %d requests)", rate, failed, requests)
+		if rate > maxErrorRate {
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("canary aborted: error rate %.2f%% exceeded %.2f%%", rate, maxErrorRate),
+				Remediation: fmt.Sprintf("Check the logs of the canary service %s, fix the errors and deploy again.", c.CanaryServiceID),
+			}
+		}
+		percent += step
+	}
+
+	text.Info(out, "Promoting the package to service %s", serviceID)
+	if err = c.deployVersion(serviceID, c.ServiceVersion, pkgPath, spinner, out); err != nil {
+		return err
+	}
+	if err = setCanaryPercent(c, storeID, 0); err != nil {
+		return err
+	}
+
+	text.Success(out, "Promoted canary package (service %s)", serviceID)
+	return nil
+}
+
+// canaryFlags validates the canary flags, returning the initial and step
+// percentages and the maximum error rate.
+func (c *DeployCommand) canaryFlags(source manifest.Source) (percent, step int, maxErrorRate float64, err error) {
+	if c.Plan {
+		return 0, 0, 0, fsterr.ErrInvalidCanaryPlanCombo
+	}
+	if source == manifest.SourceUndefined {
+		return 0, 0, 0, fsterr.RemediationError{
+			Inner:       fmt.Errorf("error reading service: no service ID found"),
+			Remediation: "A canary deploy requires an existing service. " + fsterr.ServiceIDRemediation,
+		}
+	}
+	if c.CanaryServiceID == "" {
+		return 0, 0, 0, fsterr.RemediationError{
+			Inner:       fmt.Errorf("error parsing --canary: no canary service"),
+			Remediation: "Provide the ID of the service to deploy the canary package to with --canary-service-id. " + canaryRemediation,
+		}
+	}
+	if percent, err = parsePercent("canary", c.Canary); err != nil {
+		return 0, 0, 0, err
+	}
+	if step, err = parsePercent("canary-step", c.CanaryStep); err != nil {
+		return 0, 0, 0, err
+	}
+	maxErrorRate, err = strconv.ParseFloat(strings.TrimSuffix(c.CanaryMaxErrorRate, "%"), 64)
+	if err != nil || maxErrorRate < 0 || maxErrorRate > 100 {
+		return 0, 0, 0, fsterr.RemediationError{
+			Inner:       fmt.Errorf("error parsing --canary-max-error-rate: invalid percentage '%s'", c.CanaryMaxErrorRate),
+			Remediation: "Provide a percentage between 0 and 100 (e.g. 0.5%).",
+		}
+	}
+	return percent, step, maxErrorRate, nil
+}
+
+// parsePercent parses a whole percentage between 1 and 100 (e.g. 10%).
+func parsePercent(flag, value string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(value), "%"))
+	if err != nil || n < 1 || n > 100 {
+		return 0, fsterr.RemediationError{
+			Inner:       fmt.Errorf("error parsing --%s: invalid percentage '%s'", flag, value),
+			Remediation: "Provide a whole percentage between 1 and 100 (e.g. 10%).",
+		}
+	}
+	return n, nil
+}
+
+// canaryConfigStore returns the ID of the --canary-config-store, validating
+// it is linked to the primary service.
+func (c *DeployCommand) canaryConfigStore(serviceID string) (string, error) {
+	stores, err := c.Globals.APIClient.ListConfigStores()
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return "", fmt.Errorf("error listing config stores: %w", err)
+	}
+
+	var storeID string
+	for _, s := range stores {
+		if s.Name == c.CanaryConfigStore {
+			storeID = s.ID
+			break
+		}
+	}
+	if storeID == "" {
+		return "", fsterr.RemediationError{
+			Inner:       fmt.Errorf("error finding config store '%s'", c.CanaryConfigStore),
+			Remediation: fmt.Sprintf("Create the config store (`fastly config-store create --name %s`) and link it to the service. %s", c.CanaryConfigStore, canaryRemediation),
+		}
+	}
+
+	services, err := c.Globals.APIClient.ListConfigStoreServices(&fastly.ListConfigStoreServicesInput{
+		ID: storeID,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Config Store ID": storeID,
+		})
+		return "", fmt.Errorf("error listing config store services: %w", err)
+	}
+	for _, s := range services {
+		if s.ID == serviceID {
+			return storeID, nil
+		}
+	}
+	return "", fsterr.RemediationError{
+		Inner:       fmt.Errorf("config store '%s' is not linked to service %s", c.CanaryConfigStore, serviceID),
+		Remediation: fmt.Sprintf("Link the config store to the service (`fastly resource-link create --resource-id %s --service-id %s --version latest --autoclone`) and activate the version. %s", storeID, serviceID, canaryRemediation),
+	}
+}
+
+// setCanaryPercent sets the percentage of traffic routed to the canary service.
+func setCanaryPercent(c *DeployCommand, storeID string, percent int) error {
+	_, err := c.Globals.APIClient.UpdateConfigStoreItem(&fastly.UpdateConfigStoreItemInput{
+		StoreID: storeID,
+		Key:     CanaryKey,
+		Value:   strconv.Itoa(percent),
+		Upsert:  true,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Config Store ID": storeID,
+			"Percent":         percent,
+		})
+		return fmt.Errorf("error updating config store item '%s': %w", CanaryKey, err)
+	}
+	return nil
+}
+
+// watchCanary sums the requests and 5xx responses of the canary service,
+// reported by the realtime stats API, for the given duration. It returns
+// errCanaryInterrupted if a signal is received.
+//
+// NOTE: Each request to the realtime stats API waits for the next second of
+// stats, so the stats are fetched at least once. The first request returns the
+// recent history of the service, so only the seconds recorded since the step
+// started are counted.
+func watchCanary(c *DeployCommand, serviceID string, d time.Duration, sigs <-chan os.Signal) (requests, failed uint64, err error) {
+	var timestamp uint64
+	started := uint64(time.Now().Unix())
+	deadline := time.Now().Add(d)
+	for {
+		var envelope struct {
+			Timestamp uint64 `json:"timestamp"`
+			Data      []struct {
+				Recorded   uint64 `json:"recorded"`
+				Aggregated struct {
+					Requests  uint64 `json:"requests"`
+					Status5xx uint64 `json:"status_5xx"`
+				} `json:"aggregated"`
+			} `json:"data"`
+		}
+		err = c.Globals.RTSClient.GetRealtimeStatsJSON(&fastly.GetRealtimeStatsInput{
+			ServiceID: serviceID,
+			Timestamp: timestamp,
+		}, &envelope)
+		if err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Service ID": serviceID,
+			})
+			return requests, failed, fmt.Errorf("error fetching realtime stats: %w", err)
+		}
+		timestamp = envelope.Timestamp
+
+		for _, block := range envelope.Data {
+			if block.Recorded < started {
+				continue
+			}
+			requests += block.Aggregated.Requests
+			failed += block.Aggregated.Status5xx
+		}
+
+		select {
+		case <-sigs:
+			return requests, failed, errCanaryInterrupted
+		default:
+		}
+		if !time.Now().Before(deadline) {
+			return requests, failed, nil
+		}
+	}
+}
+
+// deployVersion uploads the package to an editable version of the service
+// (cloning the version if necessary) and activates it.
+func (c *DeployCommand) deployVersion(
+	serviceID string,
+	serviceVersionFlag cmd.OptionalServiceVersion,
+	pkgPath string,
+	spinner text.Spinner,
+	out io.Writer,
+) error {
+	serviceVersion, err := manageExistingServiceFlow(serviceID, serviceVersionFlag, c.Globals.APIClient, c.Globals.Verbose(), out, c.Globals.ErrLog)
+	if err != nil {
+		return err
+	}
+
+	cont, err := processPackage(c, pkgPath, serviceID, serviceVersion.Number, spinner, out)
+	if err != nil || !cont {
+		return err
+	}
+
+	return processService(c, serviceID, serviceVersion.Number, spinner)
+}
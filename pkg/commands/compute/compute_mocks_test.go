@@ -5,10 +5,66 @@ package compute_test
 // also a mocked HTTP client).
 
 import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
 	"github.com/fastly/cli/pkg/testutil"
 	"github.com/fastly/go-fastly/v8/fastly"
 )
 
+// fakeViceroyEnv makes the test binary act as a fake Viceroy, so it can be
+// passed to --viceroy-path (see fakeViceroy).
+const fakeViceroyEnv = "FASTLY_TEST_FAKE_VICEROY"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(fakeViceroyEnv) != "" {
+		fakeViceroy()
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// fakeViceroy serves HTTP on the --addr address and responds with the
+// request path (and body) prefixed by $FASTLY_TEST_FAKE_VICEROY. Requests
+// for /missing return a 404.
+//
+// If $FASTLY_TEST_FAKE_VICEROY_REQUESTS is set, it exits with a non-zero
+// status once that many requests have been served.
+func fakeViceroy() {
+	var addr string
+	for i, arg := range os.Args {
+		if arg == "--addr" && i+1 < len(os.Args) {
+			addr = os.Args[i+1]
+		}
+	}
+	limit, _ := strconv.Atoi(os.Getenv(fakeViceroyEnv + "_REQUESTS"))
+
+	var served int32
+	err := http.ListenAndServe(addr, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "text/plain")
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		fmt.Fprintf(w, "%s %s%s", os.Getenv(fakeViceroyEnv), r.URL.Path, body)
+
+		if n := atomic.AddInt32(&served, 1); limit > 0 && int(n) >= limit {
+			go func() {
+				time.Sleep(100 * time.Millisecond)
+				os.Exit(1)
+			}()
+		}
+	})) // #nosec G114
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
+
 func getServiceOK(i *fastly.GetServiceInput) (*fastly.Service, error) {
 	return &fastly.Service{
 		ID:   "12345",
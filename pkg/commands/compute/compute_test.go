@@ -95,10 +95,14 @@ func TestServeFlagDivergence(t *testing.T) {
 	// We only want to be sure serve contains all build flags.
 	ignoreServeFlags := []string{
 		"addr",
+		"backends-from-service",
 		"debug",
-		"env",
 		"file",
+		"record",
+		"service-id",
+		"service-name",
 		"skip-build",
+		"sync-stores",
 		"viceroy-check",
 		"viceroy-path",
 		"watch",
@@ -17,6 +17,7 @@ import (
 
 	"github.com/fastly/cli/pkg/api"
 	"github.com/fastly/cli/pkg/api/undocumented"
+	"github.com/fastly/cli/pkg/check"
 	"github.com/fastly/cli/pkg/cmd"
 	"github.com/fastly/cli/pkg/commands/compute/setup"
 	fsterr "github.com/fastly/cli/pkg/errors"
@@ -35,13 +36,21 @@ const (
 // DeployCommand deploys an artifact previously produced by build.
 type DeployCommand struct {
 	cmd.Base
+	cmd.JSONOutput
 
 	// NOTE: these are public so that the "publish" composite command can set the
 	// values appropriately before calling the Exec() function.
+	Canary             string
+	CanaryConfigStore  string
+	CanaryInterval     time.Duration
+	CanaryMaxErrorRate string
+	CanaryServiceID    string
+	CanaryStep         string
 	Comment            cmd.OptionalString
 	Domain             string
 	Manifest           manifest.Data
 	Package            string
+	Plan               bool
 	ServiceName        cmd.OptionalServiceNameID
 	ServiceVersion     cmd.OptionalServiceVersion
 	StatusCheckCode    int
@@ -77,9 +86,18 @@ func NewDeployCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *D
 		Dst:         &c.ServiceVersion.Value,
 		Name:        cmd.FlagVersionName,
 	})
+	c.CmdClause.Flag("canary", "Roll the package out gradually, starting with this percentage of traffic (e.g. 10%)").StringVar(&c.Canary)
+	c.CmdClause.Flag("canary-config-store", "Name of the config store used to split traffic with the canary service").Default("canary").StringVar(&c.CanaryConfigStore)
+	c.CmdClause.Flag("canary-interval", "Time to watch the canary error rate before increasing its share of traffic").Default("5m").DurationVar(&c.CanaryInterval)
+	c.CmdClause.Flag("canary-max-error-rate", "Abort the canary if its percentage of 5xx responses exceeds this").Default("1%").StringVar(&c.CanaryMaxErrorRate)
+	c.CmdClause.Flag("canary-service-id", "Service ID to deploy the canary package to").StringVar(&c.CanaryServiceID)
+	c.CmdClause.Flag("canary-step", "Percentage to increase the canary's share of traffic by at each interval").Default("10%").StringVar(&c.CanaryStep)
 	c.CmdClause.Flag("comment", "Human-readable comment").Action(c.Comment.Set).StringVar(&c.Comment.Value)
 	c.CmdClause.Flag("domain", "The name of the domain associated to the package").StringVar(&c.Domain)
+	c.CmdClause.Flag("env", "The environment configuration to use (e.g. stage)").StringVar(&c.Manifest.Flag.Env)
+	c.RegisterFlagBool(c.JSONFlag()) // --json
 	c.CmdClause.Flag("package", "Path to a package tar.gz").Short('p').StringVar(&c.Package)
+	c.CmdClause.Flag("plan", "Display the changes the deploy would make, without making them").BoolVar(&c.Plan)
 	c.CmdClause.Flag("status-check-code", "Set the expected status response for the service availability check").IntVar(&c.StatusCheckCode)
 	c.CmdClause.Flag("status-check-off", "Disable the service availability check").BoolVar(&c.StatusCheckOff)
 	c.CmdClause.Flag("status-check-path", "Specify the URL path for the service availability check").Default("/").StringVar(&c.StatusCheckPath)
@@ -89,11 +107,22 @@ func NewDeployCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *D
 
 // Exec implements the command interface.
 func (c *DeployCommand) Exec(in io.Reader, out io.Writer) (err error) {
+	if err := checkPlanFlags(c.Plan, c.JSONOutput.Enabled, c.Globals.Verbose()); err != nil {
+		return err
+	}
+
 	fnActivateTrial, source, serviceID, pkgPath, err := setupDeploy(c, out)
 	if err != nil {
 		return err
 	}
 
+	if c.Canary != "" {
+		return c.canary(source, serviceID, pkgPath, out)
+	}
+	if c.Plan {
+		return c.plan(source, serviceID, pkgPath, out)
+	}
+
 	undoStack := undo.NewStack()
 	undoStack.Push(func() error {
 		// We'll only clean-up the service if it's a new service.
@@ -178,10 +207,10 @@ func (c *DeployCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		// Because the service availability can return an error (which we ignore),
 		// then we need to check for the 'no error' scenarios.
 		if err == nil {
-			if validStatusCodeRange(c.StatusCheckCode) && status != c.StatusCheckCode {
+			if check.ValidStatusCode(c.StatusCheckCode) && status != c.StatusCheckCode {
 				// If the user set a specific status code expectation...
 				text.Warning(out, "The service path `%s` responded with a status code (%d) that didn't match what was expected (%d).", c.StatusCheckPath, status, c.StatusCheckCode)
-			} else if !validStatusCodeRange(c.StatusCheckCode) && status >= http.StatusBadRequest {
+			} else if !check.ValidStatusCode(c.StatusCheckCode) && status >= http.StatusBadRequest {
 				// If no status code was specified, and the actual status response was an error...
 				text.Info(out, "The service path `%s` responded with a non-successful status code (%d). Please check your application code if this is an unexpected response.", c.StatusCheckPath, status)
 			}
@@ -196,15 +225,6 @@ func (c *DeployCommand) Exec(in io.Reader, out io.Writer) (err error) {
 	return nil
 }
 
-// validStatusCodeRange checks the status is a valid status code.
-// e.g. >= 100 and <= 999
-func validStatusCodeRange(status int) bool {
-	if status >= 100 && status <= 999 {
-		return true
-	}
-	return false
-}
-
 // setupDeploy prepares the environment.
 // It will do things like:
 //   - Check if there is an API token missing.
@@ -224,6 +244,11 @@ func setupDeploy(c *DeployCommand, out io.Writer) (
 		return defaultActivator, 0, "", "", fsterr.ErrNoToken
 	}
 
+	if err := c.Manifest.ApplyEnv(); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return defaultActivator, 0, "", "", err
+	}
+
 	// IMPORTANT: We don't handle the error when looking up the Service ID.
 	// This is because later in the Exec() flow we might create a 'new' service.
 	// Refer to manageNoServiceIDFlow()
@@ -246,7 +271,8 @@ func setupDeploy(c *DeployCommand, out io.Writer) (
 // validatePackage short-circuits the deploy command if the user hasn't first
 // built a package to be deployed.
 //
-// NOTE: It also validates if the package size exceeds limit:
+// NOTE: It also validates if the package size exceeds limit, and verifies the
+// package against its provenance attestation (if it has one):
 // https://docs.fastly.com/products/compute-at-edge-billing-and-resource-limits#resource-limits
 func validatePackage(
 	data manifest.Data,
@@ -307,6 +333,17 @@ func validatePackage(
 		return pkgPath, err
 	}
 
+	attested, err := verifyProvenance(pkgPath)
+	if err != nil {
+		errLog.AddWithContext(err, map[string]any{
+			"Package path": pkgPath,
+		})
+		return pkgPath, err
+	}
+	if attested && verbose {
+		text.Info(out, "Verified the package against its provenance attestation (%s%s)", pkgPath, ProvenanceSuffix)
+	}
+
 	return pkgPath, nil
 }
 
@@ -452,7 +489,7 @@ func serviceManagement(
 		serviceID, serviceVersion, err = manageNoServiceIDFlow(
 			c.Globals.Flags, in, out,
 			c.Globals.APIClient, c.Package, c.Globals.ErrLog,
-			&c.Manifest.File, fnActivateTrial, spinner, c.ServiceName,
+			&c.Manifest, fnActivateTrial, spinner, c.ServiceName,
 		)
 		if err != nil {
 			return newService, "", nil, false, err
@@ -493,17 +530,17 @@ func manageNoServiceIDFlow(
 	apiClient api.Interface,
 	packageFlag string,
 	errLog fsterr.LogInterface,
-	manifestFile *manifest.File,
+	manifestData *manifest.Data,
 	fnActivateTrial activator,
 	spinner text.Spinner,
 	serviceNameFlag cmd.OptionalServiceNameID,
 ) (serviceID string, serviceVersion *fastly.Version, err error) {
 	if !f.AutoYes && !f.NonInteractive {
-		text.Output(out, "There is no Fastly service associated with this package. To connect to an existing service add the Service ID to the fastly.toml file, otherwise follow the prompts to create a service now.")
+		text.Output(out, "There is no Fastly service associated with this package. To connect to an existing service add the Service ID to the %s file, otherwise follow the prompts to create a service now.", manifest.EnvFilename(manifestData.Env()))
 		text.Break(out)
 		text.Output(out, "Press ^C at any time to quit.")
 
-		if manifestFile.Setup.Defined() {
+		if manifestData.File.Setup.Defined() {
 			text.Info(out, "Processing of the fastly.toml [setup] configuration happens only when there is no existing service. Once a service is created, any further changes to the service or its resources must be made manually.")
 		}
 
@@ -520,7 +557,7 @@ func manageNoServiceIDFlow(
 		text.Break(out)
 	}
 
-	defaultServiceName := manifestFile.Name
+	defaultServiceName := manifestData.File.Name
 	var serviceName string
 
 	// The service name will be whatever is set in the --service-name flag.
@@ -548,7 +585,7 @@ func manageNoServiceIDFlow(
 		return serviceID, serviceVersion, err
 	}
 
-	err = updateManifestServiceID(manifestFile, manifest.Filename, serviceID)
+	err = updateManifestServiceID(manifestData, serviceID)
 
 	// NOTE: Skip error if --package flag is set.
 	//
@@ -668,9 +705,9 @@ func cleanupService(apiClient api.Interface, serviceID string, m manifest.Data,
 		return err
 	}
 
-	text.Info(out, "Removing Service ID from fastly.toml")
+	text.Info(out, "Removing Service ID from %s", manifest.EnvFilename(m.Env()))
 
-	err = updateManifestServiceID(&m.File, manifest.Filename, "")
+	err = updateManifestServiceID(&m, "")
 	if err != nil {
 		return err
 	}
@@ -686,14 +723,25 @@ func cleanupService(apiClient api.Interface, serviceID string, m manifest.Data,
 // error in the deploy flow, and for which the Service ID will be set to an
 // empty string (otherwise the service itself will be deleted while the
 // manifest will continue to hold a reference to it).
-func updateManifestServiceID(m *manifest.File, manifestFilename string, serviceID string) error {
-	if err := m.Read(manifestFilename); err != nil {
+//
+// When deploying to an environment (--env) the Service ID is saved to the
+// environment's manifest (e.g. fastly.stage.toml) rather than the fastly.toml.
+func updateManifestServiceID(m *manifest.Data, serviceID string) error {
+	if env := m.Env(); env != "" {
+		if err := manifest.WriteEnvServiceID(env, serviceID); err != nil {
+			return fmt.Errorf("error saving %s: %w", manifest.EnvFilename(env), err)
+		}
+		m.File.ServiceID = serviceID
+		return nil
+	}
+
+	if err := m.File.Read(manifest.Filename); err != nil {
 		return fmt.Errorf("error reading fastly.toml: %w", err)
 	}
 
-	m.ServiceID = serviceID
+	m.File.ServiceID = serviceID
 
-	if err := m.Write(manifestFilename); err != nil {
+	if err := m.File.Write(manifest.Filename); err != nil {
 		return fmt.Errorf("error saving fastly.toml: %w", err)
 	}
 
@@ -717,27 +765,9 @@ func manageExistingServiceFlow(
 		return serviceVersion, err
 	}
 
-	// Validate that we're dealing with a Compute@Edge 'wasm' service and not a
-	// VCL service, for which we cannot upload a wasm package format to.
-	serviceDetails, err := apiClient.GetServiceDetails(&fastly.GetServiceInput{ID: serviceID})
-	if err != nil {
-		errLog.AddWithContext(err, map[string]any{
-			"Service ID":      serviceID,
-			"Service Version": serviceVersion,
-		})
+	if _, err = checkServiceType(serviceID, serviceVersion, apiClient, errLog); err != nil {
 		return serviceVersion, err
 	}
-	if serviceDetails.Type != "wasm" {
-		errLog.AddWithContext(fmt.Errorf("error: invalid service type: '%s'", serviceDetails.Type), map[string]any{
-			"Service ID":      serviceID,
-			"Service Version": serviceVersion,
-			"Service Type":    serviceDetails.Type,
-		})
-		return serviceVersion, fsterr.RemediationError{
-			Inner:       fmt.Errorf("invalid service type: %s", serviceDetails.Type),
-			Remediation: "Ensure the provided Service ID is associated with a 'Wasm' Fastly Service and not a 'VCL' Fastly service. " + fsterr.ComputeTrialRemediation,
-		}
-	}
 
 	// Unlike other CLI commands that are a direct mapping to an API endpoint,
 	// the compute deploy command is a composite of behaviours, and so as we
@@ -764,6 +794,37 @@ func manageExistingServiceFlow(
 	return serviceVersion, nil
 }
 
+// checkServiceType validates that we're dealing with a Compute@Edge 'wasm'
+// service and not a VCL service, for which we cannot upload a wasm package
+// format to. It returns the service details.
+func checkServiceType(
+	serviceID string,
+	serviceVersion *fastly.Version,
+	apiClient api.Interface,
+	errLog fsterr.LogInterface,
+) (*fastly.ServiceDetail, error) {
+	serviceDetails, err := apiClient.GetServiceDetails(&fastly.GetServiceInput{ID: serviceID})
+	if err != nil {
+		errLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": serviceVersion,
+		})
+		return nil, err
+	}
+	if serviceDetails.Type != "wasm" {
+		errLog.AddWithContext(fmt.Errorf("error: invalid service type: '%s'", serviceDetails.Type), map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": serviceVersion,
+			"Service Type":    serviceDetails.Type,
+		})
+		return nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid service type: %s", serviceDetails.Type),
+			Remediation: "Ensure the provided Service ID is associated with a 'Wasm' Fastly Service and not a 'VCL' Fastly service. " + fsterr.ComputeTrialRemediation,
+		}
+	}
+	return serviceDetails, nil
+}
+
 // errLogService records the error, service id and version into the error log.
 func errLogService(l fsterr.LogInterface, err error, sid string, sv int) {
 	l.AddWithContext(err, map[string]any{
@@ -1216,103 +1277,23 @@ func checkingServiceAvailability(
 ) (status int, err error) {
 	remediation := "The service has been successfully deployed and activated, but the service 'availability' check %s (we were looking for a %s but the last status code response was: %d). If using a custom domain, please be sure to check your DNS settings. Otherwise, your application might be taking longer than usual to deploy across our global network. Please continue to check the service URL and if still unavailable please contact Fastly support."
 
-	dur := time.Duration(c.StatusCheckTimeout) * time.Second
-	end := time.Now().Add(dur)
-	timeout := time.After(dur)
-	ticker := time.NewTicker(1 * time.Second)
-	defer func() { ticker.Stop() }()
-
-	err = spinner.Start()
-	if err != nil {
-		return 0, err
-	}
-	msg := "Checking service availability"
-	spinner.Message(msg + generateTimeout(time.Until(end)))
-
 	expected := "non-500 status code"
-	if validStatusCodeRange(c.StatusCheckCode) {
+	if check.ValidStatusCode(c.StatusCheckCode) {
 		expected = fmt.Sprintf("%d status code", c.StatusCheckCode)
 	}
 
-	// Keep trying until we're timed out, got a result or got an error
-	for {
-		select {
-		case <-timeout:
-			returnedStatus := fmt.Sprintf(" (status: %d)", status)
-			spinner.StopFailMessage(msg + returnedStatus)
-			spinErr := spinner.StopFail()
-			if spinErr != nil {
-				return status, spinErr
-			}
-			return status, fsterr.RemediationError{
-				Inner:       errors.New("service not yet available"),
-				Remediation: fmt.Sprintf(remediation, "timed out", expected, status),
-			}
-		case t := <-ticker.C:
-			var (
-				ok  bool
-				err error
-			)
-			// We overwrite the `status` variable in the parent scope (defined in the
-			// return arguments list) so it can be used as part of both the timeout
-			// and success scenarios.
-			ok, status, err = pingServiceURL(serviceURL, c.Globals.HTTPClient, c.StatusCheckCode)
-			if err != nil {
-				returnedStatus := fmt.Sprintf(" (status: %d)", status)
-				spinner.StopFailMessage(msg + returnedStatus)
-				spinErr := spinner.StopFail()
-				if spinErr != nil {
-					return status, spinErr
-				}
-				return status, fsterr.RemediationError{
-					Inner:       err,
-					Remediation: fmt.Sprintf(remediation, "failed", expected, status),
-				}
-			} else if ok {
-				returnedStatus := fmt.Sprintf(" (status: %d)", status)
-				spinner.StopMessage(msg + returnedStatus)
-				return status, spinner.Stop()
-			}
-			// Service not available, and no error, so jump back to top of loop
-			spinner.Message(msg + generateTimeout(end.Sub(t)))
+	dur := time.Duration(c.StatusCheckTimeout) * time.Second
+	status, err = check.ServiceAvailability(serviceURL, c.Globals.HTTPClient, c.StatusCheckCode, dur, time.Second, spinner)
+	if errors.Is(err, check.ErrServiceUnavailable) {
+		return status, fsterr.RemediationError{
+			Inner:       err,
+			Remediation: fmt.Sprintf(remediation, "timed out", expected, status),
+		}
+	} else if err != nil {
+		return status, fsterr.RemediationError{
+			Inner:       err,
+			Remediation: fmt.Sprintf(remediation, "failed", expected, status),
 		}
 	}
-}
-
-// generateTimeout inserts a dynamically generated message on each tick.
-// It notifies the user what's happening and how long is left on the timer.
-func generateTimeout(d time.Duration) string {
-	remaining := fmt.Sprintf("timeout: %v", d.Round(time.Second))
-	return fmt.Sprintf(" (app deploying across Fastly's global network | %s)...", remaining)
-}
-
-// pingServiceURL indicates if the service returned a non-5xx response (or
-// whatever the user defined with --status-check-code), which should help
-// signify if the service is generally available.
-func pingServiceURL(serviceURL string, httpClient api.HTTPClient, expectedStatusCode int) (ok bool, status int, err error) {
-	req, err := http.NewRequest("GET", serviceURL, nil)
-	if err != nil {
-		return false, 0, err
-	}
-
-	// gosec flagged this:
-	// G107 (CWE-88): Potential HTTP request made with variable url
-	// Disabling as we trust the source of the variable.
-	// #nosec
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return false, 0, err
-	}
-	defer func() {
-		_ = resp.Body.Close()
-	}()
-
-	// We check for the user's defined status code expectation.
-	// Otherwise we'll default to checking for a non-500.
-	if validStatusCodeRange(expectedStatusCode) && resp.StatusCode == expectedStatusCode {
-		return true, resp.StatusCode, nil
-	} else if resp.StatusCode < http.StatusInternalServerError {
-		return true, resp.StatusCode, nil
-	}
-	return false, resp.StatusCode, nil
+	return status, nil
 }
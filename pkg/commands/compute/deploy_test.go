@@ -2,6 +2,7 @@ package compute_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -1917,6 +1918,402 @@ func TestDeploy(t *testing.T) {
 	}
 }
 
+// TestDeployPlan validates the --plan flag makes no mutating API calls.
+//
+// NOTE: The mock.API functions that would create, clone, upload or activate
+// are left undefined, so the test panics if any of them are called.
+func TestDeployPlan(t *testing.T) {
+	if os.Getenv("TEST_COMPUTE_DEPLOY") == "" {
+		t.Log("skipping test")
+		t.Skip("Set TEST_COMPUTE_DEPLOY to run this test")
+	}
+
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootdir := testutil.NewEnv(testutil.EnvOpts{
+		T: t,
+		Copy: []testutil.FileIO{
+			{
+				Src: filepath.Join("testdata", "deploy", "pkg", "package.tar.gz"),
+				Dst: filepath.Join("pkg", "package.tar.gz"),
+			},
+		},
+	})
+	defer os.RemoveAll(rootdir)
+
+	if err := os.Chdir(rootdir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(pwd)
+
+	args := testutil.Args
+	scenarios := []struct {
+		api            mock.API
+		args           []string
+		dontWantOutput []string
+		manifest       string
+		name           string
+		wantError      string
+		wantOutput     []string
+	}{
+		{
+			name:      "json without plan",
+			args:      args("compute deploy --token 123 --json"),
+			wantError: "invalid flag combination, --json without --plan",
+		},
+		{
+			name: "new service",
+			args: args("compute deploy --token 123 --plan --non-interactive"),
+			manifest: `
+			manifest_version = 2
+			name = "package"
+
+			[setup.backends.backend_name]
+			address = "developer.fastly.com"
+
+			[setup.config_stores.store_one.items.foo]
+			value = "my default value for foo"
+
+			[setup.log_endpoints.logger]
+			provider = "Splunk"
+			`,
+			wantOutput: []string{
+				"Deploy plan for a new service (package)",
+				"create    service       package",
+				"create    domain        (generated *.edgecompute.app)",
+				"create    backend       backend_name                   developer.fastly.com:443",
+				"create    config store  store_one                      1 item",
+				"manual    log endpoint  logger                         Splunk log endpoint must be created manually",
+				"upload    package       pkg/package.tar.gz",
+				"activate  version       1",
+				"No changes have been made.",
+			},
+		},
+		{
+			name: "new service with originless backend",
+			args: args("compute deploy --token 123 --plan --non-interactive --domain example.com"),
+			wantOutput: []string{
+				"Deploy plan for a new service (package)",
+				"create    domain    example.com",
+				"create    backend   originless          127.0.0.1:80",
+			},
+		},
+		{
+			name: "existing service with editable version",
+			args: args("compute deploy --service-id 123 --token 123 --plan --version latest --comment hello"),
+			api: mock.API{
+				GetPackageFn:        getPackageOk,
+				GetServiceDetailsFn: getServiceDetailsWasm,
+				ListDomainsFn:       listDomainsNone,
+				ListVersionsFn:      testutil.ListVersions,
+			},
+			wantOutput: []string{
+				"Deploy plan for service 123, version 3",
+				"create    domain    (prompted for)",
+				"upload    package   pkg/package.tar.gz",
+				"update    version   3",
+				"comment: hello",
+				"activate  version   3",
+			},
+			dontWantOutput: []string{
+				"clone",
+			},
+		},
+		{
+			name: "existing service with identical package",
+			args: args("compute deploy --service-id 123 --token 123 --plan"),
+			api: mock.API{
+				GetPackageFn:        getPackageIdentical,
+				GetServiceDetailsFn: getServiceDetailsWasm,
+				ListDomainsFn:       listDomainsOk,
+				ListVersionsFn:      testutil.ListVersions,
+			},
+			wantOutput: []string{
+				"clone   version   1",
+				"skip    package   pkg/package.tar.gz  identical to the package of version 1",
+			},
+			dontWantOutput: []string{
+				"activate",
+			},
+		},
+		{
+			name: "existing vcl service",
+			args: args("compute deploy --service-id 123 --token 123 --plan"),
+			api: mock.API{
+				GetServiceDetailsFn: func(*fastly.GetServiceInput) (*fastly.ServiceDetail, error) {
+					return &fastly.ServiceDetail{Type: "vcl"}, nil
+				},
+				ListVersionsFn: testutil.ListVersions,
+			},
+			wantError: "invalid service type: vcl",
+		},
+		{
+			name: "json",
+			args: args("compute deploy --service-id 123 --token 123 --plan --json"),
+			api: mock.API{
+				GetPackageFn:        getPackageOk,
+				GetServiceDetailsFn: getServiceDetailsWasm,
+				ListDomainsFn:       listDomainsOk,
+				ListVersionsFn:      testutil.ListVersions,
+			},
+			wantOutput: []string{
+				`"service_id": "123"`,
+				`"new_service": false`,
+				`"action": "clone"`,
+				`"action": "upload"`,
+				`"action": "activate"`,
+			},
+			dontWantOutput: []string{
+				"No changes have been made.",
+			},
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.name, func(t *testing.T) {
+			manifestContent := `manifest_version = 2
+			name = "package"
+			`
+			if testcase.manifest != "" {
+				manifestContent = testcase.manifest
+			}
+			if err := os.WriteFile(filepath.Join(rootdir, manifest.Filename), []byte(manifestContent), 0o777); err != nil {
+				t.Fatal(err)
+			}
+
+			var stdout threadsafe.Buffer
+			opts := testutil.NewRunOpts(testcase.args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.api)
+			err = app.Run(opts)
+			t.Log(stdout.String())
+
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			for _, s := range testcase.dontWantOutput {
+				testutil.AssertStringDoesntContain(t, stdout.String(), s)
+			}
+		})
+	}
+}
+
+func TestDeployCanary(t *testing.T) {
+	if os.Getenv("TEST_COMPUTE_DEPLOY") == "" {
+		t.Log("skipping test")
+		t.Skip("Set TEST_COMPUTE_DEPLOY to run this test")
+	}
+
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootdir := testutil.NewEnv(testutil.EnvOpts{
+		T: t,
+		Copy: []testutil.FileIO{
+			{
+				Src: filepath.Join("testdata", "deploy", "pkg", "package.tar.gz"),
+				Dst: filepath.Join("pkg", "package.tar.gz"),
+			},
+		},
+		Write: []testutil.FileIO{
+			{
+				Src: `manifest_version = 2
+				name = "package"
+				service_id = "123"
+				`,
+				Dst: manifest.Filename,
+			},
+		},
+	})
+	defer os.RemoveAll(rootdir)
+
+	if err := os.Chdir(rootdir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(pwd)
+
+	// percents records the values the canary config store item is set to.
+	var percents []string
+
+	listConfigStores := func() ([]*fastly.ConfigStore, error) {
+		return []*fastly.ConfigStore{{ID: "store-123", Name: "canary"}}, nil
+	}
+	listConfigStoreServices := func(i *fastly.ListConfigStoreServicesInput) ([]*fastly.Service, error) {
+		return []*fastly.Service{{ID: "123"}}, nil
+	}
+	updateConfigStoreItem := func(i *fastly.UpdateConfigStoreItemInput) (*fastly.ConfigStoreItem, error) {
+		percents = append(percents, i.Value)
+		return &fastly.ConfigStoreItem{StoreID: i.StoreID, Key: i.Key, Value: i.Value}, nil
+	}
+	deployAPI := mock.API{
+		ActivateVersionFn:         activateVersionOk,
+		CloneVersionFn:            testutil.CloneVersionResult(4),
+		GetPackageFn:              getPackageOk,
+		GetServiceDetailsFn:       getServiceDetailsWasm,
+		ListConfigStoresFn:        listConfigStores,
+		ListConfigStoreServicesFn: listConfigStoreServices,
+		ListVersionsFn:            testutil.ListVersions,
+		UpdateConfigStoreItemFn:   updateConfigStoreItem,
+		UpdatePackageFn:           updatePackageOk,
+	}
+	realtimeStats := func(requests, errors int) mock.RealtimeStats {
+		return mock.RealtimeStats{
+			GetRealtimeStatsJSONFn: func(i *fastly.GetRealtimeStatsInput, dst any) error {
+				if i.ServiceID != "456" {
+					return fmt.Errorf("unexpected service: %s", i.ServiceID)
+				}
+				// Only the first second of stats for each step has any requests.
+				// It follows a second recorded before the step, which is ignored.
+				if i.Timestamp > 0 {
+					return json.Unmarshal([]byte(`{"timestamp": 2, "data": []}`), dst)
+				}
+				return json.Unmarshal([]byte(fmt.Sprintf(`{"timestamp": 1, "data": [{"recorded": 1, "aggregated": {"requests": 1000, "status_5xx": 1000}}, {"recorded": %d, "aggregated": {"requests": %d, "status_5xx": %d}}]}`, time.Now().Unix(), requests, errors)), dst)
+			},
+		}
+	}
+
+	args := testutil.Args
+	scenarios := []struct {
+		api          mock.API
+		args         []string
+		name         string
+		rts          mock.RealtimeStats
+		wantError    string
+		wantOutput   []string
+		wantPercents []string
+	}{
+		{
+			name:      "no canary service",
+			args:      args("compute deploy --token 123 --canary 10%"),
+			wantError: "error parsing --canary: no canary service",
+		},
+		{
+			name:      "invalid percentage",
+			args:      args("compute deploy --token 123 --canary 0% --canary-service-id 456"),
+			wantError: "error parsing --canary: invalid percentage '0%'",
+		},
+		{
+			name:      "plan",
+			args:      args("compute deploy --token 123 --canary 10% --canary-service-id 456 --plan"),
+			wantError: "invalid flag combination, --canary and --plan",
+		},
+		{
+			name: "config store not found",
+			args: args("compute deploy --token 123 --canary 10% --canary-service-id 456 --canary-config-store split"),
+			api: mock.API{
+				ListConfigStoresFn: listConfigStores,
+			},
+			wantError: "error finding config store 'split'",
+		},
+		{
+			name: "config store not linked",
+			args: args("compute deploy --token 123 --canary 10% --canary-service-id 456"),
+			api: mock.API{
+				ListConfigStoresFn: listConfigStores,
+				ListConfigStoreServicesFn: func(i *fastly.ListConfigStoreServicesInput) ([]*fastly.Service, error) {
+					return []*fastly.Service{}, nil
+				},
+			},
+			wantError: "config store 'canary' is not linked to service 123",
+		},
+		{
+			name:      "canary is the primary service",
+			args:      args("compute deploy --token 123 --canary 10% --canary-service-id 123"),
+			wantError: "error parsing --canary-service-id: service 123 is the primary service",
+		},
+		{
+			name: "promote",
+			args: args("compute deploy --token 123 --canary 50% --canary-step 25% --canary-service-id 456 --canary-interval 1ms"),
+			api:  deployAPI,
+			rts:  realtimeStats(200, 1),
+			wantOutput: []string{
+				"Deploying the package to the canary service 456",
+				"Routing 50% of traffic to the canary service",
+				"Canary error rate: 0.50% (1 of 200 requests)",
+				"Routing 75% of traffic to the canary service",
+				"Promoting the package to service 123",
+				"Promoted canary package (service 123)",
+			},
+			wantPercents: []string{"50", "75", "0"},
+		},
+		{
+			name:      "abort",
+			args:      args("compute deploy --token 123 --canary 10% --canary-service-id 456 --canary-interval 1ms"),
+			api:       deployAPI,
+			rts:       realtimeStats(100, 5),
+			wantError: "canary aborted: error rate 5.00% exceeded 1.00%",
+			wantOutput: []string{
+				"Routing 10% of traffic to the canary service",
+				"Routing all traffic back to service 123",
+			},
+			wantPercents: []string{"10", "0"},
+		},
+		{
+			name:      "no requests",
+			args:      args("compute deploy --token 123 --canary 10% --canary-service-id 456 --canary-interval 1ms"),
+			api:       deployAPI,
+			rts:       realtimeStats(0, 0),
+			wantError: "canary aborted: no requests were received by the canary service",
+			wantOutput: []string{
+				"Routing 10% of traffic to the canary service",
+				"Routing all traffic back to service 123",
+			},
+			wantPercents: []string{"10", "0"},
+		},
+		{
+			name: "interrupted",
+			args: args("compute deploy --token 123 --canary 10% --canary-service-id 456 --canary-interval 5s"),
+			api:  deployAPI,
+			rts: mock.RealtimeStats{
+				GetRealtimeStatsJSONFn: func(i *fastly.GetRealtimeStatsInput, dst any) error {
+					if i.Timestamp == 0 {
+						p, err := os.FindProcess(os.Getpid())
+						if err != nil {
+							return err
+						}
+						if err := p.Signal(os.Interrupt); err != nil {
+							return err
+						}
+					}
+					return json.Unmarshal([]byte(`{"timestamp": 1, "data": [{"aggregated": {"requests": 100, "status_5xx": 0}}]}`), dst)
+				},
+			},
+			wantError: "canary aborted: interrupted",
+			wantOutput: []string{
+				"Routing 10% of traffic to the canary service",
+				"Routing all traffic back to service 123",
+			},
+			wantPercents: []string{"10", "0"},
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.name, func(t *testing.T) {
+			percents = nil
+
+			var stdout threadsafe.Buffer
+			opts := testutil.NewRunOpts(testcase.args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.api)
+			opts.RTSClient = mock.RTSClient(testcase.rts)
+			err = app.Run(opts)
+			t.Log(stdout.String())
+
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			if testcase.wantPercents != nil {
+				testutil.AssertEqual(t, testcase.wantPercents, percents)
+			}
+		})
+	}
+}
+
 func createServiceOK(i *fastly.CreateServiceInput) (*fastly.Service, error) {
 	return &fastly.Service{
 		ID:   "12345",
@@ -0,0 +1,427 @@
+package compute
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/revision"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// har is an HTTP Archive, the format used for recorded traffic.
+//
+// Only the fields the CLI uses are modelled.
+// https://w3c.github.io/web-performance/specs/HAR/Overview.html
+type har struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	Cookies     []harNameValue `json:"cookies"`
+	PostData    *harContent    `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	Cookies     []harNameValue `json:"cookies"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+// harContent models both the postData of a request and the content of a
+// response. Binary bodies are base64 encoded.
+//
+// NOTE: Bodies larger than maxRecordedBody are truncated, in which case Size
+// is larger than the decoded Text.
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Encoding string `json:"encoding,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// body returns the decoded content.
+func (c harContent) body() ([]byte, error) {
+	if c.Encoding == "base64" {
+		return base64.StdEncoding.DecodeString(c.Text)
+	}
+	return []byte(c.Text), nil
+}
+
+// header returns the first value of the named header.
+func header(headers []harNameValue, name string) string {
+	for _, h := range headers {
+		if strings.EqualFold(h.Name, name) {
+			return h.Value
+		}
+	}
+	return ""
+}
+
+// newHARContent encodes a body of the given size, of which only the recorded
+// prefix is provided, falling back to base64 for binary data.
+func newHARContent(body []byte, size int, mimeType string) harContent {
+	c := harContent{
+		Size:     size,
+		MimeType: mimeType,
+	}
+	if size > len(body) {
+		c.Comment = fmt.Sprintf("Truncated to the first %d bytes", len(body))
+	}
+	if utf8.Valid(body) {
+		c.Text = string(body)
+	} else {
+		c.Text = base64.StdEncoding.EncodeToString(body)
+		c.Encoding = "base64"
+	}
+	return c
+}
+
+// harHeaders converts the headers to name/value pairs, sorted by name.
+func harHeaders(h http.Header) []harNameValue {
+	nv := make([]harNameValue, 0, len(h))
+	for _, name := range sortedKeys(h) {
+		for _, v := range h[name] {
+			nv = append(nv, harNameValue{Name: name, Value: v})
+		}
+	}
+	return nv
+}
+
+// harQueryString converts the query parameters to name/value pairs.
+func harQueryString(u *url.URL) []harNameValue {
+	q := u.Query()
+	nv := make([]harNameValue, 0, len(q))
+	for _, name := range sortedKeys(q) {
+		for _, v := range q[name] {
+			nv = append(nv, harNameValue{Name: name, Value: v})
+		}
+	}
+	return nv
+}
+
+// readHAR reads the recorded traffic at path.
+func readHAR(path string) (*har, error) {
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as we need to load the recording provided by the user.
+	/* #nosec */
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var h har
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("invalid HAR file: %w", err)
+	}
+	return &h, nil
+}
+
+// maxRecordedBody is the size of the largest request or response body that's
+// recorded in full. Larger bodies are still proxied in full.
+const maxRecordedBody = 10 << 20
+
+// shutdownTimeout is how long stopping the recording proxy waits for the
+// requests in flight to complete.
+const shutdownTimeout = 5 * time.Second
+
+// trafficRecorder is a reverse proxy in front of the local server that
+// records each request and response to a HAR file.
+//
+// NOTE: The entries are kept in memory and the file is written once, when the
+// recording stops. It's written to a temporary file created when the
+// recording starts (so an unwritable path is reported straight away) and
+// then renamed, so an existing recording is never left half written.
+type trafficRecorder struct {
+	errLog fsterr.LogInterface
+	out    io.Writer
+	path   string
+	tmp    *os.File
+
+	mu  sync.Mutex
+	har har
+}
+
+// recordTraffic starts a recording proxy listening on addr that forwards
+// requests to the address of the local server returned by target. The
+// returned function stops it and writes the HAR file.
+func recordTraffic(addr string, target func() string, path string, errLog fsterr.LogInterface, out io.Writer) (func(), error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		errLog.Add(err)
+		return nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("error listening on %s: %w", addr, err),
+			Remediation: "Check the --addr address is valid and not already in use.",
+		}
+	}
+
+	r := &trafficRecorder{
+		errLog: errLog,
+		out:    out,
+		path:   path,
+		har: har{Log: harLog{
+			Version: "1.2",
+			Creator: harCreator{Name: "Fastly CLI", Version: revision.AppVersion},
+			Entries: []harEntry{},
+		}},
+	}
+	r.tmp, err = os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		errLog.Add(err)
+		_ = l.Close()
+		return nil, fmt.Errorf("error writing %s: %w", path, err)
+	}
+
+	srv := &http.Server{
+		Handler:           r.proxy(target),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		_ = srv.Serve(l)
+	}()
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			_ = srv.Close()
+		}
+		if err := r.save(); err != nil {
+			text.Warning(r.out, "Failed to save the recorded traffic to %s: %v", r.path, err)
+		}
+	}, nil
+}
+
+// proxy returns a reverse proxy to target that records each exchange.
+func (r *trafficRecorder) proxy(target func() string) http.Handler {
+	type exchange struct {
+		started time.Time
+		body    []byte
+		size    int
+	}
+	type exchangeKey struct{}
+
+	p := newLocalProxy(target)
+	p.ModifyResponse = func(resp *http.Response) error {
+		x, _ := resp.Request.Context().Value(exchangeKey{}).(*exchange)
+		// NOTE: The response is recorded once the proxy has streamed its body
+		// to the client and closed it.
+		resp.Body = &recordedBody{
+			ReadCloser: resp.Body,
+			done: func(body *recordedBody) {
+				r.add(resp, x.started, x.body, x.size, body.buf.Bytes(), body.size)
+			},
+		}
+		return nil
+	}
+	p.ErrorHandler = func(w http.ResponseWriter, req *http.Request, err error) {
+		// NOTE: Requests made while the local server is (re)starting aren't recorded.
+		text.Warning(r.out, "Not recorded: %s %s: %v", req.Method, req.URL.RequestURI(), err)
+		w.WriteHeader(http.StatusBadGateway)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		x := &exchange{started: time.Now()}
+		if req.Body != nil {
+			// Only the recorded prefix is read up front, the rest is streamed.
+			body, err := io.ReadAll(io.LimitReader(req.Body, maxRecordedBody))
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			x.body, x.size = body, len(body)
+			if req.ContentLength > int64(x.size) {
+				x.size = int(req.ContentLength)
+			}
+			req.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(body), req.Body), req.Body}
+		}
+		p.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), exchangeKey{}, x)))
+	})
+}
+
+// add records the exchange, with the recorded prefix and size of each body.
+func (r *trafficRecorder) add(resp *http.Response, started time.Time, reqBody []byte, reqSize int, respBody []byte, respSize int) {
+	req := resp.Request
+
+	// NOTE: The outgoing request is addressed to the local server, so the URL
+	// and headers are rebuilt from what the client sent.
+	u := *req.URL
+	u.Scheme = "http"
+	u.Host = req.Host
+	reqHeaders := req.Header.Clone()
+	reqHeaders.Set("Host", req.Host)
+	reqHeaders.Del("X-Forwarded-For")
+
+	elapsed := float64(time.Since(started)) / float64(time.Millisecond)
+	e := harEntry{
+		StartedDateTime: started.UTC().Format(time.RFC3339Nano),
+		Time:            elapsed,
+		Request: harRequest{
+			Method:      req.Method,
+			URL:         u.String(),
+			HTTPVersion: req.Proto,
+			Headers:     harHeaders(reqHeaders),
+			QueryString: harQueryString(&u),
+			Cookies:     []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    reqSize,
+		},
+		Response: harResponse{
+			Status:      resp.StatusCode,
+			StatusText:  http.StatusText(resp.StatusCode),
+			HTTPVersion: resp.Proto,
+			Headers:     harHeaders(resp.Header),
+			Cookies:     []harNameValue{},
+			Content:     newHARContent(respBody, respSize, resp.Header.Get("Content-Type")),
+			HeadersSize: -1,
+			BodySize:    respSize,
+		},
+		Timings: harTimings{Wait: elapsed},
+	}
+	if len(reqBody) > 0 {
+		pd := newHARContent(reqBody, reqSize, req.Header.Get("Content-Type"))
+		e.Request.PostData = &pd
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.har.Log.Entries = append(r.har.Log.Entries, e)
+}
+
+// save writes the recorded entries to the temporary file and renames it to
+// the HAR file.
+func (r *trafficRecorder) save() (err error) {
+	defer func() {
+		if err != nil {
+			r.errLog.Add(err)
+			_ = os.Remove(r.tmp.Name())
+		}
+	}()
+
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.har, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		_ = r.tmp.Close()
+		return err
+	}
+	if _, err = r.tmp.Write(append(data, '\n')); err != nil {
+		_ = r.tmp.Close()
+		return err
+	}
+	if err = r.tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(r.tmp.Name(), r.path)
+}
+
+// recordedBody is a body that's copied into buf as it's read, up to
+// maxRecordedBody, calling done once it's closed.
+type recordedBody struct {
+	io.ReadCloser
+	buf  bytes.Buffer
+	size int
+	done func(*recordedBody)
+	once sync.Once
+}
+
+// Read implements the io.Reader interface.
+func (b *recordedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.size += n
+	if room := maxRecordedBody - b.buf.Len(); room > 0 {
+		if n < room {
+			room = n
+		}
+		b.buf.Write(p[:room])
+	}
+	return n, err
+}
+
+// Close implements the io.Closer interface.
+func (b *recordedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() { b.done(b) })
+	return err
+}
+
+// freeAddr returns a local address with a port that's free to listen on.
+func freeAddr() (string, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	addr := l.Addr().String()
+	return addr, l.Close()
+}
+
+// sortedHeaderNames returns the canonical form of the header names, sorted.
+func sortedHeaderNames(names []string) []string {
+	canonical := make([]string, 0, len(names))
+	for _, n := range names {
+		canonical = append(canonical, http.CanonicalHeaderKey(n))
+	}
+	sort.Strings(canonical)
+	return canonical
+}
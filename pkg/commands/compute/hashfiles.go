@@ -42,6 +42,7 @@ func NewHashFilesCommand(parent cmd.Registerer, g *global.Data, build *BuildComm
 	c.Globals = g
 	c.Manifest = m
 	c.CmdClause = parent.Command("hash-files", "Generate a SHA512 digest from the contents of the Compute@Edge package")
+	c.CmdClause.Flag("env", "The environment configuration to use (e.g. stage)").StringVar(&c.Manifest.Flag.Env)
 	c.CmdClause.Flag("package", "Path to a package tar.gz").Short('p').StringVar(&c.Package)
 	c.CmdClause.Flag("skip-build", "Skip the build step").BoolVar(&c.SkipBuild)
 	return &c
@@ -81,6 +82,9 @@ func (c *HashFilesCommand) Build(in io.Reader, out io.Writer) error {
 	if !c.Globals.Verbose() {
 		output = io.Discard
 	}
+	if c.Manifest.Flag.Env != "" {
+		c.buildCmd.Manifest.Flag.Env = c.Manifest.Flag.Env
+	}
 	return c.buildCmd.Exec(in, output)
 }
 
@@ -0,0 +1,415 @@
+package compute
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fastly/go-fastly/v8/fastly"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// LocalStoresDir is the default directory, relative to the manifest, that
+// store contents are pulled into.
+const LocalStoresDir = "local-stores"
+
+// SecretPlaceholder is the value written for secrets that weren't provided.
+//
+// NOTE: The Fastly API never returns the value of a secret.
+const SecretPlaceholder = "REPLACE_WITH_LOCAL_SECRET"
+
+// LocalStoresRootCommand is the parent command for managing the stores used by
+// the local testing server.
+type LocalStoresRootCommand struct {
+	cmd.Base
+	// no flags
+}
+
+// NewLocalStoresRootCommand returns a new command registered in the parent.
+func NewLocalStoresRootCommand(parent cmd.Registerer, g *global.Data) *LocalStoresRootCommand {
+	var c LocalStoresRootCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("local-stores", "Manage the stores used by the local testing server")
+	return &c
+}
+
+// Exec implements the command interface.
+func (c *LocalStoresRootCommand) Exec(_ io.Reader, _ io.Writer) error {
+	panic("unreachable")
+}
+
+// LocalStoresPullCommand seeds the local testing server's stores from those
+// linked to a service version.
+type LocalStoresPullCommand struct {
+	cmd.Base
+
+	dir            string
+	manifest       manifest.Data
+	serviceName    cmd.OptionalServiceNameID
+	serviceVersion cmd.OptionalServiceVersion
+}
+
+// NewLocalStoresPullCommand returns a usable command registered under the parent.
+func NewLocalStoresPullCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *LocalStoresPullCommand {
+	var c LocalStoresPullCommand
+	c.Globals = g
+	c.manifest = m
+	c.CmdClause = parent.Command("pull", "Download the contents of the stores linked to a service into local files used by `compute serve`")
+	c.CmdClause.Flag("dir", "Directory, relative to the manifest, to write the store contents to").Default(LocalStoresDir).StringVar(&c.dir)
+	c.CmdClause.Flag("env", "The environment configuration to use (e.g. stage)").StringVar(&c.manifest.Flag.Env)
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagVersionName,
+		Description: cmd.FlagVersionDesc,
+		Dst:         &c.serviceVersion.Value,
+	})
+	return &c
+}
+
+// Exec implements the command interface.
+func (c *LocalStoresPullCommand) Exec(in io.Reader, out io.Writer) error {
+	if err := c.manifest.ApplyEnv(); err != nil {
+		return err
+	}
+
+	serviceID, serviceVersion, err := cmd.ServiceDetails(cmd.ServiceDetailsOpts{
+		AllowActiveLocked:  true,
+		APIClient:          c.Globals.APIClient,
+		Manifest:           c.manifest,
+		Out:                out,
+		ServiceNameFlag:    c.serviceName,
+		ServiceVersionFlag: c.serviceVersion,
+		VerboseMode:        c.Globals.Flags.Verbose,
+		ErrLog:             c.Globals.ErrLog,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": fsterr.ServiceVersion(serviceVersion),
+		})
+		return err
+	}
+
+	return pullLocalStores(c.Globals, serviceID, serviceVersion.Number, c.manifest.Env(), c.dir, in, out)
+}
+
+// pullLocalStores downloads the contents of the stores linked to the service
+// version into dir, and references them from the [local_server] section of
+// the manifest (that of the environment's overlay if env is set, so the
+// fastly.toml is left as is).
+//
+// Config stores are written to a JSON file and each KV store key to its own
+// file. Secret values can't be read from the API, so the user is prompted for
+// each one that isn't already defined in the manifest, falling back to a
+// placeholder.
+func pullLocalStores(g *global.Data, serviceID string, serviceVersion int, env, dir string, in io.Reader, out io.Writer) error {
+	resources, err := g.APIClient.ListResources(&fastly.ListResourcesInput{
+		ServiceID:      serviceID,
+		ServiceVersion: serviceVersion,
+	})
+	if err != nil {
+		errLogService(g.ErrLog, err, serviceID, serviceVersion)
+		return fmt.Errorf("error listing the resources linked to service %s, version %d: %w", serviceID, serviceVersion, err)
+	}
+
+	manifestPath := manifest.EnvFilename(env)
+	local, save, err := readLocalServer(g, env, out)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", manifestPath, err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	p := storePuller{
+		g:       g,
+		base:    wd,
+		dir:     dir,
+		in:      in,
+		out:     out,
+		local:   local,
+		prompt:  !g.Flags.AcceptDefaults && !g.Flags.NonInteractive,
+		summary: text.NewTable(out),
+	}
+	p.summary.AddHeader("NAME", "TYPE", "ENTRIES", "PATH")
+
+	var pulled int
+	for _, r := range resources {
+		var err error
+		switch {
+		case strings.HasPrefix(r.ResourceType, "config"):
+			err = p.configStore(r)
+		case strings.HasPrefix(r.ResourceType, "kv"), strings.HasPrefix(r.ResourceType, "object"):
+			err = p.kvStore(r)
+		case strings.HasPrefix(r.ResourceType, "secret"):
+			err = p.secretStore(r)
+		default:
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		pulled++
+	}
+
+	if pulled == 0 {
+		text.Info(out, "Service %s, version %d has no linked stores.", serviceID, serviceVersion)
+		return nil
+	}
+
+	if err := save(); err != nil {
+		g.ErrLog.Add(err)
+		return fmt.Errorf("error saving %s: %w", manifestPath, err)
+	}
+
+	text.Info(out, "Pulled the stores linked to service %s, version %d", serviceID, serviceVersion)
+	p.summary.Print()
+	text.Break(out)
+	text.Success(out, "Updated the [local_server] section of %s", manifestPath)
+	text.Info(out, "The %s directory may contain production data and secrets. Consider adding it to your .gitignore.", dir)
+	return nil
+}
+
+// readLocalServer reads the [local_server] section of the manifest the stores
+// are referenced from, returning a function that saves it once updated.
+func readLocalServer(g *global.Data, env string, out io.Writer) (*manifest.LocalServer, func() error, error) {
+	if env != "" {
+		ls, err := manifest.EnvLocalServer(env)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &ls, func() error {
+			return manifest.WriteEnvLocalServer(env, ls)
+		}, nil
+	}
+
+	var f manifest.File
+	f.SetErrLog(g.ErrLog)
+	f.SetOutput(out)
+	if err := f.Read(manifest.Filename); err != nil {
+		return nil, nil, err
+	}
+	return &f.LocalServer, func() error {
+		return f.Write(manifest.Filename)
+	}, nil
+}
+
+// storePuller writes the contents of linked stores to disk.
+type storePuller struct {
+	g       *global.Data
+	base    string // the directory of the manifest.
+	dir     string // relative to base, unless absolute.
+	in      io.Reader
+	out     io.Writer
+	local   *manifest.LocalServer
+	prompt  bool
+	summary *text.Table
+}
+
+// path returns the path of a pulled file as referenced from the manifest,
+// along with its location on disk.
+func (p storePuller) path(elem ...string) (string, string) {
+	ref := filepath.Join(append([]string{p.dir}, elem...)...)
+	if filepath.IsAbs(ref) {
+		return ref, ref
+	}
+	return filepath.ToSlash(ref), filepath.Join(p.base, ref)
+}
+
+// configStore writes the items of a config store to a JSON file.
+func (p storePuller) configStore(r *fastly.Resource) error {
+	items, err := p.g.APIClient.ListConfigStoreItems(&fastly.ListConfigStoreItemsInput{
+		StoreID: r.ResourceID,
+	})
+	if err != nil {
+		p.g.ErrLog.AddWithContext(err, map[string]any{
+			"Store ID": r.ResourceID,
+		})
+		return fmt.Errorf("error listing the items of config store '%s': %w", r.Name, err)
+	}
+
+	contents := make(map[string]string, len(items))
+	for _, item := range items {
+		contents[item.Key] = item.Value
+	}
+	data, err := json.MarshalIndent(contents, "", "  ")
+	if err != nil {
+		p.g.ErrLog.Add(err)
+		return fmt.Errorf("error encoding config store '%s': %w", r.Name, err)
+	}
+
+	ref, path := p.path("config", r.Name+".json")
+	if err := p.write(path, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+
+	if p.local.ConfigStores == nil {
+		p.local.ConfigStores = make(map[string]manifest.LocalConfigStore)
+	}
+	p.local.ConfigStores[r.Name] = manifest.LocalConfigStore{
+		File:   ref,
+		Format: "json",
+	}
+	p.summary.AddLine(r.Name, "config store", len(contents), ref)
+	return nil
+}
+
+// kvStore writes each key of a KV store to its own file.
+//
+// NOTE: Keys are escaped so they can contain characters (such as a slash)
+// that aren't valid in a file name.
+func (p storePuller) kvStore(r *fastly.Resource) error {
+	var keys []string
+	pager := p.g.APIClient.NewListKVStoreKeysPaginator(&fastly.ListKVStoreKeysInput{
+		ID: r.ResourceID,
+	})
+	for pager.Next() {
+		keys = append(keys, pager.Keys()...)
+	}
+	if err := pager.Err(); err != nil {
+		p.g.ErrLog.AddWithContext(err, map[string]any{
+			"Store ID": r.ResourceID,
+		})
+		return fmt.Errorf("error listing the keys of KV store '%s': %w", r.Name, err)
+	}
+
+	ref, path := p.path("kv", r.Name)
+	if err := os.RemoveAll(path); err != nil {
+		p.g.ErrLog.Add(err)
+		return fmt.Errorf("error removing previously pulled KV store '%s': %w", r.Name, err)
+	}
+
+	entries := make([]manifest.LocalKVStore, 0, len(keys))
+	for _, key := range keys {
+		value, err := p.g.APIClient.GetKVStoreKey(&fastly.GetKVStoreKeyInput{
+			ID:  r.ResourceID,
+			Key: key,
+		})
+		if err != nil {
+			p.g.ErrLog.AddWithContext(err, map[string]any{
+				"Store ID": r.ResourceID,
+				"Key":      key,
+			})
+			return fmt.Errorf("error reading key '%s' of KV store '%s': %w", key, r.Name, err)
+		}
+		keyRef, keyPath := p.path("kv", r.Name, url.PathEscape(key))
+		if err := p.write(keyPath, []byte(value), 0o644); err != nil {
+			return err
+		}
+		entries = append(entries, manifest.LocalKVStore{Key: key, File: keyRef})
+	}
+
+	if p.local.KVStores == nil {
+		p.local.KVStores = make(map[string][]manifest.LocalKVStore)
+	}
+	p.local.KVStores[r.Name] = entries
+	p.summary.AddLine(r.Name, "kv store", len(entries), ref)
+	return nil
+}
+
+// secretStore writes a file for each secret in a secret store.
+//
+// Secrets already defined in the manifest are kept as they are.
+func (p storePuller) secretStore(r *fastly.Resource) error {
+	var names []string
+	input := &fastly.ListSecretsInput{ID: r.ResourceID}
+	for {
+		secrets, err := p.g.APIClient.ListSecrets(input)
+		if err != nil {
+			p.g.ErrLog.AddWithContext(err, map[string]any{
+				"Store ID": r.ResourceID,
+			})
+			return fmt.Errorf("error listing the secrets of secret store '%s': %w", r.Name, err)
+		}
+		for _, s := range secrets.Data {
+			names = append(names, s.Name)
+		}
+		if secrets.Meta.NextCursor == "" {
+			break
+		}
+		input.Cursor = secrets.Meta.NextCursor
+	}
+
+	existing := make(map[string]manifest.LocalSecretStore)
+	for _, s := range p.local.SecretStores[r.Name] {
+		existing[s.Key] = s
+	}
+
+	ref, _ := p.path("secret", r.Name)
+	entries := make([]manifest.LocalSecretStore, 0, len(names))
+	var placeholders int
+	for _, name := range names {
+		if s, ok := existing[name]; ok {
+			entries = append(entries, s)
+			continue
+		}
+
+		value := SecretPlaceholder
+		if p.prompt {
+			v, err := text.InputSecure(p.out, text.BoldYellow(fmt.Sprintf("Value for secret '%s' of secret store '%s' (leave blank for a placeholder): ", name, r.Name)), p.in)
+			if err != nil {
+				return fmt.Errorf("error reading input: %w", err)
+			}
+			if v != "" {
+				value = v
+			}
+		}
+		if value == SecretPlaceholder {
+			placeholders++
+		}
+
+		secretRef, secretPath := p.path("secret", r.Name, url.PathEscape(name))
+		if err := p.write(secretPath, []byte(value), 0o600); err != nil {
+			return err
+		}
+		entries = append(entries, manifest.LocalSecretStore{Key: name, File: secretRef})
+	}
+
+	if p.local.SecretStores == nil {
+		p.local.SecretStores = make(map[string][]manifest.LocalSecretStore)
+	}
+	p.local.SecretStores[r.Name] = entries
+	p.summary.AddLine(r.Name, "secret store", len(entries), ref)
+	if placeholders > 0 {
+		text.Warning(p.out, "%d secret(s) of secret store '%s' were given the placeholder value %s. Replace them in %s before testing code that reads them.", placeholders, r.Name, SecretPlaceholder, ref)
+	}
+	return nil
+}
+
+// write writes the data to path, creating any missing parent directories.
+func (p storePuller) write(path string, data []byte, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		p.g.ErrLog.Add(err)
+		return fmt.Errorf("error creating directory for %s: %w", path, err)
+	}
+	// gosec flagged this:
+	// G306 (CWE-276): Expect WriteFile permissions to be 0600 or less
+	// Disabling as store contents are read by the local testing server.
+	/* #nosec */
+	if err := os.WriteFile(path, data, perm); err != nil {
+		p.g.ErrLog.Add(err)
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package compute_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fastly/go-fastly/v8/fastly"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/compute"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestLocalStoresPull(t *testing.T) {
+	args := testutil.Args
+	stores := mock.API{
+		ListVersionsFn: testutil.ListVersions,
+		ListResourcesFn: func(i *fastly.ListResourcesInput) ([]*fastly.Resource, error) {
+			return []*fastly.Resource{
+				{Name: "settings", ResourceID: "cfg", ResourceType: "config"},
+				{Name: "assets", ResourceID: "kv", ResourceType: "kv-store"},
+				{Name: "credentials", ResourceID: "sec", ResourceType: "secret-store"},
+			}, nil
+		},
+		ListConfigStoreItemsFn: func(i *fastly.ListConfigStoreItemsInput) ([]*fastly.ConfigStoreItem, error) {
+			return []*fastly.ConfigStoreItem{
+				{StoreID: i.StoreID, Key: "colour", Value: "blue"},
+			}, nil
+		},
+		NewListKVStoreKeysPaginatorFn: func(i *fastly.ListKVStoreKeysInput) fastly.PaginatorKVStoreEntries {
+			return &kvKeysPaginator{keys: []string{"index.html", "img/logo.svg"}}
+		},
+		GetKVStoreKeyFn: func(i *fastly.GetKVStoreKeyInput) (string, error) {
+			return "contents of " + i.Key, nil
+		},
+		ListSecretsFn: func(i *fastly.ListSecretsInput) (*fastly.Secrets, error) {
+			if i.Cursor == "" {
+				return &fastly.Secrets{
+					Data: []fastly.Secret{{Name: "existing"}},
+					Meta: fastly.SecretStoreMeta{NextCursor: "next"},
+				}, nil
+			}
+			return &fastly.Secrets{Data: []fastly.Secret{{Name: "api-key"}}}, nil
+		},
+	}
+
+	scenarios := []struct {
+		testutil.TestScenario
+		stdin           string
+		wantFiles       map[string]string
+		wantManifest    []string
+		notWantManifest []string
+		wantOverlay     []string
+	}{
+		{
+			TestScenario: testutil.TestScenario{
+				Name:      "validate missing service ID",
+				Args:      args("compute local-stores pull"),
+				WantError: "error reading service: no service ID found",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "validate ListResources error",
+				API: mock.API{
+					ListVersionsFn: testutil.ListVersions,
+					ListResourcesFn: func(i *fastly.ListResourcesInput) ([]*fastly.Resource, error) {
+						return nil, errors.New("fixture error")
+					},
+				},
+				Args:      args("compute local-stores pull --service-id 123 --version 1"),
+				WantError: "error listing the resources linked to service 123, version 1: fixture error",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "no linked stores",
+				API: mock.API{
+					ListVersionsFn: testutil.ListVersions,
+					ListResourcesFn: func(i *fastly.ListResourcesInput) ([]*fastly.Resource, error) {
+						return nil, nil
+					},
+				},
+				Args:        args("compute local-stores pull --service-id 123"),
+				WantOutputs: []string{"Service 123, version 1 has no linked stores."},
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "success with placeholders",
+				API:  stores,
+				Args: args("compute local-stores pull --service-id 123 --non-interactive"),
+				WantOutputs: []string{
+					"Pulled the stores linked to service 123, version 1",
+					"settings     config store  1        local-stores/config/settings.json",
+					"assets       kv store      2        local-stores/kv/assets",
+					"credentials  secret store  2        local-stores/secret/credentials",
+					"1 secret(s) of secret store 'credentials' were given the placeholder value " + compute.SecretPlaceholder,
+					"Updated the [local_server] section of fastly.toml",
+				},
+			},
+			wantFiles: map[string]string{
+				"local-stores/config/settings.json":       "{\n  \"colour\": \"blue\"\n}\n",
+				"local-stores/kv/assets/index.html":       "contents of index.html",
+				"local-stores/kv/assets/img%2Flogo.svg":   "contents of img/logo.svg",
+				"local-stores/secret/credentials/api-key": compute.SecretPlaceholder,
+			},
+			wantManifest: []string{
+				"[local_server.config_stores.settings]\n      file = \"local-stores/config/settings.json\"\n      format = \"json\"",
+				"file = \"local-stores/kv/assets/img%2Flogo.svg\"\n      key = \"img/logo.svg\"",
+				"data = \"local value\"\n      key = \"existing\"",
+				"file = \"local-stores/secret/credentials/api-key\"\n      key = \"api-key\"",
+			},
+			notWantManifest: []string{"stale"},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "success with environment",
+				API:  stores,
+				Args: args("compute local-stores pull --env stage --non-interactive"),
+				WantOutputs: []string{
+					"Pulled the stores linked to service 123, version 1",
+					"Updated the [local_server] section of fastly.stage.toml",
+				},
+			},
+			// NOTE: The fastly.toml is left as is.
+			wantManifest:    []string{"key = \"stale\""},
+			notWantManifest: []string{"local-stores/config/settings.json"},
+			wantOverlay: []string{
+				"service_id = \"123\"",
+				"[local_server.config_stores.settings]",
+				"file = \"local-stores/secret/credentials/api-key\"",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "success with prompted secret",
+				API:  stores,
+				Args: args("compute local-stores pull --service-id 123 --dir stores"),
+				WantOutputs: []string{
+					"Value for secret 'api-key' of secret store 'credentials'",
+					"Consider adding it to your .gitignore.",
+				},
+			},
+			stdin: "s3cret\n",
+			wantFiles: map[string]string{
+				"stores/secret/credentials/api-key": "s3cret",
+			},
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			wd, err := os.Getwd()
+			if err != nil {
+				t.Fatal(err)
+			}
+			rootdir := testutil.NewEnv(testutil.EnvOpts{
+				T: t,
+				Write: []testutil.FileIO{
+					{Src: "manifest_version = 2\nname = \"test\"\nlanguage = \"rust\"\n\n[[local_server.kv_stores.assets]]\nkey = \"stale\"\ndata = \"stale\"\n\n[[local_server.secret_stores.credentials]]\nkey = \"existing\"\ndata = \"local value\"\n", Dst: manifest.Filename},
+					{Src: "service_id = \"123\"\n", Dst: manifest.EnvFilename("stage")},
+				},
+			})
+			defer os.RemoveAll(rootdir)
+			if err := os.Chdir(rootdir); err != nil {
+				t.Fatal(err)
+			}
+			defer os.Chdir(wd)
+
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			opts.Stdin = strings.NewReader(testcase.stdin)
+			err = app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			for _, s := range testcase.WantOutputs {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+
+			for path, want := range testcase.wantFiles {
+				b, err := os.ReadFile(filepath.Join(rootdir, path))
+				if err != nil {
+					t.Fatal(err)
+				}
+				testutil.AssertString(t, want, string(b))
+			}
+			b, err := os.ReadFile(filepath.Join(rootdir, manifest.Filename))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range testcase.wantManifest {
+				testutil.AssertStringContains(t, string(b), s)
+			}
+			for _, s := range testcase.notWantManifest {
+				testutil.AssertStringDoesntContain(t, string(b), s)
+			}
+			if len(testcase.wantOverlay) > 0 {
+				b, err := os.ReadFile(filepath.Join(rootdir, manifest.EnvFilename("stage")))
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, s := range testcase.wantOverlay {
+					testutil.AssertStringContains(t, string(b), s)
+				}
+			}
+		})
+	}
+}
+
+// kvKeysPaginator returns a single page of keys.
+type kvKeysPaginator struct {
+	keys []string
+	done bool
+}
+
+func (p *kvKeysPaginator) Next() bool {
+	next := !p.done
+	p.done = true
+	return next
+}
+
+func (p *kvKeysPaginator) Keys() []string {
+	return p.keys
+}
+
+func (p *kvKeysPaginator) Err() error {
+	return nil
+}
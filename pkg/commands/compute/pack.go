@@ -30,6 +30,7 @@ func NewPackCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Pac
 	c.manifest = m
 
 	c.CmdClause = parent.Command("pack", "Package a pre-compiled Wasm binary for a Fastly Compute@Edge service")
+	c.CmdClause.Flag("env", "The environment configuration to use (e.g. stage), packaging the fastly.toml with its overlay applied").StringVar(&c.manifest.Flag.Env)
 	c.CmdClause.Flag("wasm-binary", "Path to a pre-compiled Wasm binary").Short('w').Required().StringVar(&c.wasmBinary)
 
 	return &c
@@ -54,6 +55,9 @@ func (c *PackCommand) Exec(_ io.Reader, out io.Writer) (err error) {
 	if err = c.manifest.File.ReadError(); err != nil {
 		return err
 	}
+	if err = c.manifest.ApplyEnv(); err != nil {
+		return err
+	}
 	bin := "pkg/package/bin/main.wasm"
 	bindir := filepath.Dir(bin)
 	err = filesystem.MakeDirectoryIfNotExists(bindir)
@@ -129,7 +133,7 @@ func (c *PackCommand) Exec(_ io.Reader, out io.Writer) (err error) {
 
 	src = manifest.Filename
 	dst = fmt.Sprintf("pkg/package/%s", manifest.Filename)
-	if err := filesystem.CopyFile(src, dst); err != nil {
+	if err := c.copyManifest(src, dst); err != nil {
 		c.Globals.ErrLog.AddWithContext(err, map[string]any{
 			"Manifest (destination)": dst,
 			"Manifest (source)":      src,
@@ -182,3 +186,12 @@ func (c *PackCommand) Exec(_ io.Reader, out io.Writer) (err error) {
 	spinner.StopMessage(msg)
 	return spinner.Stop()
 }
+
+// copyManifest copies the manifest into the package, writing the manifest
+// with the environment's overlay applied when --env is set.
+func (c *PackCommand) copyManifest(src, dst string) error {
+	if c.manifest.Env() != "" {
+		return c.manifest.File.Write(dst)
+	}
+	return filesystem.CopyFile(src, dst)
+}
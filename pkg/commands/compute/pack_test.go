@@ -2,10 +2,13 @@ package compute_test
 
 import (
 	"bytes"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/mholt/archiver/v3"
+
 	"github.com/fastly/cli/pkg/app"
 	"github.com/fastly/cli/pkg/manifest"
 	"github.com/fastly/cli/pkg/testutil"
@@ -17,9 +20,12 @@ func TestPack(t *testing.T) {
 		name          string
 		args          []string
 		manifest      string
+		overlay       string
 		wantError     string
 		wantOutput    []string
 		expectedFiles [][]string
+		// wantManifest is the content expected in the packaged manifest.
+		wantManifest []string
 	}{
 		{
 			name: "success",
@@ -36,6 +42,28 @@ func TestPack(t *testing.T) {
 				{"pkg", "package.tar.gz"},
 			},
 		},
+		{
+			name: "success with environment",
+			args: args("compute pack --wasm-binary ./main.wasm --env stage"),
+			manifest: `
+			manifest_version = 2
+			name = "mypackagename"
+			service_id = "prod-123"`,
+			overlay: `service_id = "stage-456"`,
+			wantOutput: []string{
+				"Copying manifest",
+			},
+			wantManifest: []string{
+				`name = "mypackagename"`,
+				`service_id = "stage-456"`,
+			},
+		},
+		{
+			name:      "missing environment",
+			args:      args("compute pack --wasm-binary ./main.wasm --env prod"),
+			manifest:  `name = "mypackagename"`,
+			wantError: "error reading fastly.prod.toml",
+		},
 		{
 			name:      "no wasm binary path flag",
 			args:      args("compute pack"),
@@ -67,6 +95,7 @@ func TestPack(t *testing.T) {
 				},
 				Write: []testutil.FileIO{
 					{Src: testcase.manifest, Dst: manifest.Filename},
+					{Src: testcase.overlay, Dst: manifest.EnvFilename("stage")},
 				},
 			})
 			defer os.RemoveAll(rootdir)
@@ -96,6 +125,23 @@ func TestPack(t *testing.T) {
 					t.Fatalf("the specified file is not in the expected location: %v", err)
 				}
 			}
+
+			if len(testcase.wantManifest) > 0 {
+				var packaged string
+				if err := archiver.Walk(filepath.Join(rootdir, "pkg", "package.tar.gz"), func(f archiver.File) error {
+					if f.Name() != manifest.Filename {
+						return nil
+					}
+					b, err := io.ReadAll(f)
+					packaged = string(b)
+					return err
+				}); err != nil {
+					t.Fatal(err)
+				}
+				for _, s := range testcase.wantManifest {
+					testutil.AssertStringContains(t, packaged, s)
+				}
+			}
 		})
 	}
 }
@@ -0,0 +1,300 @@
+package compute
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/commands/compute/setup"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// The actions of a deploy plan step.
+const (
+	planActivate = "activate"
+	planClone    = "clone"
+	planCreate   = "create"
+	planManual   = "manual"
+	planSkip     = "skip"
+	planUpdate   = "update"
+	planUpload   = "upload"
+)
+
+// deployPlan describes the changes `compute deploy` would make.
+type deployPlan struct {
+	ServiceID      string     `json:"service_id,omitempty"`
+	ServiceName    string     `json:"service_name,omitempty"`
+	NewService     bool       `json:"new_service"`
+	ServiceVersion int        `json:"service_version,omitempty"`
+	Package        string     `json:"package"`
+	FilesHash      string     `json:"files_hash"`
+	Steps          []planStep `json:"steps"`
+}
+
+// planStep is a single change within a deploy plan.
+type planStep struct {
+	Action   string `json:"action"`
+	Resource string `json:"resource"`
+	Name     string `json:"name,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// add appends a step to the plan.
+func (p *deployPlan) add(action, resource, name, detail string) {
+	p.Steps = append(p.Steps, planStep{
+		Action:   action,
+		Resource: resource,
+		Name:     name,
+		Detail:   detail,
+	})
+}
+
+// checkPlanFlags validates the --plan flag combinations.
+func checkPlanFlags(plan, json, verbose bool) error {
+	if json && !plan {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid flag combination, --json without --plan"),
+			Remediation: "Use --json (or --format) together with --plan to display the deploy plan in a structured format.",
+		}
+	}
+	if json && verbose {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+	return nil
+}
+
+// plan works out the changes the deploy would make and displays them.
+//
+// It follows the same flow as Exec() but only calls read-only API endpoints
+// (and never prompts), so nothing is created, cloned, uploaded or activated.
+func (c *DeployCommand) plan(source manifest.Source, serviceID, pkgPath string, out io.Writer) error {
+	filesHash, err := getFilesHash(pkgPath)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Package path": pkgPath,
+		})
+		return err
+	}
+
+	p := &deployPlan{
+		Package:   pkgPath,
+		FilesHash: filesHash,
+	}
+
+	if source == manifest.SourceUndefined {
+		c.planNewService(p)
+	} else if err := c.planExistingService(p, serviceID); err != nil {
+		return err
+	}
+
+	if ok, err := c.WriteJSON(out, p); ok {
+		return err
+	}
+
+	if p.NewService {
+		text.Output(out, "Deploy plan for a new service (%s)", p.ServiceName)
+	} else {
+		service := cmd.SelectedService{ID: p.ServiceID, Name: p.ServiceName}
+		text.Output(out, "Deploy plan for service %s, version %d", service, p.ServiceVersion)
+	}
+	text.Break(out)
+
+	t := text.NewTable(out)
+	t.AddHeader("ACTION", "RESOURCE", "NAME", "DETAIL")
+	for _, s := range p.Steps {
+		t.AddLine(s.Action, s.Resource, s.Name, s.Detail)
+	}
+	t.Print()
+
+	text.Info(out, "No changes have been made. Run the command without --plan to deploy.")
+	return nil
+}
+
+// planNewService adds the steps for creating a new service, along with the
+// resources defined in the fastly.toml [setup] configuration.
+func (c *DeployCommand) planNewService(p *deployPlan) {
+	prompt := !c.Globals.Flags.AcceptDefaults && !c.Globals.Flags.NonInteractive
+
+	p.NewService = true
+	p.ServiceName = c.Manifest.File.Name
+	serviceDetail := ""
+	if c.ServiceName.WasSet {
+		p.ServiceName = c.ServiceName.Value
+	} else if prompt {
+		serviceDetail = "name prompted for, defaults to the package name"
+	}
+	p.add(planCreate, "service", p.ServiceName, serviceDetail)
+	p.add(planCreate, "domain", c.planDomainName(prompt), "")
+
+	setupData := c.Manifest.File.Setup
+
+	backends := &setup.Backends{Setup: setupData.Backends}
+	if backends.Predefined() {
+		for _, name := range sortedKeys(backends.Setup) {
+			settings := backends.Setup[name]
+			address, port := "127.0.0.1", 443
+			if settings.Address != "" {
+				address = settings.Address
+			}
+			if settings.Port > 0 {
+				port = settings.Port
+			}
+			detail := fmt.Sprintf("%s:%d", address, port)
+			if prompt {
+				detail += " (can be changed when prompted)"
+			}
+			p.add(planCreate, "backend", name, detail)
+		}
+	} else if prompt {
+		p.add(planCreate, "backend", "", "prompted for")
+	} else {
+		p.add(planCreate, "backend", "originless", "127.0.0.1:80")
+	}
+
+	configStores := &setup.ConfigStores{Setup: setupData.ConfigStores}
+	if configStores.Predefined() {
+		for _, name := range sortedKeys(configStores.Setup) {
+			p.add(planCreate, "config store", name, itemCount(len(configStores.Setup[name].Items), "item", "items"))
+		}
+	}
+
+	loggers := &setup.Loggers{Setup: setupData.Loggers}
+	if loggers.Predefined() {
+		for _, name := range sortedKeys(loggers.Setup) {
+			detail := "log endpoint must be created manually"
+			if provider := loggers.Setup[name].Provider; provider != "" {
+				detail = fmt.Sprintf("%s %s", provider, detail)
+			}
+			p.add(planManual, "log endpoint", name, detail)
+		}
+	}
+
+	objectStores := &setup.KVStores{Setup: setupData.ObjectStores}
+	if objectStores.Predefined() {
+		for _, name := range sortedKeys(objectStores.Setup) {
+			p.add(planCreate, "object store", name, itemCount(len(objectStores.Setup[name].Items), "item", "items"))
+		}
+	}
+
+	kvStores := &setup.KVStores{Setup: setupData.KVStores}
+	if kvStores.Predefined() {
+		for _, name := range sortedKeys(kvStores.Setup) {
+			p.add(planCreate, "kv store", name, itemCount(len(kvStores.Setup[name].Items), "item", "items"))
+		}
+	}
+
+	secretStores := &setup.SecretStores{Setup: setupData.SecretStores}
+	if secretStores.Predefined() {
+		for _, name := range sortedKeys(secretStores.Setup) {
+			p.add(planCreate, "secret store", name, itemCount(len(secretStores.Setup[name].Entries), "entry", "entries"))
+		}
+	}
+
+	p.add(planUpload, "package", p.Package, "")
+	c.planActivation(p, "1")
+}
+
+// planExistingService adds the steps for deploying to an existing service.
+func (c *DeployCommand) planExistingService(p *deployPlan, serviceID string) error {
+	apiClient := c.Globals.APIClient
+
+	// NOTE: The --service-name flag takes precedence over the fastly.toml.
+	// Refer to serviceManagement().
+	if c.ServiceName.WasSet {
+		var err error
+		serviceID, err = c.ServiceName.Parse(apiClient)
+		if err != nil {
+			return err
+		}
+	}
+
+	serviceVersion, err := c.ServiceVersion.Parse(serviceID, apiClient)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID": serviceID,
+		})
+		return err
+	}
+
+	serviceDetails, err := checkServiceType(serviceID, serviceVersion, apiClient, c.Globals.ErrLog)
+	if err != nil {
+		return err
+	}
+
+	p.ServiceID = serviceID
+	p.ServiceName = serviceDetails.Name
+	p.ServiceVersion = serviceVersion.Number
+
+	// The deploy operates on a clone of a version that isn't editable.
+	version := fmt.Sprintf("%d", serviceVersion.Number)
+	if serviceVersion.Active || serviceVersion.Locked {
+		p.add(planClone, "version", version, "version is not editable")
+		version = "(cloned version)"
+	}
+
+	domains := &setup.Domains{
+		APIClient:      apiClient,
+		ServiceID:      serviceID,
+		ServiceVersion: serviceVersion.Number,
+	}
+	if err := domains.Validate(); err != nil {
+		errLogService(c.Globals.ErrLog, err, serviceID, serviceVersion.Number)
+		return fmt.Errorf("error configuring service domains: %w", err)
+	}
+	if domains.Missing() {
+		p.add(planCreate, "domain", c.planDomainName(!c.Globals.Flags.AcceptDefaults && !c.Globals.Flags.NonInteractive), "service has no domains")
+	}
+
+	upload, err := pkgCompare(apiClient, serviceID, serviceVersion.Number, p.FilesHash, io.Discard)
+	if err != nil {
+		errLogService(c.Globals.ErrLog, err, serviceID, serviceVersion.Number)
+		return err
+	}
+	if !upload {
+		p.add(planSkip, "package", p.Package, fmt.Sprintf("identical to the package of version %d", serviceVersion.Number))
+		return nil
+	}
+	p.add(planUpload, "package", p.Package, "")
+	c.planActivation(p, version)
+	return nil
+}
+
+// planDomainName returns the name of the domain that would be created.
+func (c *DeployCommand) planDomainName(prompt bool) string {
+	switch {
+	case c.Domain != "":
+		return c.Domain
+	case prompt:
+		return "(prompted for)"
+	}
+	return "(generated *.edgecompute.app)"
+}
+
+// planActivation adds the steps for activating the deployed version.
+func (c *DeployCommand) planActivation(p *deployPlan, name string) {
+	if c.Comment.WasSet {
+		p.add(planUpdate, "version", name, fmt.Sprintf("comment: %s", c.Comment.Value))
+	}
+	p.add(planActivate, "version", name, "")
+}
+
+// itemCount describes the number of items within a resource.
+func itemCount(n int, singular, plural string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", singular)
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
+
+// sortedKeys returns the keys of the map in order.
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,294 @@
+package compute
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/mholt/archiver/v3"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/revision"
+)
+
+// ProvenanceSuffix is appended to the path of a package to produce the path of
+// its provenance attestation.
+const ProvenanceSuffix = ".intoto.json"
+
+// The types identifying a provenance attestation.
+//
+// https://github.com/in-toto/attestation/blob/main/spec/v1/statement.md
+// https://slsa.dev/spec/v1.0/provenance
+const (
+	provenanceBuildType     = "https://developer.fastly.com/reference/cli/compute/build/"
+	provenanceBuilderID     = "https://github.com/fastly/cli"
+	provenancePredicateType = "https://slsa.dev/provenance/v1"
+	provenanceStatementType = "https://in-toto.io/Statement/v1"
+)
+
+// provenanceRemediation is displayed when a package can't be verified against
+// its provenance attestation.
+const provenanceRemediation = "Rebuild the package with `fastly compute build --provenance`, or delete the attestation to deploy the package without verifying it."
+
+// provenanceStatement is an in-toto statement attesting to how a package was
+// built, using the SLSA provenance predicate.
+//
+// NOTE: The statement isn't signed. It records the inputs of the build so a
+// package can be checked (and reproduced) before it's deployed.
+type provenanceStatement struct {
+	Type          string               `json:"_type"`
+	Subject       []provenanceResource `json:"subject"`
+	PredicateType string               `json:"predicateType"`
+	Predicate     provenancePredicate  `json:"predicate"`
+}
+
+type provenanceResource struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+type provenancePredicate struct {
+	BuildDefinition provenanceBuildDefinition `json:"buildDefinition"`
+	RunDetails      provenanceRunDetails      `json:"runDetails"`
+}
+
+type provenanceBuildDefinition struct {
+	BuildType          string               `json:"buildType"`
+	ExternalParameters provenanceParameters `json:"externalParameters"`
+	// ResolvedDependencies are the files within the package.
+	ResolvedDependencies []provenanceResource `json:"resolvedDependencies"`
+}
+
+type provenanceParameters struct {
+	Environment   string `json:"environment,omitempty"`
+	IncludeSource bool   `json:"includeSource"`
+	Language      string `json:"language"`
+	Manifest      string `json:"manifest"`
+	PackageName   string `json:"packageName"`
+}
+
+type provenanceRunDetails struct {
+	Builder  provenanceBuilder  `json:"builder"`
+	Metadata provenanceMetadata `json:"metadata"`
+}
+
+type provenanceBuilder struct {
+	ID string `json:"id"`
+	// Version is the version of the CLI and of each toolchain program.
+	Version map[string]string `json:"version"`
+}
+
+type provenanceMetadata struct {
+	StartedOn  string `json:"startedOn"`
+	FinishedOn string `json:"finishedOn"`
+}
+
+// provenanceVersionCommands are the commands reporting the versions of the
+// programs used by each toolchain.
+var provenanceVersionCommands = map[string][]string{
+	"assemblyscript": {"node --version", "npm --version"},
+	"go":             {"go version", "tinygo version"},
+	"javascript":     {"node --version", "npm --version"},
+	"rust":           {"cargo version", "rustc --version"},
+}
+
+// toolchainVersions returns the versions of the programs used by the
+// toolchain, keyed by program name.
+//
+// NOTE: Programs that aren't installed are omitted (e.g. TinyGo isn't required
+// by the Go toolchain), as the build has already succeeded without them.
+func toolchainVersions(toolchain string) map[string]string {
+	versions := map[string]string{
+		"fastly": revision.AppVersion,
+	}
+	for _, command := range provenanceVersionCommands[toolchain] {
+		args := strings.Split(command, " ")
+		// gosec flagged this:
+		// G204 (CWE-78): Subprocess launched with function call as argument or cmd arguments
+		// Disabling as we trust the source of the variable.
+		// #nosec
+		// nosemgrep
+		output, err := exec.Command(args[0], args[1:]...).Output()
+		if err != nil {
+			continue
+		}
+		version, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+		versions[args[0]] = version
+	}
+	return versions
+}
+
+// writeProvenance writes the provenance attestation of the package at
+// pkgPath, returning the path of the attestation.
+//
+// manifestPath is the manifest that was packaged, which has the environment's
+// overlay applied when one was selected.
+func writeProvenance(pkgPath, manifestPath string, params provenanceParameters, versions map[string]string, started time.Time) (string, error) {
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as we need to read the project's manifest.
+	/* #nosec */
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return "", fmt.Errorf("error reading %s: %w", manifest.Filename, err)
+	}
+	params.Manifest = string(data)
+
+	pkgDigest, err := fileDigest(pkgPath)
+	if err != nil {
+		return "", err
+	}
+	files, err := packageFileDigests(pkgPath)
+	if err != nil {
+		return "", err
+	}
+
+	s := provenanceStatement{
+		Type: provenanceStatementType,
+		Subject: []provenanceResource{
+			{Name: filepath.Base(pkgPath), Digest: map[string]string{"sha256": pkgDigest}},
+		},
+		PredicateType: provenancePredicateType,
+		Predicate: provenancePredicate{
+			BuildDefinition: provenanceBuildDefinition{
+				BuildType:            provenanceBuildType,
+				ExternalParameters:   params,
+				ResolvedDependencies: files,
+			},
+			RunDetails: provenanceRunDetails{
+				Builder: provenanceBuilder{
+					ID:      provenanceBuilderID,
+					Version: versions,
+				},
+				Metadata: provenanceMetadata{
+					StartedOn:  started.UTC().Format(time.RFC3339),
+					FinishedOn: time.Now().UTC().Format(time.RFC3339),
+				},
+			},
+		},
+	}
+	data, err = json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	dst := pkgPath + ProvenanceSuffix
+	if err := os.WriteFile(dst, append(data, '\n'), manifest.FilePermissions); err != nil {
+		return "", fmt.Errorf("error writing %s: %w", dst, err)
+	}
+	return dst, nil
+}
+
+// removeProvenance removes the provenance attestation of the package at
+// pkgPath, as it no longer describes a package that has been rebuilt.
+func removeProvenance(pkgPath string) error {
+	err := os.Remove(pkgPath + ProvenanceSuffix)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// verifyProvenance checks the package at pkgPath against its provenance
+// attestation, if it has one. It reports whether an attestation was found.
+func verifyProvenance(pkgPath string) (bool, error) {
+	path := pkgPath + ProvenanceSuffix
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as the path is derived from the package provided by the user.
+	/* #nosec */
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("error reading %s: %w", path, err)
+	}
+
+	var s provenanceStatement
+	if err := json.Unmarshal(data, &s); err != nil {
+		return true, fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid provenance attestation %s: %w", path, err),
+			Remediation: provenanceRemediation,
+		}
+	}
+	if s.Type != provenanceStatementType || s.PredicateType != provenancePredicateType || len(s.Subject) != 1 {
+		return true, fsterr.RemediationError{
+			Inner:       fmt.Errorf("unsupported provenance attestation %s", path),
+			Remediation: provenanceRemediation,
+		}
+	}
+
+	pkgDigest, err := fileDigest(pkgPath)
+	if err != nil {
+		return true, err
+	}
+	if want := s.Subject[0].Digest["sha256"]; want != pkgDigest {
+		return true, fsterr.RemediationError{
+			Inner:       fmt.Errorf("the package %s doesn't match its provenance attestation (sha256 %s, attested %s)", pkgPath, pkgDigest, want),
+			Remediation: provenanceRemediation,
+		}
+	}
+
+	files, err := packageFileDigests(pkgPath)
+	if err != nil {
+		return true, err
+	}
+	if !reflect.DeepEqual(files, s.Predicate.BuildDefinition.ResolvedDependencies) {
+		return true, fsterr.RemediationError{
+			Inner:       fmt.Errorf("the files in the package %s don't match its provenance attestation", pkgPath),
+			Remediation: provenanceRemediation,
+		}
+	}
+	return true, nil
+}
+
+// packageFileDigests returns the SHA-256 digest of each file in the package,
+// in the order they're archived.
+func packageFileDigests(pkgPath string) ([]provenanceResource, error) {
+	var files []provenanceResource
+	err := packageFiles(pkgPath, func(f archiver.File) error {
+		// NOTE: The full path within the archive is used, not f.Name(), which is
+		// only the filename.
+		name := f.Header.(*tar.Header).Name
+		h := sha256.New()
+		if _, err := io.Copy(h, f); err != nil {
+			return fmt.Errorf("error reading %s: %w", name, err)
+		}
+		files = append(files, provenanceResource{
+			Name:   name,
+			Digest: map[string]string{"sha256": fmt.Sprintf("%x", h.Sum(nil))},
+		})
+		return nil
+	})
+	return files, err
+}
+
+// fileDigest returns the SHA-256 digest of the file at path.
+func fileDigest(path string) (string, error) {
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as we need to read the package.
+	/* #nosec */
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading package: %w", err)
+	}
+	defer f.Close() // #nosec G307
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("error reading package: %w", err)
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
@@ -1,7 +1,9 @@
 package compute
 
 import (
+	"fmt"
 	"io"
+	"time"
 
 	"github.com/fastly/cli/pkg/cmd"
 	"github.com/fastly/cli/pkg/global"
@@ -12,6 +14,7 @@ import (
 // PublishCommand produces and deploys an artifact from files on the local disk.
 type PublishCommand struct {
 	cmd.Base
+	cmd.JSONOutput
 	manifest manifest.Data
 	build    *BuildCommand
 	deploy   *DeployCommand
@@ -20,12 +23,20 @@ type PublishCommand struct {
 	includeSrc  cmd.OptionalBool
 	lang        cmd.OptionalString
 	packageName cmd.OptionalString
+	provenance  cmd.OptionalBool
 	timeout     cmd.OptionalInt
 
 	// Deploy fields
+	canary             string
+	canaryConfigStore  string
+	canaryInterval     time.Duration
+	canaryMaxErrorRate string
+	canaryServiceID    string
+	canaryStep         string
 	comment            cmd.OptionalString
 	domain             cmd.OptionalString
 	pkg                cmd.OptionalString
+	plan               bool
 	serviceName        cmd.OptionalServiceNameID
 	serviceVersion     cmd.OptionalServiceVersion
 	statusCheckCode    int
@@ -43,12 +54,22 @@ func NewPublishCommand(parent cmd.Registerer, g *global.Data, build *BuildComman
 	c.deploy = deploy
 	c.CmdClause = parent.Command("publish", "Build and deploy a Compute@Edge package to a Fastly service")
 
+	c.CmdClause.Flag("canary", "Roll the package out gradually, starting with this percentage of traffic (e.g. 10%)").StringVar(&c.canary)
+	c.CmdClause.Flag("canary-config-store", "Name of the config store used to split traffic with the canary service").Default("canary").StringVar(&c.canaryConfigStore)
+	c.CmdClause.Flag("canary-interval", "Time to watch the canary error rate before increasing its share of traffic").Default("5m").DurationVar(&c.canaryInterval)
+	c.CmdClause.Flag("canary-max-error-rate", "Abort the canary if its percentage of 5xx responses exceeds this").Default("1%").StringVar(&c.canaryMaxErrorRate)
+	c.CmdClause.Flag("canary-service-id", "Service ID to deploy the canary package to").StringVar(&c.canaryServiceID)
+	c.CmdClause.Flag("canary-step", "Percentage to increase the canary's share of traffic by at each interval").Default("10%").StringVar(&c.canaryStep)
 	c.CmdClause.Flag("comment", "Human-readable comment").Action(c.comment.Set).StringVar(&c.comment.Value)
 	c.CmdClause.Flag("domain", "The name of the domain associated to the package").Action(c.domain.Set).StringVar(&c.domain.Value)
+	c.CmdClause.Flag("env", "The environment configuration to use (e.g. stage)").StringVar(&c.manifest.Flag.Env)
 	c.CmdClause.Flag("include-source", "Include source code in built package").Action(c.includeSrc.Set).BoolVar(&c.includeSrc.Value)
+	c.RegisterFlagBool(c.JSONFlag()) // --json
 	c.CmdClause.Flag("language", "Language type").Action(c.lang.Set).StringVar(&c.lang.Value)
 	c.CmdClause.Flag("package", "Path to a package tar.gz").Short('p').Action(c.pkg.Set).StringVar(&c.pkg.Value)
 	c.CmdClause.Flag("package-name", "Package name").Action(c.packageName.Set).StringVar(&c.packageName.Value)
+	c.CmdClause.Flag("plan", "Display the changes the deploy would make, without making them").BoolVar(&c.plan)
+	c.CmdClause.Flag("provenance", fmt.Sprintf("Write a provenance attestation of the package (to pkg/<package>.tar.gz%s), verified by `compute deploy`", ProvenanceSuffix)).Action(c.provenance.Set).BoolVar(&c.provenance.Value)
 	c.RegisterFlag(cmd.StringFlagOpts{
 		Name:        cmd.FlagServiceIDName,
 		Description: cmd.FlagServiceIDDesc,
@@ -84,6 +105,10 @@ func NewPublishCommand(parent cmd.Registerer, g *global.Data, build *BuildComman
 // non-deterministic ways. It's best to leave those nested commands to handle
 // the progress indicator.
 func (c *PublishCommand) Exec(in io.Reader, out io.Writer) (err error) {
+	if err := checkPlanFlags(c.plan, c.JSONOutput.Enabled, c.Globals.Verbose()); err != nil {
+		return err
+	}
+
 	// Reset the fields on the BuildCommand based on PublishCommand values.
 	if c.includeSrc.WasSet {
 		c.build.Flags.IncludeSrc = c.includeSrc.Value
@@ -94,18 +119,28 @@ func (c *PublishCommand) Exec(in io.Reader, out io.Writer) (err error) {
 	if c.packageName.WasSet {
 		c.build.Flags.PackageName = c.packageName.Value
 	}
+	if c.provenance.WasSet {
+		c.build.Flags.Provenance = c.provenance.Value
+	}
 	if c.timeout.WasSet {
 		c.build.Flags.Timeout = c.timeout.Value
 	}
 	c.build.Manifest = c.manifest
 
-	err = c.build.Exec(in, out)
+	// NOTE: The build output is discarded when the deploy plan is displayed in
+	// a structured format, so the output can be parsed.
+	buildOut := out
+	if c.JSONOutput.Enabled {
+		buildOut = io.Discard
+	}
+
+	err = c.build.Exec(in, buildOut)
 	if err != nil {
 		c.Globals.ErrLog.Add(err)
 		return err
 	}
 
-	text.Break(out)
+	text.Break(buildOut)
 
 	// Reset the fields on the DeployCommand based on PublishCommand values.
 	if c.pkg.WasSet {
@@ -134,6 +169,14 @@ func (c *PublishCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		c.deploy.StatusCheckTimeout = c.statusCheckTimeout
 	}
 	c.deploy.StatusCheckPath = c.statusCheckPath
+	c.deploy.Plan = c.plan
+	c.deploy.Canary = c.canary
+	c.deploy.CanaryConfigStore = c.canaryConfigStore
+	c.deploy.CanaryInterval = c.canaryInterval
+	c.deploy.CanaryMaxErrorRate = c.canaryMaxErrorRate
+	c.deploy.CanaryServiceID = c.canaryServiceID
+	c.deploy.CanaryStep = c.canaryStep
+	c.deploy.JSONOutput = c.JSONOutput
 
 	err = c.deploy.Exec(in, out)
 	if err != nil {
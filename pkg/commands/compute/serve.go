@@ -1,12 +1,9 @@
 package compute
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 	"io"
-	"io/fs"
-	"log"
 	"net"
 	"net/url"
 	"os"
@@ -17,9 +14,7 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/bep/debounce"
 	"github.com/blang/semver"
-	"github.com/fatih/color"
 	"github.com/fsnotify/fsnotify"
 	ignore "github.com/sabhiram/go-gitignore"
 
@@ -50,15 +45,20 @@ type ServeCommand struct {
 	includeSrc  cmd.OptionalBool
 	lang        cmd.OptionalString
 	packageName cmd.OptionalString
+	provenance  cmd.OptionalBool
 	timeout     cmd.OptionalInt
 
 	// Serve fields
 	addr                    string
+	backendsFromService     bool
 	debug                   bool
 	env                     cmd.OptionalString
 	file                    string
 	forceCheckViceroyLatest bool
+	record                  string
+	serviceName             cmd.OptionalServiceNameID
 	skipBuild               bool
+	syncStores              bool
 	viceroyBinPath          string
 	watch                   bool
 	watchDir                cmd.OptionalString
@@ -76,17 +76,33 @@ func NewServeCommand(parent cmd.Registerer, g *global.Data, build *BuildCommand,
 	c.manifest = m
 
 	c.CmdClause.Flag("addr", "The IPv4 address and port to listen on").Default("127.0.0.1:7676").StringVar(&c.addr)
+	c.CmdClause.Flag("backends-from-service", "Generate the [local_server.backends] from the backends of the service's active version").BoolVar(&c.backendsFromService)
 	c.CmdClause.Flag("debug", "Run the server in Debug Adapter mode").Hidden().BoolVar(&c.debug)
 	c.CmdClause.Flag("env", "The environment configuration to use (e.g. stage)").Action(c.env.Set).StringVar(&c.env.Value)
 	c.CmdClause.Flag("file", "The Wasm file to run").Default("bin/main.wasm").StringVar(&c.file)
 	c.CmdClause.Flag("include-source", "Include source code in built package").Action(c.includeSrc.Set).BoolVar(&c.includeSrc.Value)
 	c.CmdClause.Flag("language", "Language type").Action(c.lang.Set).StringVar(&c.lang.Value)
 	c.CmdClause.Flag("package-name", "Package name").Action(c.packageName.Set).StringVar(&c.packageName.Value)
+	c.CmdClause.Flag("provenance", fmt.Sprintf("Write a provenance attestation of the package (to pkg/<package>.tar.gz%s), verified by `compute deploy`", ProvenanceSuffix)).Action(c.provenance.Set).BoolVar(&c.provenance.Value)
+	c.CmdClause.Flag("record", "Record each request and response to a HAR file (replay it with `compute test --replay`)").StringVar(&c.record)
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
 	c.CmdClause.Flag("skip-build", "Skip the build step").BoolVar(&c.skipBuild)
+	c.CmdClause.Flag("sync-stores", fmt.Sprintf("Pull the contents of the stores linked to the service's active version into %s/ (see `compute local-stores pull`)", LocalStoresDir)).BoolVar(&c.syncStores)
 	c.CmdClause.Flag("timeout", "Timeout, in seconds, for the build compilation step").Action(c.timeout.Set).IntVar(&c.timeout.Value)
 	c.CmdClause.Flag("viceroy-check", "Force the CLI to check for a newer version of the Viceroy binary").BoolVar(&c.forceCheckViceroyLatest)
 	c.CmdClause.Flag("viceroy-path", "The path to a user installed version of the Viceroy binary").StringVar(&c.viceroyBinPath)
-	c.CmdClause.Flag("watch", "Watch for file changes, then rebuild project and restart local server").BoolVar(&c.watch)
+	c.CmdClause.Flag("watch", "Watch for file changes, then rebuild project and swap the local server over to the new build").BoolVar(&c.watch)
 	c.CmdClause.Flag("watch-dir", "The directory to watch files from (can be relative or absolute). Defaults to current directory.").Action(c.watchDir.Set).StringVar(&c.watchDir.Value)
 
 	return &c
@@ -112,7 +128,40 @@ func (c *ServeCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		}
 	}
 
-	c.setBackendsWithDefaultOverrideHostIfMissing(out)
+	c.setBackendsWithDefaultOverrideHostIfMissing(c.Globals.Manifest.File.LocalServer.Backends, out)
+
+	var (
+		serviceID      string
+		serviceVersion int
+	)
+	if c.backendsFromService || c.syncStores {
+		serviceID, serviceVersion, err = c.activeServiceVersion(out)
+		if err != nil {
+			return err
+		}
+	}
+	if c.syncStores {
+		err = pullLocalStores(c.Globals, serviceID, serviceVersion, c.env.Value, LocalStoresDir, in, out)
+		if err != nil {
+			return err
+		}
+	}
+
+	// NOTE: The manifest is resolved after the stores are pulled, as that
+	// updates its [local_server] section.
+	manifestPath, removeManifest, err := localManifest(c.env.Value, c.Globals.ErrLog, out)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	defer removeManifest()
+	if c.backendsFromService {
+		manifestPath, err = c.writeServiceBackends(serviceID, serviceVersion, manifestPath, out)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(manifestPath)
+	}
 
 	spinner, err := text.NewSpinner(out)
 	if err != nil {
@@ -137,28 +186,42 @@ func (c *ServeCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		return err
 	}
 
-	for {
-		err = local(bin, c.file, c.addr, c.env.Value, c.debug, c.watch, c.watchDir, c.Globals.Verbose(), out, c.Globals.ErrLog)
+	opts := localOpts{
+		addr:         c.addr,
+		bin:          bin,
+		debug:        c.debug,
+		errLog:       c.Globals.ErrLog,
+		file:         c.file,
+		manifestPath: manifestPath,
+		out:          out,
+		verbose:      c.Globals.Verbose(),
+	}
+	if c.watch {
+		return c.watchAndServe(in, out, opts)
+	}
+	if c.record != "" {
+		// Viceroy listens on a free port behind the recording proxy.
+		opts.addr, err = freeAddr()
 		if err != nil {
-			if err != fsterr.ErrViceroyRestart {
-				if err == fsterr.ErrSignalInterrupt || err == fsterr.ErrSignalKilled {
-					text.Info(out, "Local server stopped")
-					return nil
-				}
-				return err
-			}
-
-			// Before restarting Viceroy we should rebuild.
-			text.Break(out)
-			err = c.Build(in, out)
-			if err != nil {
-				// NOTE: build errors at this point are going to be user related, so we
-				// should display the error but keep watching the files so we can
-				// rebuild successfully once the user has fixed the issues.
-				fsterr.Deduce(err).Print(color.Error)
-			}
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("error finding a free port for the local server: %w", err)
 		}
+		opts.listenAddr = c.addr
+		target := opts.addr
+		stop, err := recordTraffic(c.addr, func() string { return target }, c.record, c.Globals.ErrLog, out)
+		if err != nil {
+			return err
+		}
+		defer stop()
+		text.Info(out, "Recording requests and responses to %s", c.record)
 	}
+
+	err = local(opts)
+	if err == fsterr.ErrSignalInterrupt || err == fsterr.ErrSignalKilled {
+		text.Info(out, "Local server stopped")
+		return nil
+	}
+	return err
 }
 
 // Build constructs and executes the build logic.
@@ -173,9 +236,15 @@ func (c *ServeCommand) Build(in io.Reader, out io.Writer) error {
 	if c.packageName.WasSet {
 		c.build.Flags.PackageName = c.packageName.Value
 	}
+	if c.provenance.WasSet {
+		c.build.Flags.Provenance = c.provenance.Value
+	}
 	if c.timeout.WasSet {
 		c.build.Flags.Timeout = c.timeout.Value
 	}
+	if c.env.WasSet {
+		c.build.Manifest.Flag.Env = c.env.Value
+	}
 
 	err := c.build.Exec(in, out)
 	if err != nil {
@@ -191,10 +260,10 @@ func (c *ServeCommand) Build(in io.Reader, out io.Writer) error {
 // local_server.backends that is missing that property. The value will only be
 // set if the URL defined uses a hostname (e.g. http://127.0.0.1/ won't) so we
 // can set the override_host to match the hostname.
-func (c *ServeCommand) setBackendsWithDefaultOverrideHostIfMissing(out io.Writer) {
+func (c *ServeCommand) setBackendsWithDefaultOverrideHostIfMissing(backends map[string]manifest.LocalBackend, out io.Writer) {
 	var missingOverrideHost bool
 
-	for k, backend := range c.Globals.Manifest.File.LocalServer.Backends {
+	for k, backend := range backends {
 		if backend.OverrideHost == "" {
 			if u, err := url.Parse(backend.URL); err == nil {
 				segs := strings.Split(u.Host, ":") // avoid parsing IP with port
@@ -203,7 +272,7 @@ func (c *ServeCommand) setBackendsWithDefaultOverrideHostIfMissing(out io.Writer
 						text.Info(out, "[local_server.backends.%s] (%s) is configured without an `override_host`. We will use %s as a default to help avoid any unexpected errors. See https://developer.fastly.com/reference/compute/fastly-toml/#local-server for more details.", k, backend.URL, u.Host)
 					}
 					backend.OverrideHost = u.Host
-					c.Globals.Manifest.File.LocalServer.Backends[k] = backend
+					backends[k] = backend
 					missingOverrideHost = true
 				}
 			}
@@ -510,29 +579,96 @@ func setBinPerms(bin string) error {
 	return nil
 }
 
-// local spawns a subprocess that runs the compiled binary.
-func local(bin, file, addr, env string, debug, watch bool, watchDir cmd.OptionalString, verbose bool, out io.Writer, errLog fsterr.LogInterface) error {
-	if env != "" {
-		env = "." + env
+// localManifest returns the path of the manifest the local server should
+// read, and a function that removes it once the local server has stopped.
+//
+// For an environment it's a copy of the fastly.toml with the environment's
+// overlay applied (see manifest.Data.ApplyEnv), as the overlay only contains
+// the settings that differ. The copy is written to the project directory so
+// any relative paths within it still resolve.
+func localManifest(env string, errLog fsterr.LogInterface, out io.Writer) (string, func(), error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", nil, err
+	}
+	path := filepath.Join(wd, manifest.Filename)
+	if env == "" {
+		return path, func() {}, nil
 	}
 
-	wd, err := os.Getwd()
+	var d manifest.Data
+	d.File.SetErrLog(errLog)
+	d.File.SetOutput(out)
+	d.File.SetQuiet(true)
+	if err := d.File.Read(path); err != nil {
+		return "", nil, fmt.Errorf("error reading %s: %w", manifest.Filename, err)
+	}
+	d.Flag.Env = env
+	if err := d.ApplyEnv(); err != nil {
+		return "", nil, err
+	}
+
+	tmp, err := os.CreateTemp(wd, fmt.Sprintf(".fastly-%s-*.toml", env))
 	if err != nil {
-		errLog.Add(err)
-		return err
+		return "", nil, fmt.Errorf("error creating the manifest for the '%s' environment: %w", env, err)
+	}
+	remove := func() {
+		_ = os.Remove(tmp.Name())
 	}
+	if err := tmp.Close(); err != nil {
+		remove()
+		return "", nil, fmt.Errorf("error creating the manifest for the '%s' environment: %w", env, err)
+	}
+	if err := d.File.Write(tmp.Name()); err != nil {
+		remove()
+		return "", nil, fmt.Errorf("error writing the manifest for the '%s' environment: %w", env, err)
+	}
+	return tmp.Name(), remove, nil
+}
 
-	manifestPath := filepath.Join(wd, fmt.Sprintf("fastly%s.toml", env))
+// localOpts are the arguments for running the local server.
+type localOpts struct {
+	// addr is the address Viceroy listens on.
+	addr string
+	// bin is the path to the Viceroy binary.
+	bin string
+	// debug runs Viceroy in Debug Adapter mode.
+	debug bool
+	// errLog records errors to disk.
+	errLog fsterr.LogInterface
+	// file is the Wasm binary to run.
+	file string
+	// listenAddr is the address clients should use, when it differs from addr
+	// (e.g. when traffic is recorded by a proxy in front of Viceroy).
+	listenAddr string
+	// manifestPath is the path to the manifest Viceroy reads.
+	manifestPath string
+	// out is where the output of Viceroy is written.
+	out io.Writer
+	// stop, if not nil, stops Viceroy when closed.
+	stop <-chan struct{}
+	// verbose displays additional information.
+	verbose bool
+}
+
+// local spawns a subprocess that runs the compiled binary.
+func local(opts localOpts) error {
+	bin, file, manifestPath, out, verbose := opts.bin, opts.file, opts.manifestPath, opts.out, opts.verbose
 
 	// NOTE: Viceroy no longer displays errors unless in verbose mode.
 	// This can cause confusion for customers: https://github.com/fastly/cli/issues/913
 	// So regardless of CLI --verbose flag we'll always set verbose for Viceroy.
-	args := []string{"-v", "-C", manifestPath, "--addr", addr, file}
+	args := []string{"-v", "-C", manifestPath, "--addr", opts.addr, file}
 
-	if debug {
+	if opts.debug {
 		args = append(args, "--debug")
 	}
 
+	listenAddr := opts.addr
+	if opts.listenAddr != "" {
+		listenAddr = opts.listenAddr
+	}
+
 	if verbose {
 		text.Break(out)
 		text.Output(out, "%s: %s", text.BoldYellow("Manifest"), manifestPath)
@@ -551,7 +687,7 @@ func local(bin, file, addr, env string, debug, watch bool, watchDir cmd.Optional
 	} else {
 		// IMPORTANT: Viceroy 0.4.0 changed its INFO log output behind a -v flag.
 		// We display the address unless in verbose mode to avoid duplicate output.
-		text.Info(out, "Listening on http://%s", addr)
+		text.Info(out, "Listening on http://%s", listenAddr)
 	}
 
 	s := &fstexec.Streaming{
@@ -563,58 +699,46 @@ func local(bin, file, addr, env string, debug, watch bool, watchDir cmd.Optional
 		SignalCh:    make(chan os.Signal, 1),
 	}
 	s.MonitorSignals()
-
-	restart := make(chan bool)
-	if watch {
-		root := "."
-		if watchDir.WasSet {
-			root = watchDir.Value
-		}
-
-		if verbose {
-			text.Info(out, "Watching files for changes (using --watch-dir=%s). To ignore certain files, define patterns within a .fastlyignore config file (uses .fastlyignore from --watch-dir).", root)
-		}
-
-		gi := ignoreFiles(watchDir)
-		go watchFiles(root, gi, verbose, s, out, restart)
+	if opts.stop != nil {
+		go func() {
+			<-opts.stop
+			select {
+			case s.SignalCh <- syscall.SIGTERM:
+			default:
+			}
+		}()
 	}
 
 	// NOTE: Once we run the viceroy executable, then it can be stopped by one of
 	// two separate mechanisms:
 	//
-	// 1. File modification
+	// 1. Closing the stop channel (e.g. when `compute serve --watch` swaps in a
+	//    new build, or `compute test --replay` has finished).
 	// 2. Explicit signal (SIGINT, SIGTERM etc).
 	//
-	// In the case of a signal (e.g. user presses Ctrl-c) the listener logic
-	// inside of (*fstexec.Streaming).MonitorSignals() will call
+	// In both cases the listener logic inside of
+	// (*fstexec.Streaming).MonitorSignals() will call
 	// (*fstexec.Streaming).Signal(signal os.Signal) to kill the process.
-	//
-	// In the case of a file modification the viceroy executable needs to first
-	// be killed (handled by the watchFiles() function) and then we can stop the
-	// signal listeners (handled below by sending a message to cmd.SignalCh).
-	//
-	// If we don't tell the signal listening channel to close, then the restart
-	// of the viceroy executable will cause the user to end up with N number of
-	// listeners. This will result in a "os: process already finished" error when
-	// we do finally come to stop the `serve` command (e.g. user presses Ctrl-c).
-	// How big an issue this is depends on how many file modifications a user
-	// makes, because having lots of signal listeners could exhaust resources.
-	if err := s.Exec(); err != nil {
+	err := s.Exec()
+
+	// NOTE: If the process exited by itself, the signal listener is still
+	// running and needs to be told to stop, otherwise every instance of Viceroy
+	// started by `compute serve --watch` would leave a listener behind.
+	select {
+	case s.SignalCh <- syscall.SIGTERM:
+	default:
+	}
+
+	if err != nil {
 		if !strings.Contains(err.Error(), "signal: ") {
-			errLog.Add(err)
+			opts.errLog.Add(err)
 		}
 		e := strings.TrimSpace(err.Error())
 		if strings.Contains(e, "interrupt") {
 			return fsterr.ErrSignalInterrupt
 		}
 		if strings.Contains(e, "killed") {
-			select {
-			case <-restart:
-				s.SignalCh <- syscall.SIGTERM
-				return fsterr.ErrViceroyRestart
-			case <-time.After(1 * time.Second):
-				return fsterr.ErrSignalKilled
-			}
+			return fsterr.ErrSignalKilled
 		}
 		return err
 	}
@@ -622,123 +746,6 @@ func local(bin, file, addr, env string, debug, watch bool, watchDir cmd.Optional
 	return nil
 }
 
-// watchFiles watches the language source directory and restarts the viceroy
-// executable when changes are detected.
-func watchFiles(root string, gi *ignore.GitIgnore, verbose bool, s *fstexec.Streaming, out io.Writer, restart chan<- bool) {
-	watcher, err := fsnotify.NewWatcher()
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer watcher.Close()
-
-	done := make(chan bool)
-	debounced := debounce.New(1 * time.Second)
-	eventHandler := func(modifiedFile string, _ fsnotify.Op) {
-		// NOTE: We avoid describing the file operation (e.g. created, modified,
-		// deleted, renamed etc) rather than checking the fsnotify.Op iota/enum type
-		// because the output can be confusing depending on the application used to
-		// edit a file.
-		//
-		// For example, modifying a file in Vim might cause the file to be
-		// temporarily copied/renamed and this can cause the watcher to report an
-		// existing file has been 'created' or 'renamed' when from a user's
-		// perspective the file already exists and was only modified.
-		text.Break(out)
-		text.Output(out, "%s Restarting local server (%s)", text.BoldGreen("✓"), modifiedFile)
-
-		// NOTE: We force closing the watcher by pushing true into a done channel.
-		// We do this because if we didn't, then we'd get an error after one
-		// restart of the viceroy executable: "os: process already finished".
-		//
-		// This error happens happens because the compute.watchFiles() function is
-		// run in a goroutine and so it will keep running with a copy of the
-		// fstexec.Streaming command instance that wraps a process which has
-		// already been terminated.
-		done <- true
-
-		// NOTE: To be able to force both the current viceroy process signal listener
-		// to close, and to restart the viceroy executable, we need to kill the
-		// process and also send 'true' to a restart channel.
-		//
-		// If we only sent a message to the restart channel, but didn't terminate
-		// the process, then we'd end up in a deadlock because we wouldn't be able
-		// to take a message from the restart channel inside the local() function
-		// because we need to have the process terminate first in order for us to
-		// execute the flushing of channel messages.
-		//
-		// When we stop the signal listener it will internally try to kill the
-		// process and discover it has already been killed and return an error:
-		// `os: process already finished`. This is why we don't do error handling
-		// within (*fstexec.Streaming).MonitorSignalsAsync() as the process could
-		// well be killed already when a user is doing local development with the
-		// --watch flag. The obvious downside to this logic flow is that if the
-		// user is running `compute serve` just to validate the program once, then
-		// there might be an unhandled error when they press Ctrl-c to stop the
-		// serve command from blocking their terminal. That said, this is unlikely
-		// and is a low risk concern.
-		err := s.Signal(os.Kill)
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		restart <- true
-	}
-
-	go func() {
-		for {
-			select {
-			case event, ok := <-watcher.Events:
-				if !ok {
-					return
-				}
-				debounced(func() {
-					eventHandler(event.Name, event.Op)
-				})
-			case err, ok := <-watcher.Errors:
-				if !ok {
-					return
-				}
-				text.Output(out, "error event while watching files: %v", err)
-			}
-		}
-	}()
-
-	var buf bytes.Buffer
-
-	// Walk all directories and files starting from the project's root directory.
-	err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
-		if err != nil {
-			return fmt.Errorf("error configuring watching for file changes: %w", err)
-		}
-		// If there's no ignore file, we'll default to watching all directories
-		// within the specified top-level directory.
-		//
-		// NOTE: Watching a directory implies watching all files within the root of
-		// the directory. This means we don't need to call Add(path) for each file.
-		if gi == nil && entry.IsDir() {
-			watchFile(path, watcher, verbose, &buf)
-		}
-		if gi != nil && !entry.IsDir() && !gi.MatchesPath(path) {
-			// If there is an ignore file, we avoid watching directories and instead
-			// will only add files that don't match the exclusion patterns defined.
-			watchFile(path, watcher, verbose, &buf)
-		}
-		return nil
-	})
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	if verbose {
-		text.Output(out, "%s", text.BoldYellow("Watching..."))
-		text.Break(out)
-		text.Output(out, buf.String())
-		text.Break(out)
-	}
-
-	<-done
-}
-
 // ignoreFiles returns the specific ignore rules being respected.
 //
 // NOTE: We also ignore the .git directory.
@@ -0,0 +1,167 @@
+package compute
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/fastly/go-fastly/v8/fastly"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// activeServiceVersion returns the ID and active version of the service whose
+// configuration the local server should use.
+func (c *ServeCommand) activeServiceVersion(out io.Writer) (string, int, error) {
+	apiClient := c.Globals.APIClient
+
+	// NOTE: The service_id of the environment's manifest takes precedence.
+	if c.env.WasSet {
+		c.manifest.Flag.Env = c.env.Value
+		if err := c.manifest.ApplyEnv(); err != nil {
+			return "", 0, err
+		}
+	}
+
+	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, apiClient, c.Globals.ErrLog)
+	if err != nil {
+		if err == fsterr.ErrNoServiceID {
+			return "", 0, fsterr.RemediationError{
+				Inner:       fmt.Errorf("error reading service: no service ID found"),
+				Remediation: "The --backends-from-service and --sync-stores flags read the configuration of an existing service. " + fsterr.ServiceIDRemediation,
+			}
+		}
+		return "", 0, err
+	}
+	if c.Globals.Verbose() {
+		cmd.DisplayServiceID(serviceID, flag, source, out)
+	}
+
+	active := cmd.OptionalServiceVersion{OptionalString: cmd.OptionalString{Value: "active"}}
+	serviceVersion, err := active.Parse(serviceID, apiClient)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID": serviceID,
+		})
+		return "", 0, fsterr.RemediationError{
+			Inner:       fmt.Errorf("error finding the active version of service %s: %w", serviceID, err),
+			Remediation: "The --backends-from-service and --sync-stores flags read the configuration of the service's active version. Activate a version of the service, or define the resources in the [local_server] section of the fastly.toml instead.",
+		}
+	}
+	return serviceID, serviceVersion.Number, nil
+}
+
+// writeServiceBackends writes a copy of the manifest whose
+// [local_server.backends] are generated from the backends of the service
+// version, returning the path of the copy.
+//
+// Backends defined in the manifest take precedence over those of the service,
+// so individual backends can still be mocked.
+//
+// NOTE: The copy is written to the same directory as the manifest so any
+// relative paths within it (e.g. [local_server.config_stores] files) still
+// resolve. It should be removed once the local server has stopped.
+func (c *ServeCommand) writeServiceBackends(serviceID string, serviceVersion int, manifestPath string, out io.Writer) (string, error) {
+	backends, err := c.Globals.APIClient.ListBackends(&fastly.ListBackendsInput{
+		ServiceID:      serviceID,
+		ServiceVersion: serviceVersion,
+	})
+	if err != nil {
+		errLogService(c.Globals.ErrLog, err, serviceID, serviceVersion)
+		return "", fmt.Errorf("error listing backends: %w", err)
+	}
+
+	var f manifest.File
+	f.SetErrLog(c.Globals.ErrLog)
+	f.SetOutput(out)
+	if err := f.Read(manifestPath); err != nil {
+		return "", fmt.Errorf("error reading %s: %w", manifestPath, err)
+	}
+
+	generated := make(map[string]manifest.LocalBackend, len(backends)+len(f.LocalServer.Backends))
+	sources := make(map[string]string, len(generated))
+	for _, b := range backends {
+		generated[b.Name] = localBackend(b)
+		sources[b.Name] = fmt.Sprintf("service version %d", serviceVersion)
+	}
+	local := manifest.Filename
+	if c.env.Value != "" {
+		local = fmt.Sprintf("%s + %s", manifest.Filename, manifest.EnvFilename(c.env.Value))
+	}
+	for name, b := range f.LocalServer.Backends {
+		generated[name] = b
+		sources[name] = local
+	}
+	c.setBackendsWithDefaultOverrideHostIfMissing(generated, out)
+	f.LocalServer.Backends = generated
+
+	tmp, err := os.CreateTemp(filepath.Dir(manifestPath), ".fastly-serve-*.toml")
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return "", fmt.Errorf("error creating the local server manifest: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return "", fmt.Errorf("error creating the local server manifest: %w", err)
+	}
+	if err := f.Write(tmp.Name()); err != nil {
+		c.Globals.ErrLog.Add(err)
+		_ = os.Remove(tmp.Name())
+		return "", fmt.Errorf("error writing the local server manifest: %w", err)
+	}
+
+	text.Info(out, "Using the backends of service %s, version %d", serviceID, serviceVersion)
+	t := text.NewTable(out)
+	t.AddHeader("NAME", "URL", "OVERRIDE HOST", "CERT HOST", "SOURCE")
+	for _, name := range sortedKeys(generated) {
+		b := generated[name]
+		t.AddLine(name, b.URL, b.OverrideHost, b.CertHost, sources[name])
+	}
+	t.Print()
+	text.Break(out)
+
+	return tmp.Name(), nil
+}
+
+// localBackend maps a service backend to a local server backend.
+//
+// The backend uses TLS if it's enabled or the backend uses port 443, in which
+// case the certificate hostname is taken from the ssl_cert_hostname (falling
+// back to the ssl_sni_hostname and the deprecated ssl_hostname).
+func localBackend(b *fastly.Backend) manifest.LocalBackend {
+	useTLS := b.UseSSL || b.Port == 443
+	scheme, defaultPort := "http", 80
+	if useTLS {
+		scheme, defaultPort = "https", 443
+	}
+
+	host := b.Address
+	switch {
+	case b.Port != 0 && b.Port != defaultPort:
+		host = net.JoinHostPort(b.Address, strconv.Itoa(b.Port))
+	case strings.Contains(b.Address, ":"): // IPv6
+		host = "[" + b.Address + "]"
+	}
+
+	lb := manifest.LocalBackend{
+		URL:          fmt.Sprintf("%s://%s", scheme, host),
+		OverrideHost: b.OverrideHost,
+	}
+	if useTLS {
+		for _, h := range []string{b.SSLCertHostname, b.SSLSNIHostname, b.SSLHostname} {
+			if h != "" {
+				lb.CertHost = h
+				break
+			}
+		}
+		lb.UseSNI = true
+	}
+	return lb
+}
@@ -2,18 +2,30 @@ package compute_test
 
 import (
 	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"syscall"
 	"testing"
+	"time"
 
+	"github.com/fastly/go-fastly/v8/fastly"
+
+	"github.com/fastly/cli/pkg/app"
 	"github.com/fastly/cli/pkg/commands/compute"
 	"github.com/fastly/cli/pkg/config"
 	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
 	"github.com/fastly/cli/pkg/mock"
 	"github.com/fastly/cli/pkg/testutil"
 	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/cli/pkg/threadsafe"
 )
 
 // TestGetViceroy validates that Viceroy is installed to the appropriate
@@ -112,3 +124,229 @@ func TestGetViceroy(t *testing.T) {
 		t.Fatalf("binary was not moved to the install directory: %s", err)
 	}
 }
+
+// TestServeBackendsFromService validates the [local_server.backends] passed to
+// Viceroy are generated from the backends of the service's active version.
+//
+// The Viceroy binary is substituted with a script that prints the manifest it
+// was given (and exits with an error, which stops `compute serve`).
+func TestServeBackendsFromService(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the Viceroy substitute is a shell script")
+	}
+
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name:      "validate missing service ID",
+			Args:      args("compute serve --skip-build --backends-from-service --viceroy-path ./viceroy"),
+			WantError: "error reading service: no service ID found",
+		},
+		{
+			Name: "validate missing active version",
+			API: mock.API{
+				ListVersionsFn: func(i *fastly.ListVersionsInput) ([]*fastly.Version, error) {
+					return []*fastly.Version{{ServiceID: i.ServiceID, Number: 1}}, nil
+				},
+			},
+			Args:      args("compute serve --skip-build --backends-from-service --service-id 123 --viceroy-path ./viceroy"),
+			WantError: "error finding the active version of service 123",
+		},
+		{
+			Name: "success",
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				ListBackendsFn: func(i *fastly.ListBackendsInput) ([]*fastly.Backend, error) {
+					if i.ServiceVersion != 1 {
+						return nil, fmt.Errorf("unexpected version %d", i.ServiceVersion)
+					}
+					return []*fastly.Backend{
+						{Name: "origin", Address: "www.example.com", Port: 443, OverrideHost: "example.com", SSLCertHostname: "cert.example.com"},
+						{Name: "api", Address: "api.example.com", Port: 8080},
+						{Name: "mocked", Address: "mocked.example.com", Port: 443},
+					}, nil
+				},
+			},
+			Args:      args("compute serve --skip-build --backends-from-service --service-id 123 --viceroy-path ./viceroy"),
+			WantError: "exit status 1",
+			WantOutputs: []string{
+				"Using the backends of service 123, version 1",
+				"api     http://api.example.com:8080  api.example.com:8080                    service version 1",
+				"mocked  http://127.0.0.1:8080                                                fastly.toml",
+				"origin  https://www.example.com      example.com           cert.example.com  service version 1",
+				"[local_server.backends.origin]\n      cert_host = \"cert.example.com\"\n      override_host = \"example.com\"\n      url = \"https://www.example.com\"\n      use_sni = true",
+				"[local_server.backends.mocked]\n      url = \"http://127.0.0.1:8080\"",
+			},
+		},
+		{
+			Name: "success with sync stores",
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				ListResourcesFn: func(i *fastly.ListResourcesInput) ([]*fastly.Resource, error) {
+					return []*fastly.Resource{{Name: "settings", ResourceID: "cfg", ResourceType: "config"}}, nil
+				},
+				ListConfigStoreItemsFn: func(i *fastly.ListConfigStoreItemsInput) ([]*fastly.ConfigStoreItem, error) {
+					return []*fastly.ConfigStoreItem{{Key: "colour", Value: "blue"}}, nil
+				},
+			},
+			Args:      args("compute serve --skip-build --sync-stores --service-id 123 --viceroy-path ./viceroy"),
+			WantError: "exit status 1",
+			WantOutputs: []string{
+				"Pulled the stores linked to service 123, version 1",
+				"[local_server.config_stores.settings]\n      file = \"local-stores/config/settings.json\"",
+			},
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			wd, err := os.Getwd()
+			if err != nil {
+				t.Fatal(err)
+			}
+			rootdir := testutil.NewEnv(testutil.EnvOpts{
+				T: t,
+				Write: []testutil.FileIO{
+					{Src: "manifest_version = 2\nname = \"test\"\nlanguage = \"rust\"\n\n[local_server.backends.mocked]\nurl = \"http://127.0.0.1:8080\"\n", Dst: manifest.Filename},
+					{Src: "#!/bin/sh\ncat \"$3\"\nexit 1\n", Dst: "viceroy"},
+				},
+			})
+			defer os.RemoveAll(rootdir)
+			if err := os.Chmod(filepath.Join(rootdir, "viceroy"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chdir(rootdir); err != nil {
+				t.Fatal(err)
+			}
+			defer os.Chdir(wd)
+
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err = app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			for _, s := range testcase.WantOutputs {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+
+			// The generated manifest is removed once Viceroy stops.
+			files, err := filepath.Glob(filepath.Join(rootdir, ".fastly-serve-*.toml"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(files) > 0 {
+				t.Fatalf("generated manifest wasn't removed: %v", files)
+			}
+		})
+	}
+}
+
+// TestServeWatch validates that `compute serve --watch` keeps serving requests
+// while the package is rebuilt, and swaps to the new build once it's ready.
+//
+// The build script rewrites bin/main.wasm, which mustn't trigger a rebuild.
+func TestServeWatch(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake Viceroy is not supported on Windows")
+	}
+
+	viceroy, err := filepath.Abs(os.Args[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootdir := testutil.NewEnv(testutil.EnvOpts{
+		T: t,
+		Copy: []testutil.FileIO{
+			{Src: filepath.Join(wd, "testdata", "main.wasm"), Dst: "bin/main.wasm"},
+		},
+		Write: []testutil.FileIO{
+			{Src: "manifest_version = 2\nname = \"test\"\nlanguage = \"other\"\n[scripts]\nbuild = \"cp bin/main.wasm main.wasm.tmp && mv main.wasm.tmp bin/main.wasm\"\n", Dst: manifest.Filename},
+			{Src: "package main", Dst: "main.go"},
+		},
+	})
+	defer os.RemoveAll(rootdir)
+	if err := os.Chdir(rootdir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	get := func() string {
+		resp, err := http.Get("http://" + addr + "/")
+		if err != nil {
+			return ""
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	t.Setenv(fakeViceroyEnv, "first")
+
+	var stdout threadsafe.Buffer
+	served := make(chan error, 1)
+	go func() {
+		opts := testutil.NewRunOpts(testutil.Args("compute serve --auto-yes --verbose --addr "+addr+" --watch --viceroy-path "+viceroy), &stdout)
+		served <- app.Run(opts)
+	}()
+
+	// NOTE: The files are watched once the verbose output lists them.
+	deadline := time.Now().Add(10 * time.Second)
+	for get() != "first /" || !strings.Contains(stdout.String(), "Watching...") {
+		if time.Now().After(deadline) {
+			t.Fatalf("the local server didn't start: %s", stdout.String())
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	// NOTE: New instances of the fake Viceroy respond with the updated value.
+	t.Setenv(fakeViceroyEnv, "second")
+	if err := os.WriteFile(filepath.Join(rootdir, "main.go"), []byte("package main // changed"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	deadline = time.Now().Add(20 * time.Second)
+	for {
+		body := get()
+		if body == "second /" {
+			break
+		}
+		if body != "first /" {
+			t.Fatalf("unexpected response while rebuilding: %q", body)
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("the new build wasn't served: %s", stdout.String())
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	testutil.AssertStringContains(t, stdout.String(), "main.go)")
+	testutil.AssertStringContains(t, stdout.String(), "Serving the new build")
+
+	// NOTE: A change to the build output would be noticed within the debounce
+	// period of half a second.
+	time.Sleep(2 * time.Second)
+	if n := strings.Count(stdout.String(), "Rebuilding ("); n != 1 {
+		t.Fatalf("want 1 rebuild, got %d: %s", n, stdout.String())
+	}
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-served:
+		testutil.AssertNoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("compute serve didn't stop")
+	}
+	testutil.AssertStringContains(t, stdout.String(), "Local server stopped")
+}
@@ -0,0 +1,352 @@
+package compute
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/bep/debounce"
+	"github.com/fatih/color"
+	"github.com/fsnotify/fsnotify"
+	ignore "github.com/sabhiram/go-gitignore"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// swapGracePeriod is how long the previous instance of Viceroy keeps running
+// after a new build is swapped in, so in-flight requests can complete.
+const swapGracePeriod = time.Second
+
+// watchDebounce is how long to wait for file changes to settle before
+// rebuilding, so an editor saving in bursts only triggers one build.
+const watchDebounce = 500 * time.Millisecond
+
+// watchAndServe serves the package, rebuilding it when files change.
+//
+// Clients connect to a proxy listening on --addr, which forwards requests to
+// the current instance of Viceroy. When a change is detected, the package is
+// rebuilt while the current instance keeps answering requests. Only once the
+// build succeeds, and a new instance of Viceroy is accepting connections, are
+// requests swapped over to it. Build errors are displayed without stopping the
+// current instance.
+func (c *ServeCommand) watchAndServe(in io.Reader, out io.Writer, opts localOpts) error {
+	var target atomic.Value
+	target.Store("")
+	current := func() string { return target.Load().(string) }
+
+	opts.listenAddr = c.addr
+	var (
+		stopProxy func()
+		err       error
+	)
+	if c.record != "" {
+		stopProxy, err = recordTraffic(c.addr, current, c.record, c.Globals.ErrLog, out)
+		if err == nil {
+			text.Info(out, "Recording requests and responses to %s", c.record)
+		}
+	} else {
+		stopProxy, err = serveLocalProxy(c.addr, current, c.Globals.ErrLog)
+	}
+	if err != nil {
+		return err
+	}
+	defer stopProxy()
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+
+	server, err := startViceroy(opts)
+	if err != nil {
+		return err
+	}
+	target.Store(server.addr)
+
+	root := "."
+	if c.watchDir.WasSet {
+		root = c.watchDir.Value
+	}
+	if c.Globals.Verbose() {
+		text.Info(out, "Watching files for changes (using --watch-dir=%s). To ignore certain files, define patterns within a .fastlyignore config file (uses .fastlyignore from --watch-dir).", root)
+	}
+	changes := make(chan string, 1)
+	done := make(chan struct{})
+	defer close(done)
+	go watchChanges(root, ignoreFiles(c.watchDir), c.Globals.Verbose(), out, changes, done)
+
+	for {
+		// NOTE: A nil channel blocks, so an exit is only reported for a running
+		// instance of Viceroy.
+		var exited <-chan error
+		if server != nil {
+			exited = server.stopped
+		}
+
+		select {
+		case <-sigs:
+			if server != nil {
+				server.stop()
+			}
+			text.Info(out, "Local server stopped")
+			return nil
+
+		case err := <-exited:
+			if err == fsterr.ErrSignalInterrupt || err == fsterr.ErrSignalKilled {
+				text.Info(out, "Local server stopped")
+				return nil
+			}
+			// NOTE: The listener is kept open so the server can be restarted by
+			// fixing the issue and saving the file.
+			server = nil
+			target.Store("")
+			if err == nil {
+				err = fmt.Errorf("the local server exited")
+			}
+			fsterr.Deduce(err).Print(color.Error)
+			text.Info(out, "Waiting for changes to restart the local server.")
+
+		case file := <-changes:
+			// NOTE: We avoid describing the file operation (e.g. created, modified,
+			// deleted, renamed etc) rather than checking the fsnotify.Op iota/enum
+			// type because the output can be confusing depending on the application
+			// used to edit a file.
+			text.Break(out)
+			text.Output(out, "%s Rebuilding (%s)", text.BoldGreen("✓"), file)
+
+			if err := c.Build(in, out); err != nil {
+				// NOTE: build errors at this point are going to be user related, so we
+				// should display the error but keep watching the files so we can
+				// rebuild successfully once the user has fixed the issues.
+				fsterr.Deduce(err).Print(color.Error)
+				if server != nil {
+					text.Info(out, "Still serving the previous build.")
+				}
+				continue
+			}
+
+			next, err := startViceroy(opts)
+			if err != nil {
+				fsterr.Deduce(err).Print(color.Error)
+				if server != nil {
+					text.Info(out, "Still serving the previous build.")
+				}
+				continue
+			}
+			target.Store(next.addr)
+			if previous := server; previous != nil {
+				go func() {
+					time.Sleep(swapGracePeriod)
+					previous.stop()
+				}()
+			}
+			server = next
+			text.Output(out, "%s Serving the new build", text.BoldGreen("✓"))
+		}
+	}
+}
+
+// viceroyInstance is an instance of Viceroy started by startViceroy.
+type viceroyInstance struct {
+	addr    string
+	halt    chan struct{}
+	stopped chan error
+}
+
+// stop stops the instance and waits for it to exit.
+func (v *viceroyInstance) stop() {
+	close(v.halt)
+	<-v.stopped
+}
+
+// startViceroy starts Viceroy on a free port and waits for it to accept
+// connections.
+func startViceroy(opts localOpts) (*viceroyInstance, error) {
+	addr, err := freeAddr()
+	if err != nil {
+		opts.errLog.Add(err)
+		return nil, fmt.Errorf("error finding a free port for the local server: %w", err)
+	}
+	v := &viceroyInstance{
+		addr:    addr,
+		halt:    make(chan struct{}),
+		stopped: make(chan error, 1),
+	}
+	opts.addr = addr
+	opts.stop = v.halt
+	go func() {
+		v.stopped <- local(opts)
+	}()
+
+	if err := waitForLocalServer(addr, v.stopped); err != nil {
+		// NOTE: The instance may still be starting, so it's stopped regardless.
+		close(v.halt)
+		return nil, err
+	}
+	return v, nil
+}
+
+// newLocalProxy returns a reverse proxy to the address of the local server
+// returned by target.
+func newLocalProxy(target func() string) *httputil.ReverseProxy {
+	return &httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			// NOTE: The Host header the client sent is kept, as it would be if the
+			// client had connected to Viceroy directly.
+			req.URL.Scheme = "http"
+			req.URL.Host = target()
+			// Stop the default User-Agent of Go being sent when the client didn't
+			// send one.
+			if _, ok := req.Header["User-Agent"]; !ok {
+				req.Header.Set("User-Agent", "")
+			}
+		},
+		// NOTE: Bodies are passed on as they were sent over the wire.
+		Transport: &http.Transport{DisableCompression: true},
+	}
+}
+
+// serveLocalProxy starts a proxy listening on addr that forwards requests to
+// the address of the local server returned by target. The returned function
+// stops it.
+func serveLocalProxy(addr string, target func() string, errLog fsterr.LogInterface) (func(), error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		errLog.Add(err)
+		return nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("error listening on %s: %w", addr, err),
+			Remediation: "Check the --addr address is valid and not already in use.",
+		}
+	}
+
+	p := newLocalProxy(target)
+	p.ErrorHandler = func(w http.ResponseWriter, _ *http.Request, err error) {
+		w.WriteHeader(http.StatusBadGateway)
+		fmt.Fprintf(w, "The local server isn't running (%v). Check the output of `fastly compute serve`.\n", err)
+	}
+	srv := &http.Server{
+		Handler:           p,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		_ = srv.Serve(l)
+	}()
+	return func() { _ = srv.Close() }, nil
+}
+
+// watchChanges watches the language source directory and sends the name of a
+// changed file once changes have settled, until done is closed.
+//
+// NOTE: If a change is already pending (e.g. files changed while a build was
+// running) further changes are coalesced into it.
+func watchChanges(root string, gi *ignore.GitIgnore, verbose bool, out io.Writer, changes chan<- string, done <-chan struct{}) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		text.Warning(out, "Unable to watch files for changes: %v", err)
+		return
+	}
+	defer watcher.Close()
+
+	// NOTE: The build writes the Wasm binary and package archive, which would
+	// otherwise trigger another build. The events are named relative to root,
+	// so both are compared as absolute paths.
+	var outputs []string
+	for _, dir := range []string{"bin", "pkg"} {
+		if abs, err := filepath.Abs(dir); err == nil {
+			outputs = append(outputs, abs)
+		}
+	}
+	isOutput := func(path string) bool {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return false
+		}
+		for _, dir := range outputs {
+			if abs == dir || strings.HasPrefix(abs, dir+string(filepath.Separator)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	debounced := debounce.New(watchDebounce)
+
+	var buf bytes.Buffer
+
+	// Walk all directories and files starting from the project's root directory.
+	err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("error configuring watching for file changes: %w", err)
+		}
+		// If there's no ignore file, we'll default to watching all directories
+		// within the specified top-level directory.
+		//
+		// NOTE: Watching a directory implies watching all files within the root of
+		// the directory. This means we don't need to call Add(path) for each file.
+		if gi == nil && entry.IsDir() {
+			watchFile(path, watcher, verbose, &buf)
+		}
+		if gi != nil && !entry.IsDir() && !gi.MatchesPath(path) {
+			// If there is an ignore file, we avoid watching directories and instead
+			// will only add files that don't match the exclusion patterns defined.
+			watchFile(path, watcher, verbose, &buf)
+		}
+		return nil
+	})
+	if err != nil {
+		text.Warning(out, "Unable to watch files for changes: %v", err)
+		return
+	}
+
+	if verbose {
+		text.Output(out, "%s", text.BoldYellow("Watching..."))
+		text.Break(out)
+		text.Output(out, buf.String())
+		text.Break(out)
+	}
+
+	for {
+		select {
+		case <-done:
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if isOutput(event.Name) {
+				continue
+			}
+			name := event.Name
+			replaced := event.Op&(fsnotify.Remove|fsnotify.Rename) != 0
+			debounced(func() {
+				// NOTE: Editors often save a file by replacing it, which removes the
+				// watch on the original file.
+				if replaced && gi != nil {
+					if _, err := os.Stat(name); err == nil {
+						_ = watcher.Add(name)
+					}
+				}
+				select {
+				case changes <- name:
+				default:
+				}
+			})
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			text.Output(out, "error event while watching files: %v", err)
+		}
+	}
+}
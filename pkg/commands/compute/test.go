@@ -0,0 +1,384 @@
+package compute
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/github"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/cli/pkg/threadsafe"
+)
+
+// localServerStartTimeout is how long to wait for the local server to accept
+// connections.
+const localServerStartTimeout = 30 * time.Second
+
+// TestCommand runs the tests of a Compute@Edge package, using Viceroy as the
+// Wasm runner, or replays recorded traffic against it.
+type TestCommand struct {
+	cmd.Base
+	manifest manifest.Data
+	build    *BuildCommand
+	av       github.AssetVersioner
+
+	env                     cmd.OptionalString
+	file                    string
+	forceCheckViceroyLatest bool
+	headers                 []string
+	junit                   string
+	lang                    string
+	replay                  string
+	skipBuild               bool
+	viceroyBinPath          string
+}
+
+// NewTestCommand returns a usable command registered under the parent.
+func NewTestCommand(parent cmd.Registerer, g *global.Data, build *BuildCommand, av github.AssetVersioner, m manifest.Data) *TestCommand {
+	var c TestCommand
+
+	c.build = build
+	c.av = av
+
+	c.Globals = g
+	c.CmdClause = parent.Command("test", "Run the tests of a Compute@Edge package, or replay recorded traffic against it")
+	c.manifest = m
+
+	c.CmdClause.Flag("env", "The environment configuration to use (e.g. stage)").Action(c.env.Set).StringVar(&c.env.Value)
+	c.CmdClause.Flag("file", "The Wasm file to run").Default("bin/main.wasm").StringVar(&c.file)
+	c.CmdClause.Flag("header", "Response header to compare when replaying (can be repeated)").Default("Content-Type").StringsVar(&c.headers)
+	c.CmdClause.Flag("junit", "Write the test results to a JUnit XML file").StringVar(&c.junit)
+	c.CmdClause.Flag("language", "Language type").StringVar(&c.lang)
+	c.CmdClause.Flag("replay", "Replay the requests of a HAR file recorded by `compute serve --record` and compare the responses").StringVar(&c.replay)
+	c.CmdClause.Flag("skip-build", "Skip the build step (when replaying)").BoolVar(&c.skipBuild)
+	c.CmdClause.Flag("viceroy-check", "Force the CLI to check for a newer version of the Viceroy binary").BoolVar(&c.forceCheckViceroyLatest)
+	c.CmdClause.Flag("viceroy-path", "The path to a user installed version of the Viceroy binary").StringVar(&c.viceroyBinPath)
+
+	return &c
+}
+
+// Exec implements the command interface.
+func (c *TestCommand) Exec(in io.Reader, out io.Writer) error {
+	if runtime.GOARCH == "386" {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("this command doesn't support the '386' architecture"),
+			Remediation: "Although the Fastly CLI supports '386', the `compute test` command requires https://github.com/fastly/Viceroy which does not.",
+		}
+	}
+
+	if c.replay == "" {
+		return c.runTests(out)
+	}
+
+	recording, err := readHAR(c.replay)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("error reading %s: %w", c.replay, err),
+			Remediation: "Record traffic with `fastly compute serve --record <file>`.",
+		}
+	}
+	if len(recording.Log.Entries) == 0 {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("%s has no recorded requests", c.replay),
+			Remediation: "Make some requests to `fastly compute serve --record <file>` before stopping it.",
+		}
+	}
+
+	if !c.skipBuild {
+		if c.env.WasSet {
+			c.build.Manifest.Flag.Env = c.env.Value
+		}
+		if err := c.build.Exec(in, out); err != nil {
+			return err
+		}
+		text.Break(out)
+	}
+
+	manifestPath, removeManifest, err := localManifest(c.env.Value, c.Globals.ErrLog, out)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	defer removeManifest()
+
+	spinner, err := text.NewSpinner(out)
+	if err != nil {
+		return err
+	}
+	bin, err := GetViceroy(spinner, out, c.av, c.Globals, c.viceroyBinPath, c.forceCheckViceroyLatest)
+	if err != nil {
+		return err
+	}
+
+	addr, err := freeAddr()
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return fmt.Errorf("error finding a free port for the local server: %w", err)
+	}
+
+	// NOTE: The output of Viceroy is only displayed if it fails to start.
+	var logs threadsafe.Buffer
+	stop := make(chan struct{})
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- local(localOpts{
+			addr:         addr,
+			bin:          bin,
+			errLog:       c.Globals.ErrLog,
+			file:         c.file,
+			manifestPath: manifestPath,
+			out:          &logs,
+			stop:         stop,
+			verbose:      c.Globals.Verbose(),
+		})
+	}()
+	defer func() {
+		close(stop)
+		<-stopped
+	}()
+
+	if err := waitForLocalServer(addr, stopped); err != nil {
+		text.Output(out, logs.String())
+		return err
+	}
+
+	return c.replayHAR(recording, addr, out)
+}
+
+// runTests runs the project's [scripts.test], or the default tests of its
+// language.
+func (c *TestCommand) runTests(out io.Writer) error {
+	err := c.manifest.File.ReadError()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = fsterr.ErrReadingManifest
+		}
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	if c.env.WasSet {
+		c.manifest.Flag.Env = c.env.Value
+	}
+	if err := c.manifest.ApplyEnv(); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	toolchain, err := toolchain(c.lang, c.manifest.File.Language)
+	if err != nil {
+		return err
+	}
+	manifestPath, removeManifest, err := localManifest(c.env.Value, c.Globals.ErrLog, out)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	defer removeManifest()
+
+	// NOTE: Viceroy is resolved before the default test script as the default
+	// scripts of some languages reference it.
+	var viceroy string
+	if c.manifest.File.Scripts.Test != "" || toolchain == "rust" || toolchain == "go" || toolchain == "javascript" {
+		spinner, err := text.NewSpinner(out)
+		if err != nil {
+			return err
+		}
+		viceroy, err = GetViceroy(spinner, out, c.av, c.Globals, c.viceroyBinPath, c.forceCheckViceroyLatest)
+		if err != nil {
+			return err
+		}
+	}
+
+	script := testScript{command: c.manifest.File.Scripts.Test}
+	if script.command == "" {
+		dir, err := os.MkdirTemp("", "fastly-test-*")
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("error creating a temporary directory: %w", err)
+		}
+		defer os.RemoveAll(dir)
+
+		script, err = defaultTestScript(toolchain, c.manifest.File.Scripts.Build, viceroy, manifestPath, dir)
+		if err != nil {
+			return err
+		}
+	}
+	script.env = append(append([]string{}, c.manifest.File.Scripts.EnvVars...), script.env...)
+
+	results, testErr := execTestScript(script, viceroy, c.Globals.Verbose(), c.Globals.ErrLog, out)
+
+	if c.junit != "" {
+		suite := c.manifest.File.Name
+		if suite == "" {
+			suite = toolchain
+		}
+		if err := writeJUnit(c.junit, suite, results); err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("error writing the JUnit report %s: %w", c.junit, err)
+		}
+	}
+
+	text.Break(out)
+	if len(results.cases) > 0 {
+		text.Output(out, "%d passed, %d failed, %d skipped (%s)", results.count("pass"), results.count("fail"), results.count("skip"), results.duration.Round(time.Millisecond))
+	}
+	if c.junit != "" {
+		text.Output(out, "JUnit report written to %s", c.junit)
+	}
+	if testErr != nil {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("the tests failed: %w", testErr),
+			Remediation: fmt.Sprintf("Review the test output above. To change how the tests are run, define a [scripts.test] in the fastly.toml (the path to Viceroy is available as $%s).", ViceroyEnv),
+		}
+	}
+	text.Success(out, "Tests passed")
+	return nil
+}
+
+// waitForLocalServer waits for the local server to accept connections.
+func waitForLocalServer(addr string, stopped <-chan error) error {
+	deadline := time.Now().Add(localServerStartTimeout)
+	for time.Now().Before(deadline) {
+		select {
+		case err := <-stopped:
+			return fmt.Errorf("the local server stopped unexpectedly: %v", err)
+		default:
+		}
+		conn, err := net.DialTimeout("tcp", addr, time.Second)
+		if err == nil {
+			return conn.Close()
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return fmt.Errorf("the local server didn't start within %s", localServerStartTimeout)
+}
+
+// replayHAR replays the recorded requests, in order, against the local server
+// at addr and compares each response's status, selected headers and body.
+func (c *TestCommand) replayHAR(recording *har, addr string, out io.Writer) error {
+	client := &http.Client{
+		// NOTE: Redirects were recorded as is, so they shouldn't be followed.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+		Timeout:   30 * time.Second,
+		Transport: &http.Transport{DisableCompression: true},
+	}
+	headers := sortedHeaderNames(c.headers)
+
+	var failed int
+	for _, e := range recording.Log.Entries {
+		label := e.Request.Method + " " + e.Request.URL
+		if u, err := url.Parse(e.Request.URL); err == nil {
+			label = e.Request.Method + " " + u.RequestURI()
+		}
+
+		diffs, err := replayEntry(client, addr, e, headers)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			diffs = []string{err.Error()}
+		}
+		if len(diffs) == 0 {
+			text.Output(out, "%s %s", text.BoldGreen("✓"), label)
+			continue
+		}
+		failed++
+		text.Output(out, "%s %s", text.BoldRed("✗"), label)
+		for _, d := range diffs {
+			text.Output(out, "    %s", d)
+		}
+	}
+
+	total := len(recording.Log.Entries)
+	text.Break(out)
+	if failed > 0 {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("%d of %d replayed requests didn't match %s", failed, total, c.replay),
+			Remediation: "If the changes in behaviour are expected, record the traffic again with `fastly compute serve --record <file>`.",
+		}
+	}
+	text.Success(out, "All %d replayed requests matched %s", total, c.replay)
+	return nil
+}
+
+// replayEntry sends the recorded request to the local server at addr and
+// returns how the response differs from the recorded one.
+func replayEntry(client *http.Client, addr string, e harEntry, headers []string) ([]string, error) {
+	u, err := url.Parse(e.Request.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request URL: %w", err)
+	}
+	u.Scheme, u.Host = "http", addr
+
+	var body io.Reader
+	if e.Request.PostData != nil {
+		b, err := e.Request.PostData.body()
+		if err != nil {
+			return nil, fmt.Errorf("invalid request body: %w", err)
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(e.Request.Method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	for _, h := range e.Request.Headers {
+		switch strings.ToLower(h.Name) {
+		case "host":
+			req.Host = h.Value
+		case "content-length":
+			// Set by the client from the body.
+		default:
+			req.Header.Add(h.Name, h.Value)
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending the request: %w", err)
+	}
+	defer resp.Body.Close() // #nosec G307
+	got, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the response: %w", err)
+	}
+
+	var diffs []string
+	if resp.StatusCode != e.Response.Status {
+		diffs = append(diffs, fmt.Sprintf("status: want %d, got %d", e.Response.Status, resp.StatusCode))
+	}
+	for _, name := range headers {
+		want, have := header(e.Response.Headers, name), resp.Header.Get(name)
+		if want != have {
+			diffs = append(diffs, fmt.Sprintf("header %s: want %q, got %q", name, want, have))
+		}
+	}
+	want, err := e.Response.Content.body()
+	if err != nil {
+		return nil, fmt.Errorf("invalid response body: %w", err)
+	}
+	// NOTE: Only the prefix of a truncated body is recorded, so it's compared
+	// along with the size.
+	if n := len(want); e.Response.Content.Size > n && len(got) == e.Response.Content.Size {
+		got = got[:n]
+	}
+	if wantSum, haveSum := sha256.Sum256(want), sha256.Sum256(got); wantSum != haveSum {
+		diffs = append(diffs, fmt.Sprintf("body: want sha256 %x (%d bytes), got %x (%d bytes)", wantSum[:8], len(want), haveSum[:8], len(got)))
+	}
+	return diffs, nil
+}
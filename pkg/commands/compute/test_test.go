@@ -0,0 +1,349 @@
+package compute_test
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/cli/pkg/threadsafe"
+)
+
+// TestRecordAndReplay records traffic through `compute serve --record` and
+// replays it with `compute test --replay`, using the test binary as a fake
+// Viceroy.
+func TestRecordAndReplay(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake Viceroy is not supported on Windows")
+	}
+
+	viceroy, err := filepath.Abs(os.Args[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootdir := testutil.NewEnv(testutil.EnvOpts{
+		T: t,
+		Write: []testutil.FileIO{
+			{Src: "manifest_version = 2\nname = \"test\"\nlanguage = \"rust\"\n", Dst: manifest.Filename},
+		},
+	})
+	defer os.RemoveAll(rootdir)
+	if err := os.Chdir(rootdir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	// The fake Viceroy exits after the third request, stopping `compute serve`.
+	t.Setenv(fakeViceroyEnv, "hello")
+	t.Setenv(fakeViceroyEnv+"_REQUESTS", "3")
+
+	var stdout threadsafe.Buffer
+	served := make(chan error, 1)
+	go func() {
+		opts := testutil.NewRunOpts(testutil.Args("compute serve --skip-build --addr "+addr+" --record traffic.har --viceroy-path "+viceroy), &stdout)
+		served <- app.Run(opts)
+	}()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		resp, err := http.Get("http://" + addr + "/")
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				break
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("the local server didn't start: %s", stdout.String())
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	for _, req := range []func() (*http.Response, error){
+		func() (*http.Response, error) {
+			return http.Post("http://"+addr+"/echo", "text/plain", strings.NewReader(" ping"))
+		},
+		func() (*http.Response, error) {
+			return http.Get("http://" + addr + "/missing")
+		},
+	} {
+		resp, err := req()
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	select {
+	case err := <-served:
+		testutil.AssertErrorContains(t, err, "exit status 1")
+	case <-time.After(10 * time.Second):
+		t.Fatal("compute serve didn't stop")
+	}
+	testutil.AssertStringContains(t, stdout.String(), "Listening on http://"+addr)
+	testutil.AssertStringContains(t, stdout.String(), "Recording requests and responses to traffic.har")
+
+	data, err := os.ReadFile(filepath.Join(rootdir, "traffic.har"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var recording struct {
+		Log struct {
+			Entries []struct {
+				Request struct {
+					Method string `json:"method"`
+					URL    string `json:"url"`
+				} `json:"request"`
+				Response struct {
+					Status  int `json:"status"`
+					Content struct {
+						Text string `json:"text"`
+					} `json:"content"`
+				} `json:"response"`
+			} `json:"entries"`
+		} `json:"log"`
+	}
+	if err := json.Unmarshal(data, &recording); err != nil {
+		t.Fatal(err)
+	}
+	var have []string
+	for _, e := range recording.Log.Entries {
+		have = append(have, strings.Join([]string{e.Request.Method, e.Request.URL, http.StatusText(e.Response.Status), e.Response.Content.Text}, " | "))
+	}
+	testutil.AssertEqual(t, []string{
+		"GET | http://" + addr + "/ | OK | hello /",
+		"POST | http://" + addr + "/echo | OK | hello /echo ping",
+		"GET | http://" + addr + "/missing | Not Found | hello /missing",
+	}, have)
+
+	t.Setenv(fakeViceroyEnv+"_REQUESTS", "")
+	args := testutil.Args
+	scenarios := []struct {
+		testutil.TestScenario
+		viceroyBody string
+	}{
+		{
+			TestScenario: testutil.TestScenario{
+				Name:      "validate missing HAR file",
+				Args:      args("compute test --skip-build --replay missing.har"),
+				WantError: "error reading missing.har",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "success",
+				Args: args("compute test --skip-build --replay traffic.har --viceroy-path " + viceroy),
+				WantOutputs: []string{
+					"✓ GET /\n",
+					"✓ POST /echo\n",
+					"✓ GET /missing\n",
+					"All 3 replayed requests matched traffic.har",
+				},
+			},
+			viceroyBody: "hello",
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:      "changed responses",
+				Args:      args("compute test --skip-build --replay traffic.har --viceroy-path " + viceroy),
+				WantError: "3 of 3 replayed requests didn't match traffic.har",
+				WantOutputs: []string{
+					"✗ GET /missing\n",
+					"body: want sha256",
+				},
+			},
+			viceroyBody: "goodbye",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			t.Setenv(fakeViceroyEnv, testcase.viceroyBody)
+
+			var stdout threadsafe.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			for _, s := range testcase.WantOutputs {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+		})
+	}
+}
+
+func TestRunTests(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test scripts require a POSIX shell")
+	}
+
+	args := testutil.Args
+	scenarios := []struct {
+		testutil.TestScenario
+		manifest  string
+		overlay   string
+		wantJUnit []string
+	}{
+		{
+			TestScenario: testutil.TestScenario{
+				Name:      "validate missing test script",
+				Args:      args("compute test"),
+				WantError: "no test script for the 'other' language",
+			},
+			manifest: "manifest_version = 2\nname = \"test\"\nlanguage = \"other\"\n",
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:      "validate unsupported language",
+				Args:      args("compute test --language cobol"),
+				WantError: "unsupported language cobol",
+			},
+			manifest: "manifest_version = 2\nname = \"test\"\nlanguage = \"other\"\n",
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:      "failing tests",
+				Args:      args("compute test --junit report.xml --viceroy-path ./viceroy"),
+				WantError: "the tests failed",
+				WantOutputs: []string{
+					"test tests::fails ... FAILED",
+					"1 passed, 1 failed, 1 skipped",
+					"JUnit report written to report.xml",
+				},
+			},
+			manifest: "manifest_version = 2\nname = \"test\"\nlanguage = \"rust\"\n[scripts]\ntest = \"echo 'test tests::passes ... ok'; echo 'test tests::fails ... FAILED'; echo 'test tests::later ... ignored'; exit 1\"\n",
+			wantJUnit: []string{
+				`<testsuites name="fastly compute test" tests="3" failures="1" skipped="1"`,
+				`<testcase name="tests::passes" classname="test" time="0.000"></testcase>`,
+				`<testcase name="tests::fails" classname="test" time="0.000">` + "\n" + `      <failure message="failed"></failure>`,
+				`<testcase name="tests::later" classname="test" time="0.000">` + "\n" + `      <skipped></skipped>`,
+				"<system-out>",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "passing tests with Viceroy",
+				Args: args("compute test --viceroy-path ./viceroy"),
+				WantOutputs: []string{
+					"/viceroy",
+					"1 passed, 0 failed, 0 skipped",
+					"Tests passed",
+				},
+			},
+			manifest: "manifest_version = 2\nname = \"test\"\nlanguage = \"go\"\n[scripts]\ntest = \"echo \\\"--- PASS: TestHandler (0.25s)\\\"; echo $VICEROY\"\n",
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:        "unrecognised test output",
+				Args:        args("compute test --junit report.xml --viceroy-path ./viceroy"),
+				WantOutputs: []string{"all good", "Tests passed"},
+			},
+			manifest: "manifest_version = 2\nname = \"test\"\nlanguage = \"javascript\"\n[scripts]\ntest = \"echo all good\"\n",
+			wantJUnit: []string{
+				`<testsuites name="fastly compute test" tests="1" failures="0" skipped="0"`,
+				`<testcase name="test" classname="test"`,
+			},
+		},
+		// NOTE: The default test scripts aren't expected to pass, as there's no
+		// project, but the verbose output shows the toolchain used.
+		{
+			TestScenario: testutil.TestScenario{
+				Name:        "default TinyGo tests",
+				Args:        args("compute test --verbose --viceroy-path ./viceroy"),
+				WantError:   "the tests failed",
+				WantOutputs: []string{"tinygo test -v -target=", "fastly-viceroy.json"},
+			},
+			manifest: "manifest_version = 2\nname = \"test\"\nlanguage = \"go\"\n",
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:        "default Go tests",
+				Args:        args("compute test --verbose --viceroy-path ./viceroy"),
+				WantError:   "the tests failed",
+				WantOutputs: []string{"go test -v -exec", "GOOS=wasip1 GOARCH=wasm"},
+			},
+			manifest: "manifest_version = 2\nname = \"test\"\nlanguage = \"go\"\n[scripts]\nbuild = \"go build -o bin/main.wasm .\"\nenv_vars = [\"GOARCH=wasm\", \"GOOS=wasip1\"]\n",
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:        "default JavaScript tests",
+				Args:        args("compute test --verbose --viceroy-path ./viceroy"),
+				WantError:   "the tests failed",
+				WantOutputs: []string{"npm exec js-compute-runtime ./test/index.js", "viceroy run -C"},
+			},
+			manifest: "manifest_version = 2\nname = \"test\"\nlanguage = \"javascript\"\n",
+		},
+		// NOTE: The local server reads a copy of the fastly.toml with the
+		// overlay applied, which only exists while the tests run.
+		{
+			TestScenario: testutil.TestScenario{
+				Name:        "environment overlay",
+				Args:        args("compute test --env stage --viceroy-path ./viceroy"),
+				WantOutputs: []string{"stage tests", ".fastly-stage-", "Tests passed"},
+			},
+			manifest: "manifest_version = 2\nname = \"test\"\nlanguage = \"javascript\"\n[scripts]\ntest = \"echo all good\"\n",
+			overlay:  "[scripts]\ntest = \"echo stage tests; ls -a\"\n",
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			wd, err := os.Getwd()
+			if err != nil {
+				t.Fatal(err)
+			}
+			rootdir := testutil.NewEnv(testutil.EnvOpts{
+				T: t,
+				Write: []testutil.FileIO{
+					{Src: testcase.manifest, Dst: manifest.Filename},
+					{Src: testcase.overlay, Dst: manifest.EnvFilename("stage")},
+				},
+			})
+			defer os.RemoveAll(rootdir)
+			if err := os.Chdir(rootdir); err != nil {
+				t.Fatal(err)
+			}
+			defer os.Chdir(wd)
+
+			var stdout threadsafe.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			err = app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			for _, s := range testcase.WantOutputs {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+
+			if tmp, _ := filepath.Glob(filepath.Join(rootdir, ".fastly-*.toml")); len(tmp) > 0 {
+				t.Fatalf("the local server manifest wasn't removed: %v", tmp)
+			}
+
+			if len(testcase.wantJUnit) > 0 {
+				report, err := os.ReadFile(filepath.Join(rootdir, "report.xml"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, s := range testcase.wantJUnit {
+					testutil.AssertStringContains(t, string(report), s)
+				}
+			}
+		})
+	}
+}
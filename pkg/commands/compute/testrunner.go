@@ -0,0 +1,303 @@
+package compute
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	fstexec "github.com/fastly/cli/pkg/exec"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/cli/pkg/threadsafe"
+)
+
+// ViceroyEnv is the environment variable that the path of the Viceroy binary
+// is exposed as to test scripts.
+const ViceroyEnv = "VICEROY"
+
+// testScript is the command that runs a project's tests.
+type testScript struct {
+	// command is run within a subprocess shell.
+	command string
+	// env is environment variables to be set.
+	env []string
+	// viceroy indicates the tests run under Viceroy.
+	viceroy bool
+}
+
+// tinygoTarget is a TinyGo target, based on its wasi target, whose emulator
+// runs the compiled test binaries under Viceroy.
+const tinygoTarget = `{"inherits": ["wasi"], "emulator": %q}`
+
+// jsTestEntrypoint is the entrypoint of a JavaScript project's tests, which
+// is compiled to Wasm and run under Viceroy.
+const jsTestEntrypoint = "./test/index.js"
+
+// defaultTestScript returns how the tests of the toolchain are run when a
+// [scripts.test] isn't defined. The viceroy argument is the path to the
+// Viceroy binary, which runs the compiled Wasm test binaries.
+//
+// JavaScript tests are compiled by js-compute-runtime from jsTestEntrypoint.
+// Go tests are compiled by the same toolchain as the [scripts.build] (TinyGo,
+// unless it builds with standard Go). As TinyGo has no flag to set the program
+// running the tests, a target referencing Viceroy is written to dir.
+func defaultTestScript(toolchain, build, viceroy, manifestPath, dir string) (testScript, error) {
+	runner := fmt.Sprintf("%s run -C %s --", viceroy, manifestPath)
+	switch toolchain {
+	case "rust":
+		return testScript{
+			command: "cargo test --target wasm32-wasi",
+			env:     []string{"CARGO_TARGET_WASM32_WASI_RUNNER=" + runner},
+			viceroy: true,
+		}, nil
+	case "go":
+		if build != "" && !strings.Contains(build, "tinygo build") {
+			return testScript{
+				command: fmt.Sprintf("go test -v -exec %q ./...", runner),
+				env:     []string{"GOOS=wasip1", "GOARCH=wasm"},
+				viceroy: true,
+			}, nil
+		}
+		target := filepath.Join(dir, "fastly-viceroy.json")
+		if err := os.WriteFile(target, []byte(fmt.Sprintf(tinygoTarget, runner+" {}")), 0o600); err != nil {
+			return testScript{}, fmt.Errorf("error writing the TinyGo target %s: %w", target, err)
+		}
+		return testScript{
+			command: fmt.Sprintf("tinygo test -v -target=%q -gc=conservative ./...", target),
+			viceroy: true,
+		}, nil
+	case "javascript":
+		// NOTE: The tests are compiled to dir, so the package's Wasm binary
+		// isn't overwritten.
+		wasm := filepath.Join(dir, "test.wasm")
+		return testScript{
+			command: fmt.Sprintf("npm exec js-compute-runtime %s %q && %s %q", jsTestEntrypoint, wasm, runner, wasm),
+			viceroy: true,
+		}, nil
+	case "assemblyscript":
+		// NOTE: AssemblyScript tests are typically run by Node.js, rather than
+		// compiled to Wasm, so they're left to the project's package.json.
+		return testScript{command: "npm test"}, nil
+	case "other":
+		return testScript{}, fsterr.RemediationError{
+			Inner:       fmt.Errorf("no test script for the 'other' language"),
+			Remediation: fmt.Sprintf("Define a [scripts.test] in the fastly.toml. The path to Viceroy is available as $%s.", ViceroyEnv),
+		}
+	default:
+		return testScript{}, fmt.Errorf("unsupported language %s", toolchain)
+	}
+}
+
+// execTestScript runs the test script, streaming its output, and returns the
+// results parsed from the output.
+func execTestScript(script testScript, viceroy string, verbose bool, errLog fsterr.LogInterface, out io.Writer) (testResults, error) {
+	cmd, args := Shell{}.Build(script.command)
+	env := script.env
+	if viceroy != "" {
+		env = append(env, ViceroyEnv+"="+viceroy)
+	}
+
+	if verbose {
+		text.Description(out, "Test script to execute", script.command)
+		if len(env) > 0 {
+			text.Description(out, "Test environment variables set", strings.Join(env, " "))
+		}
+	}
+
+	var output threadsafe.Buffer
+	s := fstexec.Streaming{
+		Args:        args,
+		Command:     cmd,
+		Env:         env,
+		ForceOutput: true,
+		Output:      io.MultiWriter(out, &output),
+		Verbose:     verbose,
+	}
+	start := time.Now()
+	// gosec flagged this:
+	// G204 (CWE-78): Subprocess launched with function call as argument or cmd arguments
+	// Disabling as we require the user to provide this command.
+	// #nosec
+	// nosemgrep: go.lang.security.audit.dangerous-exec-command.dangerous-exec-command
+	err := s.Exec()
+	if err != nil {
+		errLog.Add(err)
+	}
+
+	results := parseTestResults(output.String())
+	results.duration = time.Since(start)
+	results.output = output.String()
+	results.err = err
+	return results, err
+}
+
+// testResults are the results of a test run.
+type testResults struct {
+	cases    []testCase
+	duration time.Duration
+	err      error
+	output   string
+}
+
+// testCase is the result of a single test.
+type testCase struct {
+	name     string
+	status   string // one of pass, fail or skip.
+	duration time.Duration
+}
+
+// count returns the number of test cases with the status.
+func (r testResults) count(status string) int {
+	var n int
+	for _, c := range r.cases {
+		if c.status == status {
+			n++
+		}
+	}
+	return n
+}
+
+var (
+	// e.g. `test tests::it_works ... ok`
+	cargoTestLine = regexp.MustCompile(`^test (\S+)(?: - should panic)? \.\.\. (ok|FAILED|ignored)`)
+	// e.g. `--- PASS: TestHandler (0.01s)`
+	goTestLine = regexp.MustCompile(`^\s*--- (PASS|FAIL|SKIP): (\S+) \(([\d.]+)s\)`)
+	// e.g. `not ok 2 - handles errors # SKIP` (the Test Anything Protocol).
+	tapTestLine = regexp.MustCompile(`^\s*(not )?ok \d+ - (.+?)(\s+#\s+(?i:skip|todo).*)?$`)
+)
+
+// parseTestResults extracts the test cases from the output of the test
+// runners of the supported languages.
+func parseTestResults(output string) testResults {
+	var r testResults
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := cargoTestLine.FindStringSubmatch(line); m != nil {
+			status := map[string]string{"ok": "pass", "FAILED": "fail", "ignored": "skip"}[m[2]]
+			r.cases = append(r.cases, testCase{name: m[1], status: status})
+			continue
+		}
+		if m := goTestLine.FindStringSubmatch(line); m != nil {
+			seconds, _ := strconv.ParseFloat(m[3], 64)
+			r.cases = append(r.cases, testCase{
+				name:     m[2],
+				status:   strings.ToLower(m[1]),
+				duration: time.Duration(seconds * float64(time.Second)),
+			})
+			continue
+		}
+		if m := tapTestLine.FindStringSubmatch(line); m != nil {
+			status := "pass"
+			switch {
+			case m[3] != "":
+				status = "skip"
+			case m[1] != "":
+				status = "fail"
+			}
+			r.cases = append(r.cases, testCase{name: m[2], status: status})
+		}
+	}
+	return r
+}
+
+// junitTestSuites is the root element of a JUnit XML report.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+	SystemOut string          `xml:"system-out,omitempty"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// writeJUnit writes the results to path as a JUnit XML report.
+//
+// If no test cases could be identified in the output, the whole run is
+// reported as a single test case named after the suite.
+func writeJUnit(path, suite string, r testResults) error {
+	cases := r.cases
+	if len(cases) == 0 {
+		c := testCase{name: suite, status: "pass", duration: r.duration}
+		if r.err != nil {
+			c.status = "fail"
+		}
+		cases = []testCase{c}
+	}
+
+	ts := junitTestSuite{
+		Name:      suite,
+		Tests:     len(cases),
+		Time:      seconds(r.duration),
+		Timestamp: time.Now().Add(-r.duration).UTC().Format("2006-01-02T15:04:05"),
+		SystemOut: r.output,
+	}
+	for _, c := range cases {
+		tc := junitTestCase{
+			Name:      c.name,
+			Classname: suite,
+			Time:      seconds(c.duration),
+		}
+		switch c.status {
+		case "fail":
+			ts.Failures++
+			tc.Failure = &junitMessage{Message: "failed"}
+		case "skip":
+			ts.Skipped++
+			tc.Skipped = &junitMessage{}
+		}
+		ts.Cases = append(ts.Cases, tc)
+	}
+
+	report := junitTestSuites{
+		Name:     "fastly compute test",
+		Tests:    ts.Tests,
+		Failures: ts.Failures,
+		Skipped:  ts.Skipped,
+		Time:     ts.Time,
+		Suites:   []junitTestSuite{ts},
+	}
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append([]byte(xml.Header), append(data, '\n')...)
+	return os.WriteFile(path, data, manifest.FilePermissions)
+}
+
+// seconds formats the duration as seconds, as used by JUnit reports.
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
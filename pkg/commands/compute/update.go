@@ -57,6 +57,7 @@ func NewUpdateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *U
 		Action: c.autoClone.Set,
 		Dst:    &c.autoClone.Value,
 	})
+	c.CmdClause.Flag("env", "The environment configuration to use (e.g. stage)").StringVar(&c.manifest.Flag.Env)
 	c.CmdClause.Flag("package", "Path to a package tar.gz").Short('p').StringVar(&c.path)
 	return &c
 }
@@ -68,6 +69,12 @@ func (c *UpdateCommand) Exec(_ io.Reader, out io.Writer) (err error) {
 		return fsterr.ErrNoToken
 	}
 
+	// NOTE: The service_id of the environment's manifest takes precedence.
+	if err := c.manifest.ApplyEnv(); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
 	serviceID, serviceVersion, err := cmd.ServiceDetails(cmd.ServiceDetailsOpts{
 		AutoCloneFlag:      c.autoClone,
 		APIClient:          c.Globals.APIClient,
@@ -23,12 +23,18 @@ func NewValidateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data)
 	c.Globals = g
 	c.manifest = m
 	c.CmdClause = parent.Command("validate", "Validate a Compute@Edge package")
+	c.CmdClause.Flag("env", "The environment configuration to use (e.g. stage)").StringVar(&c.manifest.Flag.Env)
 	c.CmdClause.Flag("package", "Path to a package tar.gz").Short('p').StringVar(&c.path)
 	return &c
 }
 
 // Exec implements the command interface.
 func (c *ValidateCommand) Exec(_ io.Reader, out io.Writer) error {
+	if err := c.manifest.ApplyEnv(); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
 	packagePath := c.path
 	if packagePath == "" {
 		projectName, source := c.manifest.Name()
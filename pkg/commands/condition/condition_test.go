@@ -0,0 +1,269 @@
+package condition_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fastly/go-fastly/v8/fastly"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestConditionCreate(t *testing.T) {
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name:      "validate --type is a known condition type",
+			Args:      args("condition create --service-id 123 --version 1 --name is-api --type ERROR"),
+			WantError: "error parsing arguments: enum value must be one of REQUEST,CACHE,RESPONSE,PREFETCH, got 'ERROR'",
+		},
+		{
+			Name: "validate CreateCondition API error",
+			Args: args("condition create --service-id 123 --version 1 --name is-api --autoclone"),
+			API: mock.API{
+				ListVersionsFn:    testutil.ListVersions,
+				CloneVersionFn:    testutil.CloneVersionResult(4),
+				CreateConditionFn: createConditionError,
+			},
+			WantError: errTest.Error(),
+		},
+		{
+			Name: "validate the condition is created from the flags",
+			Args: args(`condition create --service-id 123 --version 1 --name is-api --autoclone --priority 10 --statement req.url~"^/api/" --type REQUEST`),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CloneVersionFn: testutil.CloneVersionResult(4),
+				CreateConditionFn: createConditionWith(fastly.CreateConditionInput{
+					ServiceID:      "123",
+					ServiceVersion: 4,
+					Name:           fastly.String("is-api"),
+					Priority:       fastly.Int(10),
+					Statement:      fastly.String(`req.url~"^/api/"`),
+					Type:           fastly.String("REQUEST"),
+				}),
+			},
+			WantOutput: "Created condition is-api (service 123 version 4)",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+		})
+	}
+}
+
+func TestConditionList(t *testing.T) {
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Args: args("condition list --service-id 123 --version 1"),
+			API: mock.API{
+				ListVersionsFn:   testutil.ListVersions,
+				ListConditionsFn: listConditionsOK,
+			},
+			WantOutput: listConditionsShortOutput,
+		},
+		{
+			Args: args("condition list --service-id 123 --version 1 --verbose"),
+			API: mock.API{
+				ListVersionsFn:   testutil.ListVersions,
+				ListConditionsFn: listConditionsOK,
+			},
+			WantOutput: listConditionsVerboseOutput,
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(strings.Join(testcase.Args, " "), func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertString(t, testcase.WantOutput, stdout.String())
+		})
+	}
+}
+
+func TestConditionDescribe(t *testing.T) {
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("condition describe --service-id 123 --version 1 --name is-api"), &stdout)
+	opts.APIClient = mock.APIClient(mock.API{
+		ListVersionsFn: testutil.ListVersions,
+		GetConditionFn: getConditionOK,
+	})
+	err := app.Run(opts)
+	testutil.AssertNoError(t, err)
+	testutil.AssertString(t, describeConditionOutput, stdout.String())
+}
+
+func TestConditionUpdate(t *testing.T) {
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name:      "validate missing --name flag",
+			Args:      args("condition update --service-id 123 --version 1 --comment example"),
+			WantError: "error parsing arguments: required flag --name not provided",
+		},
+		{
+			Name: "validate only the given fields are updated",
+			Args: args("condition update --service-id 123 --version 1 --name is-api --comment example --type CACHE --autoclone"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CloneVersionFn: testutil.CloneVersionResult(4),
+				UpdateConditionFn: updateConditionWith(fastly.UpdateConditionInput{
+					ServiceID:      "123",
+					ServiceVersion: 4,
+					Name:           "is-api",
+					Comment:        fastly.String("example"),
+					Type:           fastly.String("CACHE"),
+				}),
+			},
+			WantOutput: "Updated condition is-api (service 123 version 4)",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+		})
+	}
+}
+
+func TestConditionDelete(t *testing.T) {
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("condition delete --service-id 123 --version 1 --name is-api --autoclone"), &stdout)
+	opts.APIClient = mock.APIClient(mock.API{
+		ListVersionsFn: testutil.ListVersions,
+		CloneVersionFn: testutil.CloneVersionResult(4),
+		DeleteConditionFn: func(i *fastly.DeleteConditionInput) error {
+			if i.Name != "is-api" || i.ServiceVersion != 4 {
+				return errTest
+			}
+			return nil
+		},
+	})
+	err := app.Run(opts)
+	testutil.AssertNoError(t, err)
+	testutil.AssertStringContains(t, stdout.String(), "Deleted condition is-api (service 123 version 4)")
+}
+
+var errTest = errors.New("fixture error")
+
+func createConditionWith(want fastly.CreateConditionInput) func(*fastly.CreateConditionInput) (*fastly.Condition, error) {
+	return func(i *fastly.CreateConditionInput) (*fastly.Condition, error) {
+		if !reflect.DeepEqual(*i, want) {
+			return nil, fmt.Errorf("unexpected input: %+v", *i)
+		}
+		return &fastly.Condition{
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+			Name:           *i.Name,
+		}, nil
+	}
+}
+
+func createConditionError(i *fastly.CreateConditionInput) (*fastly.Condition, error) {
+	return nil, errTest
+}
+
+func listConditionsOK(i *fastly.ListConditionsInput) ([]*fastly.Condition, error) {
+	return []*fastly.Condition{
+		{
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+			Name:           "is-api",
+			Comment:        "API requests",
+			Type:           "REQUEST",
+			Priority:       10,
+			Statement:      `req.url ~ "^/api/"`,
+		},
+		{
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+			Name:           "is-static",
+			Type:           "CACHE",
+			Priority:       20,
+			Statement:      `req.url.ext == "css"`,
+		},
+	}, nil
+}
+
+var listConditionsShortOutput = strings.TrimSpace(`
+SERVICE  VERSION  NAME       TYPE     PRIORITY  STATEMENT
+123      1        is-api     REQUEST  10        req.url ~ "^/api/"
+123      1        is-static  CACHE    20        req.url.ext == "css"
+`) + "\n"
+
+var listConditionsVerboseOutput = strings.Join([]string{
+	"Fastly API token not provided",
+	"Fastly API endpoint: https://api.fastly.com",
+	"",
+	"Service ID (via --service-id): 123",
+	"",
+	"Version: 1",
+	"	Condition 1/2",
+	"		Name: is-api",
+	"		Comment: API requests",
+	"		Type: REQUEST",
+	"		Priority: 10",
+	`		Statement: req.url ~ "^/api/"`,
+	"	Condition 2/2",
+	"		Name: is-static",
+	"		Comment: ",
+	"		Type: CACHE",
+	"		Priority: 20",
+	`		Statement: req.url.ext == "css"`,
+}, "\n") + "\n\n"
+
+func getConditionOK(i *fastly.GetConditionInput) (*fastly.Condition, error) {
+	return &fastly.Condition{
+		ServiceID:      i.ServiceID,
+		ServiceVersion: i.ServiceVersion,
+		Name:           "is-api",
+		Comment:        "API requests",
+		Type:           "REQUEST",
+		Priority:       10,
+		Statement:      `req.url ~ "^/api/"`,
+	}, nil
+}
+
+var describeConditionOutput = "\n" + strings.Join([]string{
+	"Service ID: 123",
+	"Version: 1",
+	"Name: is-api",
+	"Comment: API requests",
+	"Type: REQUEST",
+	"Priority: 10",
+	`Statement: req.url ~ "^/api/"`,
+}, "\n") + "\n"
+
+func updateConditionWith(want fastly.UpdateConditionInput) func(*fastly.UpdateConditionInput) (*fastly.Condition, error) {
+	return func(i *fastly.UpdateConditionInput) (*fastly.Condition, error) {
+		if !reflect.DeepEqual(*i, want) {
+			return nil, fmt.Errorf("unexpected input: %+v", *i)
+		}
+		return &fastly.Condition{
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+			Name:           i.Name,
+		}, nil
+	}
+}
@@ -22,6 +22,8 @@ func NewListCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Lis
 	c.CmdClause = parent.Command("list", "List config stores")
 
 	// Optional.
+	c.RegisterFlag(c.FieldsFlag())   // --fields
+	c.RegisterFlag(c.FilterFlag())   // --filter
 	c.RegisterFlagBool(c.JSONFlag()) // --json
 
 	return &c
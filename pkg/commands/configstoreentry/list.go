@@ -26,6 +26,8 @@ func NewListCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Lis
 	c.RegisterFlag(cmd.StoreIDFlag(&c.input.StoreID)) // --store-id
 
 	// Optional.
+	c.RegisterFlag(c.FieldsFlag())   // --fields
+	c.RegisterFlag(c.FilterFlag())   // --filter
 	c.RegisterFlagBool(c.JSONFlag()) // --json
 
 	return &c
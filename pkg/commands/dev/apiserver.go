@@ -0,0 +1,76 @@
+package dev
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/fastly/cli/pkg/api/fake"
+	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/env"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// APIServerCommand starts a local fake of the Fastly API.
+type APIServerCommand struct {
+	cmd.Base
+
+	listen string
+}
+
+// NewAPIServerCommand returns a usable command registered under the parent.
+func NewAPIServerCommand(parent cmd.Registerer, g *global.Data) *APIServerCommand {
+	var c APIServerCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("api-server", "Start a local, in-memory fake of the Fastly API for offline testing")
+	c.CmdClause.Flag("listen", "Address to listen on (a port of 0 picks a free port)").Default("127.0.0.1:0").StringVar(&c.listen)
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *APIServerCommand) Exec(_ io.Reader, out io.Writer) error {
+	l, err := net.Listen("tcp", c.listen)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("error listening on %s: %w", c.listen, err),
+			Remediation: "Check the --listen address is valid and not already in use.",
+		}
+	}
+
+	srv := &http.Server{
+		Handler:           fake.New(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Serve(l)
+	}()
+
+	endpoint := fmt.Sprintf("http://%s", l.Addr())
+	text.Output(out, "Fake Fastly API listening on %s", endpoint)
+	text.Info(out, "Point the CLI at it with `--endpoint %s` or `export %s=%s`. Any token is accepted. Press Ctrl-C to stop.", endpoint, env.Endpoint, endpoint)
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+
+	select {
+	case <-sigs:
+		err = srv.Close()
+	case err = <-errCh:
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		c.Globals.ErrLog.Add(err)
+		return fmt.Errorf("error running the fake API: %w", err)
+	}
+	return nil
+}
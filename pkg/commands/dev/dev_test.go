@@ -0,0 +1,43 @@
+package dev_test
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestAPIServer(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name:      "validate invalid --listen address",
+			Args:      args("dev api-server --listen nonsense"),
+			WantError: "error listening on nonsense",
+		},
+		{
+			Name:      "validate --listen address in use",
+			Args:      args("dev api-server --listen " + l.Addr().String()),
+			WantError: "address already in use",
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+		})
+	}
+}
@@ -0,0 +1,3 @@
+// Package dev contains commands that support developing and testing against
+// the Fastly API without a Fastly account.
+package dev
@@ -16,6 +16,7 @@ import (
 type ListCommand struct {
 	cmd.Base
 	cmd.JSONOutput
+	cmd.Pagination
 
 	manifest    manifest.Data
 	input       fastly.ListDictionaryItemsInput
@@ -36,8 +37,12 @@ func NewListCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Lis
 	c.CmdClause.Flag("dictionary-id", "Dictionary ID").Required().StringVar(&c.input.DictionaryID)
 
 	// Optional.
+	c.RegisterFlagBool(c.AllFlag()) // --all
 	c.CmdClause.Flag("direction", "Direction in which to sort results").Default(cmd.PaginationDirection[0]).HintOptions(cmd.PaginationDirection...).EnumVar(&c.input.Direction, cmd.PaginationDirection...)
+	c.RegisterFlag(c.FieldsFlag())   // --fields
+	c.RegisterFlag(c.FilterFlag())   // --filter
 	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterFlagInt(c.LimitFlag()) // --limit
 	c.CmdClause.Flag("page", "Page number of data set to fetch").IntVar(&c.input.Page)
 	c.CmdClause.Flag("per-page", "Number of records per page").IntVar(&c.input.PerPage)
 	c.RegisterFlag(cmd.StringFlagOpts{
@@ -71,6 +76,9 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 	}
 
 	c.input.ServiceID = serviceID
+	if c.Streaming() {
+		return c.stream(out)
+	}
 	paginator := c.Globals.APIClient.NewListDictionaryItemsPaginator(&c.input)
 
 	var o []*fastly.DictionaryItem
@@ -102,3 +110,53 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 
 	return nil
 }
+
+// stream prints the items a page at a time as they're fetched, fetching the
+// pages after the first concurrently.
+func (c *ListCommand) stream(out io.Writer) error {
+	if c.input.PerPage <= 0 {
+		c.input.PerPage = cmd.MaxPerPage
+	}
+	start := c.input.Page
+	if start <= 0 {
+		start = 1
+	}
+
+	var count int
+	w := cmd.NewPageWriter(out, &c.JSONOutput, func(items []*fastly.DictionaryItem) {
+		if !c.Globals.Verbose() {
+			fmt.Fprintf(out, "\nService ID: %s\n", c.input.ServiceID)
+		}
+		for _, item := range items {
+			count++
+			text.Output(out, "Item: %d", count)
+			text.PrintDictionaryItem(out, "\t", item)
+			text.Break(out)
+		}
+	})
+
+	first := func() ([]*fastly.DictionaryItem, int, error) {
+		var (
+			items     []*fastly.DictionaryItem
+			err       error
+			paginator = c.Globals.APIClient.NewListDictionaryItemsPaginator(&c.input)
+		)
+		if paginator.HasNext() {
+			items, err = paginator.GetNext()
+		}
+		return items, paginator.Remaining(), err
+	}
+	page := func(n int) ([]*fastly.DictionaryItem, error) {
+		i := c.input
+		i.Page = start + n
+		return c.Globals.APIClient.ListDictionaryItems(&i)
+	}
+	if err := cmd.FetchPages(c.Limit, first, page, w.Write); err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Dictionary ID": c.input.DictionaryID,
+			"Service ID":    c.input.ServiceID,
+		})
+		return err
+	}
+	return w.Close()
+}
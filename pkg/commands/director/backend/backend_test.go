@@ -0,0 +1,81 @@
+package backend_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/go-fastly/v8/fastly"
+)
+
+func TestDirectorBackendAttach(t *testing.T) {
+	args := testutil.Args
+	scenarios := []struct {
+		args       []string
+		api        mock.API
+		wantError  string
+		wantOutput string
+	}{
+		{
+			args:      args("director backend attach --service-id 123 --version 1 --backend origin-a"),
+			wantError: "error parsing arguments: required flag --director not provided",
+		},
+		{
+			args:      args("director backend attach --service-id 123 --version 1 --director origins"),
+			wantError: "error parsing arguments: required flag --backend not provided",
+		},
+		{
+			args: args("director backend attach --service-id 123 --version 1 --director origins --backend origin-a --autoclone"),
+			api: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CloneVersionFn: testutil.CloneVersionResult(4),
+				CreateDirectorBackendFn: func(i *fastly.CreateDirectorBackendInput) (*fastly.DirectorBackend, error) {
+					if i.Director != "origins" || i.Backend != "origin-a" || i.ServiceVersion != 4 {
+						return nil, errTest
+					}
+					return &fastly.DirectorBackend{
+						Backend:        i.Backend,
+						Director:       i.Director,
+						ServiceID:      i.ServiceID,
+						ServiceVersion: i.ServiceVersion,
+					}, nil
+				},
+			},
+			wantOutput: "Attached backend origin-a to director origins (service 123 version 4)",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(strings.Join(testcase.args, " "), func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.api)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+		})
+	}
+}
+
+func TestDirectorBackendDetach(t *testing.T) {
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("director backend detach --service-id 123 --version 3 --director origins --backend origin-a"), &stdout)
+	opts.APIClient = mock.APIClient(mock.API{
+		ListVersionsFn: testutil.ListVersions,
+		DeleteDirectorBackendFn: func(i *fastly.DeleteDirectorBackendInput) error {
+			if i.Director != "origins" || i.Backend != "origin-a" || i.ServiceVersion != 3 {
+				return errTest
+			}
+			return nil
+		},
+	})
+	err := app.Run(opts)
+	testutil.AssertNoError(t, err)
+	testutil.AssertStringContains(t, stdout.String(), "Detached backend origin-a from director origins (service 123 version 3)")
+}
+
+var errTest = errors.New("fixture error")
@@ -0,0 +1,3 @@
+// Package backend contains commands for attaching backends to, and detaching
+// backends from, Fastly service directors.
+package backend
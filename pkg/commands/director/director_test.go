@@ -0,0 +1,286 @@
+package director_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fastly/go-fastly/v8/fastly"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestDirectorCreate(t *testing.T) {
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name:      "validate --type is a known director type",
+			Args:      args("director create --service-id 123 --version 1 --name origins --type fallback"),
+			WantError: "error parsing arguments: enum value must be one of random,hash,client, got 'fallback'",
+		},
+		{
+			Name: "validate CreateDirector API error",
+			Args: args("director create --service-id 123 --version 1 --name origins --autoclone"),
+			API: mock.API{
+				ListVersionsFn:   testutil.ListVersions,
+				CloneVersionFn:   testutil.CloneVersionResult(4),
+				CreateDirectorFn: createDirectorError,
+			},
+			WantError: errTest.Error(),
+		},
+		// NOTE: The --type name is sent as the API's numeric director type.
+		{
+			Name: "validate the director is created from the flags",
+			Args: args("director create --service-id 123 --version 1 --name origins --autoclone --quorum 75 --retries 5 --shield iad-va-us --type client"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CloneVersionFn: testutil.CloneVersionResult(4),
+				CreateDirectorFn: createDirectorWith(fastly.CreateDirectorInput{
+					ServiceID:      "123",
+					ServiceVersion: 4,
+					Name:           fastly.String("origins"),
+					Quorum:         fastly.Int(75),
+					Retries:        fastly.Int(5),
+					Shield:         fastly.String("iad-va-us"),
+					Type:           fastly.DirectorTypePtr(fastly.DirectorTypeClient),
+				}),
+			},
+			WantOutput: "Created director origins (service 123 version 4)",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+		})
+	}
+}
+
+func TestDirectorList(t *testing.T) {
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Args: args("director list --service-id 123 --version 1"),
+			API: mock.API{
+				ListVersionsFn:  testutil.ListVersions,
+				ListDirectorsFn: listDirectorsOK,
+			},
+			WantOutput: listDirectorsShortOutput,
+		},
+		{
+			Args: args("director list --service-id 123 --version 1 --verbose"),
+			API: mock.API{
+				ListVersionsFn:  testutil.ListVersions,
+				ListDirectorsFn: listDirectorsOK,
+			},
+			WantOutput: listDirectorsVerboseOutput,
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(strings.Join(testcase.Args, " "), func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertString(t, testcase.WantOutput, stdout.String())
+		})
+	}
+}
+
+func TestDirectorDescribe(t *testing.T) {
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("director describe --service-id 123 --version 1 --name origins"), &stdout)
+	opts.APIClient = mock.APIClient(mock.API{
+		ListVersionsFn: testutil.ListVersions,
+		GetDirectorFn:  getDirectorOK,
+	})
+	err := app.Run(opts)
+	testutil.AssertNoError(t, err)
+	testutil.AssertString(t, describeDirectorOutput, stdout.String())
+}
+
+func TestDirectorUpdate(t *testing.T) {
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name:      "validate missing --name flag",
+			Args:      args("director update --service-id 123 --version 1 --quorum 50"),
+			WantError: "error parsing arguments: required flag --name not provided",
+		},
+		{
+			Name: "validate the director switches to hash load balancing",
+			Args: args("director update --service-id 123 --version 1 --name origins --quorum 50 --type hash --autoclone"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CloneVersionFn: testutil.CloneVersionResult(4),
+				UpdateDirectorFn: updateDirectorWith(fastly.UpdateDirectorInput{
+					ServiceID:      "123",
+					ServiceVersion: 4,
+					Name:           "origins",
+					Quorum:         fastly.Int(50),
+					Type:           fastly.DirectorTypeHash,
+				}),
+			},
+			WantOutput: "Updated director origins (service 123 version 4)",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+		})
+	}
+}
+
+func TestDirectorDelete(t *testing.T) {
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("director delete --service-id 123 --version 1 --name origins --autoclone"), &stdout)
+	opts.APIClient = mock.APIClient(mock.API{
+		ListVersionsFn: testutil.ListVersions,
+		CloneVersionFn: testutil.CloneVersionResult(4),
+		DeleteDirectorFn: func(i *fastly.DeleteDirectorInput) error {
+			if i.Name != "origins" || i.ServiceVersion != 4 {
+				return errTest
+			}
+			return nil
+		},
+	})
+	err := app.Run(opts)
+	testutil.AssertNoError(t, err)
+	testutil.AssertStringContains(t, stdout.String(), "Deleted director origins (service 123 version 4)")
+}
+
+var errTest = errors.New("fixture error")
+
+func createDirectorWith(want fastly.CreateDirectorInput) func(*fastly.CreateDirectorInput) (*fastly.Director, error) {
+	return func(i *fastly.CreateDirectorInput) (*fastly.Director, error) {
+		if !reflect.DeepEqual(*i, want) {
+			return nil, fmt.Errorf("unexpected input: %+v", *i)
+		}
+		return &fastly.Director{
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+			Name:           *i.Name,
+		}, nil
+	}
+}
+
+func createDirectorError(i *fastly.CreateDirectorInput) (*fastly.Director, error) {
+	return nil, errTest
+}
+
+func listDirectorsOK(i *fastly.ListDirectorsInput) ([]*fastly.Director, error) {
+	return []*fastly.Director{
+		{
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+			Name:           "origins",
+			Type:           fastly.DirectorTypeRandom,
+			Backends:       []string{"origin-a", "origin-b"},
+			Quorum:         75,
+			Retries:        5,
+		},
+		{
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+			Name:           "sticky",
+			Comment:        "session affinity",
+			Type:           fastly.DirectorTypeClient,
+			Backends:       []string{"origin-c"},
+			Quorum:         50,
+			Retries:        3,
+		},
+	}, nil
+}
+
+var listDirectorsShortOutput = strings.TrimSpace(`
+SERVICE  VERSION  NAME     TYPE    QUORUM  RETRIES
+123      1        origins  random  75      5
+123      1        sticky   client  50      3
+`) + "\n"
+
+var listDirectorsVerboseOutput = strings.Join([]string{
+	"Fastly API token not provided",
+	"Fastly API endpoint: https://api.fastly.com",
+	"",
+	"Service ID (via --service-id): 123",
+	"",
+	"Version: 1",
+	"	Director 1/2",
+	"		Name: origins",
+	"		Comment: ",
+	"		Type: random",
+	"		Backends: origin-a, origin-b",
+	"		Quorum: 75",
+	"		Retries: 5",
+	"		Capacity: 0",
+	"		Shield: ",
+	"	Director 2/2",
+	"		Name: sticky",
+	"		Comment: session affinity",
+	"		Type: client",
+	"		Backends: origin-c",
+	"		Quorum: 50",
+	"		Retries: 3",
+	"		Capacity: 0",
+	"		Shield: ",
+}, "\n") + "\n\n"
+
+func getDirectorOK(i *fastly.GetDirectorInput) (*fastly.Director, error) {
+	return &fastly.Director{
+		ServiceID:      i.ServiceID,
+		ServiceVersion: i.ServiceVersion,
+		Name:           "origins",
+		Type:           fastly.DirectorTypeRandom,
+		Backends:       []string{"origin-a", "origin-b"},
+		Quorum:         75,
+		Retries:        5,
+	}, nil
+}
+
+var describeDirectorOutput = "\n" + strings.Join([]string{
+	"Service ID: 123",
+	"Version: 1",
+	"Name: origins",
+	"Comment: ",
+	"Type: random",
+	"Backends: origin-a, origin-b",
+	"Quorum: 75",
+	"Retries: 5",
+	"Capacity: 0",
+	"Shield: ",
+}, "\n") + "\n"
+
+func updateDirectorWith(want fastly.UpdateDirectorInput) func(*fastly.UpdateDirectorInput) (*fastly.Director, error) {
+	return func(i *fastly.UpdateDirectorInput) (*fastly.Director, error) {
+		if !reflect.DeepEqual(*i, want) {
+			return nil, fmt.Errorf("unexpected input: %+v", *i)
+		}
+		name := i.Name
+		if i.NewName != nil {
+			name = *i.NewName
+		}
+		return &fastly.Director{
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+			Name:           name,
+		}, nil
+	}
+}
@@ -0,0 +1,120 @@
+package gzip
+
+import (
+	"io"
+
+	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v8/fastly"
+)
+
+// CreateCommand calls the Fastly API to create gzip configurations.
+type CreateCommand struct {
+	cmd.Base
+	manifest manifest.Data
+
+	// Required.
+	serviceVersion cmd.OptionalServiceVersion
+
+	// Optional.
+	autoClone      cmd.OptionalAutoClone
+	cacheCondition cmd.OptionalString
+	contentTypes   cmd.OptionalString
+	extensions     cmd.OptionalString
+	name           cmd.OptionalString
+	serviceName    cmd.OptionalServiceNameID
+}
+
+// NewCreateCommand returns a usable command registered under the parent.
+func NewCreateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *CreateCommand {
+	c := CreateCommand{
+		Base: cmd.Base{
+			Globals: g,
+		},
+		manifest: m,
+	}
+	c.CmdClause = parent.Command("create", "Create a gzip configuration on a Fastly service version").Alias("add")
+
+	// Required.
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagVersionName,
+		Description: cmd.FlagVersionDesc,
+		Dst:         &c.serviceVersion.Value,
+		Required:    true,
+	})
+
+	// Optional.
+	c.RegisterAutoCloneFlag(cmd.AutoCloneFlagOpts{
+		Action: c.autoClone.Set,
+		Dst:    &c.autoClone.Value,
+	})
+	c.CmdClause.Flag("cache-condition", "Name of the cache condition controlling when this configuration applies").Action(c.cacheCondition.Set).StringVar(&c.cacheCondition.Value)
+	c.CmdClause.Flag("content-types", "Space-separated list of content types to compress").Action(c.contentTypes.Set).StringVar(&c.contentTypes.Value)
+	c.CmdClause.Flag("extensions", "Space-separated list of file extensions to compress").Action(c.extensions.Set).StringVar(&c.extensions.Value)
+	c.CmdClause.Flag("name", "Name of the gzip configuration").Short('n').Action(c.name.Set).StringVar(&c.name.Value)
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
+	serviceID, serviceVersion, err := cmd.ServiceDetails(cmd.ServiceDetailsOpts{
+		AutoCloneFlag:      c.autoClone,
+		APIClient:          c.Globals.APIClient,
+		Manifest:           c.manifest,
+		Out:                out,
+		ServiceNameFlag:    c.serviceName,
+		ServiceVersionFlag: c.serviceVersion,
+		VerboseMode:        c.Globals.Flags.Verbose,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": errors.ServiceVersion(serviceVersion),
+		})
+		return err
+	}
+
+	input := fastly.CreateGzipInput{
+		ServiceID:      serviceID,
+		ServiceVersion: serviceVersion.Number,
+	}
+	if c.name.WasSet {
+		input.Name = &c.name.Value
+	}
+	if c.cacheCondition.WasSet {
+		input.CacheCondition = &c.cacheCondition.Value
+	}
+	if c.contentTypes.WasSet {
+		input.ContentTypes = &c.contentTypes.Value
+	}
+	if c.extensions.WasSet {
+		input.Extensions = &c.extensions.Value
+	}
+
+	o, err := c.Globals.APIClient.CreateGzip(&input)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": serviceVersion.Number,
+		})
+		return err
+	}
+
+	text.Success(out, "Created gzip configuration %s (service %s version %d)", o.Name, o.ServiceID, o.ServiceVersion)
+	return nil
+}
@@ -0,0 +1,266 @@
+package gzip_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fastly/go-fastly/v8/fastly"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestGzipCreate(t *testing.T) {
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name: "validate CreateGzip API error",
+			Args: args("gzip create --service-id 123 --version 1 --name compress-text --autoclone"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CloneVersionFn: testutil.CloneVersionResult(4),
+				CreateGzipFn:   createGzipError,
+			},
+			WantError: errTest.Error(),
+		},
+		// NOTE: The content types and extensions are passed to the API as the
+		// space-separated lists they're given as.
+		{
+			Name: "validate the gzip configuration is created from the flags",
+			Args: append(
+				args("gzip create --service-id 123 --version 1 --name compress-text --autoclone --cache-condition is-text --extensions css --content-types"),
+				"text/html application/json",
+			),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CloneVersionFn: testutil.CloneVersionResult(4),
+				CreateGzipFn: createGzipWith(fastly.CreateGzipInput{
+					ServiceID:      "123",
+					ServiceVersion: 4,
+					Name:           fastly.String("compress-text"),
+					CacheCondition: fastly.String("is-text"),
+					ContentTypes:   fastly.String("text/html application/json"),
+					Extensions:     fastly.String("css"),
+				}),
+			},
+			WantOutput: "Created gzip configuration compress-text (service 123 version 4)",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+		})
+	}
+}
+
+func TestGzipList(t *testing.T) {
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Args: args("gzip list --service-id 123 --version 1"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				ListGzipsFn:    listGzipsOK,
+			},
+			WantOutput: listGzipsShortOutput,
+		},
+		{
+			Args: args("gzip list --service-id 123 --version 1 --verbose"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				ListGzipsFn:    listGzipsOK,
+			},
+			WantOutput: listGzipsVerboseOutput,
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(strings.Join(testcase.Args, " "), func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertString(t, testcase.WantOutput, stdout.String())
+		})
+	}
+}
+
+func TestGzipDescribe(t *testing.T) {
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("gzip describe --service-id 123 --version 1 --name compress-text"), &stdout)
+	opts.APIClient = mock.APIClient(mock.API{
+		ListVersionsFn: testutil.ListVersions,
+		GetGzipFn:      getGzipOK,
+	})
+	err := app.Run(opts)
+	testutil.AssertNoError(t, err)
+	testutil.AssertString(t, describeGzipOutput, stdout.String())
+}
+
+func TestGzipUpdate(t *testing.T) {
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name:      "validate missing --name flag",
+			Args:      args("gzip update --service-id 123 --version 1 --new-name renamed"),
+			WantError: "error parsing arguments: required flag --name not provided",
+		},
+		{
+			Name: "validate the gzip configuration is renamed and its extensions replaced",
+			Args: append(
+				args("gzip update --service-id 123 --version 1 --name compress-text --new-name renamed --autoclone --extensions"),
+				"css js",
+			),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CloneVersionFn: testutil.CloneVersionResult(4),
+				UpdateGzipFn: updateGzipWith(fastly.UpdateGzipInput{
+					ServiceID:      "123",
+					ServiceVersion: 4,
+					Name:           "compress-text",
+					NewName:        fastly.String("renamed"),
+					Extensions:     fastly.String("css js"),
+				}),
+			},
+			WantOutput: "Updated gzip configuration renamed (service 123 version 4)",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+		})
+	}
+}
+
+func TestGzipDelete(t *testing.T) {
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("gzip delete --service-id 123 --version 1 --name compress-text --autoclone"), &stdout)
+	opts.APIClient = mock.APIClient(mock.API{
+		ListVersionsFn: testutil.ListVersions,
+		CloneVersionFn: testutil.CloneVersionResult(4),
+		DeleteGzipFn: func(i *fastly.DeleteGzipInput) error {
+			if i.Name != "compress-text" || i.ServiceVersion != 4 {
+				return errTest
+			}
+			return nil
+		},
+	})
+	err := app.Run(opts)
+	testutil.AssertNoError(t, err)
+	testutil.AssertStringContains(t, stdout.String(), "Deleted gzip configuration compress-text (service 123 version 4)")
+}
+
+var errTest = errors.New("fixture error")
+
+func createGzipWith(want fastly.CreateGzipInput) func(*fastly.CreateGzipInput) (*fastly.Gzip, error) {
+	return func(i *fastly.CreateGzipInput) (*fastly.Gzip, error) {
+		if !reflect.DeepEqual(*i, want) {
+			return nil, fmt.Errorf("unexpected input: %+v", *i)
+		}
+		return &fastly.Gzip{
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+			Name:           *i.Name,
+		}, nil
+	}
+}
+
+func createGzipError(i *fastly.CreateGzipInput) (*fastly.Gzip, error) {
+	return nil, errTest
+}
+
+func listGzipsOK(i *fastly.ListGzipsInput) ([]*fastly.Gzip, error) {
+	return []*fastly.Gzip{
+		{
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+			Name:           "text",
+			ContentTypes:   "text/html text/css",
+			Extensions:     "html css",
+			CacheCondition: "is-text",
+		},
+		{
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+			Name:           "scripts",
+			ContentTypes:   "application/javascript",
+			Extensions:     "js",
+		},
+	}, nil
+}
+
+var listGzipsShortOutput = strings.TrimSpace(`
+SERVICE  VERSION  NAME     CONTENT TYPES           EXTENSIONS
+123      1        text     text/html text/css      html css
+123      1        scripts  application/javascript  js
+`) + "\n"
+
+var listGzipsVerboseOutput = strings.Join([]string{
+	"Fastly API token not provided",
+	"Fastly API endpoint: https://api.fastly.com",
+	"",
+	"Service ID (via --service-id): 123",
+	"",
+	"Version: 1",
+	"	Gzip configuration 1/2",
+	"		Name: text",
+	"		Content types: text/html text/css",
+	"		Extensions: html css",
+	"		Cache condition: is-text",
+	"	Gzip configuration 2/2",
+	"		Name: scripts",
+	"		Content types: application/javascript",
+	"		Extensions: js",
+	"		Cache condition: ",
+}, "\n") + "\n\n"
+
+func getGzipOK(i *fastly.GetGzipInput) (*fastly.Gzip, error) {
+	return &fastly.Gzip{
+		ServiceID:      i.ServiceID,
+		ServiceVersion: i.ServiceVersion,
+		Name:           "text",
+		ContentTypes:   "text/html text/css",
+		Extensions:     "html css",
+		CacheCondition: "is-text",
+	}, nil
+}
+
+var describeGzipOutput = "\n" + strings.Join([]string{
+	"Service ID: 123",
+	"Version: 1",
+	"Name: text",
+	"Content types: text/html text/css",
+	"Extensions: html css",
+	"Cache condition: is-text",
+}, "\n") + "\n"
+
+func updateGzipWith(want fastly.UpdateGzipInput) func(*fastly.UpdateGzipInput) (*fastly.Gzip, error) {
+	return func(i *fastly.UpdateGzipInput) (*fastly.Gzip, error) {
+		if !reflect.DeepEqual(*i, want) {
+			return nil, fmt.Errorf("unexpected input: %+v", *i)
+		}
+		return &fastly.Gzip{
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+			Name:           *i.NewName,
+		}, nil
+	}
+}
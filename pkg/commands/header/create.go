@@ -0,0 +1,179 @@
+package header
+
+import (
+	"io"
+
+	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v8/fastly"
+)
+
+// headerActions are the accepted values for the --action flag.
+var headerActions = []string{
+	string(fastly.HeaderActionSet),
+	string(fastly.HeaderActionAppend),
+	string(fastly.HeaderActionDelete),
+	string(fastly.HeaderActionRegex),
+	string(fastly.HeaderActionRegexRepeat),
+}
+
+// headerTypes are the accepted values for the --type flag.
+var headerTypes = []string{
+	string(fastly.HeaderTypeRequest),
+	string(fastly.HeaderTypeFetch),
+	string(fastly.HeaderTypeCache),
+	string(fastly.HeaderTypeResponse),
+}
+
+// CreateCommand calls the Fastly API to create headers.
+type CreateCommand struct {
+	cmd.Base
+	manifest manifest.Data
+
+	// Required.
+	serviceVersion cmd.OptionalServiceVersion
+
+	// Optional.
+	action            cmd.OptionalString
+	autoClone         cmd.OptionalAutoClone
+	cacheCondition    cmd.OptionalString
+	destination       cmd.OptionalString
+	headerType        cmd.OptionalString
+	ignoreIfSet       cmd.OptionalBool
+	name              cmd.OptionalString
+	priority          cmd.OptionalInt
+	regex             cmd.OptionalString
+	requestCondition  cmd.OptionalString
+	responseCondition cmd.OptionalString
+	serviceName       cmd.OptionalServiceNameID
+	source            cmd.OptionalString
+	substitution      cmd.OptionalString
+}
+
+// NewCreateCommand returns a usable command registered under the parent.
+func NewCreateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *CreateCommand {
+	c := CreateCommand{
+		Base: cmd.Base{
+			Globals: g,
+		},
+		manifest: m,
+	}
+	c.CmdClause = parent.Command("create", "Create a header on a Fastly service version").Alias("add")
+
+	// Required.
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagVersionName,
+		Description: cmd.FlagVersionDesc,
+		Dst:         &c.serviceVersion.Value,
+		Required:    true,
+	})
+
+	// Optional.
+	c.RegisterAutoCloneFlag(cmd.AutoCloneFlagOpts{
+		Action: c.autoClone.Set,
+		Dst:    &c.autoClone.Value,
+	})
+	c.CmdClause.Flag("action", "The action to perform on the header (set, append, delete, regex, regex_repeat)").Action(c.action.Set).HintOptions(headerActions...).EnumVar(&c.action.Value, headerActions...)
+	c.CmdClause.Flag("cache-condition", "Name of the cache condition controlling when this configuration applies").Action(c.cacheCondition.Set).StringVar(&c.cacheCondition.Value)
+	c.CmdClause.Flag("dst", "Header to set (e.g. http.X-Example)").Action(c.destination.Set).StringVar(&c.destination.Value)
+	c.CmdClause.Flag("ignore-if-set", "Don't add the header if it is already set. Only applies to the 'set' action").Action(c.ignoreIfSet.Set).BoolVar(&c.ignoreIfSet.Value)
+	c.CmdClause.Flag("name", "Name of the header configuration").Short('n').Action(c.name.Set).StringVar(&c.name.Value)
+	c.CmdClause.Flag("priority", "Priority determines execution order. Lower numbers execute first").Action(c.priority.Set).IntVar(&c.priority.Value)
+	c.CmdClause.Flag("regex", "Regular expression to use. Only applies to the 'regex' and 'regex_repeat' actions").Action(c.regex.Set).StringVar(&c.regex.Value)
+	c.CmdClause.Flag("request-condition", "Name of the request condition controlling when this configuration applies").Action(c.requestCondition.Set).StringVar(&c.requestCondition.Value)
+	c.CmdClause.Flag("response-condition", "Name of the response condition controlling when this configuration applies").Action(c.responseCondition.Set).StringVar(&c.responseCondition.Value)
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	c.CmdClause.Flag("src", "Variable to be used as a source for the header content. Does not apply to the 'delete' action").Action(c.source.Set).StringVar(&c.source.Value)
+	c.CmdClause.Flag("substitution", "Value to substitute in place of the regular expression. Only applies to the 'regex' and 'regex_repeat' actions").Action(c.substitution.Set).StringVar(&c.substitution.Value)
+	c.CmdClause.Flag("type", "The point in the request lifecycle to apply the header (request, fetch, cache, response)").Action(c.headerType.Set).HintOptions(headerTypes...).EnumVar(&c.headerType.Value, headerTypes...)
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
+	serviceID, serviceVersion, err := cmd.ServiceDetails(cmd.ServiceDetailsOpts{
+		AutoCloneFlag:      c.autoClone,
+		APIClient:          c.Globals.APIClient,
+		Manifest:           c.manifest,
+		Out:                out,
+		ServiceNameFlag:    c.serviceName,
+		ServiceVersionFlag: c.serviceVersion,
+		VerboseMode:        c.Globals.Flags.Verbose,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": errors.ServiceVersion(serviceVersion),
+		})
+		return err
+	}
+
+	input := fastly.CreateHeaderInput{
+		ServiceID:      serviceID,
+		ServiceVersion: serviceVersion.Number,
+	}
+	if c.name.WasSet {
+		input.Name = &c.name.Value
+	}
+	if c.action.WasSet {
+		action := fastly.HeaderAction(c.action.Value)
+		input.Action = &action
+	}
+	if c.cacheCondition.WasSet {
+		input.CacheCondition = &c.cacheCondition.Value
+	}
+	if c.destination.WasSet {
+		input.Destination = &c.destination.Value
+	}
+	if c.ignoreIfSet.WasSet {
+		input.IgnoreIfSet = fastly.CBool(c.ignoreIfSet.Value)
+	}
+	if c.priority.WasSet {
+		input.Priority = &c.priority.Value
+	}
+	if c.regex.WasSet {
+		input.Regex = &c.regex.Value
+	}
+	if c.requestCondition.WasSet {
+		input.RequestCondition = &c.requestCondition.Value
+	}
+	if c.responseCondition.WasSet {
+		input.ResponseCondition = &c.responseCondition.Value
+	}
+	if c.source.WasSet {
+		input.Source = &c.source.Value
+	}
+	if c.substitution.WasSet {
+		input.Substitution = &c.substitution.Value
+	}
+	if c.headerType.WasSet {
+		headerType := fastly.HeaderType(c.headerType.Value)
+		input.Type = &headerType
+	}
+
+	o, err := c.Globals.APIClient.CreateHeader(&input)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": serviceVersion.Number,
+		})
+		return err
+	}
+
+	text.Success(out, "Created header %s (service %s version %d)", o.Name, o.ServiceID, o.ServiceVersion)
+	return nil
+}
@@ -0,0 +1,2 @@
+// Package header contains commands to inspect and manipulate Fastly service headers.
+package header
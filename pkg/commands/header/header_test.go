@@ -0,0 +1,325 @@
+package header_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fastly/go-fastly/v8/fastly"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestHeaderCreate(t *testing.T) {
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name:      "validate --action is a known header action",
+			Args:      args("header create --service-id 123 --version 1 --name set-origin --action replace"),
+			WantError: "error parsing arguments: enum value must be one of set,append,delete,regex,regex_repeat, got 'replace'",
+		},
+		{
+			Name:      "validate --type is a known point in the request lifecycle",
+			Args:      args("header create --service-id 123 --version 1 --name set-origin --type deliver"),
+			WantError: "error parsing arguments: enum value must be one of request,fetch,cache,response, got 'deliver'",
+		},
+		{
+			Name: "validate CreateHeader API error",
+			Args: args("header create --service-id 123 --version 1 --name set-origin --autoclone"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CloneVersionFn: testutil.CloneVersionResult(4),
+				CreateHeaderFn: createHeaderError,
+			},
+			WantError: errTest.Error(),
+		},
+		{
+			Name: "validate a set action is created from the flags",
+			Args: args(`header create --service-id 123 --version 1 --name set-origin --autoclone --action set --type request --dst http.X-Origin --src "www" --ignore-if-set --priority 10 --request-condition is-api`),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CloneVersionFn: testutil.CloneVersionResult(4),
+				CreateHeaderFn: createHeaderWith(fastly.CreateHeaderInput{
+					ServiceID:        "123",
+					ServiceVersion:   4,
+					Name:             fastly.String("set-origin"),
+					Action:           fastly.HeaderActionPtr(fastly.HeaderActionSet),
+					Type:             fastly.HeaderTypePtr(fastly.HeaderTypeRequest),
+					Destination:      fastly.String("http.X-Origin"),
+					Source:           fastly.String(`"www"`),
+					IgnoreIfSet:      fastly.CBool(true),
+					Priority:         fastly.Int(10),
+					RequestCondition: fastly.String("is-api"),
+				}),
+			},
+			WantOutput: "Created header set-origin (service 123 version 4)",
+		},
+		{
+			Name: "validate a regex action is created from the flags",
+			Args: args(`header create --service-id 123 --version 1 --name strip-www --autoclone --action regex --type request --dst http.Host --src req.http.Host --regex ^www\.(.*) --substitution \1`),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CloneVersionFn: testutil.CloneVersionResult(4),
+				CreateHeaderFn: createHeaderWith(fastly.CreateHeaderInput{
+					ServiceID:      "123",
+					ServiceVersion: 4,
+					Name:           fastly.String("strip-www"),
+					Action:         fastly.HeaderActionPtr(fastly.HeaderActionRegex),
+					Type:           fastly.HeaderTypePtr(fastly.HeaderTypeRequest),
+					Destination:    fastly.String("http.Host"),
+					Source:         fastly.String("req.http.Host"),
+					Regex:          fastly.String(`^www\.(.*)`),
+					Substitution:   fastly.String(`\1`),
+				}),
+			},
+			WantOutput: "Created header strip-www (service 123 version 4)",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+		})
+	}
+}
+
+func TestHeaderList(t *testing.T) {
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Args: args("header list --service-id 123 --version 1"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				ListHeadersFn:  listHeadersOK,
+			},
+			WantOutput: listHeadersShortOutput,
+		},
+		{
+			Args: args("header list --service-id 123 --version 1 --verbose"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				ListHeadersFn:  listHeadersOK,
+			},
+			WantOutput: listHeadersVerboseOutput,
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(strings.Join(testcase.Args, " "), func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertString(t, testcase.WantOutput, stdout.String())
+		})
+	}
+}
+
+func TestHeaderDescribe(t *testing.T) {
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("header describe --service-id 123 --version 1 --name set-origin"), &stdout)
+	opts.APIClient = mock.APIClient(mock.API{
+		ListVersionsFn: testutil.ListVersions,
+		GetHeaderFn:    getHeaderOK,
+	})
+	err := app.Run(opts)
+	testutil.AssertNoError(t, err)
+	testutil.AssertString(t, describeHeaderOutput, stdout.String())
+}
+
+func TestHeaderUpdate(t *testing.T) {
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name:      "validate missing --name flag",
+			Args:      args("header update --service-id 123 --version 1 --new-name renamed"),
+			WantError: "error parsing arguments: required flag --name not provided",
+		},
+		{
+			Name: "validate the header is renamed and moved to the response",
+			Args: args("header update --service-id 123 --version 1 --name set-origin --new-name renamed --type response --response-condition is-error --autoclone"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CloneVersionFn: testutil.CloneVersionResult(4),
+				UpdateHeaderFn: updateHeaderWith(fastly.UpdateHeaderInput{
+					ServiceID:         "123",
+					ServiceVersion:    4,
+					Name:              "set-origin",
+					NewName:           fastly.String("renamed"),
+					Type:              fastly.HeaderTypePtr(fastly.HeaderTypeResponse),
+					ResponseCondition: fastly.String("is-error"),
+				}),
+			},
+			WantOutput: "Updated header renamed (service 123 version 4)",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+		})
+	}
+}
+
+func TestHeaderDelete(t *testing.T) {
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("header delete --service-id 123 --version 1 --name set-origin --autoclone"), &stdout)
+	opts.APIClient = mock.APIClient(mock.API{
+		ListVersionsFn: testutil.ListVersions,
+		CloneVersionFn: testutil.CloneVersionResult(4),
+		DeleteHeaderFn: func(i *fastly.DeleteHeaderInput) error {
+			if i.Name != "set-origin" || i.ServiceVersion != 4 {
+				return errTest
+			}
+			return nil
+		},
+	})
+	err := app.Run(opts)
+	testutil.AssertNoError(t, err)
+	testutil.AssertStringContains(t, stdout.String(), "Deleted header set-origin (service 123 version 4)")
+}
+
+var errTest = errors.New("fixture error")
+
+func createHeaderWith(want fastly.CreateHeaderInput) func(*fastly.CreateHeaderInput) (*fastly.Header, error) {
+	return func(i *fastly.CreateHeaderInput) (*fastly.Header, error) {
+		if !reflect.DeepEqual(*i, want) {
+			return nil, fmt.Errorf("unexpected input: %+v", *i)
+		}
+		return &fastly.Header{
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+			Name:           *i.Name,
+		}, nil
+	}
+}
+
+func createHeaderError(i *fastly.CreateHeaderInput) (*fastly.Header, error) {
+	return nil, errTest
+}
+
+func listHeadersOK(i *fastly.ListHeadersInput) ([]*fastly.Header, error) {
+	return []*fastly.Header{
+		{
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+			Name:           "set-origin",
+			Type:           fastly.HeaderTypeRequest,
+			Action:         fastly.HeaderActionSet,
+			Destination:    "http.X-Origin",
+			Source:         `"www"`,
+			Priority:       10,
+		},
+		{
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+			Name:           "cache-status",
+			Type:           fastly.HeaderTypeResponse,
+			Action:         fastly.HeaderActionSet,
+			Destination:    "http.X-Cache-Status",
+			Source:         "fastly_info.state",
+			Priority:       100,
+			IgnoreIfSet:    true,
+		},
+	}, nil
+}
+
+var listHeadersShortOutput = strings.TrimSpace(`
+SERVICE  VERSION  NAME          TYPE      ACTION  DESTINATION          SOURCE
+123      1        set-origin    request   set     http.X-Origin        "www"
+123      1        cache-status  response  set     http.X-Cache-Status  fastly_info.state
+`) + "\n"
+
+var listHeadersVerboseOutput = strings.Join([]string{
+	"Fastly API token not provided",
+	"Fastly API endpoint: https://api.fastly.com",
+	"",
+	"Service ID (via --service-id): 123",
+	"",
+	"Version: 1",
+	"	Header 1/2",
+	"		Name: set-origin",
+	"		Type: request",
+	"		Action: set",
+	"		Destination: http.X-Origin",
+	`		Source: "www"`,
+	"		Regex: ",
+	"		Substitution: ",
+	"		Ignore if set: false",
+	"		Priority: 10",
+	"		Request condition: ",
+	"		Cache condition: ",
+	"		Response condition: ",
+	"	Header 2/2",
+	"		Name: cache-status",
+	"		Type: response",
+	"		Action: set",
+	"		Destination: http.X-Cache-Status",
+	"		Source: fastly_info.state",
+	"		Regex: ",
+	"		Substitution: ",
+	"		Ignore if set: true",
+	"		Priority: 100",
+	"		Request condition: ",
+	"		Cache condition: ",
+	"		Response condition: ",
+}, "\n") + "\n\n"
+
+func getHeaderOK(i *fastly.GetHeaderInput) (*fastly.Header, error) {
+	return &fastly.Header{
+		ServiceID:      i.ServiceID,
+		ServiceVersion: i.ServiceVersion,
+		Name:           "set-origin",
+		Type:           fastly.HeaderTypeRequest,
+		Action:         fastly.HeaderActionSet,
+		Destination:    "http.X-Origin",
+		Source:         `"www"`,
+		Priority:       10,
+	}, nil
+}
+
+var describeHeaderOutput = "\n" + strings.Join([]string{
+	"Service ID: 123",
+	"Version: 1",
+	"Name: set-origin",
+	"Type: request",
+	"Action: set",
+	"Destination: http.X-Origin",
+	`Source: "www"`,
+	"Regex: ",
+	"Substitution: ",
+	"Ignore if set: false",
+	"Priority: 10",
+	"Request condition: ",
+	"Cache condition: ",
+	"Response condition: ",
+}, "\n") + "\n"
+
+func updateHeaderWith(want fastly.UpdateHeaderInput) func(*fastly.UpdateHeaderInput) (*fastly.Header, error) {
+	return func(i *fastly.UpdateHeaderInput) (*fastly.Header, error) {
+		if !reflect.DeepEqual(*i, want) {
+			return nil, fmt.Errorf("unexpected input: %+v", *i)
+		}
+		return &fastly.Header{
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+			Name:           *i.NewName,
+		}, nil
+	}
+}
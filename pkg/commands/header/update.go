@@ -0,0 +1,171 @@
+package header
+
+import (
+	"io"
+
+	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v8/fastly"
+)
+
+// UpdateCommand calls the Fastly API to update headers.
+type UpdateCommand struct {
+	cmd.Base
+	manifest       manifest.Data
+	input          fastly.UpdateHeaderInput
+	serviceName    cmd.OptionalServiceNameID
+	serviceVersion cmd.OptionalServiceVersion
+	autoClone      cmd.OptionalAutoClone
+
+	NewName           cmd.OptionalString
+	Action            cmd.OptionalString
+	CacheCondition    cmd.OptionalString
+	Destination       cmd.OptionalString
+	IgnoreIfSet       cmd.OptionalBool
+	Priority          cmd.OptionalInt
+	Regex             cmd.OptionalString
+	RequestCondition  cmd.OptionalString
+	ResponseCondition cmd.OptionalString
+	Source            cmd.OptionalString
+	Substitution      cmd.OptionalString
+	Type              cmd.OptionalString
+}
+
+// NewUpdateCommand returns a usable command registered under the parent.
+func NewUpdateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *UpdateCommand {
+	c := UpdateCommand{
+		Base: cmd.Base{
+			Globals: g,
+		},
+		manifest: m,
+	}
+	c.CmdClause = parent.Command("update", "Update a header on a Fastly service version")
+
+	// Required.
+	c.CmdClause.Flag("name", "Name of the header configuration").Short('n').Required().StringVar(&c.input.Name)
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagVersionName,
+		Description: cmd.FlagVersionDesc,
+		Dst:         &c.serviceVersion.Value,
+		Required:    true,
+	})
+
+	// Optional.
+	c.RegisterAutoCloneFlag(cmd.AutoCloneFlagOpts{
+		Action: c.autoClone.Set,
+		Dst:    &c.autoClone.Value,
+	})
+	c.CmdClause.Flag("action", "The action to perform on the header (set, append, delete, regex, regex_repeat)").Action(c.Action.Set).HintOptions(headerActions...).EnumVar(&c.Action.Value, headerActions...)
+	c.CmdClause.Flag("cache-condition", "Name of the cache condition controlling when this configuration applies").Action(c.CacheCondition.Set).StringVar(&c.CacheCondition.Value)
+	c.CmdClause.Flag("dst", "Header to set (e.g. http.X-Example)").Action(c.Destination.Set).StringVar(&c.Destination.Value)
+	c.CmdClause.Flag("ignore-if-set", "Don't add the header if it is already set. Only applies to the 'set' action").Action(c.IgnoreIfSet.Set).BoolVar(&c.IgnoreIfSet.Value)
+	c.CmdClause.Flag("new-name", "New name of the header").Action(c.NewName.Set).StringVar(&c.NewName.Value)
+	c.CmdClause.Flag("priority", "Priority determines execution order. Lower numbers execute first").Action(c.Priority.Set).IntVar(&c.Priority.Value)
+	c.CmdClause.Flag("regex", "Regular expression to use. Only applies to the 'regex' and 'regex_repeat' actions").Action(c.Regex.Set).StringVar(&c.Regex.Value)
+	c.CmdClause.Flag("request-condition", "Name of the request condition controlling when this configuration applies").Action(c.RequestCondition.Set).StringVar(&c.RequestCondition.Value)
+	c.CmdClause.Flag("response-condition", "Name of the response condition controlling when this configuration applies").Action(c.ResponseCondition.Set).StringVar(&c.ResponseCondition.Value)
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	c.CmdClause.Flag("src", "Variable to be used as a source for the header content. Does not apply to the 'delete' action").Action(c.Source.Set).StringVar(&c.Source.Value)
+	c.CmdClause.Flag("substitution", "Value to substitute in place of the regular expression. Only applies to the 'regex' and 'regex_repeat' actions").Action(c.Substitution.Set).StringVar(&c.Substitution.Value)
+	c.CmdClause.Flag("type", "The point in the request lifecycle to apply the header (request, fetch, cache, response)").Action(c.Type.Set).HintOptions(headerTypes...).EnumVar(&c.Type.Value, headerTypes...)
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *UpdateCommand) Exec(_ io.Reader, out io.Writer) error {
+	serviceID, serviceVersion, err := cmd.ServiceDetails(cmd.ServiceDetailsOpts{
+		AutoCloneFlag:      c.autoClone,
+		APIClient:          c.Globals.APIClient,
+		Manifest:           c.manifest,
+		Out:                out,
+		ServiceNameFlag:    c.serviceName,
+		ServiceVersionFlag: c.serviceVersion,
+		VerboseMode:        c.Globals.Flags.Verbose,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": errors.ServiceVersion(serviceVersion),
+		})
+		return err
+	}
+
+	c.input.ServiceID = serviceID
+	c.input.ServiceVersion = serviceVersion.Number
+
+	if c.NewName.WasSet {
+		c.input.NewName = &c.NewName.Value
+	}
+
+	if c.Action.WasSet {
+		action := fastly.HeaderAction(c.Action.Value)
+		c.input.Action = &action
+	}
+
+	if c.CacheCondition.WasSet {
+		c.input.CacheCondition = &c.CacheCondition.Value
+	}
+
+	if c.Destination.WasSet {
+		c.input.Destination = &c.Destination.Value
+	}
+
+	if c.IgnoreIfSet.WasSet {
+		c.input.IgnoreIfSet = fastly.CBool(c.IgnoreIfSet.Value)
+	}
+
+	if c.Priority.WasSet {
+		c.input.Priority = &c.Priority.Value
+	}
+
+	if c.Regex.WasSet {
+		c.input.Regex = &c.Regex.Value
+	}
+
+	if c.RequestCondition.WasSet {
+		c.input.RequestCondition = &c.RequestCondition.Value
+	}
+
+	if c.ResponseCondition.WasSet {
+		c.input.ResponseCondition = &c.ResponseCondition.Value
+	}
+
+	if c.Source.WasSet {
+		c.input.Source = &c.Source.Value
+	}
+
+	if c.Substitution.WasSet {
+		c.input.Substitution = &c.Substitution.Value
+	}
+
+	if c.Type.WasSet {
+		headerType := fastly.HeaderType(c.Type.Value)
+		c.input.Type = &headerType
+	}
+
+	o, err := c.Globals.APIClient.UpdateHeader(&c.input)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": serviceVersion.Number,
+		})
+		return err
+	}
+
+	text.Success(out, "Updated header %s (service %s version %d)", o.Name, o.ServiceID, o.ServiceVersion)
+	return nil
+}
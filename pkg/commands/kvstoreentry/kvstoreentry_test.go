@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -361,6 +362,20 @@ func TestListCommand(t *testing.T) {
 			},
 			WantOutput: fstfmt.EncodeJSON(testItems),
 		},
+		{
+			Args: testutil.Args(fmt.Sprintf("%s list --store-id %s --all --json", kvstoreentry.RootName, storeID)),
+			API: mock.API{
+				ListKVStoreKeysFn: listKVStoreKeysPages(testItems),
+			},
+			WantOutput: fstfmt.EncodeJSON(testItems),
+		},
+		{
+			Args: testutil.Args(fmt.Sprintf("%s list --store-id %s --limit 2", kvstoreentry.RootName, storeID)),
+			API: mock.API{
+				ListKVStoreKeysFn: listKVStoreKeysPages(testItems),
+			},
+			WantOutput: "key-00\nkey-01\n",
+		},
 	}
 
 	for _, testcase := range scenarios {
@@ -379,6 +394,24 @@ func TestListCommand(t *testing.T) {
 	}
 }
 
+// listKVStoreKeysPages returns the keys one per page.
+func listKVStoreKeysPages(keys []string) func(i *fastly.ListKVStoreKeysInput) (*fastly.ListKVStoreKeysResponse, error) {
+	return func(i *fastly.ListKVStoreKeysInput) (*fastly.ListKVStoreKeysResponse, error) {
+		var n int
+		if i.Cursor != "" {
+			n, _ = strconv.Atoi(i.Cursor)
+		}
+		o := &fastly.ListKVStoreKeysResponse{
+			Data: keys[n : n+1],
+			Meta: map[string]string{},
+		}
+		if n+1 < len(keys) {
+			o.Meta["next_cursor"] = strconv.Itoa(n + 1)
+		}
+		return o, nil
+	}
+}
+
 type mockKVStoresEntriesPaginator struct {
 	next bool
 	keys []string
@@ -16,6 +16,7 @@ import (
 type ListCommand struct {
 	cmd.Base
 	cmd.JSONOutput
+	cmd.Pagination
 
 	manifest manifest.Data
 	Input    fastly.ListKVStoreKeysInput
@@ -36,7 +37,11 @@ func NewListCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Lis
 	c.CmdClause.Flag("store-id", "Store ID").Short('s').Required().StringVar(&c.Input.ID)
 
 	// Optional.
+	c.RegisterFlagBool(c.AllFlag())  // --all
+	c.RegisterFlag(c.FieldsFlag())   // --fields
+	c.RegisterFlag(c.FilterFlag())   // --filter
 	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterFlagInt(c.LimitFlag()) // --limit
 	return &c
 }
 
@@ -46,6 +51,10 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 		return fsterr.ErrInvalidVerboseJSONCombo
 	}
 
+	if c.Streaming() {
+		return c.stream(out)
+	}
+
 	var (
 		cursor string
 		keys   []string
@@ -122,3 +131,33 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 	}
 	return nil
 }
+
+// stream prints the keys a page at a time as they're fetched.
+//
+// NOTE: The pages are fetched sequentially as each page is identified by a
+// cursor returned with the previous page.
+func (c *ListCommand) stream(out io.Writer) error {
+	w := cmd.NewPageWriter(out, &c.JSONOutput, func(keys []string) {
+		if c.Globals.Flags.Verbose {
+			text.PrintKVStoreKeys(out, "", keys)
+			return
+		}
+		for _, k := range keys {
+			text.Output(out, k)
+		}
+	})
+
+	page := func(cursor string) ([]string, string, error) {
+		c.Input.Cursor = cursor
+		o, err := c.Globals.APIClient.ListKVStoreKeys(&c.Input)
+		if err != nil {
+			return nil, "", err
+		}
+		return o.Data, o.Meta["next_cursor"], nil
+	}
+	if err := cmd.FetchCursorPages(c.Limit, c.Input.Cursor, page, w.Write); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	return w.Close()
+}
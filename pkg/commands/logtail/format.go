@@ -0,0 +1,130 @@
+package logtail
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+)
+
+// The output formats accepted by the --format flag.
+const (
+	FormatText   = "text"
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+	FormatLogfmt = "logfmt"
+)
+
+// Formats is a list of the output formats accepted by the --format flag.
+var Formats = []string{FormatText, FormatJSON, FormatNDJSON, FormatLogfmt}
+
+// logOutput is a log as displayed by the structured output formats.
+//
+// NOTE: Unlike the text format, the request ID isn't truncated.
+type logOutput struct {
+	Service        string `json:"service,omitempty"`
+	RequestStart   string `json:"request_start"`
+	SequenceNumber int    `json:"sequence_number"`
+	Stream         string `json:"stream"`
+	RequestID      string `json:"request_id"`
+	Message        string `json:"message"`
+}
+
+// formatLog renders the log in the given output format.
+func formatLog(format string, l Log) (string, error) {
+	o := logOutput{
+		Service:        l.Service,
+		RequestStart:   l.RequestStartFromRaw().UTC().Format(time.RFC3339Nano),
+		SequenceNumber: l.SequenceNum,
+		Stream:         l.Stream,
+		RequestID:      l.RequestID,
+		Message:        l.Message,
+	}
+
+	switch format {
+	case FormatJSON:
+		data, err := json.MarshalIndent(o, "", "  ")
+		return string(data), err
+	case FormatNDJSON:
+		data, err := json.Marshal(o)
+		return string(data), err
+	case FormatLogfmt:
+		var service string
+		if o.Service != "" {
+			service = "service=" + logfmtValue(o.Service) + " "
+		}
+		return service + fmt.Sprintf("request_start=%s sequence_number=%d stream=%s request_id=%s message=%s",
+			logfmtValue(o.RequestStart),
+			o.SequenceNumber,
+			logfmtValue(o.Stream),
+			logfmtValue(o.RequestID),
+			logfmtValue(o.Message),
+		), nil
+	default:
+		if l.Service != "" {
+			return fmt.Sprintf("%s | %s", l.Service, l.String()), nil
+		}
+		return l.String(), nil
+	}
+}
+
+// logfmtValue quotes the value if it can't be represented as a bare logfmt
+// value.
+func logfmtValue(v string) string {
+	if v == "" || strings.ContainsAny(v, " =\"\\") || strings.IndexFunc(v, func(r rune) bool { return r < ' ' || r == 0x7f }) >= 0 {
+		return strconv.Quote(v)
+	}
+	return v
+}
+
+// logFilter selects the logs to be displayed.
+type logFilter struct {
+	// grep matches the log message.
+	grep *regexp.Regexp
+	// requestID matches the request ID, or a prefix of it (as displayed by the
+	// text format).
+	requestID string
+	// since excludes logs of requests that started before it.
+	since time.Time
+}
+
+// newLogFilter compiles the filter from the flags.
+func newLogFilter(cfg cfg) (logFilter, error) {
+	f := logFilter{requestID: cfg.requestID}
+	if cfg.grep != "" {
+		re, err := regexp.Compile(cfg.grep)
+		if err != nil {
+			return f, fsterr.RemediationError{
+				Inner:       fmt.Errorf("invalid --grep pattern: %w", err),
+				Remediation: "Provide a regular expression using the Go syntax: https://pkg.go.dev/regexp/syntax",
+			}
+		}
+		f.grep = re
+	}
+	if cfg.since > 0 {
+		f.since = time.Now().Add(-cfg.since)
+	}
+	return f, nil
+}
+
+// filterLogs returns only the logs that match the filter.
+func filterLogs(f logFilter, logs []Log) []Log {
+	var out []Log
+	for _, l := range logs {
+		if f.grep != nil && !f.grep.MatchString(l.Message) {
+			continue
+		}
+		if f.requestID != "" && !strings.HasPrefix(l.RequestID, f.requestID) {
+			continue
+		}
+		if !f.since.IsZero() && l.RequestStartFromRaw().Before(f.since) {
+			continue
+		}
+		out = append(out, l)
+	}
+	return out
+}
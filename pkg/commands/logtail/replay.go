@@ -0,0 +1,102 @@
+package logtail
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// ReplayCommand displays the logs of a session saved with `log-tail --save`.
+//
+// NOTE: The output flags (e.g. --format, --grep, --stream) are those of the
+// parent `log-tail` command, as kingpin doesn't allow a subcommand to redefine
+// the flags of its parent.
+type ReplayCommand struct {
+	cmd.Base
+
+	root    *RootCommand
+	session string
+}
+
+// NewReplayCommand returns a usable command registered under the parent.
+func NewReplayCommand(parent *RootCommand, g *global.Data) *ReplayCommand {
+	var c ReplayCommand
+	c.Globals = g
+	c.root = parent
+	c.CmdClause = parent.CmdClause.Command("replay", "Display the logs of a session saved with `log-tail --save`, applying the --format, --grep, --request-id, --since and --stream flags")
+	c.CmdClause.Arg("session", "Path to the saved session").Required().StringVar(&c.session)
+	return &c
+}
+
+// Exec implements the command interface.
+func (c *ReplayCommand) Exec(_ io.Reader, out io.Writer) error {
+	cfg := c.root.cfg
+	filter, err := newLogFilter(cfg)
+	if err != nil {
+		return err
+	}
+
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as we need to read the session provided by the user.
+	/* #nosec */
+	f, err := os.Open(c.session)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return fmt.Errorf("error reading %s: %w", c.session, err)
+	}
+	defer f.Close() // #nosec G307
+
+	// NOTE: The batches are passed through the same output loop as `log-tail`
+	// so logs are sorted, filtered and formatted in the same way.
+	tail := &RootCommand{
+		Base:    c.Base,
+		batchCh: make(chan Batch),
+		cfg:     cfg,
+		dieCh:   make(chan struct{}),
+		doneCh:  make(chan struct{}),
+		filter:  filter,
+	}
+	defer close(tail.dieCh)
+
+	finished := make(chan struct{})
+	go func() {
+		tail.outputLoop(out)
+		close(finished)
+	}()
+
+	scanner := bufio.NewScanner(f)
+	// NOTE: A batch is saved on a single line, which can be as big as a
+	// response from the API.
+	const tmb = 10 << 20
+	scanner.Buffer(make([]byte, 64*1024), tmb)
+
+	var line int
+	for scanner.Scan() {
+		line++
+		b := scanner.Bytes()
+		if len(b) == 0 {
+			continue
+		}
+		batch, err := parseResponseData(b)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			text.Warning(out, "unable to parse batch on line %d of %s: %v", line, c.session, err)
+			continue
+		}
+		tail.batchCh <- batch
+	}
+	close(tail.doneCh)
+	<-finished
+
+	if err := scanner.Err(); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return fmt.Errorf("error reading %s: %w", c.session, err)
+	}
+	return nil
+}
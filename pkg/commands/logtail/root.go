@@ -14,14 +14,17 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/manifest"
 	"github.com/fastly/cli/pkg/text"
 	"github.com/fastly/go-fastly/v8/fastly"
+	"github.com/fatih/color"
 	"github.com/tomnomnom/linkheader"
 )
 
@@ -35,24 +38,47 @@ type RootCommand struct {
 	cfg         cfg
 	dieCh       chan struct{} // channel to end output/printing
 	doneCh      chan struct{} // channel to signal we've reached the end of the run
+	filter      logFilter     // the logs to print, compiled from cfg
+	merge       bool          // order logs across services by request start
+	saveFile    *os.File      // the --save file, if set
+	saveMu      sync.Mutex    // serialises writes to saveFile
 	hClient     *http.Client  // TODO: this will go away when GET is in go-fastly
 	manifest    manifest.Data
+	serviceIDs  []string                       // the --service-id flags
+	colours     map[string]func(...any) string // colour of each service name
 	serviceName cmd.OptionalServiceNameID
 	token       string // TODO: this will go away when GET is in go-fastly
 }
 
+// tailedService is a service whose logs are tailed.
+type tailedService struct {
+	id string
+	// name prefixes the logs of the service, and is only set when tailing
+	// several services.
+	name string
+	// path is the full path to fetch the logs of the service from.
+	path string
+}
+
+// serviceColours are the colours used to tell the logs of each service apart.
+var serviceColours = []color.Attribute{
+	color.FgCyan,
+	color.FgMagenta,
+	color.FgYellow,
+	color.FgGreen,
+	color.FgBlue,
+	color.FgRed,
+}
+
 // NewRootCommand returns a new command registered in the parent.
 func NewRootCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *RootCommand {
 	var c RootCommand
 	c.Globals = g
 	c.manifest = m
-	c.CmdClause = parent.Command("log-tail", "Tail Compute@Edge logs")
-	c.RegisterFlag(cmd.StringFlagOpts{
-		Name:        cmd.FlagServiceIDName,
-		Description: cmd.FlagServiceIDDesc,
-		Dst:         &c.manifest.Flag.ServiceID,
-		Short:       's',
-	})
+	c.CmdClause = parent.Command("log-tail", "Tail Compute@Edge logs").OptionalSubcommands()
+	// NOTE: The flag can be repeated to tail several services, so it isn't
+	// registered with RegisterFlag (see selectServices).
+	c.CmdClause.Flag(cmd.FlagServiceIDName, cmd.FlagServiceIDDesc+". Repeat to tail several services").Short('s').StringsVar(&c.serviceIDs)
 	c.RegisterFlag(cmd.StringFlagOpts{
 		Action:      c.serviceName.Set,
 		Name:        cmd.FlagServiceName,
@@ -64,24 +90,44 @@ func NewRootCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Roo
 	c.CmdClause.Flag("sort-buffer", "Duration of sort buffer for received logs").Default("1s").DurationVar(&c.cfg.sortBuffer)
 	c.CmdClause.Flag("search-padding", "Time beyond from/to to consider in searches").Default("2s").DurationVar(&c.cfg.searchPadding)
 	c.CmdClause.Flag("stream", "Output: stdout, stderr, both (default)").StringVar(&c.cfg.stream)
+	c.CmdClause.Flag("format", "Output format: text (default), json, ndjson, logfmt").Default(FormatText).HintOptions(Formats...).EnumVar(&c.cfg.format, Formats...)
+	c.CmdClause.Flag("grep", "Only display logs whose message matches the regular expression").StringVar(&c.cfg.grep)
+	c.CmdClause.Flag("request-id", "Only display logs of the request with this ID (or ID prefix)").StringVar(&c.cfg.requestID)
+	c.CmdClause.Flag("since", "Only display logs of requests that started within this duration (e.g. 10m), starting the tail from then if --from isn't set").DurationVar(&c.cfg.since)
+	c.CmdClause.Flag("save", "Save every batch of logs received to a file (replay it with `log-tail replay`)").StringVar(&c.cfg.save)
 	return &c
 }
 
 // Exec implements the command interface.
 func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
-	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
+	services, err := c.selectServices(out)
 	if err != nil {
 		return err
 	}
-	if c.Globals.Verbose() {
-		cmd.DisplayServiceID(serviceID, flag, source, out)
-	}
 
-	c.Input.ServiceID = serviceID
+	c.filter, err = newLogFilter(c.cfg)
+	if err != nil {
+		return err
+	}
+	if !c.filter.since.IsZero() && c.cfg.from == 0 {
+		c.cfg.from = c.filter.since.Unix()
+	}
 
 	c.Input.Kind = fastly.ManagedLoggingInstanceOutput
 	endpoint, _ := c.Globals.Endpoint()
-	c.cfg.path = fmt.Sprintf("%s/service/%s/log_stream/managed/instance_output", endpoint, c.Input.ServiceID)
+	for i := range services {
+		services[i].path = fmt.Sprintf("%s/service/%s/log_stream/managed/instance_output", endpoint, services[i].id)
+	}
+
+	// NOTE: The logs of several services are received independently of each
+	// other, so they're merged by the start time of their request.
+	c.merge = len(services) > 1 && c.cfg.sortBuffer > 0
+	if len(services) > 1 {
+		c.colours = make(map[string]func(...any) string)
+		for i, s := range services {
+			c.colours[s.name] = color.New(serviceColours[i%len(serviceColours)]).SprintFunc()
+		}
+	}
 
 	c.dieCh = make(chan struct{})
 	c.batchCh = make(chan Batch)
@@ -90,41 +136,139 @@ func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
 	c.hClient = http.DefaultClient
 	c.token, _ = c.Globals.Token()
 
+	if c.cfg.save != "" {
+		// gosec flagged this:
+		// G304 (CWE-22): Potential file inclusion via variable
+		// Disabling as we need to write to the file provided by the user.
+		/* #nosec */
+		c.saveFile, err = os.Create(c.cfg.save)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("error creating %s: %w", c.cfg.save, err)
+		}
+		defer c.saveFile.Close() // #nosec G307
+	}
+
 	// Adjust the from/to times if they are
 	// defined. We adjust the times based on searchPadding.
 	c.adjustTimes()
 
 	// Enable managed logging if not already enabled.
-	if err := c.enableManagedLogging(out); err != nil {
-		c.Globals.ErrLog.Add(err)
-		return err
+	for _, s := range services {
+		if err := c.enableManagedLogging(out, s.id); err != nil {
+			c.Globals.ErrLog.Add(err)
+			return err
+		}
 	}
 
 	sigs := make(chan os.Signal, 2)
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
 
 	// Start the output loop.
-	go c.outputLoop(out)
+	finished := make(chan struct{})
+	go func() {
+		c.outputLoop(out)
+		close(finished)
+	}()
 
-	// Start tailing the logs.
-	go c.tail(out)
+	// Start tailing the logs of each service. Once all of them have reached
+	// the end of the run, the output loop prints what's left and finishes.
+	var wg sync.WaitGroup
+	for _, s := range services {
+		wg.Add(1)
+		go func(s tailedService) {
+			defer wg.Done()
+			c.tail(out, s)
+		}(s)
+	}
+	go func() {
+		wg.Wait()
+		close(c.doneCh)
+	}()
 
-	<-sigs
+	select {
+	case <-sigs:
+	case <-finished:
+	}
 	close(c.dieCh)
 
 	return nil
 }
 
+// selectServices returns the services to tail.
+//
+// A single service is resolved like any other command (i.e. from the
+// --service-id or --service-name flags, FASTLY_SERVICE_ID, then fastly.toml).
+// Several services must each be given with --service-id, and their names are
+// looked up to tell their logs apart.
+func (c *RootCommand) selectServices(out io.Writer) ([]tailedService, error) {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, id := range c.serviceIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) < 2 {
+		if len(ids) == 1 {
+			c.manifest.Flag.ServiceID = ids[0]
+		}
+		serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
+		if err != nil {
+			return nil, err
+		}
+		if c.Globals.Verbose() {
+			cmd.DisplayServiceID(serviceID, flag, source, out)
+		}
+		c.Input.ServiceID = serviceID
+		return []tailedService{{id: serviceID}}, nil
+	}
+
+	if c.serviceName.WasSet {
+		err := fmt.Errorf("--service-name can't be used with more than one --service-id")
+		c.Globals.ErrLog.Add(err)
+		return nil, fsterr.RemediationError{
+			Inner:       err,
+			Remediation: "Provide the ID of each service to tail with --service-id.",
+		}
+	}
+
+	services := make([]tailedService, 0, len(ids))
+	for _, id := range ids {
+		s, err := c.Globals.APIClient.GetService(&fastly.GetServiceInput{ID: id})
+		if err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Service ID": id,
+			})
+			return nil, err
+		}
+		name := s.Name
+		if name == "" {
+			name = id
+		}
+		services = append(services, tailedService{id: id, name: name})
+	}
+	if c.Globals.Verbose() {
+		for _, s := range services {
+			text.Output(out, "Service: %s (%s)", s.name, s.id)
+		}
+		text.Break(out)
+	}
+	return services, nil
+}
+
 // Tail starts the virtual tail process. Tail fetches data from the eventbuffer
-// API. It hands off the requested logs to the outputloop for the actual
-// printing.
-func (c *RootCommand) tail(out io.Writer) {
+// API for the given service. It hands off the requested logs to the outputloop
+// for the actual printing.
+func (c *RootCommand) tail(out io.Writer, s tailedService) {
 	// Start this with --from and --to if set.
 	curWindow := c.cfg.from
 	toWindow := c.cfg.to
 
 	// Start the loop with an initial address to query.
-	path := makeNewPath(out, c.cfg.path, curWindow, "")
+	path := makeNewPath(out, s.path, curWindow, "")
 
 	// lastBatchID keeps the last successfully read Batch.ID in case we need
 	// re-request on failure.
@@ -135,8 +279,7 @@ func (c *RootCommand) tail(out io.Writer) {
 		if toWindow != 0 && curWindow > toWindow {
 			text.Info(out, "Reached window: %v which is newer than the requested 'to': %v", curWindow, toWindow)
 			// We are done, but we still want printing to finish.
-			close(c.doneCh)
-			break
+			return
 		}
 
 		req, err := http.NewRequest("GET", path, nil)
@@ -222,6 +365,10 @@ func (c *RootCommand) tail(out io.Writer) {
 				// anything fails along the way, we
 				// can re-request.
 				lastBatchID = batch.ID
+				for i := range batch.Logs {
+					batch.Logs[i].Service = s.name
+				}
+				c.saveBatch(out, b, batch)
 				// Send batch down batchCh to the output loop.
 				c.batchCh <- batch
 			}
@@ -262,6 +409,32 @@ func (c *RootCommand) tail(out io.Writer) {
 	}
 }
 
+// saveBatch appends the raw batch to the --save file, one batch per line.
+//
+// NOTE: When tailing several services, the batch is saved with the name of
+// the service of each log so a replay can tell them apart.
+func (c *RootCommand) saveBatch(out io.Writer, b []byte, batch Batch) {
+	if c.saveFile == nil {
+		return
+	}
+	if len(batch.Logs) > 0 && batch.Logs[0].Service != "" {
+		data, err := json.Marshal(batch)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			text.Warning(out, "unable to save batch to %s: %v", c.cfg.save, err)
+			return
+		}
+		b = data
+	}
+
+	c.saveMu.Lock()
+	defer c.saveMu.Unlock()
+	if _, err := c.saveFile.Write(append(bytes.TrimSpace(b), '\n')); err != nil {
+		c.Globals.ErrLog.Add(err)
+		text.Warning(out, "unable to save batch to %s: %v", c.cfg.save, err)
+	}
+}
+
 // adjustTimes adjusts the passed in from and to flags based on the
 // specified padding.
 func (c *RootCommand) adjustTimes() {
@@ -277,15 +450,21 @@ func (c *RootCommand) adjustTimes() {
 	}
 }
 
-// enableManagedLogging enables managed logging in our API.
-func (c *RootCommand) enableManagedLogging(out io.Writer) error {
-	_, err := c.Globals.APIClient.CreateManagedLogging(&c.Input)
+// enableManagedLogging enables managed logging of the service in our API.
+func (c *RootCommand) enableManagedLogging(out io.Writer, serviceID string) error {
+	input := c.Input
+	input.ServiceID = serviceID
+	_, err := c.Globals.APIClient.CreateManagedLogging(&input)
 	if err != nil && err != fastly.ErrManagedLoggingEnabled {
 		c.Globals.ErrLog.Add(err)
 		return err
 	}
 
-	text.Info(out, "Managed logging enabled on service %s", c.Input.ServiceID)
+	// NOTE: The structured output formats only display logs, so the output can
+	// be parsed.
+	if c.cfg.format == "" || c.cfg.format == FormatText {
+		text.Info(out, "Managed logging enabled on service %s", serviceID)
+	}
 	return nil
 }
 
@@ -315,10 +494,37 @@ func (c *RootCommand) outputLoop(out io.Writer) {
 	// well recording when logs were received.
 	logmap := make(map[string]logrecv)
 
+	// When merging the logs of several services, the logs ready to be
+	// printed are held for another sort buffer, then printed in the order
+	// their requests started.
+	var (
+		pending []Log
+		flush   <-chan time.Time
+	)
+	if c.merge {
+		ticker := time.NewTicker(c.cfg.sortBuffer)
+		defer ticker.Stop()
+		flush = ticker.C
+	}
+	emit := func(logs []Log) {
+		if !c.merge {
+			c.printLogs(out, logs)
+			return
+		}
+		pending = append(pending, logs...)
+	}
+	flushPending := func() {
+		sortByRequestStart(pending)
+		c.printLogs(out, pending)
+		pending = nil
+	}
+
 	for {
 		select {
 		case <-c.dieCh:
 			return
+		case <-flush:
+			flushPending()
 		case batch := <-c.batchCh: // Got new batch.
 			// Range through batch logs, for each
 			// RequestID we create a timer based on the
@@ -364,9 +570,9 @@ func (c *RootCommand) outputLoop(out io.Writer) {
 				// since this is the head of the slice.
 				if len(recv) == 0 {
 					time.AfterFunc(c.cfg.sortBuffer, func() {
-						tdCh <- bufferedLog{
-							reqID: req,
-							seq:   highSeq,
+						select {
+						case tdCh <- bufferedLog{reqID: req, seq: highSeq}:
+						case <-c.dieCh:
 						}
 					})
 				}
@@ -388,7 +594,7 @@ func (c *RootCommand) outputLoop(out io.Writer) {
 			// remaining logs to be printed later.
 			toPrint, remainingLogs := reqLogs.logs[:idx], reqLogs.logs[idx:]
 			reqLogs.logs = remainingLogs
-			c.printLogs(out, toPrint)
+			emit(toPrint)
 
 			// Special case if we just printed the entire set of
 			// logs, we remove the keys from the maps and finish.
@@ -408,9 +614,9 @@ func (c *RootCommand) outputLoop(out io.Writer) {
 				// off time already served from the
 				// user defined sortBuffer.
 				time.AfterFunc(c.cfg.sortBuffer-time.Since(recv[0].when), func() {
-					tdCh <- bufferedLog{
-						reqID: reqID,
-						seq:   recv[0].highSeq,
+					select {
+					case tdCh <- bufferedLog{reqID: reqID, seq: recv[0].highSeq}:
+					case <-c.dieCh:
 					}
 				})
 			}
@@ -420,19 +626,46 @@ func (c *RootCommand) outputLoop(out io.Writer) {
 			logmap[reqID] = reqLogs
 
 		case <-c.doneCh:
-			os.Exit(0)
+			// We've reached the end of the run, so print whatever is still
+			// buffered, in the order it was received.
+			reqIDs := make([]string, 0, len(logmap))
+			for reqID := range logmap {
+				reqIDs = append(reqIDs, reqID)
+			}
+			sort.Slice(reqIDs, func(i, j int) bool {
+				a, b := logmap[reqIDs[i]].receives, logmap[reqIDs[j]].receives
+				if len(a) > 0 && len(b) > 0 && !a[0].when.Equal(b[0].when) {
+					return a[0].when.Before(b[0].when)
+				}
+				return reqIDs[i] < reqIDs[j]
+			})
+			for _, reqID := range reqIDs {
+				emit(logmap[reqID].logs)
+			}
+			if c.merge {
+				flushPending()
+			}
+			return
 		}
 	}
 }
 
 // printLogs is a simple printer for Log slices, only printing requested
-// streams.
+// streams and the logs matching the filters, in the requested format.
 func (c *RootCommand) printLogs(out io.Writer, logs []Log) {
 	if len(logs) > 0 {
-		filtered := filterStream(c.cfg.stream, logs)
+		filtered := filterLogs(c.filter, filterStream(c.cfg.stream, logs))
 
 		for _, l := range filtered {
-			fmt.Fprintln(out, l.String())
+			line, err := formatLog(c.cfg.format, l)
+			if err != nil {
+				c.Globals.ErrLog.Add(err)
+				continue
+			}
+			if colour, ok := c.colours[l.Service]; ok && (c.cfg.format == "" || c.cfg.format == FormatText) {
+				line = colour(line)
+			}
+			fmt.Fprintln(out, line)
 		}
 	}
 }
@@ -457,9 +690,6 @@ type (
 	// cfg holds the configuration parameters passed in through
 	// command line arguments.
 	cfg struct {
-		// path is the full path to fetch
-		path string
-
 		// from is how far in the past to start showing logs.
 		from int64
 
@@ -478,6 +708,17 @@ type (
 		// customer wants to consume.
 		// Undefined == both stderr and stdout.
 		stream string
+		// format is the output format, one of Formats.
+		format string
+		// grep is a regular expression the log message must match.
+		grep string
+		// requestID is the ID, or ID prefix, of the request to show logs of.
+		requestID string
+		// since is how far in the past a request can have started to have its
+		// logs shown.
+		since time.Duration
+		// save is the path of the file to save each batch received to.
+		save string
 	}
 
 	// Log defines the message envelope that compute@edge (C@E) wraps the
@@ -496,6 +737,9 @@ type (
 		RequestID string `json:"id"`
 		// Message is the actual message body the user wants printed.
 		Message string `json:"message"`
+		// Service is the name of the service the log was received from. It's
+		// set by the CLI, and only when tailing several services.
+		Service string `json:"service,omitempty"`
 	}
 
 	// Batch encompasses a batch ID and the logs for this batch.
@@ -550,6 +794,14 @@ func makeNewPath(out io.Writer, path string, window int64, batchID string) strin
 	return basePath.String()
 }
 
+// sortByRequestStart sorts logs by the time their request started, keeping
+// the logs of a request in order.
+func sortByRequestStart(logs []Log) {
+	sort.SliceStable(logs, func(i, j int) bool {
+		return logs[i].RequestStart < logs[j].RequestStart
+	})
+}
+
 // splitByReqID splits slices of logs based on RequestID,
 func splitByReqID(in []Log) map[string][]Log {
 	out := make(map[string][]Log)
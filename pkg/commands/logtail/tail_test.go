@@ -1,13 +1,19 @@
 package logtail
 
 import (
+	"bytes"
 	"net/http"
 	"os"
+	"path/filepath"
 	"reflect"
+	"regexp"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
 )
 
 const responseFile = "testdata/response.json"
@@ -339,3 +345,203 @@ func TestHighSequence(t *testing.T) {
 		}
 	}
 }
+
+// TestFormatLog tests that a log is rendered in each output format.
+func TestFormatLog(t *testing.T) {
+	l := Log{
+		SequenceNum:  2,
+		RequestStart: 1601645172164667,
+		Stream:       "stderr",
+		RequestID:    "44a1eedd-5831-49fe-b094-7435908ba1fb",
+		Message:      `failed to fetch "/api": timeout`,
+	}
+
+	for _, test := range []struct {
+		format string
+		want   string
+	}{
+		{
+			format: FormatText,
+			want:   `stderr | 44a1eedd | failed to fetch "/api": timeout`,
+		},
+		{
+			format: FormatJSON,
+			want: `{
+  "request_start": "2020-10-02T13:26:12.164667Z",
+  "sequence_number": 2,
+  "stream": "stderr",
+  "request_id": "44a1eedd-5831-49fe-b094-7435908ba1fb",
+  "message": "failed to fetch \"/api\": timeout"
+}`,
+		},
+		{
+			format: FormatNDJSON,
+			want:   `{"request_start":"2020-10-02T13:26:12.164667Z","sequence_number":2,"stream":"stderr","request_id":"44a1eedd-5831-49fe-b094-7435908ba1fb","message":"failed to fetch \"/api\": timeout"}`,
+		},
+		{
+			format: FormatLogfmt,
+			want:   `request_start=2020-10-02T13:26:12.164667Z sequence_number=2 stream=stderr request_id=44a1eedd-5831-49fe-b094-7435908ba1fb message="failed to fetch \"/api\": timeout"`,
+		},
+	} {
+		got, err := formatLog(test.format, l)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.format, err)
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("%s: formatLog mismatch (-want +got):\n%s", test.format, diff)
+		}
+	}
+}
+
+// TestFilterLogs tests that the --grep, --request-id and --since filters
+// select the expected logs.
+func TestFilterLogs(t *testing.T) {
+	start := time.Date(2020, 10, 2, 13, 26, 12, 0, time.UTC)
+	logs := []Log{
+		{SequenceNum: 1, RequestID: "41f82900-aaaa", RequestStart: start.UnixMicro(), Message: "GET /"},
+		{SequenceNum: 2, RequestID: "41f82900-aaaa", RequestStart: start.UnixMicro(), Message: "error: timeout"},
+		{SequenceNum: 1, RequestID: "2bef4613-bbbb", RequestStart: start.Add(time.Minute).UnixMicro(), Message: "GET /api"},
+		{SequenceNum: 2, RequestID: "2bef4613-bbbb", RequestStart: start.Add(time.Minute).UnixMicro(), Message: "error: not found"},
+	}
+
+	for i, test := range []struct {
+		filter logFilter
+		want   []string
+	}{
+		{
+			want: []string{"GET /", "error: timeout", "GET /api", "error: not found"},
+		},
+		{
+			filter: logFilter{grep: regexp.MustCompile(`^error`)},
+			want:   []string{"error: timeout", "error: not found"},
+		},
+		{
+			filter: logFilter{requestID: "2bef4613"},
+			want:   []string{"GET /api", "error: not found"},
+		},
+		{
+			filter: logFilter{since: start.Add(30 * time.Second)},
+			want:   []string{"GET /api", "error: not found"},
+		},
+		{
+			filter: logFilter{grep: regexp.MustCompile(`error`), requestID: "41f82900-aaaa"},
+			want:   []string{"error: timeout"},
+		},
+	} {
+		var got []string
+		for _, l := range filterLogs(test.filter, logs) {
+			got = append(got, l.Message)
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("#%d: filterLogs mismatch (-want +got):\n%s", i, diff)
+		}
+	}
+}
+
+// TestReplay tests that a saved session is passed back through the output
+// loop, so logs are reordered, filtered and formatted.
+func TestReplay(t *testing.T) {
+	session := filepath.Join(t.TempDir(), "session.ndjson")
+	data := `{"batch_id":"MC0x","logs":[{"sequence_number":2,"request_start_us":1601645172164667,"stream":"stdout","id":"41f82900-aaaa","message":"A2"},{"sequence_number":1,"request_start_us":1601645172164667,"stream":"stdout","id":"41f82900-aaaa","message":"A1"}]}
+not a batch
+{"batch_id":"MC0y","logs":[{"sequence_number":1,"request_start_us":1601645173000000,"stream":"stderr","id":"2bef4613-bbbb","message":"B1"},{"sequence_number":3,"request_start_us":1601645172164667,"stream":"stdout","id":"41f82900-aaaa","message":"A3"}]}
+`
+	if err := os.WriteFile(session, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	warning := "\nWARNING: unable to parse batch on line 2 of " + session + ": invalid character 'o' in literal null (expecting 'u')\n"
+
+	for _, test := range []struct {
+		cfg  cfg
+		want string
+	}{
+		{
+			cfg: cfg{format: FormatText, sortBuffer: time.Minute},
+			want: warning +
+				"stdout | 41f82900 | A1\n" +
+				"stdout | 41f82900 | A2\n" +
+				"stdout | 41f82900 | A3\n" +
+				"stderr | 2bef4613 | B1\n",
+		},
+		{
+			cfg: cfg{format: FormatLogfmt, grep: "^A[13]$", sortBuffer: time.Minute},
+			want: warning +
+				"request_start=2020-10-02T13:26:12.164667Z sequence_number=1 stream=stdout request_id=41f82900-aaaa message=A1\n" +
+				"request_start=2020-10-02T13:26:12.164667Z sequence_number=3 stream=stdout request_id=41f82900-aaaa message=A3\n",
+		},
+	} {
+		var stdout bytes.Buffer
+		c := &ReplayCommand{root: &RootCommand{cfg: test.cfg}, session: session}
+		c.Globals = &global.Data{ErrLog: fsterr.MockLog{}}
+		if err := c.Exec(nil, &stdout); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff := cmp.Diff(test.want, stdout.String()); diff != "" {
+			t.Errorf("%s: replay mismatch (-want +got):\n%s", test.cfg.format, diff)
+		}
+	}
+}
+
+// TestOutputLoopMerge tests that the logs of several services are merged in
+// the order their requests started, and prefixed with the service name.
+func TestOutputLoopMerge(t *testing.T) {
+	batches := []Batch{
+		{ID: "MC0x", Logs: []Log{
+			{SequenceNum: 1, RequestStart: 1601645173000000, Stream: "stdout", RequestID: "41f82900-aaaa", Message: "A1", Service: "frontend"},
+			{SequenceNum: 2, RequestStart: 1601645173000000, Stream: "stdout", RequestID: "41f82900-aaaa", Message: "A2", Service: "frontend"},
+		}},
+		{ID: "MC0y", Logs: []Log{
+			{SequenceNum: 1, RequestStart: 1601645172000000, Stream: "stderr", RequestID: "2bef4613-bbbb", Message: "B1", Service: "origin"},
+		}},
+		{ID: "MC0z", Logs: []Log{
+			{SequenceNum: 1, RequestStart: 1601645174000000, Stream: "stdout", RequestID: "7c3a2e10-cccc", Message: "C1", Service: "origin"},
+		}},
+	}
+
+	for _, test := range []struct {
+		format string
+		want   string
+	}{
+		{
+			format: FormatText,
+			want: "origin | stderr | 2bef4613 | B1\n" +
+				"frontend | stdout | 41f82900 | A1\n" +
+				"frontend | stdout | 41f82900 | A2\n" +
+				"origin | stdout | 7c3a2e10 | C1\n",
+		},
+		{
+			format: FormatNDJSON,
+			want: `{"service":"origin","request_start":"2020-10-02T13:26:12Z","sequence_number":1,"stream":"stderr","request_id":"2bef4613-bbbb","message":"B1"}` + "\n" +
+				`{"service":"frontend","request_start":"2020-10-02T13:26:13Z","sequence_number":1,"stream":"stdout","request_id":"41f82900-aaaa","message":"A1"}` + "\n" +
+				`{"service":"frontend","request_start":"2020-10-02T13:26:13Z","sequence_number":2,"stream":"stdout","request_id":"41f82900-aaaa","message":"A2"}` + "\n" +
+				`{"service":"origin","request_start":"2020-10-02T13:26:14Z","sequence_number":1,"stream":"stdout","request_id":"7c3a2e10-cccc","message":"C1"}` + "\n",
+		},
+	} {
+		var stdout bytes.Buffer
+		c := &RootCommand{
+			batchCh: make(chan Batch),
+			cfg:     cfg{format: test.format, sortBuffer: time.Minute},
+			dieCh:   make(chan struct{}),
+			doneCh:  make(chan struct{}),
+			merge:   true,
+		}
+		c.Globals = &global.Data{ErrLog: fsterr.MockLog{}}
+
+		finished := make(chan struct{})
+		go func() {
+			c.outputLoop(&stdout)
+			close(finished)
+		}()
+		for _, batch := range batches {
+			c.batchCh <- batch
+		}
+		close(c.doneCh)
+		<-finished
+		close(c.dieCh)
+
+		if diff := cmp.Diff(test.want, stdout.String()); diff != "" {
+			t.Errorf("%s: merged output mismatch (-want +got):\n%s", test.format, diff)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package pool
+
+import (
+	"io"
+
+	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v8/fastly"
+)
+
+// poolTypes are the accepted values for the --type flag.
+var poolTypes = []string{
+	string(fastly.PoolTypeRandom),
+	string(fastly.PoolTypeHash),
+	string(fastly.PoolTypeClient),
+}
+
+// CreateCommand calls the Fastly API to create pools.
+type CreateCommand struct {
+	cmd.Base
+	manifest manifest.Data
+
+	// Required.
+	serviceVersion cmd.OptionalServiceVersion
+
+	// Optional.
+	autoClone        cmd.OptionalAutoClone
+	comment          cmd.OptionalString
+	connectTimeout   cmd.OptionalInt
+	firstByteTimeout cmd.OptionalInt
+	healthcheck      cmd.OptionalString
+	maxConnDefault   cmd.OptionalInt
+	maxTLSVersion    cmd.OptionalString
+	minTLSVersion    cmd.OptionalString
+	name             cmd.OptionalString
+	overrideHost     cmd.OptionalString
+	poolType         cmd.OptionalString
+	quorum           cmd.OptionalInt
+	requestCondition cmd.OptionalString
+	serviceName      cmd.OptionalServiceNameID
+	shield           cmd.OptionalString
+	tlsCACert        cmd.OptionalString
+	tlsCertHostname  cmd.OptionalString
+	tlsCheckCert     cmd.OptionalBool
+	tlsCiphers       cmd.OptionalString
+	tlsClientCert    cmd.OptionalString
+	tlsClientKey     cmd.OptionalString
+	tlsSNIHostname   cmd.OptionalString
+	useTLS           cmd.OptionalBool
+}
+
+// NewCreateCommand returns a usable command registered under the parent.
+func NewCreateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *CreateCommand {
+	c := CreateCommand{
+		Base: cmd.Base{
+			Globals: g,
+		},
+		manifest: m,
+	}
+	c.CmdClause = parent.Command("create", "Create a pool on a Fastly service version").Alias("add")
+
+	// Required.
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagVersionName,
+		Description: cmd.FlagVersionDesc,
+		Dst:         &c.serviceVersion.Value,
+		Required:    true,
+	})
+
+	// Optional.
+	c.RegisterAutoCloneFlag(cmd.AutoCloneFlagOpts{
+		Action: c.autoClone.Set,
+		Dst:    &c.autoClone.Value,
+	})
+	c.CmdClause.Flag("comment", "A descriptive note").Action(c.comment.Set).StringVar(&c.comment.Value)
+	c.CmdClause.Flag("connect-timeout", "How long to wait for a timeout in milliseconds").Action(c.connectTimeout.Set).IntVar(&c.connectTimeout.Value)
+	c.CmdClause.Flag("first-byte-timeout", "How long to wait for the first byte in milliseconds").Action(c.firstByteTimeout.Set).IntVar(&c.firstByteTimeout.Value)
+	c.CmdClause.Flag("healthcheck", "Name of the healthcheck to use with this pool").Action(c.healthcheck.Set).StringVar(&c.healthcheck.Value)
+	c.CmdClause.Flag("max-conn-default", "Maximum number of connections for each server in the pool").Action(c.maxConnDefault.Set).IntVar(&c.maxConnDefault.Value)
+	c.CmdClause.Flag("max-tls-version", "Maximum allowed TLS version on connections to this server").Action(c.maxTLSVersion.Set).StringVar(&c.maxTLSVersion.Value)
+	c.CmdClause.Flag("min-tls-version", "Minimum allowed TLS version on connections to this server").Action(c.minTLSVersion.Set).StringVar(&c.minTLSVersion.Value)
+	c.CmdClause.Flag("name", "Name of the pool").Short('n').Action(c.name.Set).StringVar(&c.name.Value)
+	c.CmdClause.Flag("override-host", "The hostname to override the Host header").Action(c.overrideHost.Set).StringVar(&c.overrideHost.Value)
+	c.CmdClause.Flag("quorum", "Percentage of capacity that needs to be operational for the pool to be considered up").Action(c.quorum.Set).IntVar(&c.quorum.Value)
+	c.CmdClause.Flag("request-condition", "Name of the request condition controlling when this configuration applies").Action(c.requestCondition.Set).StringVar(&c.requestCondition.Value)
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	c.CmdClause.Flag("shield", "Selected POP to serve as a shield for the servers").Action(c.shield.Set).StringVar(&c.shield.Value)
+	c.CmdClause.Flag("tls-ca-cert", "A secure certificate to authenticate the server with").Action(c.tlsCACert.Set).StringVar(&c.tlsCACert.Value)
+	c.CmdClause.Flag("tls-cert-hostname", "The hostname used to verify the server's certificate").Action(c.tlsCertHostname.Set).StringVar(&c.tlsCertHostname.Value)
+	c.CmdClause.Flag("tls-check-cert", "Be strict on checking TLS certs").Action(c.tlsCheckCert.Set).BoolVar(&c.tlsCheckCert.Value)
+	c.CmdClause.Flag("tls-ciphers", "List of OpenSSL ciphers").Action(c.tlsCiphers.Set).StringVar(&c.tlsCiphers.Value)
+	c.CmdClause.Flag("tls-client-cert", "The client certificate used to make authenticated requests").Action(c.tlsClientCert.Set).StringVar(&c.tlsClientCert.Value)
+	c.CmdClause.Flag("tls-client-key", "The client private key used to make authenticated requests").Action(c.tlsClientKey.Set).StringVar(&c.tlsClientKey.Value)
+	c.CmdClause.Flag("tls-sni-hostname", "SNI hostname").Action(c.tlsSNIHostname.Set).StringVar(&c.tlsSNIHostname.Value)
+	c.CmdClause.Flag("type", "What type of load balance group to use (random, hash, client)").Action(c.poolType.Set).HintOptions(poolTypes...).EnumVar(&c.poolType.Value, poolTypes...)
+	c.CmdClause.Flag("use-tls", "Whether to use TLS").Action(c.useTLS.Set).BoolVar(&c.useTLS.Value)
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
+	serviceID, serviceVersion, err := cmd.ServiceDetails(cmd.ServiceDetailsOpts{
+		AutoCloneFlag:      c.autoClone,
+		APIClient:          c.Globals.APIClient,
+		Manifest:           c.manifest,
+		Out:                out,
+		ServiceNameFlag:    c.serviceName,
+		ServiceVersionFlag: c.serviceVersion,
+		VerboseMode:        c.Globals.Flags.Verbose,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": errors.ServiceVersion(serviceVersion),
+		})
+		return err
+	}
+
+	input := fastly.CreatePoolInput{
+		ServiceID:      serviceID,
+		ServiceVersion: serviceVersion.Number,
+	}
+	if c.name.WasSet {
+		input.Name = &c.name.Value
+	}
+	if c.comment.WasSet {
+		input.Comment = &c.comment.Value
+	}
+	if c.connectTimeout.WasSet {
+		input.ConnectTimeout = &c.connectTimeout.Value
+	}
+	if c.firstByteTimeout.WasSet {
+		input.FirstByteTimeout = &c.firstByteTimeout.Value
+	}
+	if c.healthcheck.WasSet {
+		input.Healthcheck = &c.healthcheck.Value
+	}
+	if c.maxConnDefault.WasSet {
+		input.MaxConnDefault = &c.maxConnDefault.Value
+	}
+	if c.maxTLSVersion.WasSet {
+		input.MaxTLSVersion = &c.maxTLSVersion.Value
+	}
+	if c.minTLSVersion.WasSet {
+		input.MinTLSVersion = &c.minTLSVersion.Value
+	}
+	if c.overrideHost.WasSet {
+		input.OverrideHost = &c.overrideHost.Value
+	}
+	if c.quorum.WasSet {
+		input.Quorum = &c.quorum.Value
+	}
+	if c.requestCondition.WasSet {
+		input.RequestCondition = &c.requestCondition.Value
+	}
+	if c.shield.WasSet {
+		input.Shield = &c.shield.Value
+	}
+	if c.tlsCACert.WasSet {
+		input.TLSCACert = &c.tlsCACert.Value
+	}
+	if c.tlsCertHostname.WasSet {
+		input.TLSCertHostname = &c.tlsCertHostname.Value
+	}
+	if c.tlsCheckCert.WasSet {
+		input.TLSCheckCert = fastly.CBool(c.tlsCheckCert.Value)
+	}
+	if c.tlsCiphers.WasSet {
+		input.TLSCiphers = &c.tlsCiphers.Value
+	}
+	if c.tlsClientCert.WasSet {
+		input.TLSClientCert = &c.tlsClientCert.Value
+	}
+	if c.tlsClientKey.WasSet {
+		input.TLSClientKey = &c.tlsClientKey.Value
+	}
+	if c.tlsSNIHostname.WasSet {
+		input.TLSSNIHostname = &c.tlsSNIHostname.Value
+	}
+	if c.poolType.WasSet {
+		poolType := fastly.PoolType(c.poolType.Value)
+		input.Type = &poolType
+	}
+	if c.useTLS.WasSet {
+		input.UseTLS = fastly.CBool(c.useTLS.Value)
+	}
+
+	o, err := c.Globals.APIClient.CreatePool(&input)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": serviceVersion.Number,
+		})
+		return err
+	}
+
+	text.Success(out, "Created pool %s (service %s version %d)", o.Name, o.ServiceID, o.ServiceVersion)
+	return nil
+}
@@ -0,0 +1,2 @@
+// Package pool contains commands to inspect and manipulate Fastly service load balancing pools.
+package pool
@@ -0,0 +1,337 @@
+package pool_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fastly/go-fastly/v8/fastly"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestPoolCreate(t *testing.T) {
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name:      "validate --type is a known pool type",
+			Args:      args("pool create --service-id 123 --version 1 --name origins --type fallback"),
+			WantError: "error parsing arguments: enum value must be one of random,hash,client, got 'fallback'",
+		},
+		{
+			Name: "validate CreatePool API error",
+			Args: args("pool create --service-id 123 --version 1 --name origins --autoclone"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CloneVersionFn: testutil.CloneVersionResult(4),
+				CreatePoolFn:   createPoolError,
+			},
+			WantError: errTest.Error(),
+		},
+		{
+			Name: "validate the pool is created with its TLS settings",
+			Args: args("pool create --service-id 123 --version 1 --name origins --autoclone --healthcheck origin-health --max-conn-default 200 --min-tls-version 1.2 --tls-cert-hostname origin.example.com --tls-check-cert --type random --use-tls"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CloneVersionFn: testutil.CloneVersionResult(4),
+				CreatePoolFn: createPoolWith(fastly.CreatePoolInput{
+					ServiceID:       "123",
+					ServiceVersion:  4,
+					Name:            fastly.String("origins"),
+					Healthcheck:     fastly.String("origin-health"),
+					MaxConnDefault:  fastly.Int(200),
+					MinTLSVersion:   fastly.String("1.2"),
+					TLSCertHostname: fastly.String("origin.example.com"),
+					TLSCheckCert:    fastly.CBool(true),
+					Type:            fastly.PoolTypePtr(fastly.PoolTypeRandom),
+					UseTLS:          fastly.CBool(true),
+				}),
+			},
+			WantOutput: "Created pool origins (service 123 version 4)",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+		})
+	}
+}
+
+func TestPoolList(t *testing.T) {
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Args: args("pool list --service-id 123 --version 1"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				ListPoolsFn:    listPoolsOK,
+			},
+			WantOutput: listPoolsShortOutput,
+		},
+		{
+			Args: args("pool list --service-id 123 --version 1 --verbose"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				ListPoolsFn:    listPoolsOK,
+			},
+			WantOutput: listPoolsVerboseOutput,
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(strings.Join(testcase.Args, " "), func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertString(t, testcase.WantOutput, stdout.String())
+		})
+	}
+}
+
+func TestPoolDescribe(t *testing.T) {
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("pool describe --service-id 123 --version 1 --name origins"), &stdout)
+	opts.APIClient = mock.APIClient(mock.API{
+		ListVersionsFn: testutil.ListVersions,
+		GetPoolFn:      getPoolOK,
+	})
+	err := app.Run(opts)
+	testutil.AssertNoError(t, err)
+	testutil.AssertString(t, describePoolOutput, stdout.String())
+}
+
+func TestPoolUpdate(t *testing.T) {
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name:      "validate missing --name flag",
+			Args:      args("pool update --service-id 123 --version 1 --quorum 50"),
+			WantError: "error parsing arguments: required flag --name not provided",
+		},
+		{
+			Name: "validate the pool is renamed with new timeouts and load balancing",
+			Args: args("pool update --service-id 123 --version 1 --name origins --new-name assets --connect-timeout 500 --first-byte-timeout 15000 --type hash --autoclone"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CloneVersionFn: testutil.CloneVersionResult(4),
+				UpdatePoolFn: updatePoolWith(fastly.UpdatePoolInput{
+					ServiceID:        "123",
+					ServiceVersion:   4,
+					Name:             "origins",
+					NewName:          fastly.String("assets"),
+					ConnectTimeout:   fastly.Int(500),
+					FirstByteTimeout: fastly.Int(15000),
+					Type:             fastly.PoolTypePtr(fastly.PoolTypeHash),
+				}),
+			},
+			WantOutput: "Updated pool assets (service 123 version 4)",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+		})
+	}
+}
+
+func TestPoolDelete(t *testing.T) {
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("pool delete --service-id 123 --version 1 --name origins --autoclone"), &stdout)
+	opts.APIClient = mock.APIClient(mock.API{
+		ListVersionsFn: testutil.ListVersions,
+		CloneVersionFn: testutil.CloneVersionResult(4),
+		DeletePoolFn: func(i *fastly.DeletePoolInput) error {
+			if i.Name != "origins" || i.ServiceVersion != 4 {
+				return errTest
+			}
+			return nil
+		},
+	})
+	err := app.Run(opts)
+	testutil.AssertNoError(t, err)
+	testutil.AssertStringContains(t, stdout.String(), "Deleted pool origins (service 123 version 4)")
+}
+
+var errTest = errors.New("fixture error")
+
+func createPoolWith(want fastly.CreatePoolInput) func(*fastly.CreatePoolInput) (*fastly.Pool, error) {
+	return func(i *fastly.CreatePoolInput) (*fastly.Pool, error) {
+		if !reflect.DeepEqual(*i, want) {
+			return nil, fmt.Errorf("unexpected input: %+v", *i)
+		}
+		return &fastly.Pool{
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+			Name:           *i.Name,
+		}, nil
+	}
+}
+
+func createPoolError(i *fastly.CreatePoolInput) (*fastly.Pool, error) {
+	return nil, errTest
+}
+
+func listPoolsOK(i *fastly.ListPoolsInput) ([]*fastly.Pool, error) {
+	return []*fastly.Pool{
+		{
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+			ID:             "7Jm4XQMUdkdFN1vGCmHSzl",
+			Name:           "origins",
+			Type:           fastly.PoolTypeRandom,
+			Quorum:         75,
+			MaxConnDefault: 200,
+			Healthcheck:    "origin-health",
+			UseTLS:         true,
+			TLSCheckCert:   true,
+		},
+		{
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+			ID:             "2kbyvQW4a2aG8lWZfOUcOc",
+			Name:           "assets",
+			Type:           fastly.PoolTypeHash,
+			Quorum:         50,
+			MaxConnDefault: 100,
+		},
+	}, nil
+}
+
+var listPoolsShortOutput = strings.TrimSpace(`
+SERVICE  VERSION  NAME     ID                      TYPE    QUORUM
+123      1        origins  7Jm4XQMUdkdFN1vGCmHSzl  random  75
+123      1        assets   2kbyvQW4a2aG8lWZfOUcOc  hash    50
+`) + "\n"
+
+var listPoolsVerboseOutput = strings.Join([]string{
+	"Fastly API token not provided",
+	"Fastly API endpoint: https://api.fastly.com",
+	"",
+	"Service ID (via --service-id): 123",
+	"",
+	"Version: 1",
+	"	Pool 1/2",
+	"		ID: 7Jm4XQMUdkdFN1vGCmHSzl",
+	"		Name: origins",
+	"		Comment: ",
+	"		Type: random",
+	"		Shield: ",
+	"		Request condition: ",
+	"		Max conn default: 200",
+	"		Connect timeout: 0",
+	"		First byte timeout: 0",
+	"		Quorum: 75",
+	"		Healthcheck: origin-health",
+	"		Override host: ",
+	"		Use TLS: true",
+	"		TLS check cert: true",
+	"		TLS CA cert: ",
+	"		TLS client cert: ",
+	"		TLS client key: ",
+	"		TLS cert hostname: ",
+	"		TLS SNI hostname: ",
+	"		Min TLS version: ",
+	"		Max TLS version: ",
+	"		TLS ciphers: ",
+	"	Pool 2/2",
+	"		ID: 2kbyvQW4a2aG8lWZfOUcOc",
+	"		Name: assets",
+	"		Comment: ",
+	"		Type: hash",
+	"		Shield: ",
+	"		Request condition: ",
+	"		Max conn default: 100",
+	"		Connect timeout: 0",
+	"		First byte timeout: 0",
+	"		Quorum: 50",
+	"		Healthcheck: ",
+	"		Override host: ",
+	"		Use TLS: false",
+	"		TLS check cert: false",
+	"		TLS CA cert: ",
+	"		TLS client cert: ",
+	"		TLS client key: ",
+	"		TLS cert hostname: ",
+	"		TLS SNI hostname: ",
+	"		Min TLS version: ",
+	"		Max TLS version: ",
+	"		TLS ciphers: ",
+}, "\n") + "\n\n"
+
+func getPoolOK(i *fastly.GetPoolInput) (*fastly.Pool, error) {
+	return &fastly.Pool{
+		ServiceID:      i.ServiceID,
+		ServiceVersion: i.ServiceVersion,
+		ID:             "7Jm4XQMUdkdFN1vGCmHSzl",
+		Name:           "origins",
+		Type:           fastly.PoolTypeRandom,
+		Quorum:         75,
+		MaxConnDefault: 200,
+		Healthcheck:    "origin-health",
+		UseTLS:         true,
+		TLSCheckCert:   true,
+	}, nil
+}
+
+var describePoolOutput = "\n" + strings.Join([]string{
+	"Service ID: 123",
+	"Version: 1",
+	"ID: 7Jm4XQMUdkdFN1vGCmHSzl",
+	"Name: origins",
+	"Comment: ",
+	"Type: random",
+	"Shield: ",
+	"Request condition: ",
+	"Max conn default: 200",
+	"Connect timeout: 0",
+	"First byte timeout: 0",
+	"Quorum: 75",
+	"Healthcheck: origin-health",
+	"Override host: ",
+	"Use TLS: true",
+	"TLS check cert: true",
+	"TLS CA cert: ",
+	"TLS client cert: ",
+	"TLS client key: ",
+	"TLS cert hostname: ",
+	"TLS SNI hostname: ",
+	"Min TLS version: ",
+	"Max TLS version: ",
+	"TLS ciphers: ",
+}, "\n") + "\n"
+
+func updatePoolWith(want fastly.UpdatePoolInput) func(*fastly.UpdatePoolInput) (*fastly.Pool, error) {
+	return func(i *fastly.UpdatePoolInput) (*fastly.Pool, error) {
+		if !reflect.DeepEqual(*i, want) {
+			return nil, fmt.Errorf("unexpected input: %+v", *i)
+		}
+		name := i.Name
+		if i.NewName != nil {
+			name = *i.NewName
+		}
+		return &fastly.Pool{
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+			Name:           name,
+		}, nil
+	}
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"io"
+
+	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v8/fastly"
+)
+
+// NewCreateCommand returns a usable command registered under the parent.
+func NewCreateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *CreateCommand {
+	c := CreateCommand{
+		Base: cmd.Base{
+			Globals: g,
+		},
+		manifest: m,
+	}
+	c.CmdClause = parent.Command("create", "Add a server to a pool").Alias("add")
+
+	// Required.
+	c.CmdClause.Flag("address", "A hostname, IPv4, or IPv6 address for the server").Required().StringVar(&c.address)
+	c.CmdClause.Flag("pool-id", "Alphanumeric string identifying a pool").Required().StringVar(&c.poolID)
+
+	// Optional.
+	c.CmdClause.Flag("comment", "A freeform descriptive note").Action(c.comment.Set).StringVar(&c.comment.Value)
+	c.CmdClause.Flag("disabled", "Whether the server is disabled").Action(c.disabled.Set).BoolVar(&c.disabled.Value)
+	c.CmdClause.Flag("max-conn", "Maximum number of connections. If unset, the pool's --max-conn-default is used").Action(c.maxConn.Set).IntVar(&c.maxConn.Value)
+	c.CmdClause.Flag("override-host", "The hostname to override the Host header").Action(c.overrideHost.Set).StringVar(&c.overrideHost.Value)
+	c.CmdClause.Flag("port", "Port number").Action(c.port.Set).IntVar(&c.port.Value)
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	c.CmdClause.Flag("weight", "Weight (1-100) used to load balance this server against others").Action(c.weight.Set).IntVar(&c.weight.Value)
+
+	return &c
+}
+
+// CreateCommand calls the Fastly API to create an appropriate resource.
+type CreateCommand struct {
+	cmd.Base
+
+	address      string
+	comment      cmd.OptionalString
+	disabled     cmd.OptionalBool
+	manifest     manifest.Data
+	maxConn      cmd.OptionalInt
+	overrideHost cmd.OptionalString
+	poolID       string
+	port         cmd.OptionalInt
+	serviceName  cmd.OptionalServiceNameID
+	weight       cmd.OptionalInt
+}
+
+// Exec invokes the application logic for the command.
+func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
+	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
+	if err != nil {
+		return err
+	}
+	if c.Globals.Verbose() {
+		cmd.DisplayServiceID(serviceID, flag, source, out)
+	}
+
+	input := c.constructInput(serviceID)
+
+	s, err := c.Globals.APIClient.CreateServer(input)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID": serviceID,
+			"Pool ID":    c.poolID,
+		})
+		return err
+	}
+
+	text.Success(out, "Created server '%s' (address: %s, pool: %s, service: %s)", s.ID, s.Address, s.PoolID, s.ServiceID)
+	return nil
+}
+
+// constructInput transforms values parsed from CLI flags into an object to be used by the API client library.
+func (c *CreateCommand) constructInput(serviceID string) *fastly.CreateServerInput {
+	input := fastly.CreateServerInput{
+		Address:   &c.address,
+		PoolID:    c.poolID,
+		ServiceID: serviceID,
+	}
+	if c.comment.WasSet {
+		input.Comment = &c.comment.Value
+	}
+	if c.disabled.WasSet {
+		input.Disabled = &c.disabled.Value
+	}
+	if c.maxConn.WasSet {
+		input.MaxConn = &c.maxConn.Value
+	}
+	if c.overrideHost.WasSet {
+		input.OverrideHost = &c.overrideHost.Value
+	}
+	if c.port.WasSet {
+		input.Port = &c.port.Value
+	}
+	if c.weight.WasSet {
+		input.Weight = &c.weight.Value
+	}
+
+	return &input
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"io"
+
+	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v8/fastly"
+)
+
+// NewDeleteCommand returns a usable command registered under the parent.
+func NewDeleteCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *DeleteCommand {
+	c := DeleteCommand{
+		Base: cmd.Base{
+			Globals: g,
+		},
+		manifest: m,
+	}
+	c.CmdClause = parent.Command("delete", "Delete a server from a pool").Alias("remove")
+
+	// Required.
+	c.CmdClause.Flag("id", "Alphanumeric string identifying a server").Required().StringVar(&c.id)
+	c.CmdClause.Flag("pool-id", "Alphanumeric string identifying a pool").Required().StringVar(&c.poolID)
+
+	// Optional.
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+
+	return &c
+}
+
+// DeleteCommand calls the Fastly API to delete an appropriate resource.
+type DeleteCommand struct {
+	cmd.Base
+
+	id          string
+	manifest    manifest.Data
+	poolID      string
+	serviceName cmd.OptionalServiceNameID
+}
+
+// Exec invokes the application logic for the command.
+func (c *DeleteCommand) Exec(_ io.Reader, out io.Writer) error {
+	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
+	if err != nil {
+		return err
+	}
+	if c.Globals.Verbose() {
+		cmd.DisplayServiceID(serviceID, flag, source, out)
+	}
+
+	input := c.constructInput(serviceID)
+
+	err = c.Globals.APIClient.DeleteServer(input)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID": serviceID,
+			"Pool ID":    c.poolID,
+			"Server ID":  c.id,
+		})
+		return err
+	}
+
+	text.Success(out, "Deleted server '%s' (pool: %s, service: %s)", c.id, c.poolID, serviceID)
+	return nil
+}
+
+// constructInput transforms values parsed from CLI flags into an object to be used by the API client library.
+func (c *DeleteCommand) constructInput(serviceID string) *fastly.DeleteServerInput {
+	var input fastly.DeleteServerInput
+
+	input.PoolID = c.poolID
+	input.Server = c.id
+	input.ServiceID = serviceID
+
+	return &input
+}
@@ -0,0 +1,3 @@
+// Package server contains commands to inspect and manipulate the servers of
+// Fastly service load balancing pools.
+package server
@@ -0,0 +1,286 @@
+package server_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/go-fastly/v8/fastly"
+)
+
+func TestPoolServerCreate(t *testing.T) {
+	args := testutil.Args
+	scenarios := []struct {
+		args       []string
+		api        mock.API
+		wantError  string
+		wantOutput string
+	}{
+		{
+			args:      args("pool server create --service-id 123 --address 127.0.0.1"),
+			wantError: "error parsing arguments: required flag --pool-id not provided",
+		},
+		{
+			args:      args("pool server create --service-id 123 --pool-id 456"),
+			wantError: "error parsing arguments: required flag --address not provided",
+		},
+		{
+			args: args("pool server create --service-id 123 --pool-id 456 --address 127.0.0.1"),
+			api: mock.API{
+				CreateServerFn: createServerError,
+			},
+			wantError: errTest.Error(),
+		},
+		{
+			args: args("pool server create --service-id 123 --pool-id 456 --address 127.0.0.1 --max-conn 200 --port 443 --weight 50 --disabled"),
+			api: mock.API{
+				CreateServerFn: createServerWith(fastly.CreateServerInput{
+					ServiceID: "123",
+					PoolID:    "456",
+					Address:   fastly.String("127.0.0.1"),
+					Disabled:  fastly.Bool(true),
+					MaxConn:   fastly.Int(200),
+					Port:      fastly.Int(443),
+					Weight:    fastly.Int(50),
+				}),
+			},
+			wantOutput: "Created server '789' (address: 127.0.0.1, pool: 456, service: 123)",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(strings.Join(testcase.args, " "), func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.api)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+		})
+	}
+}
+
+func TestPoolServerList(t *testing.T) {
+	args := testutil.Args
+	scenarios := []struct {
+		args       []string
+		api        mock.API
+		wantError  string
+		wantOutput string
+	}{
+		{
+			args: args("pool server list --service-id 123 --pool-id 456"),
+			api: mock.API{
+				ListServersFn: listServersOK,
+			},
+			wantOutput: listServersShortOutput,
+		},
+		{
+			args: args("pool server list --service-id 123 --pool-id 456 --verbose"),
+			api: mock.API{
+				ListServersFn: listServersOK,
+			},
+			wantOutput: listServersVerboseOutput,
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(strings.Join(testcase.args, " "), func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.api)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertString(t, testcase.wantOutput, stdout.String())
+		})
+	}
+}
+
+func TestPoolServerDescribe(t *testing.T) {
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("pool server describe --service-id 123 --pool-id 456 --id 789"), &stdout)
+	opts.APIClient = mock.APIClient(mock.API{
+		GetServerFn: getServerOK,
+	})
+	err := app.Run(opts)
+	testutil.AssertNoError(t, err)
+	testutil.AssertString(t, describeServerOutput, stdout.String())
+}
+
+func TestPoolServerUpdate(t *testing.T) {
+	args := testutil.Args
+	scenarios := []struct {
+		args       []string
+		api        mock.API
+		wantError  string
+		wantOutput string
+	}{
+		{
+			args:      args("pool server update --service-id 123 --pool-id 456 --weight 10"),
+			wantError: "error parsing arguments: required flag --id not provided",
+		},
+		{
+			args: args("pool server update --service-id 123 --pool-id 456 --id 789 --address 127.0.0.2 --disabled"),
+			api: mock.API{
+				UpdateServerFn: updateServerWith(fastly.UpdateServerInput{
+					ServiceID: "123",
+					PoolID:    "456",
+					Server:    "789",
+					Address:   fastly.String("127.0.0.2"),
+					Disabled:  fastly.Bool(true),
+				}),
+			},
+			wantOutput: "Updated server '789' (address: 127.0.0.2, pool: 456, service: 123)",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(strings.Join(testcase.args, " "), func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.api)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+		})
+	}
+}
+
+func TestPoolServerDelete(t *testing.T) {
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("pool server delete --service-id 123 --pool-id 456 --id 789"), &stdout)
+	opts.APIClient = mock.APIClient(mock.API{
+		DeleteServerFn: func(i *fastly.DeleteServerInput) error {
+			if i.PoolID != "456" || i.Server != "789" {
+				return errTest
+			}
+			return nil
+		},
+	})
+	err := app.Run(opts)
+	testutil.AssertNoError(t, err)
+	testutil.AssertStringContains(t, stdout.String(), "Deleted server '789' (pool: 456, service: 123)")
+}
+
+var errTest = errors.New("fixture error")
+
+func createServerWith(want fastly.CreateServerInput) func(*fastly.CreateServerInput) (*fastly.Server, error) {
+	return func(i *fastly.CreateServerInput) (*fastly.Server, error) {
+		if !reflect.DeepEqual(*i, want) {
+			return nil, fmt.Errorf("unexpected input: %+v", *i)
+		}
+		return &fastly.Server{
+			ServiceID: i.ServiceID,
+			PoolID:    i.PoolID,
+			ID:        "789",
+			Address:   *i.Address,
+		}, nil
+	}
+}
+
+func createServerError(_ *fastly.CreateServerInput) (*fastly.Server, error) {
+	return nil, errTest
+}
+
+func listServersOK(i *fastly.ListServersInput) ([]*fastly.Server, error) {
+	return []*fastly.Server{
+		{
+			ServiceID: i.ServiceID,
+			PoolID:    i.PoolID,
+			ID:        "789",
+			Address:   "127.0.0.1",
+			Port:      443,
+			Weight:    100,
+		},
+		{
+			ServiceID: i.ServiceID,
+			PoolID:    i.PoolID,
+			ID:        "abc",
+			Address:   "origin.example.com",
+			Port:      80,
+			Weight:    50,
+			Disabled:  true,
+			Comment:   "draining",
+		},
+	}, nil
+}
+
+var listServersShortOutput = strings.TrimSpace(`
+SERVICE  POOL  ID   ADDRESS             PORT  WEIGHT  DISABLED
+123      456   789  127.0.0.1           443   100     false
+123      456   abc  origin.example.com  80    50      true
+`) + "\n"
+
+var listServersVerboseOutput = strings.Join([]string{
+	"Fastly API token not provided",
+	"Fastly API endpoint: https://api.fastly.com",
+	"",
+	"Service ID (via --service-id): 123",
+	"",
+	"Pool ID: 456",
+	"	Server 1/2",
+	"		ID: 789",
+	"		Pool ID: 456",
+	"		Address: 127.0.0.1",
+	"		Port: 443",
+	"		Weight: 100",
+	"		Max connections: 0",
+	"		Override host: ",
+	"		Comment: ",
+	"		Disabled: false",
+	"	Server 2/2",
+	"		ID: abc",
+	"		Pool ID: 456",
+	"		Address: origin.example.com",
+	"		Port: 80",
+	"		Weight: 50",
+	"		Max connections: 0",
+	"		Override host: ",
+	"		Comment: draining",
+	"		Disabled: true",
+}, "\n") + "\n\n"
+
+func getServerOK(i *fastly.GetServerInput) (*fastly.Server, error) {
+	return &fastly.Server{
+		ServiceID: i.ServiceID,
+		PoolID:    i.PoolID,
+		ID:        i.Server,
+		Address:   "127.0.0.1",
+		Port:      443,
+		Weight:    100,
+		MaxConn:   200,
+	}, nil
+}
+
+var describeServerOutput = "\n" + strings.Join([]string{
+	"Service ID: 123",
+	"ID: 789",
+	"Pool ID: 456",
+	"Address: 127.0.0.1",
+	"Port: 443",
+	"Weight: 100",
+	"Max connections: 200",
+	"Override host: ",
+	"Comment: ",
+	"Disabled: false",
+}, "\n") + "\n"
+
+func updateServerWith(want fastly.UpdateServerInput) func(*fastly.UpdateServerInput) (*fastly.Server, error) {
+	return func(i *fastly.UpdateServerInput) (*fastly.Server, error) {
+		if !reflect.DeepEqual(*i, want) {
+			return nil, fmt.Errorf("unexpected input: %+v", *i)
+		}
+		return &fastly.Server{
+			ServiceID: i.ServiceID,
+			PoolID:    i.PoolID,
+			ID:        i.Server,
+			Address:   *i.Address,
+		}, nil
+	}
+}
@@ -0,0 +1,224 @@
+package pool
+
+import (
+	"io"
+
+	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v8/fastly"
+)
+
+// UpdateCommand calls the Fastly API to update pools.
+type UpdateCommand struct {
+	cmd.Base
+	manifest       manifest.Data
+	input          fastly.UpdatePoolInput
+	serviceName    cmd.OptionalServiceNameID
+	serviceVersion cmd.OptionalServiceVersion
+	autoClone      cmd.OptionalAutoClone
+
+	NewName          cmd.OptionalString
+	Comment          cmd.OptionalString
+	ConnectTimeout   cmd.OptionalInt
+	FirstByteTimeout cmd.OptionalInt
+	Healthcheck      cmd.OptionalString
+	MaxConnDefault   cmd.OptionalInt
+	MaxTLSVersion    cmd.OptionalString
+	MinTLSVersion    cmd.OptionalString
+	OverrideHost     cmd.OptionalString
+	Quorum           cmd.OptionalInt
+	RequestCondition cmd.OptionalString
+	Shield           cmd.OptionalString
+	TLSCACert        cmd.OptionalString
+	TLSCertHostname  cmd.OptionalString
+	TLSCheckCert     cmd.OptionalBool
+	TLSCiphers       cmd.OptionalString
+	TLSClientCert    cmd.OptionalString
+	TLSClientKey     cmd.OptionalString
+	TLSSNIHostname   cmd.OptionalString
+	Type             cmd.OptionalString
+	UseTLS           cmd.OptionalBool
+}
+
+// NewUpdateCommand returns a usable command registered under the parent.
+func NewUpdateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *UpdateCommand {
+	c := UpdateCommand{
+		Base: cmd.Base{
+			Globals: g,
+		},
+		manifest: m,
+	}
+	c.CmdClause = parent.Command("update", "Update a pool on a Fastly service version")
+
+	// Required.
+	c.CmdClause.Flag("name", "Name of the pool").Short('n').Required().StringVar(&c.input.Name)
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagVersionName,
+		Description: cmd.FlagVersionDesc,
+		Dst:         &c.serviceVersion.Value,
+		Required:    true,
+	})
+
+	// Optional.
+	c.RegisterAutoCloneFlag(cmd.AutoCloneFlagOpts{
+		Action: c.autoClone.Set,
+		Dst:    &c.autoClone.Value,
+	})
+	c.CmdClause.Flag("comment", "A descriptive note").Action(c.Comment.Set).StringVar(&c.Comment.Value)
+	c.CmdClause.Flag("connect-timeout", "How long to wait for a timeout in milliseconds").Action(c.ConnectTimeout.Set).IntVar(&c.ConnectTimeout.Value)
+	c.CmdClause.Flag("first-byte-timeout", "How long to wait for the first byte in milliseconds").Action(c.FirstByteTimeout.Set).IntVar(&c.FirstByteTimeout.Value)
+	c.CmdClause.Flag("healthcheck", "Name of the healthcheck to use with this pool").Action(c.Healthcheck.Set).StringVar(&c.Healthcheck.Value)
+	c.CmdClause.Flag("max-conn-default", "Maximum number of connections for each server in the pool").Action(c.MaxConnDefault.Set).IntVar(&c.MaxConnDefault.Value)
+	c.CmdClause.Flag("max-tls-version", "Maximum allowed TLS version on connections to this server").Action(c.MaxTLSVersion.Set).StringVar(&c.MaxTLSVersion.Value)
+	c.CmdClause.Flag("min-tls-version", "Minimum allowed TLS version on connections to this server").Action(c.MinTLSVersion.Set).StringVar(&c.MinTLSVersion.Value)
+	c.CmdClause.Flag("new-name", "New name of the pool").Action(c.NewName.Set).StringVar(&c.NewName.Value)
+	c.CmdClause.Flag("override-host", "The hostname to override the Host header").Action(c.OverrideHost.Set).StringVar(&c.OverrideHost.Value)
+	c.CmdClause.Flag("quorum", "Percentage of capacity that needs to be operational for the pool to be considered up").Action(c.Quorum.Set).IntVar(&c.Quorum.Value)
+	c.CmdClause.Flag("request-condition", "Name of the request condition controlling when this configuration applies").Action(c.RequestCondition.Set).StringVar(&c.RequestCondition.Value)
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	c.CmdClause.Flag("shield", "Selected POP to serve as a shield for the servers").Action(c.Shield.Set).StringVar(&c.Shield.Value)
+	c.CmdClause.Flag("tls-ca-cert", "A secure certificate to authenticate the server with").Action(c.TLSCACert.Set).StringVar(&c.TLSCACert.Value)
+	c.CmdClause.Flag("tls-cert-hostname", "The hostname used to verify the server's certificate").Action(c.TLSCertHostname.Set).StringVar(&c.TLSCertHostname.Value)
+	c.CmdClause.Flag("tls-check-cert", "Be strict on checking TLS certs").Action(c.TLSCheckCert.Set).BoolVar(&c.TLSCheckCert.Value)
+	c.CmdClause.Flag("tls-ciphers", "List of OpenSSL ciphers").Action(c.TLSCiphers.Set).StringVar(&c.TLSCiphers.Value)
+	c.CmdClause.Flag("tls-client-cert", "The client certificate used to make authenticated requests").Action(c.TLSClientCert.Set).StringVar(&c.TLSClientCert.Value)
+	c.CmdClause.Flag("tls-client-key", "The client private key used to make authenticated requests").Action(c.TLSClientKey.Set).StringVar(&c.TLSClientKey.Value)
+	c.CmdClause.Flag("tls-sni-hostname", "SNI hostname").Action(c.TLSSNIHostname.Set).StringVar(&c.TLSSNIHostname.Value)
+	c.CmdClause.Flag("type", "What type of load balance group to use (random, hash, client)").Action(c.Type.Set).HintOptions(poolTypes...).EnumVar(&c.Type.Value, poolTypes...)
+	c.CmdClause.Flag("use-tls", "Whether to use TLS").Action(c.UseTLS.Set).BoolVar(&c.UseTLS.Value)
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *UpdateCommand) Exec(_ io.Reader, out io.Writer) error {
+	serviceID, serviceVersion, err := cmd.ServiceDetails(cmd.ServiceDetailsOpts{
+		AutoCloneFlag:      c.autoClone,
+		APIClient:          c.Globals.APIClient,
+		Manifest:           c.manifest,
+		Out:                out,
+		ServiceNameFlag:    c.serviceName,
+		ServiceVersionFlag: c.serviceVersion,
+		VerboseMode:        c.Globals.Flags.Verbose,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": errors.ServiceVersion(serviceVersion),
+		})
+		return err
+	}
+
+	c.input.ServiceID = serviceID
+	c.input.ServiceVersion = serviceVersion.Number
+
+	if c.NewName.WasSet {
+		c.input.NewName = &c.NewName.Value
+	}
+
+	if c.Comment.WasSet {
+		c.input.Comment = &c.Comment.Value
+	}
+
+	if c.ConnectTimeout.WasSet {
+		c.input.ConnectTimeout = &c.ConnectTimeout.Value
+	}
+
+	if c.FirstByteTimeout.WasSet {
+		c.input.FirstByteTimeout = &c.FirstByteTimeout.Value
+	}
+
+	if c.Healthcheck.WasSet {
+		c.input.Healthcheck = &c.Healthcheck.Value
+	}
+
+	if c.MaxConnDefault.WasSet {
+		c.input.MaxConnDefault = &c.MaxConnDefault.Value
+	}
+
+	if c.MaxTLSVersion.WasSet {
+		c.input.MaxTLSVersion = &c.MaxTLSVersion.Value
+	}
+
+	if c.MinTLSVersion.WasSet {
+		c.input.MinTLSVersion = &c.MinTLSVersion.Value
+	}
+
+	if c.OverrideHost.WasSet {
+		c.input.OverrideHost = &c.OverrideHost.Value
+	}
+
+	if c.Quorum.WasSet {
+		c.input.Quorum = &c.Quorum.Value
+	}
+
+	if c.RequestCondition.WasSet {
+		c.input.RequestCondition = &c.RequestCondition.Value
+	}
+
+	if c.Shield.WasSet {
+		c.input.Shield = &c.Shield.Value
+	}
+
+	if c.TLSCACert.WasSet {
+		c.input.TLSCACert = &c.TLSCACert.Value
+	}
+
+	if c.TLSCertHostname.WasSet {
+		c.input.TLSCertHostname = &c.TLSCertHostname.Value
+	}
+
+	if c.TLSCheckCert.WasSet {
+		c.input.TLSCheckCert = fastly.CBool(c.TLSCheckCert.Value)
+	}
+
+	if c.TLSCiphers.WasSet {
+		c.input.TLSCiphers = &c.TLSCiphers.Value
+	}
+
+	if c.TLSClientCert.WasSet {
+		c.input.TLSClientCert = &c.TLSClientCert.Value
+	}
+
+	if c.TLSClientKey.WasSet {
+		c.input.TLSClientKey = &c.TLSClientKey.Value
+	}
+
+	if c.TLSSNIHostname.WasSet {
+		c.input.TLSSNIHostname = &c.TLSSNIHostname.Value
+	}
+
+	if c.Type.WasSet {
+		poolType := fastly.PoolType(c.Type.Value)
+		c.input.Type = &poolType
+	}
+
+	if c.UseTLS.WasSet {
+		c.input.UseTLS = fastly.CBool(c.UseTLS.Value)
+	}
+
+	o, err := c.Globals.APIClient.UpdatePool(&c.input)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": serviceVersion.Number,
+		})
+		return err
+	}
+
+	text.Success(out, "Updated pool %s (service %s version %d)", o.Name, o.ServiceID, o.ServiceVersion)
+	return nil
+}
@@ -3,6 +3,9 @@ package purge_test
 import (
 	"bytes"
 	"reflect"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/fastly/cli/pkg/app"
@@ -262,3 +265,141 @@ func TestPurgeURL(t *testing.T) {
 		})
 	}
 }
+
+func TestPurgeServices(t *testing.T) {
+	args := testutil.Args
+	listServices := func(i *fastly.ListServicesInput) fastly.PaginatorServices {
+		return &testutil.ServicesPaginator{MaxPages: 2}
+	}
+	scenarios := []testutil.TestScenario{
+		{
+			Name:      "validate --services and --service-id are mutually exclusive",
+			Args:      args("purge --all --services Ba* --service-id 123 --token 456"),
+			WantError: "invalid flag combination, --services and --service-id/--service-name",
+		},
+		{
+			Name:      "validate --services requires --all, --file or --key",
+			Args:      args("purge --services Ba* --token 456 --url https://example.com"),
+			WantError: "the --services flag requires one of --all, --file or --key",
+		},
+		{
+			Name: "validate no matching services",
+			API: mock.API{
+				NewListServicesPaginatorFn: listServices,
+			},
+			Args:      args("purge --all --services Qux* --token 456"),
+			WantError: "no services match --services 'Qux*'",
+		},
+		{
+			Name: "validate PurgeAll API success",
+			API: mock.API{
+				NewListServicesPaginatorFn: listServices,
+				PurgeAllFn: func(i *fastly.PurgeAllInput) (*fastly.Purge, error) {
+					return &fastly.Purge{
+						Status: "ok",
+					}, nil
+				},
+			},
+			Args:       args("purge --all --services Ba* --token 456 --auto-yes"),
+			WantOutput: "Service: Bar (456)\n\nSUCCESS: Purge all status: ok\n\nService: Baz (789)\n\nSUCCESS: Purge all status: ok\n\nSERVICE ID  NAME  RESULT\n456         Bar   ok\n789         Baz   ok\n",
+		},
+		{
+			Name: "validate PurgeKey API error doesn't stop other services",
+			API: mock.API{
+				NewListServicesPaginatorFn: listServices,
+				PurgeKeyFn: func(i *fastly.PurgeKeyInput) (*fastly.Purge, error) {
+					if i.ServiceID == "456" {
+						return nil, testutil.Err
+					}
+					return &fastly.Purge{
+						Status: "ok",
+						ID:     "123",
+					}, nil
+				},
+			},
+			Args:       args("purge --key foobar --services Ba*,Foo --token 456"),
+			WantError:  "1 of 3 services failed",
+			WantOutput: "SERVICE ID  NAME  RESULT\n123         Foo   ok\n456         Bar   failed: test error\n789         Baz   ok\n",
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+		})
+	}
+}
+
+func TestPurgeServicesConfirm(t *testing.T) {
+	scenarios := []struct {
+		name       string
+		args       string
+		stdin      string
+		wantPurges []string
+		wantOutput []string
+	}{
+		{
+			name:       "validate purge is confirmed",
+			args:       "purge --all --services Ba* --token 456",
+			stdin:      "y",
+			wantPurges: []string{"456", "789"},
+			wantOutput: []string{"This will purge ALL cached content from the following 2 services:", "Bar (456)", "Baz (789)", "Are you sure you want to continue?"},
+		},
+		{
+			name:       "validate purge is declined",
+			args:       "purge --all --services Ba* --token 456",
+			stdin:      "n",
+			wantOutput: []string{"Are you sure you want to continue?"},
+		},
+		{
+			name:       "validate --non-interactive skips the prompt",
+			args:       "purge --all --services Ba* --token 456 --non-interactive",
+			wantPurges: []string{"456", "789"},
+		},
+		{
+			name:       "validate a single service isn't confirmed",
+			args:       "purge --all --services Bar --token 456",
+			wantPurges: []string{"456"},
+		},
+	}
+
+	for _, testcase := range scenarios {
+		testcase := testcase
+		t.Run(testcase.name, func(t *testing.T) {
+			var (
+				stdout bytes.Buffer
+				mu     sync.Mutex
+				purges []string
+			)
+			opts := testutil.NewRunOpts(testutil.Args(testcase.args), &stdout)
+			opts.Stdin = strings.NewReader(testcase.stdin)
+			opts.APIClient = mock.APIClient(mock.API{
+				NewListServicesPaginatorFn: func(i *fastly.ListServicesInput) fastly.PaginatorServices {
+					return &testutil.ServicesPaginator{MaxPages: 2}
+				},
+				PurgeAllFn: func(i *fastly.PurgeAllInput) (*fastly.Purge, error) {
+					mu.Lock()
+					defer mu.Unlock()
+					purges = append(purges, i.ServiceID)
+					return &fastly.Purge{Status: "ok"}, nil
+				},
+			})
+			err := app.Run(opts)
+			testutil.AssertNoError(t, err)
+			sort.Strings(purges) // services are purged concurrently
+			testutil.AssertEqual(t, testcase.wantPurges, purges)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			if testcase.stdin == "" {
+				testutil.AssertStringDoesntContain(t, stdout.String(), "Are you sure you want to continue?")
+			}
+		})
+	}
+}
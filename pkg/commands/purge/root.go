@@ -41,6 +41,7 @@ func NewRootCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Roo
 		Description: cmd.FlagServiceDesc,
 		Dst:         &c.serviceName.Value,
 	})
+	c.RegisterFlag(c.ServicesFlag()) // --services
 	c.CmdClause.Flag("soft", "A 'soft' purge marks affected objects as stale rather than making them inaccessible").BoolVar(&c.soft)
 	c.CmdClause.Flag("url", "Purge an individual URL").StringVar(&c.url)
 
@@ -51,6 +52,7 @@ func NewRootCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Roo
 // It should be installed under the primary root command.
 type RootCommand struct {
 	cmd.Base
+	cmd.ServiceSelector
 
 	all         bool
 	file        string
@@ -62,12 +64,26 @@ type RootCommand struct {
 }
 
 // Exec implements the command interface.
-func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
+func (c *RootCommand) Exec(in io.Reader, out io.Writer) error {
 	_, s := c.Globals.Token()
 	if s == lookup.SourceUndefined {
 		return fsterr.ErrNoToken
 	}
 
+	if c.all && c.soft {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("purge-all requests cannot be done in soft mode (--soft) and will always immediately invalidate all cached content associated with the service"),
+			Remediation: "The --soft flag should not be used with --all so retry command without it.",
+		}
+	}
+
+	if c.SelectingServices() {
+		if c.manifest.Flag.ServiceID != "" || c.serviceName.WasSet {
+			return fsterr.ErrInvalidServicesCombo
+		}
+		return c.purgeServices(in, out)
+	}
+
 	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
 	if err != nil {
 		return err
@@ -84,12 +100,6 @@ func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
 	}
 
 	if c.all {
-		if c.soft {
-			return fsterr.RemediationError{
-				Inner:       fmt.Errorf("purge-all requests cannot be done in soft mode (--soft) and will always immediately invalidate all cached content associated with the service"),
-				Remediation: "The --soft flag should not be used with --all so retry command without it.",
-			}
-		}
 		err := c.purgeAll(serviceID, out)
 		if err != nil {
 			c.Globals.ErrLog.AddWithContext(err, map[string]any{
@@ -139,6 +149,57 @@ func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
 	return nil
 }
 
+// purgeServices purges each of the services matching the --services selector,
+// reporting the result for each service rather than stopping at the first
+// failure.
+//
+// Purging everything from more than one service must be confirmed, unless
+// --auto-yes or --non-interactive is set.
+func (c *RootCommand) purgeServices(in io.Reader, out io.Writer) error {
+	if c.url != "" || (!c.all && c.file == "" && c.key == "") {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("the --services flag requires one of --all, --file or --key"),
+			Remediation: "URL purges don't require a service, so run the command without --services to purge a URL.",
+		}
+	}
+
+	services, err := cmd.SelectServices(c.Services, c.Globals.APIClient)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Services": c.Services,
+		})
+		return err
+	}
+
+	if c.all && len(services) > 1 && !c.Globals.Flags.AutoYes && !c.Globals.Flags.NonInteractive {
+		text.Warning(out, "This will purge ALL cached content from the following %d services:", len(services))
+		text.Break(out)
+		for _, s := range services {
+			text.Indent(out, 4, "%s", s)
+		}
+		text.Break(out)
+		cont, err := text.AskYesNo(out, "Are you sure you want to continue? [yes/no]: ", in)
+		if err != nil {
+			return err
+		}
+		if !cont {
+			return nil
+		}
+		text.Break(out)
+	}
+
+	results := cmd.ForEachService(out, services, func(s cmd.SelectedService, out io.Writer) error {
+		switch {
+		case c.all:
+			return c.purgeAll(s.ID, out)
+		case c.file != "":
+			return c.purgeKeys(s.ID, out)
+		}
+		return c.purgeKey(s.ID, out)
+	})
+	return cmd.PrintServiceSummary(out, results)
+}
+
 func (c *RootCommand) purgeAll(serviceID string, out io.Writer) error {
 	p, err := c.Globals.APIClient.PurgeAll(&fastly.PurgeAllInput{
 		ServiceID: serviceID,
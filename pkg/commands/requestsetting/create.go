@@ -0,0 +1,177 @@
+package requestsetting
+
+import (
+	"io"
+
+	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v8/fastly"
+)
+
+// requestSettingActions are the accepted values for the --action flag.
+var requestSettingActions = []string{
+	string(fastly.RequestSettingActionLookup),
+	string(fastly.RequestSettingActionPass),
+}
+
+// requestSettingXFFs are the accepted values for the --xff flag.
+var requestSettingXFFs = []string{
+	string(fastly.RequestSettingXFFClear),
+	string(fastly.RequestSettingXFFLeave),
+	string(fastly.RequestSettingXFFAppend),
+	string(fastly.RequestSettingXFFAppendAll),
+	string(fastly.RequestSettingXFFOverwrite),
+}
+
+// CreateCommand calls the Fastly API to create request settings.
+type CreateCommand struct {
+	cmd.Base
+	manifest manifest.Data
+
+	// Required.
+	serviceVersion cmd.OptionalServiceVersion
+
+	// Optional.
+	action           cmd.OptionalString
+	autoClone        cmd.OptionalAutoClone
+	bypassBusyWait   cmd.OptionalBool
+	defaultHost      cmd.OptionalString
+	forceMiss        cmd.OptionalBool
+	forceSSL         cmd.OptionalBool
+	geoHeaders       cmd.OptionalBool
+	hashKeys         cmd.OptionalString
+	maxStaleAge      cmd.OptionalInt
+	name             cmd.OptionalString
+	requestCondition cmd.OptionalString
+	serviceName      cmd.OptionalServiceNameID
+	timerSupport     cmd.OptionalBool
+	xff              cmd.OptionalString
+}
+
+// NewCreateCommand returns a usable command registered under the parent.
+func NewCreateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *CreateCommand {
+	c := CreateCommand{
+		Base: cmd.Base{
+			Globals: g,
+		},
+		manifest: m,
+	}
+	c.CmdClause = parent.Command("create", "Create a request setting on a Fastly service version").Alias("add")
+
+	// Required.
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagVersionName,
+		Description: cmd.FlagVersionDesc,
+		Dst:         &c.serviceVersion.Value,
+		Required:    true,
+	})
+
+	// Optional.
+	c.RegisterAutoCloneFlag(cmd.AutoCloneFlagOpts{
+		Action: c.autoClone.Set,
+		Dst:    &c.autoClone.Value,
+	})
+	c.CmdClause.Flag("action", "Allows you to terminate request handling and immediately perform an action (lookup, pass)").Action(c.action.Set).HintOptions(requestSettingActions...).EnumVar(&c.action.Value, requestSettingActions...)
+	c.CmdClause.Flag("bypass-busy-wait", "Disable collapsed forwarding, so you don't wait for other objects to origin").Action(c.bypassBusyWait.Set).BoolVar(&c.bypassBusyWait.Value)
+	c.CmdClause.Flag("default-host", "Sets the host header").Action(c.defaultHost.Set).StringVar(&c.defaultHost.Value)
+	c.CmdClause.Flag("force-miss", "Allows you to force a cache miss for the request").Action(c.forceMiss.Set).BoolVar(&c.forceMiss.Value)
+	c.CmdClause.Flag("force-ssl", "Forces the request to use SSL (redirects a non-SSL request to SSL)").Action(c.forceSSL.Set).BoolVar(&c.forceSSL.Value)
+	c.CmdClause.Flag("geo-headers", "Injects Fastly-Geo-Country, Fastly-Geo-City, and Fastly-Geo-Region into the request headers").Action(c.geoHeaders.Set).BoolVar(&c.geoHeaders.Value)
+	c.CmdClause.Flag("hash-keys", "Comma separated list of varnish request object fields that should be in the hash key").Action(c.hashKeys.Set).StringVar(&c.hashKeys.Value)
+	c.CmdClause.Flag("max-stale-age", "How old an object is allowed to be to serve stale-if-error or stale-while-revalidate").Action(c.maxStaleAge.Set).IntVar(&c.maxStaleAge.Value)
+	c.CmdClause.Flag("name", "Name of the request setting").Short('n').Action(c.name.Set).StringVar(&c.name.Value)
+	c.CmdClause.Flag("request-condition", "Name of the request condition controlling when this configuration applies").Action(c.requestCondition.Set).StringVar(&c.requestCondition.Value)
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	c.CmdClause.Flag("timer-support", "Injects the X-Timer info into the request for viewing origin fetch durations").Action(c.timerSupport.Set).BoolVar(&c.timerSupport.Value)
+	c.CmdClause.Flag("xff", "Short for X-Forwarded-For (clear, leave, append, append_all, overwrite)").Action(c.xff.Set).HintOptions(requestSettingXFFs...).EnumVar(&c.xff.Value, requestSettingXFFs...)
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
+	serviceID, serviceVersion, err := cmd.ServiceDetails(cmd.ServiceDetailsOpts{
+		AutoCloneFlag:      c.autoClone,
+		APIClient:          c.Globals.APIClient,
+		Manifest:           c.manifest,
+		Out:                out,
+		ServiceNameFlag:    c.serviceName,
+		ServiceVersionFlag: c.serviceVersion,
+		VerboseMode:        c.Globals.Flags.Verbose,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": errors.ServiceVersion(serviceVersion),
+		})
+		return err
+	}
+
+	input := fastly.CreateRequestSettingInput{
+		ServiceID:      serviceID,
+		ServiceVersion: serviceVersion.Number,
+	}
+	if c.name.WasSet {
+		input.Name = &c.name.Value
+	}
+	if c.action.WasSet {
+		action := fastly.RequestSettingAction(c.action.Value)
+		input.Action = &action
+	}
+	if c.bypassBusyWait.WasSet {
+		input.BypassBusyWait = fastly.CBool(c.bypassBusyWait.Value)
+	}
+	if c.defaultHost.WasSet {
+		input.DefaultHost = &c.defaultHost.Value
+	}
+	if c.forceMiss.WasSet {
+		input.ForceMiss = fastly.CBool(c.forceMiss.Value)
+	}
+	if c.forceSSL.WasSet {
+		input.ForceSSL = fastly.CBool(c.forceSSL.Value)
+	}
+	if c.geoHeaders.WasSet {
+		input.GeoHeaders = fastly.CBool(c.geoHeaders.Value)
+	}
+	if c.hashKeys.WasSet {
+		input.HashKeys = &c.hashKeys.Value
+	}
+	if c.maxStaleAge.WasSet {
+		input.MaxStaleAge = &c.maxStaleAge.Value
+	}
+	if c.requestCondition.WasSet {
+		input.RequestCondition = &c.requestCondition.Value
+	}
+	if c.timerSupport.WasSet {
+		input.TimerSupport = fastly.CBool(c.timerSupport.Value)
+	}
+	if c.xff.WasSet {
+		xff := fastly.RequestSettingXFF(c.xff.Value)
+		input.XForwardedFor = &xff
+	}
+
+	o, err := c.Globals.APIClient.CreateRequestSetting(&input)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": serviceVersion.Number,
+		})
+		return err
+	}
+
+	text.Success(out, "Created request setting %s (service %s version %d)", o.Name, o.ServiceID, o.ServiceVersion)
+	return nil
+}
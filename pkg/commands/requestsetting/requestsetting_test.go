@@ -0,0 +1,306 @@
+package requestsetting_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fastly/go-fastly/v8/fastly"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestRequestSettingCreate(t *testing.T) {
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name:      "validate --action is a known request setting action",
+			Args:      args("request-setting create --service-id 123 --version 1 --name force-ssl --action cache"),
+			WantError: "error parsing arguments: enum value must be one of lookup,pass, got 'cache'",
+		},
+		{
+			Name:      "validate --xff is a known X-Forwarded-For mode",
+			Args:      args("request-setting create --service-id 123 --version 1 --name force-ssl --xff prepend"),
+			WantError: "error parsing arguments: enum value must be one of clear,leave,append,append_all,overwrite, got 'prepend'",
+		},
+		{
+			Name: "validate CreateRequestSetting API error",
+			Args: args("request-setting create --service-id 123 --version 1 --name force-ssl --autoclone"),
+			API: mock.API{
+				ListVersionsFn:         testutil.ListVersions,
+				CloneVersionFn:         testutil.CloneVersionResult(4),
+				CreateRequestSettingFn: createRequestSettingError,
+			},
+			WantError: errTest.Error(),
+		},
+		{
+			Name: "validate the request setting is created from the flags",
+			Args: args("request-setting create --service-id 123 --version 1 --name force-ssl --autoclone --action lookup --force-ssl --timer-support --hash-keys req.url,req.http.host --max-stale-age 60 --xff append_all"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CloneVersionFn: testutil.CloneVersionResult(4),
+				CreateRequestSettingFn: createRequestSettingWith(fastly.CreateRequestSettingInput{
+					ServiceID:      "123",
+					ServiceVersion: 4,
+					Name:           fastly.String("force-ssl"),
+					Action:         fastly.RequestSettingActionPtr(fastly.RequestSettingActionLookup),
+					ForceSSL:       fastly.CBool(true),
+					HashKeys:       fastly.String("req.url,req.http.host"),
+					MaxStaleAge:    fastly.Int(60),
+					TimerSupport:   fastly.CBool(true),
+					XForwardedFor:  fastly.RequestSettingXFFPtr(fastly.RequestSettingXFFAppendAll),
+				}),
+			},
+			WantOutput: "Created request setting force-ssl (service 123 version 4)",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+		})
+	}
+}
+
+func TestRequestSettingList(t *testing.T) {
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Args: args("request-setting list --service-id 123 --version 1"),
+			API: mock.API{
+				ListVersionsFn:        testutil.ListVersions,
+				ListRequestSettingsFn: listRequestSettingsOK,
+			},
+			WantOutput: listRequestSettingsShortOutput,
+		},
+		{
+			Args: args("request-setting list --service-id 123 --version 1 --verbose"),
+			API: mock.API{
+				ListVersionsFn:        testutil.ListVersions,
+				ListRequestSettingsFn: listRequestSettingsOK,
+			},
+			WantOutput: listRequestSettingsVerboseOutput,
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(strings.Join(testcase.Args, " "), func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertString(t, testcase.WantOutput, stdout.String())
+		})
+	}
+}
+
+func TestRequestSettingDescribe(t *testing.T) {
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("request-setting describe --service-id 123 --version 1 --name force-ssl"), &stdout)
+	opts.APIClient = mock.APIClient(mock.API{
+		ListVersionsFn:      testutil.ListVersions,
+		GetRequestSettingFn: getRequestSettingOK,
+	})
+	err := app.Run(opts)
+	testutil.AssertNoError(t, err)
+	testutil.AssertString(t, describeRequestSettingOutput, stdout.String())
+}
+
+func TestRequestSettingUpdate(t *testing.T) {
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name:      "validate missing --name flag",
+			Args:      args("request-setting update --service-id 123 --version 1 --force-miss"),
+			WantError: "error parsing arguments: required flag --name not provided",
+		},
+		{
+			Name: "validate the request setting is renamed with its new XFF mode and flags",
+			Args: args("request-setting update --service-id 123 --version 1 --name force-ssl --new-name bypass --bypass-busy-wait --default-host origin.example.com --xff overwrite --autoclone"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CloneVersionFn: testutil.CloneVersionResult(4),
+				UpdateRequestSettingFn: updateRequestSettingWith(fastly.UpdateRequestSettingInput{
+					ServiceID:      "123",
+					ServiceVersion: 4,
+					Name:           "force-ssl",
+					NewName:        fastly.String("bypass"),
+					BypassBusyWait: fastly.CBool(true),
+					DefaultHost:    fastly.String("origin.example.com"),
+					XForwardedFor:  fastly.RequestSettingXFFOverwrite,
+				}),
+			},
+			WantOutput: "Updated request setting bypass (service 123 version 4)",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+		})
+	}
+}
+
+func TestRequestSettingDelete(t *testing.T) {
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("request-setting delete --service-id 123 --version 1 --name force-ssl --autoclone"), &stdout)
+	opts.APIClient = mock.APIClient(mock.API{
+		ListVersionsFn: testutil.ListVersions,
+		CloneVersionFn: testutil.CloneVersionResult(4),
+		DeleteRequestSettingFn: func(i *fastly.DeleteRequestSettingInput) error {
+			if i.Name != "force-ssl" || i.ServiceVersion != 4 {
+				return errTest
+			}
+			return nil
+		},
+	})
+	err := app.Run(opts)
+	testutil.AssertNoError(t, err)
+	testutil.AssertStringContains(t, stdout.String(), "Deleted request setting force-ssl (service 123 version 4)")
+}
+
+var errTest = errors.New("fixture error")
+
+func createRequestSettingWith(want fastly.CreateRequestSettingInput) func(*fastly.CreateRequestSettingInput) (*fastly.RequestSetting, error) {
+	return func(i *fastly.CreateRequestSettingInput) (*fastly.RequestSetting, error) {
+		if !reflect.DeepEqual(*i, want) {
+			return nil, fmt.Errorf("unexpected input: %+v", *i)
+		}
+		return &fastly.RequestSetting{
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+			Name:           *i.Name,
+		}, nil
+	}
+}
+
+func createRequestSettingError(i *fastly.CreateRequestSettingInput) (*fastly.RequestSetting, error) {
+	return nil, errTest
+}
+
+func listRequestSettingsOK(i *fastly.ListRequestSettingsInput) ([]*fastly.RequestSetting, error) {
+	return []*fastly.RequestSetting{
+		{
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+			Name:           "force-ssl",
+			Action:         fastly.RequestSettingActionLookup,
+			ForceSSL:       true,
+			XForwardedFor:  fastly.RequestSettingXFFAppend,
+			DefaultHost:    "www.example.com",
+		},
+		{
+			ServiceID:        i.ServiceID,
+			ServiceVersion:   i.ServiceVersion,
+			Name:             "pass-admin",
+			Action:           fastly.RequestSettingActionPass,
+			XForwardedFor:    fastly.RequestSettingXFFLeave,
+			RequestCondition: "is-admin",
+			MaxStaleAge:      60,
+			DefaultHost:      "admin.example.com",
+		},
+	}, nil
+}
+
+var listRequestSettingsShortOutput = strings.TrimSpace(`
+SERVICE  VERSION  NAME        ACTION  FORCE SSL  XFF     DEFAULT HOST
+123      1        force-ssl   lookup  true       append  www.example.com
+123      1        pass-admin  pass    false      leave   admin.example.com
+`) + "\n"
+
+var listRequestSettingsVerboseOutput = strings.Join([]string{
+	"Fastly API token not provided",
+	"Fastly API endpoint: https://api.fastly.com",
+	"",
+	"Service ID (via --service-id): 123",
+	"",
+	"Version: 1",
+	"	Request setting 1/2",
+	"		Name: force-ssl",
+	"		Action: lookup",
+	"		Default host: www.example.com",
+	"		Force miss: false",
+	"		Force SSL: true",
+	"		Bypass busy wait: false",
+	"		Geo headers: false",
+	"		Hash keys: ",
+	"		Max stale age: 0",
+	"		Timer support: false",
+	"		X-Forwarded-For: append",
+	"		Request condition: ",
+	"	Request setting 2/2",
+	"		Name: pass-admin",
+	"		Action: pass",
+	"		Default host: admin.example.com",
+	"		Force miss: false",
+	"		Force SSL: false",
+	"		Bypass busy wait: false",
+	"		Geo headers: false",
+	"		Hash keys: ",
+	"		Max stale age: 60",
+	"		Timer support: false",
+	"		X-Forwarded-For: leave",
+	"		Request condition: is-admin",
+}, "\n") + "\n\n"
+
+func getRequestSettingOK(i *fastly.GetRequestSettingInput) (*fastly.RequestSetting, error) {
+	return &fastly.RequestSetting{
+		ServiceID:      i.ServiceID,
+		ServiceVersion: i.ServiceVersion,
+		Name:           "force-ssl",
+		Action:         fastly.RequestSettingActionLookup,
+		ForceSSL:       true,
+		XForwardedFor:  fastly.RequestSettingXFFAppend,
+		DefaultHost:    "www.example.com",
+	}, nil
+}
+
+var describeRequestSettingOutput = "\n" + strings.Join([]string{
+	"Service ID: 123",
+	"Version: 1",
+	"Name: force-ssl",
+	"Action: lookup",
+	"Default host: www.example.com",
+	"Force miss: false",
+	"Force SSL: true",
+	"Bypass busy wait: false",
+	"Geo headers: false",
+	"Hash keys: ",
+	"Max stale age: 0",
+	"Timer support: false",
+	"X-Forwarded-For: append",
+	"Request condition: ",
+}, "\n") + "\n"
+
+func updateRequestSettingWith(want fastly.UpdateRequestSettingInput) func(*fastly.UpdateRequestSettingInput) (*fastly.RequestSetting, error) {
+	return func(i *fastly.UpdateRequestSettingInput) (*fastly.RequestSetting, error) {
+		if !reflect.DeepEqual(*i, want) {
+			return nil, fmt.Errorf("unexpected input: %+v", *i)
+		}
+		name := i.Name
+		if i.NewName != nil {
+			name = *i.NewName
+		}
+		return &fastly.RequestSetting{
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+			Name:           name,
+		}, nil
+	}
+}
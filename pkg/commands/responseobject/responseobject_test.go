@@ -0,0 +1,287 @@
+package responseobject_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fastly/go-fastly/v8/fastly"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestResponseObjectCreate(t *testing.T) {
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name:      "validate --status is a number",
+			Args:      args("response-object create --service-id 123 --version 1 --name not-found --status missing"),
+			WantError: `error parsing arguments: strconv.ParseFloat: parsing "missing": invalid syntax`,
+		},
+		{
+			Name: "validate CreateResponseObject API error",
+			Args: args("response-object create --service-id 123 --version 1 --name not-found --autoclone"),
+			API: mock.API{
+				ListVersionsFn:         testutil.ListVersions,
+				CloneVersionFn:         testutil.CloneVersionResult(4),
+				CreateResponseObjectFn: createResponseObjectError,
+			},
+			WantError: errTest.Error(),
+		},
+		{
+			Name: "validate the response object is created from the flags",
+			Args: append(
+				args("response-object create --service-id 123 --version 1 --name not-found --autoclone --content-type text/plain --request-condition is-missing --status 404 --content NotFound --response"),
+				"Not Found",
+			),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CloneVersionFn: testutil.CloneVersionResult(4),
+				CreateResponseObjectFn: createResponseObjectWith(fastly.CreateResponseObjectInput{
+					ServiceID:        "123",
+					ServiceVersion:   4,
+					Name:             fastly.String("not-found"),
+					Content:          fastly.String("NotFound"),
+					ContentType:      fastly.String("text/plain"),
+					RequestCondition: fastly.String("is-missing"),
+					Response:         fastly.String("Not Found"),
+					Status:           fastly.Int(404),
+				}),
+			},
+			WantOutput: "Created response object not-found (service 123 version 4)",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+		})
+	}
+}
+
+func TestResponseObjectList(t *testing.T) {
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Args: args("response-object list --service-id 123 --version 1"),
+			API: mock.API{
+				ListVersionsFn:        testutil.ListVersions,
+				ListResponseObjectsFn: listResponseObjectsOK,
+			},
+			WantOutput: listResponseObjectsShortOutput,
+		},
+		{
+			Args: args("response-object list --service-id 123 --version 1 --verbose"),
+			API: mock.API{
+				ListVersionsFn:        testutil.ListVersions,
+				ListResponseObjectsFn: listResponseObjectsOK,
+			},
+			WantOutput: listResponseObjectsVerboseOutput,
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(strings.Join(testcase.Args, " "), func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertString(t, testcase.WantOutput, stdout.String())
+		})
+	}
+}
+
+func TestResponseObjectDescribe(t *testing.T) {
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("response-object describe --service-id 123 --version 1 --name not-found"), &stdout)
+	opts.APIClient = mock.APIClient(mock.API{
+		ListVersionsFn:      testutil.ListVersions,
+		GetResponseObjectFn: getResponseObjectOK,
+	})
+	err := app.Run(opts)
+	testutil.AssertNoError(t, err)
+	testutil.AssertString(t, describeResponseObjectOutput, stdout.String())
+}
+
+func TestResponseObjectUpdate(t *testing.T) {
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name:      "validate missing --name flag",
+			Args:      args("response-object update --service-id 123 --version 1 --status 503"),
+			WantError: "error parsing arguments: required flag --name not provided",
+		},
+		{
+			Name: "validate the response object moves to a cache condition with a new status",
+			Args: args("response-object update --service-id 123 --version 1 --name not-found --cache-condition is-stale --status 503 --autoclone"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CloneVersionFn: testutil.CloneVersionResult(4),
+				UpdateResponseObjectFn: updateResponseObjectWith(fastly.UpdateResponseObjectInput{
+					ServiceID:      "123",
+					ServiceVersion: 4,
+					Name:           "not-found",
+					CacheCondition: fastly.String("is-stale"),
+					Status:         fastly.Int(503),
+				}),
+			},
+			WantOutput: "Updated response object not-found (service 123 version 4)",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+		})
+	}
+}
+
+func TestResponseObjectDelete(t *testing.T) {
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("response-object delete --service-id 123 --version 1 --name not-found --autoclone"), &stdout)
+	opts.APIClient = mock.APIClient(mock.API{
+		ListVersionsFn: testutil.ListVersions,
+		CloneVersionFn: testutil.CloneVersionResult(4),
+		DeleteResponseObjectFn: func(i *fastly.DeleteResponseObjectInput) error {
+			if i.Name != "not-found" || i.ServiceVersion != 4 {
+				return errTest
+			}
+			return nil
+		},
+	})
+	err := app.Run(opts)
+	testutil.AssertNoError(t, err)
+	testutil.AssertStringContains(t, stdout.String(), "Deleted response object not-found (service 123 version 4)")
+}
+
+var errTest = errors.New("fixture error")
+
+func createResponseObjectWith(want fastly.CreateResponseObjectInput) func(*fastly.CreateResponseObjectInput) (*fastly.ResponseObject, error) {
+	return func(i *fastly.CreateResponseObjectInput) (*fastly.ResponseObject, error) {
+		if !reflect.DeepEqual(*i, want) {
+			return nil, fmt.Errorf("unexpected input: %+v", *i)
+		}
+		return &fastly.ResponseObject{
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+			Name:           *i.Name,
+		}, nil
+	}
+}
+
+func createResponseObjectError(i *fastly.CreateResponseObjectInput) (*fastly.ResponseObject, error) {
+	return nil, errTest
+}
+
+func listResponseObjectsOK(i *fastly.ListResponseObjectsInput) ([]*fastly.ResponseObject, error) {
+	return []*fastly.ResponseObject{
+		{
+			ServiceID:        i.ServiceID,
+			ServiceVersion:   i.ServiceVersion,
+			Name:             "not-found",
+			Status:           404,
+			Response:         "Not Found",
+			ContentType:      "text/plain",
+			Content:          "Not Found",
+			RequestCondition: "is-missing",
+		},
+		{
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+			Name:           "maintenance",
+			Status:         503,
+			Response:       "Service Unavailable",
+			ContentType:    "text/html",
+			Content:        "<h1>Down for maintenance</h1>",
+		},
+	}, nil
+}
+
+var listResponseObjectsShortOutput = strings.TrimSpace(`
+SERVICE  VERSION  NAME         STATUS  RESPONSE             CONTENT TYPE
+123      1        not-found    404     Not Found            text/plain
+123      1        maintenance  503     Service Unavailable  text/html
+`) + "\n"
+
+var listResponseObjectsVerboseOutput = strings.Join([]string{
+	"Fastly API token not provided",
+	"Fastly API endpoint: https://api.fastly.com",
+	"",
+	"Service ID (via --service-id): 123",
+	"",
+	"Version: 1",
+	"	Response object 1/2",
+	"		Name: not-found",
+	"		Status: 404",
+	"		Response: Not Found",
+	"		Content type: text/plain",
+	"		Content: Not Found",
+	"		Request condition: is-missing",
+	"		Cache condition: ",
+	"	Response object 2/2",
+	"		Name: maintenance",
+	"		Status: 503",
+	"		Response: Service Unavailable",
+	"		Content type: text/html",
+	"		Content: <h1>Down for maintenance</h1>",
+	"		Request condition: ",
+	"		Cache condition: ",
+}, "\n") + "\n\n"
+
+func getResponseObjectOK(i *fastly.GetResponseObjectInput) (*fastly.ResponseObject, error) {
+	return &fastly.ResponseObject{
+		ServiceID:        i.ServiceID,
+		ServiceVersion:   i.ServiceVersion,
+		Name:             "not-found",
+		Status:           404,
+		Response:         "Not Found",
+		ContentType:      "text/plain",
+		Content:          "Not Found",
+		RequestCondition: "is-missing",
+	}, nil
+}
+
+var describeResponseObjectOutput = "\n" + strings.Join([]string{
+	"Service ID: 123",
+	"Version: 1",
+	"Name: not-found",
+	"Status: 404",
+	"Response: Not Found",
+	"Content type: text/plain",
+	"Content: Not Found",
+	"Request condition: is-missing",
+	"Cache condition: ",
+}, "\n") + "\n"
+
+func updateResponseObjectWith(want fastly.UpdateResponseObjectInput) func(*fastly.UpdateResponseObjectInput) (*fastly.ResponseObject, error) {
+	return func(i *fastly.UpdateResponseObjectInput) (*fastly.ResponseObject, error) {
+		if !reflect.DeepEqual(*i, want) {
+			return nil, fmt.Errorf("unexpected input: %+v", *i)
+		}
+		name := i.Name
+		if i.NewName != nil {
+			name = *i.NewName
+		}
+		return &fastly.ResponseObject{
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+			Name:           name,
+		}, nil
+	}
+}
@@ -24,9 +24,13 @@ func NewListCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Lis
 	c.CmdClause = parent.Command("list", "List secret stores")
 
 	// Optional.
+	c.RegisterFlagBool(c.AllFlag())                  // --all
 	c.RegisterFlag(cmd.CursorFlag(&c.Input.Cursor))  // --cursor
+	c.RegisterFlag(c.FieldsFlag())                   // --fields
+	c.RegisterFlag(c.FilterFlag())                   // --filter
 	c.RegisterFlagBool(c.JSONFlag())                 // --json
 	c.RegisterFlagInt(cmd.LimitFlag(&c.Input.Limit)) // --limit
+	c.RegisterFlagInt(c.MaxResultsFlag())            // --max-results
 
 	return &c
 }
@@ -35,6 +39,7 @@ func NewListCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Lis
 type ListCommand struct {
 	cmd.Base
 	cmd.JSONOutput
+	cmd.Pagination
 
 	// NOTE: API returns 10 items even when --limit is set to smaller.
 	Input    fastly.ListSecretStoresInput
@@ -47,6 +52,10 @@ func (c *ListCommand) Exec(in io.Reader, out io.Writer) error {
 		return fsterr.ErrInvalidVerboseJSONCombo
 	}
 
+	if c.Streaming() {
+		return c.stream(out)
+	}
+
 	var data []fastly.SecretStore
 
 	for {
@@ -59,7 +68,7 @@ func (c *ListCommand) Exec(in io.Reader, out io.Writer) error {
 		if o != nil {
 			data = append(data, o.Data...)
 
-			if c.JSONOutput.Enabled || c.Globals.Flags.NonInteractive || c.Globals.Flags.AutoYes {
+			if c.JSONOutput.Enabled || c.Fields != "" || c.Filter != "" || c.Globals.Flags.NonInteractive || c.Globals.Flags.AutoYes {
 				if o.Meta.NextCursor != "" {
 					c.Input.Cursor = o.Meta.NextCursor
 					continue
@@ -99,3 +108,30 @@ func (c *ListCommand) Exec(in io.Reader, out io.Writer) error {
 	}
 	return nil
 }
+
+// stream prints the stores a page at a time as they're fetched, without
+// prompting for each page.
+func (c *ListCommand) stream(out io.Writer) error {
+	w := cmd.NewPageWriter(out, &c.JSONOutput, func(stores []fastly.SecretStore) {
+		tbl := text.NewTable(out)
+		tbl.AddHeader("Name", "ID")
+		for _, store := range stores {
+			tbl.AddLine(store.Name, store.ID)
+		}
+		tbl.Print()
+	})
+
+	page := func(cursor string) ([]fastly.SecretStore, string, error) {
+		c.Input.Cursor = cursor
+		o, err := c.Globals.APIClient.ListSecretStores(&c.Input)
+		if err != nil || o == nil {
+			return nil, "", err
+		}
+		return o.Data, o.Meta.NextCursor, nil
+	}
+	if err := cmd.FetchCursorPages(c.Limit, c.Input.Cursor, page, w.Write); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	return w.Close()
+}
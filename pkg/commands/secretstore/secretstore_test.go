@@ -329,6 +329,37 @@ func TestListStoresCommand(t *testing.T) {
 			wantAPIInvoked: true,
 			wantOutput:     fstfmt.EncodeJSON([]fastly.SecretStore{stores.Data[0]}),
 		},
+		{
+			args: "list --all",
+			api: mock.API{
+				ListSecretStoresFn: func(i *fastly.ListSecretStoresInput) (*fastly.SecretStores, error) {
+					if i.Cursor == "" {
+						return &fastly.SecretStores{
+							Meta: fastly.SecretStoreMeta{NextCursor: "next"},
+							Data: []fastly.SecretStore{{ID: "a", Name: "first"}},
+						}, nil
+					}
+					return &fastly.SecretStores{
+						Data: []fastly.SecretStore{{ID: "b", Name: "second"}},
+					}, nil
+				},
+			},
+			wantAPIInvoked: true,
+			wantOutput:     "Name    ID\nfirst   a\nsecond  b\n",
+		},
+		{
+			args: "list --max-results 1 --json",
+			api: mock.API{
+				ListSecretStoresFn: func(i *fastly.ListSecretStoresInput) (*fastly.SecretStores, error) {
+					return &fastly.SecretStores{
+						Meta: fastly.SecretStoreMeta{NextCursor: "next"},
+						Data: []fastly.SecretStore{{ID: "a", Name: "first"}},
+					}, nil
+				},
+			},
+			wantAPIInvoked: true,
+			wantOutput:     "[\n  {\n    \"id\": \"a\",\n    \"name\": \"first\",\n    \"created_at\": \"0001-01-01T00:00:00Z\"\n  }\n]\n",
+		},
 	}
 
 	for _, testcase := range scenarios {
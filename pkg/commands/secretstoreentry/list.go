@@ -1,6 +1,7 @@
 package secretstoreentry
 
 import (
+	"encoding/hex"
 	"io"
 
 	"github.com/fastly/cli/pkg/cmd"
@@ -26,9 +27,13 @@ func NewListCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Lis
 	c.RegisterFlag(cmd.StoreIDFlag(&c.Input.ID)) // --store-id
 
 	// Optional.
+	c.RegisterFlagBool(c.AllFlag())                  // --all
 	c.RegisterFlag(cmd.CursorFlag(&c.Input.Cursor))  // --cursor
+	c.RegisterFlag(c.FieldsFlag())                   // --fields
+	c.RegisterFlag(c.FilterFlag())                   // --filter
 	c.RegisterFlagBool(c.JSONFlag())                 // --json
 	c.RegisterFlagInt(cmd.LimitFlag(&c.Input.Limit)) // --limit
+	c.RegisterFlagInt(c.MaxResultsFlag())            // --max-results
 
 	return &c
 }
@@ -37,6 +42,7 @@ func NewListCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Lis
 type ListCommand struct {
 	cmd.Base
 	cmd.JSONOutput
+	cmd.Pagination
 
 	Input    fastly.ListSecretsInput
 	manifest manifest.Data
@@ -48,6 +54,10 @@ func (c *ListCommand) Exec(in io.Reader, out io.Writer) error {
 		return fsterr.ErrInvalidVerboseJSONCombo
 	}
 
+	if c.Streaming() {
+		return c.stream(out)
+	}
+
 	for {
 		o, err := c.Globals.APIClient.ListSecrets(&c.Input)
 		if err != nil {
@@ -79,3 +89,32 @@ func (c *ListCommand) Exec(in io.Reader, out io.Writer) error {
 		return nil
 	}
 }
+
+// stream prints the secrets a page at a time as they're fetched, without
+// prompting for each page.
+func (c *ListCommand) stream(out io.Writer) error {
+	w := cmd.NewPageWriter(out, &c.JSONOutput, func(secrets []fastly.Secret) {
+		tbl := text.NewTable(out)
+		tbl.AddHeader("Name", "Digest")
+		for _, s := range secrets {
+			// avoid gosec loop aliasing check :/
+			s := s
+			tbl.AddLine(s.Name, hex.EncodeToString(s.Digest))
+		}
+		tbl.Print()
+	})
+
+	page := func(cursor string) ([]fastly.Secret, string, error) {
+		c.Input.Cursor = cursor
+		o, err := c.Globals.APIClient.ListSecrets(&c.Input)
+		if err != nil || o == nil {
+			return nil, "", err
+		}
+		return o.Data, o.Meta.NextCursor, nil
+	}
+	if err := cmd.FetchCursorPages(c.Limit, c.Input.Cursor, page, w.Write); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	return w.Close()
+}
@@ -508,6 +508,17 @@ func TestListSecretsCommand(t *testing.T) {
 			wantAPIInvoked: true,
 			wantOutput:     fstfmt.EncodeJSON(secrets),
 		},
+		{
+			args: fmt.Sprintf("list --store-id %s --max-results 2", storeID),
+			api: mock.API{
+				ListSecretsFn: func(i *fastly.ListSecretsInput) (*fastly.Secrets, error) {
+					// Every page has a next cursor, so only --max-results stops it.
+					return secrets, nil
+				},
+			},
+			wantAPIInvoked: true,
+			wantOutput:     fmt.Sprintf("Name     Digest\n%[1]s  %[2]x\n%[1]s  %[2]x\n", secretName, secretName),
+		},
 	}
 
 	for _, testcase := range scenarios {
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/fastly/go-fastly/v8/fastly"
+
+	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/spec"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/cli/pkg/undo"
+)
+
+// ApplyCommand reconciles a service version with a spec file.
+type ApplyCommand struct {
+	cmd.Base
+
+	autoClone      cmd.OptionalAutoClone
+	file           string
+	manifest       manifest.Data
+	serviceName    cmd.OptionalServiceNameID
+	serviceVersion cmd.OptionalServiceVersion
+}
+
+// NewApplyCommand returns a usable command registered under the parent.
+func NewApplyCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *ApplyCommand {
+	c := ApplyCommand{
+		Base: cmd.Base{
+			Globals: g,
+		},
+		manifest: m,
+	}
+	c.CmdClause = parent.Command("apply", "Make a Fastly service version match a TOML/JSON spec file. WARNING: dictionary items and ACL entries aren't versioned, so changes to those of an existing dictionary or ACL take effect immediately, including on the active version")
+
+	// Required.
+	c.CmdClause.Flag("file", "Path to a spec file describing the service version (.toml or .json)").Short('f').Required().StringVar(&c.file)
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagVersionName,
+		Description: cmd.FlagVersionDesc,
+		Dst:         &c.serviceVersion.Value,
+		Required:    true,
+	})
+
+	// Optional.
+	c.RegisterAutoCloneFlag(cmd.AutoCloneFlagOpts{
+		Action: c.autoClone.Set,
+		Dst:    &c.autoClone.Value,
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *ApplyCommand) Exec(_ io.Reader, out io.Writer) (err error) {
+	desired, err := spec.Read(c.file)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"File": c.file,
+		})
+		return err
+	}
+
+	// NOTE: The version is only cloned (when --autoclone is set) once there are
+	// changes to apply, as the API doesn't allow a version to be deleted. A
+	// clone is identical to its source, so the changes are found by comparing
+	// the spec with the source version.
+	serviceID, sourceVersion, err := cmd.ServiceDetails(cmd.ServiceDetailsOpts{
+		AllowActiveLocked:  true,
+		APIClient:          c.Globals.APIClient,
+		Manifest:           c.manifest,
+		Out:                out,
+		ServiceNameFlag:    c.serviceName,
+		ServiceVersionFlag: c.serviceVersion,
+		VerboseMode:        c.Globals.Flags.Verbose,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": errors.ServiceVersion(sourceVersion),
+		})
+		return err
+	}
+	if (sourceVersion.Active || sourceVersion.Locked) && !c.autoClone.Value {
+		err = errors.RemediationError{
+			Inner:       fmt.Errorf("service version %d is not editable", sourceVersion.Number),
+			Remediation: errors.AutoCloneRemediation,
+		}
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": sourceVersion.Number,
+		})
+		return err
+	}
+
+	var kinds []spec.Kind
+	for _, s := range desired.Sections() {
+		kinds = append(kinds, s.Kind)
+	}
+	current, err := spec.Fetch(c.Globals.APIClient, serviceID, sourceVersion.Number, kinds)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": sourceVersion.Number,
+		})
+		return err
+	}
+
+	changes := spec.Diff(current, desired)
+	if len(changes) == 0 {
+		text.Info(out, "Service %s version %d already matches the spec", serviceID, sourceVersion.Number)
+		return nil
+	}
+
+	serviceVersion, err := c.autoClone.Parse(sourceVersion, serviceID, c.Globals.Verbose(), out, c.Globals.APIClient)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": sourceVersion.Number,
+		})
+		return err
+	}
+	cloned := serviceVersion.Number != sourceVersion.Number
+
+	for _, ch := range changes {
+		if ch.Live() {
+			text.Warning(out, "The %s of %s '%s' aren't versioned, so changing them takes effect immediately, including on the active version.", ch.Kind.Nested, ch.Kind.Description, ch.Name)
+		}
+	}
+
+	// If any change fails, then the changes already made are reverted so the
+	// service version is left as it was found.
+	undoStack := undo.NewStack()
+	defer func() {
+		if err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Service ID":      serviceID,
+				"Service Version": serviceVersion.Number,
+			})
+			text.Warning(out, "Reverting the changes made to service %s version %d", serviceID, serviceVersion.Number)
+		}
+		undoStack.RunIfError(out, err)
+		if err != nil && cloned {
+			c.discardClone(out, serviceID, serviceVersion.Number, sourceVersion.Number)
+		}
+	}()
+
+	if err = spec.Apply(c.Globals.APIClient, serviceID, serviceVersion.Number, changes, undoStack); err != nil {
+		return err
+	}
+
+	for _, ch := range changes {
+		text.Output(out, "%s %s '%s'", appliedAction[ch.Action], ch.Kind.Description, ch.Name)
+	}
+	text.Success(out, "Applied %d change(s) to service %s version %d", len(changes), serviceID, serviceVersion.Number)
+	return nil
+}
+
+// discardClone marks the version cloned by --autoclone as unused, once the
+// changes made to it have been reverted.
+//
+// NOTE: The API doesn't allow a version to be deleted, so the comment of the
+// version is updated to explain it can be ignored.
+func (c *ApplyCommand) discardClone(out io.Writer, serviceID string, version, source int) {
+	comment := fmt.Sprintf("Unused clone of version %d (`fastly service apply` failed)", source)
+	_, err := c.Globals.APIClient.UpdateVersion(&fastly.UpdateVersionInput{
+		ServiceID:      serviceID,
+		ServiceVersion: version,
+		Comment:        &comment,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": version,
+		})
+	}
+	text.Info(out, "Version %d was cloned from version %d but is unchanged, and can be ignored (versions can't be deleted).", version, source)
+}
+
+// appliedAction describes each type of change once it has been made.
+var appliedAction = map[spec.Action]string{
+	spec.ActionCreate: "Created",
+	spec.ActionUpdate: "Updated",
+	spec.ActionDelete: "Deleted",
+}
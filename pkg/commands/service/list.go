@@ -17,6 +17,7 @@ import (
 type ListCommand struct {
 	cmd.Base
 	cmd.JSONOutput
+	cmd.Pagination
 
 	input fastly.ListServicesInput
 }
@@ -31,8 +32,12 @@ func NewListCommand(parent cmd.Registerer, g *global.Data) *ListCommand {
 	c.CmdClause = parent.Command("list", "List Fastly services")
 
 	// Optional.
+	c.RegisterFlagBool(c.AllFlag()) // --all
 	c.CmdClause.Flag("direction", "Direction in which to sort results").Default(cmd.PaginationDirection[0]).HintOptions(cmd.PaginationDirection...).EnumVar(&c.input.Direction, cmd.PaginationDirection...)
+	c.RegisterFlag(c.FieldsFlag())   // --fields
+	c.RegisterFlag(c.FilterFlag())   // --filter
 	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterFlagInt(c.LimitFlag()) // --limit
 	c.CmdClause.Flag("page", "Page number of data set to fetch").IntVar(&c.input.Page)
 	c.CmdClause.Flag("per-page", "Number of records per page").IntVar(&c.input.PerPage)
 	c.CmdClause.Flag("sort", "Field on which to sort").Default("created").StringVar(&c.input.Sort)
@@ -45,6 +50,9 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 		return fsterr.ErrInvalidVerboseJSONCombo
 	}
 
+	if c.Streaming() {
+		return c.stream(out)
+	}
 	paginator := c.Globals.APIClient.NewListServicesPaginator(&c.input)
 
 	var o []*fastly.Service
@@ -63,33 +71,84 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
-	if !c.Globals.Verbose() {
-		tw := text.NewTable(out)
-		tw.AddHeader("NAME", "ID", "TYPE", "ACTIVE VERSION", "LAST EDITED (UTC)")
-		for _, service := range o {
-			updatedAt := "n/a"
-			if service.UpdatedAt != nil {
-				updatedAt = service.UpdatedAt.UTC().Format(time.Format)
-			}
+	if c.Globals.Verbose() {
+		printVerbose(out, o)
+	} else {
+		printSummary(out, o)
+	}
+	return nil
+}
 
-			activeVersion := fmt.Sprint(service.ActiveVersion)
-			for _, v := range service.Versions {
-				if int(v.Number) == service.ActiveVersion && !v.Active {
-					activeVersion = "n/a"
-				}
-			}
+// stream prints the services a page at a time as they're fetched, fetching
+// the pages after the first concurrently.
+func (c *ListCommand) stream(out io.Writer) error {
+	input := c.input
+	if input.PerPage <= 0 {
+		input.PerPage = cmd.MaxPerPage
+	}
+	start := input.Page
+	if start <= 0 {
+		start = 1
+	}
 
-			tw.AddLine(service.Name, service.ID, service.Type, activeVersion, updatedAt)
+	w := cmd.NewPageWriter(out, &c.JSONOutput, func(ss []*fastly.Service) {
+		if c.Globals.Verbose() {
+			printVerbose(out, ss)
+		} else {
+			printSummary(out, ss)
+		}
+	})
+
+	first := func() ([]*fastly.Service, int, error) {
+		var (
+			ss        []*fastly.Service
+			err       error
+			paginator = c.Globals.APIClient.NewListServicesPaginator(&input)
+		)
+		if paginator.HasNext() {
+			ss, err = paginator.GetNext()
 		}
-		tw.Print()
-		return nil
+		return ss, paginator.Remaining(), err
+	}
+	page := func(n int) ([]*fastly.Service, error) {
+		i := input
+		i.Page = start + n
+		return c.Globals.APIClient.ListServices(&i)
+	}
+	if err := cmd.FetchPages(c.Limit, first, page, w.Write); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
 	}
+	return w.Close()
+}
+
+// printSummary displays the services in a table.
+func printSummary(out io.Writer, ss []*fastly.Service) {
+	tw := text.NewTable(out)
+	tw.AddHeader("NAME", "ID", "TYPE", "ACTIVE VERSION", "LAST EDITED (UTC)")
+	for _, service := range ss {
+		updatedAt := "n/a"
+		if service.UpdatedAt != nil {
+			updatedAt = service.UpdatedAt.UTC().Format(time.Format)
+		}
+
+		activeVersion := fmt.Sprint(service.ActiveVersion)
+		for _, v := range service.Versions {
+			if int(v.Number) == service.ActiveVersion && !v.Active {
+				activeVersion = "n/a"
+			}
+		}
 
-	for i, service := range o {
-		fmt.Fprintf(out, "Service %d/%d\n", i+1, len(o))
+		tw.AddLine(service.Name, service.ID, service.Type, activeVersion, updatedAt)
+	}
+	tw.Print()
+}
+
+// printVerbose displays the services in detail.
+func printVerbose(out io.Writer, ss []*fastly.Service) {
+	for i, service := range ss {
+		fmt.Fprintf(out, "Service %d/%d\n", i+1, len(ss))
 		text.PrintService(out, "\t", service)
 		fmt.Fprintln(out)
 	}
-
-	return nil
 }
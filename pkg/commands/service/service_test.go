@@ -128,6 +128,29 @@ func TestServiceList(t *testing.T) {
 			args:       args("service list --verbose"),
 			wantOutput: listServicesVerboseOutput,
 		},
+		// NOTE: With --all the pages after the first are fetched with
+		// ListServices, and the table is printed once every page has arrived.
+		{
+			api: mock.API{
+				NewListServicesPaginatorFn: func(i *fastly.ListServicesInput) fastly.PaginatorServices {
+					return &testutil.ServicesPaginator{MaxPages: 3}
+				},
+				ListServicesFn: func(i *fastly.ListServicesInput) ([]*fastly.Service, error) {
+					return []*fastly.Service{{ID: "456", Name: "Bar", Type: "wasm", ActiveVersion: 1}}, nil
+				},
+			},
+			args:       args("service list --all"),
+			wantOutput: listServicesAllOutput,
+		},
+		{
+			api: mock.API{
+				NewListServicesPaginatorFn: func(i *fastly.ListServicesInput) fastly.PaginatorServices {
+					return &testutil.ServicesPaginator{MaxPages: 3}
+				},
+			},
+			args:       args("service list --limit 1"),
+			wantOutput: listServicesShortOutputPageOne,
+		},
 	}
 	for testcaseIdx := range scenarios {
 		testcase := &scenarios[testcaseIdx]
@@ -412,6 +435,213 @@ func TestServiceDelete(t *testing.T) {
 	}
 }
 
+func TestServiceApply(t *testing.T) {
+	args := testutil.Args
+	scenarios := []struct {
+		args       []string
+		api        mock.API
+		wantError  string
+		wantOutput string
+	}{
+		{
+			args:      args("service apply --service-id 123 --version 3"),
+			wantError: "required flag --file not provided",
+		},
+		{
+			args:      args("service apply --service-id 123 --version 3 --file testdata/missing.toml"),
+			wantError: "error reading spec file",
+		},
+		{
+			args: args("service apply --service-id 123 --version 1 --file testdata/spec.toml"),
+			api: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+			},
+			wantError: "service version 1 is not editable",
+		},
+		{
+			args: args("service apply --service-id 123 --version 3 --file testdata/spec.toml"),
+			api: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				ListBackendsFn: listBackendsMatching,
+			},
+			wantOutput: "Service 123 version 3 already matches the spec",
+		},
+		{
+			args: args("service apply --service-id 123 --version 1 --autoclone --file testdata/spec.toml"),
+			api: mock.API{
+				ListVersionsFn:  testutil.ListVersions,
+				CloneVersionFn:  testutil.CloneVersionResult(4),
+				ListBackendsFn:  listBackendsDrifted,
+				CreateBackendFn: createBackendOK,
+				UpdateBackendFn: updateBackendOK,
+				DeleteBackendFn: deleteBackendOK,
+			},
+			wantOutput: "Updated backend 'origin'\nCreated backend 'static'\nDeleted backend 'legacy'\n\nSUCCESS: Applied 3 change(s) to service 123 version 4",
+		},
+		{
+			args: args("service apply --service-id 123 --version 3 --file testdata/spec.toml"),
+			api: mock.API{
+				ListVersionsFn:  testutil.ListVersions,
+				ListBackendsFn:  listBackendsDrifted,
+				CreateBackendFn: createBackendOK,
+				UpdateBackendFn: updateBackendOK,
+				DeleteBackendFn: deleteBackendError,
+			},
+			wantError:  "error attempting to delete backend 'legacy'",
+			wantOutput: "Reverting the changes made to service 123 version 3",
+		},
+		{
+			// NOTE: CloneVersionFn isn't set, as the version mustn't be cloned when
+			// there are no changes to apply.
+			args: args("service apply --service-id 123 --version 1 --autoclone --file testdata/spec.toml"),
+			api: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				ListBackendsFn: listBackendsMatching,
+			},
+			wantOutput: "Service 123 version 1 already matches the spec",
+		},
+		{
+			args: args("service apply --service-id 123 --version 1 --autoclone --file testdata/spec.toml"),
+			api: mock.API{
+				ListVersionsFn:  testutil.ListVersions,
+				CloneVersionFn:  testutil.CloneVersionResult(4),
+				ListBackendsFn:  listBackendsDrifted,
+				CreateBackendFn: createBackendOK,
+				UpdateBackendFn: updateBackendOK,
+				DeleteBackendFn: deleteBackendError,
+				UpdateVersionFn: func(i *fastly.UpdateVersionInput) (*fastly.Version, error) {
+					if i.ServiceVersion != 4 || i.Comment == nil || *i.Comment != "Unused clone of version 1 (`fastly service apply` failed)" {
+						return nil, testutil.Err
+					}
+					return &fastly.Version{ServiceID: i.ServiceID, Number: i.ServiceVersion, Comment: *i.Comment}, nil
+				},
+			},
+			wantError:  "error attempting to delete backend 'legacy'",
+			wantOutput: "Version 4 was cloned from version 1 but is unchanged, and can be ignored (versions can't be deleted).",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(strings.Join(testcase.args, " "), func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.api)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+		})
+	}
+}
+
+func TestServiceExport(t *testing.T) {
+	args := testutil.Args
+
+	withResources := exportAPI()
+	withResources.ListBackendsFn = listBackendsMatching
+	withResources.ListSyslogsFn = func(i *fastly.ListSyslogsInput) ([]*fastly.Syslog, error) {
+		return []*fastly.Syslog{
+			{ServiceID: i.ServiceID, ServiceVersion: i.ServiceVersion, Name: "audit", Address: "syslog.example.com", Token: "s3cr3t"},
+		}, nil
+	}
+	withResources.ListHTTPSFn = func(i *fastly.ListHTTPSInput) ([]*fastly.HTTPS, error) {
+		return []*fastly.HTTPS{
+			{ServiceID: i.ServiceID, ServiceVersion: i.ServiceVersion, Name: "collector", URL: "https://logs.example.com", HeaderName: "Authorization", HeaderValue: "Bearer s3cr3t"},
+		}, nil
+	}
+	withResources.ListSumologicsFn = func(i *fastly.ListSumologicsInput) ([]*fastly.Sumologic, error) {
+		return []*fastly.Sumologic{
+			{ServiceID: i.ServiceID, ServiceVersion: i.ServiceVersion, Name: "sumo", URL: "https://collectors.sumologic.com/receiver/v1/http/s3cr3t"},
+		}, nil
+	}
+	withResources.ListERLsFn = func(i *fastly.ListERLsInput) ([]*fastly.ERL, error) {
+		return []*fastly.ERL{
+			{ServiceID: i.ServiceID, Version: i.ServiceVersion, ID: "erl1", Name: "limit", RpsLimit: 100, Response: &fastly.ERLResponse{ERLStatus: 429}},
+		}, nil
+	}
+
+	listError := exportAPI()
+	listError.ListDomainsFn = func(*fastly.ListDomainsInput) ([]*fastly.Domain, error) {
+		return nil, errTest
+	}
+
+	scenarios := []struct {
+		args       []string
+		api        mock.API
+		wantError  string
+		wantOutput string
+		dontWant   string
+	}{
+		{
+			args:      args("service export --service-id 123"),
+			wantError: "required flag --version not provided",
+		},
+		{
+			args:      args("service export --service-id 123 --version 1 --format yaml"),
+			wantError: "enum value must be one of toml,json, got 'yaml'",
+		},
+		{
+			args:      args("service export --service-id 123 --version 1"),
+			api:       listError,
+			wantError: "error listing domain resources: " + errTest.Error(),
+		},
+		{
+			args:       args("service export --service-id 123 --version 1"),
+			api:        withResources,
+			wantOutput: "[[backends]]\n  address = \"origin.example.com\"\n  name = \"origin\"\n  port = 443\n",
+		},
+		{
+			args:       args("service export --service-id 123 --version 1"),
+			api:        withResources,
+			wantOutput: "[[logging.syslog]]\n    address = \"syslog.example.com\"\n    name = \"audit\"\n    token = \"<redacted>\"\n",
+			dontWant:   "s3cr3t",
+		},
+		{
+			args:       args("service export --service-id 123 --version 1"),
+			api:        withResources,
+			wantOutput: "[[logging.https]]\n    header_name = \"Authorization\"\n    header_value = \"<redacted>\"\n",
+			dontWant:   "Bearer",
+		},
+		{
+			args:       args("service export --service-id 123 --version 1"),
+			api:        withResources,
+			wantOutput: "[[logging.sumologic]]\n    name = \"sumo\"\n    url = \"<redacted>\"\n",
+			dontWant:   "receiver",
+		},
+		{
+			args:       args("service export --service-id 123 --version 1 --format json --include-secrets"),
+			api:        withResources,
+			wantOutput: `"token": "s3cr3t"`,
+		},
+		{
+			args: args("service export --service-id 123 --version 1 --format json"),
+			api:  withResources,
+			wantOutput: `"rate_limiters": [
+    {
+      "name": "limit",
+      "response": {
+        "status": 429
+      },
+      "rps_limit": 100
+    }
+  ]`,
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(strings.Join(testcase.args, " "), func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.api)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+			if testcase.dontWant != "" {
+				testutil.AssertStringDoesntContain(t, stdout.String(), testcase.dontWant)
+			}
+		})
+	}
+}
+
 var errTest = errors.New("fixture error")
 
 func createServiceOK(i *fastly.CreateServiceInput) (*fastly.Service, error) {
@@ -432,6 +662,12 @@ Bar   456  wasm  1               2015-03-14 12:59
 Baz   789  vcl   1               n/a
 `) + "\n"
 
+var listServicesAllOutput = strings.TrimSpace(`
+NAME  ID   TYPE  ACTIVE VERSION  LAST EDITED (UTC)
+Foo   123  wasm  2               2010-11-15 19:01
+Bar   456  wasm  1               n/a
+`) + "\n"
+
 var listServicesShortOutputPageOne = strings.TrimSpace(`
 NAME  ID   TYPE  ACTIVE VERSION  LAST EDITED (UTC)
 Foo   123  wasm  2               2010-11-15 19:01
@@ -752,3 +988,146 @@ func deleteServiceOK(*fastly.DeleteServiceInput) error {
 func deleteServiceError(*fastly.DeleteServiceInput) error {
 	return errTest
 }
+
+func listBackendsMatching(i *fastly.ListBackendsInput) ([]*fastly.Backend, error) {
+	return []*fastly.Backend{
+		{ServiceID: i.ServiceID, ServiceVersion: i.ServiceVersion, Name: "origin", Address: "origin.example.com", Port: 443},
+		{ServiceID: i.ServiceID, ServiceVersion: i.ServiceVersion, Name: "static", Address: "static.example.com", Port: 443},
+	}, nil
+}
+
+func listBackendsDrifted(i *fastly.ListBackendsInput) ([]*fastly.Backend, error) {
+	return []*fastly.Backend{
+		{ServiceID: i.ServiceID, ServiceVersion: i.ServiceVersion, Name: "origin", Address: "old.example.com", Port: 443},
+		{ServiceID: i.ServiceID, ServiceVersion: i.ServiceVersion, Name: "legacy", Address: "legacy.example.com", Port: 80},
+	}, nil
+}
+
+func createBackendOK(i *fastly.CreateBackendInput) (*fastly.Backend, error) {
+	return &fastly.Backend{ServiceID: i.ServiceID, ServiceVersion: i.ServiceVersion, Name: *i.Name}, nil
+}
+
+func updateBackendOK(i *fastly.UpdateBackendInput) (*fastly.Backend, error) {
+	return &fastly.Backend{ServiceID: i.ServiceID, ServiceVersion: i.ServiceVersion, Name: i.Name}, nil
+}
+
+func deleteBackendOK(*fastly.DeleteBackendInput) error {
+	return nil
+}
+
+func deleteBackendError(*fastly.DeleteBackendInput) error {
+	return errTest
+}
+
+// exportAPI returns a mock API where every resource listed by `service export`
+// is empty, so individual test cases only need to populate what they assert.
+func exportAPI() mock.API {
+	return mock.API{
+		ListVersionsFn: testutil.ListVersions,
+		ListDomainsFn: func(*fastly.ListDomainsInput) ([]*fastly.Domain, error) {
+			return nil, nil
+		},
+		ListHealthChecksFn: func(*fastly.ListHealthChecksInput) ([]*fastly.HealthCheck, error) {
+			return nil, nil
+		},
+		ListBackendsFn: func(*fastly.ListBackendsInput) ([]*fastly.Backend, error) {
+			return nil, nil
+		},
+		ListVCLsFn: func(*fastly.ListVCLsInput) ([]*fastly.VCL, error) {
+			return nil, nil
+		},
+		ListSnippetsFn: func(*fastly.ListSnippetsInput) ([]*fastly.Snippet, error) {
+			return nil, nil
+		},
+		ListDictionariesFn: func(*fastly.ListDictionariesInput) ([]*fastly.Dictionary, error) {
+			return nil, nil
+		},
+		ListACLsFn: func(*fastly.ListACLsInput) ([]*fastly.ACL, error) {
+			return nil, nil
+		},
+		ListBlobStoragesFn: func(*fastly.ListBlobStoragesInput) ([]*fastly.BlobStorage, error) {
+			return nil, nil
+		},
+		ListBigQueriesFn: func(*fastly.ListBigQueriesInput) ([]*fastly.BigQuery, error) {
+			return nil, nil
+		},
+		ListCloudfilesFn: func(*fastly.ListCloudfilesInput) ([]*fastly.Cloudfiles, error) {
+			return nil, nil
+		},
+		ListDatadogFn: func(*fastly.ListDatadogInput) ([]*fastly.Datadog, error) {
+			return nil, nil
+		},
+		ListDigitalOceansFn: func(*fastly.ListDigitalOceansInput) ([]*fastly.DigitalOcean, error) {
+			return nil, nil
+		},
+		ListElasticsearchFn: func(*fastly.ListElasticsearchInput) ([]*fastly.Elasticsearch, error) {
+			return nil, nil
+		},
+		ListFTPsFn: func(*fastly.ListFTPsInput) ([]*fastly.FTP, error) {
+			return nil, nil
+		},
+		ListGCSsFn: func(*fastly.ListGCSsInput) ([]*fastly.GCS, error) {
+			return nil, nil
+		},
+		ListPubsubsFn: func(*fastly.ListPubsubsInput) ([]*fastly.Pubsub, error) {
+			return nil, nil
+		},
+		ListHerokusFn: func(*fastly.ListHerokusInput) ([]*fastly.Heroku, error) {
+			return nil, nil
+		},
+		ListHoneycombsFn: func(*fastly.ListHoneycombsInput) ([]*fastly.Honeycomb, error) {
+			return nil, nil
+		},
+		ListHTTPSFn: func(*fastly.ListHTTPSInput) ([]*fastly.HTTPS, error) {
+			return nil, nil
+		},
+		ListKafkasFn: func(*fastly.ListKafkasInput) ([]*fastly.Kafka, error) {
+			return nil, nil
+		},
+		ListKinesisFn: func(*fastly.ListKinesisInput) ([]*fastly.Kinesis, error) {
+			return nil, nil
+		},
+		ListLogentriesFn: func(*fastly.ListLogentriesInput) ([]*fastly.Logentries, error) {
+			return nil, nil
+		},
+		ListLogglyFn: func(*fastly.ListLogglyInput) ([]*fastly.Loggly, error) {
+			return nil, nil
+		},
+		ListLogshuttlesFn: func(*fastly.ListLogshuttlesInput) ([]*fastly.Logshuttle, error) {
+			return nil, nil
+		},
+		ListNewRelicFn: func(*fastly.ListNewRelicInput) ([]*fastly.NewRelic, error) {
+			return nil, nil
+		},
+		ListNewRelicOTLPFn: func(*fastly.ListNewRelicOTLPInput) ([]*fastly.NewRelicOTLP, error) {
+			return nil, nil
+		},
+		ListOpenstacksFn: func(*fastly.ListOpenstackInput) ([]*fastly.Openstack, error) {
+			return nil, nil
+		},
+		ListPapertrailsFn: func(*fastly.ListPapertrailsInput) ([]*fastly.Papertrail, error) {
+			return nil, nil
+		},
+		ListS3sFn: func(*fastly.ListS3sInput) ([]*fastly.S3, error) {
+			return nil, nil
+		},
+		ListScalyrsFn: func(*fastly.ListScalyrsInput) ([]*fastly.Scalyr, error) {
+			return nil, nil
+		},
+		ListSFTPsFn: func(*fastly.ListSFTPsInput) ([]*fastly.SFTP, error) {
+			return nil, nil
+		},
+		ListSplunksFn: func(*fastly.ListSplunksInput) ([]*fastly.Splunk, error) {
+			return nil, nil
+		},
+		ListSumologicsFn: func(*fastly.ListSumologicsInput) ([]*fastly.Sumologic, error) {
+			return nil, nil
+		},
+		ListSyslogsFn: func(*fastly.ListSyslogsInput) ([]*fastly.Syslog, error) {
+			return nil, nil
+		},
+		ListERLsFn: func(*fastly.ListERLsInput) ([]*fastly.ERL, error) {
+			return nil, nil
+		},
+	}
+}
@@ -16,6 +16,7 @@ import (
 type ListCommand struct {
 	cmd.Base
 	cmd.JSONOutput
+	cmd.Pagination
 
 	input fastly.ListServiceAuthorizationsInput
 }
@@ -27,7 +28,11 @@ func NewListCommand(parent cmd.Registerer, g *global.Data) *ListCommand {
 	c.CmdClause = parent.Command("list", "List service authorizations")
 
 	// Optional.
+	c.RegisterFlagBool(c.AllFlag())  // --all
+	c.RegisterFlag(c.FieldsFlag())   // --fields
+	c.RegisterFlag(c.FilterFlag())   // --filter
 	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterFlagInt(c.LimitFlag()) // --limit
 	c.CmdClause.Flag("page", "Page number of data set to fetch").IntVar(&c.input.PageNumber)
 	c.CmdClause.Flag("per-page", "Number of records per page").IntVar(&c.input.PageSize)
 	return &c
@@ -39,6 +44,10 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 		return fsterr.ErrInvalidVerboseJSONCombo
 	}
 
+	if c.Streaming() {
+		return c.stream(out)
+	}
+
 	o, err := c.Globals.APIClient.ListServiceAuthorizations(&c.input)
 	if err != nil {
 		c.Globals.ErrLog.AddWithContext(err, map[string]any{
@@ -54,19 +63,75 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 
 	if !c.Globals.Verbose() {
 		if len(o.Items) > 0 {
-			tw := text.NewTable(out)
-			tw.AddHeader("AUTH ID", "USER ID", "SERVICE ID", "PERMISSION")
+			printSummary(out, o.Items)
+			return nil
+		}
+	}
+	printVerbose(out, o.Items)
+	return nil
+}
 
-			for _, s := range o.Items {
-				tw.AddLine(s.ID, s.User.ID, s.Service.ID, s.Permission)
-			}
-			tw.Print()
+// stream prints the service authorizations a page at a time as they're
+// fetched, fetching the pages after the first concurrently.
+func (c *ListCommand) stream(out io.Writer) error {
+	input := c.input
+	if input.PageSize <= 0 {
+		input.PageSize = cmd.MaxPerPage
+	}
+	if input.PageNumber <= 0 {
+		input.PageNumber = 1
+	}
 
-			return nil
+	w := cmd.NewPageWriter(out, &c.JSONOutput, func(sas []*fastly.ServiceAuthorization) {
+		if c.Globals.Verbose() {
+			printVerbose(out, sas)
+			return
+		}
+		printSummary(out, sas)
+	})
+
+	first := func() ([]*fastly.ServiceAuthorization, int, error) {
+		o, err := c.Globals.APIClient.ListServiceAuthorizations(&input)
+		if err != nil {
+			return nil, 0, err
+		}
+		remaining := o.Info.Meta.TotalPages - input.PageNumber
+		if remaining < 0 {
+			remaining = 0
 		}
+		return o.Items, remaining, nil
 	}
+	page := func(n int) ([]*fastly.ServiceAuthorization, error) {
+		i := input
+		i.PageNumber += n
+		o, err := c.Globals.APIClient.ListServiceAuthorizations(&i)
+		if err != nil {
+			return nil, err
+		}
+		return o.Items, nil
+	}
+	if err := cmd.FetchPages(c.Limit, first, page, w.Write); err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Page Size": input.PageSize,
+		})
+		return err
+	}
+	return w.Close()
+}
+
+// printSummary displays the service authorizations in a table.
+func printSummary(out io.Writer, sas []*fastly.ServiceAuthorization) {
+	tw := text.NewTable(out)
+	tw.AddHeader("AUTH ID", "USER ID", "SERVICE ID", "PERMISSION")
+	for _, s := range sas {
+		tw.AddLine(s.ID, s.User.ID, s.Service.ID, s.Permission)
+	}
+	tw.Print()
+}
 
-	for _, s := range o.Items {
+// printVerbose displays the service authorizations in detail.
+func printVerbose(out io.Writer, sas []*fastly.ServiceAuthorization) {
+	for _, s := range sas {
 		fmt.Fprintf(out, "Auth ID: %s\n", s.ID)
 		fmt.Fprintf(out, "User ID: %s\n", s.User.ID)
 		fmt.Fprintf(out, "Service ID: %s\n", s.Service.ID)
@@ -82,6 +147,4 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 			fmt.Fprintf(out, "Deleted (UTC): %s\n", s.DeletedAt.UTC().Format(time.Format))
 		}
 	}
-
-	return nil
 }
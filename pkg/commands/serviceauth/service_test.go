@@ -3,6 +3,7 @@ package serviceauth_test
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -100,6 +101,16 @@ func TestServiceAuthList(t *testing.T) {
 			api:        mock.API{ListServiceAuthorizationsFn: listServiceAuthOK},
 			wantOutput: "Fastly API token not provided\nFastly API endpoint: https://api.fastly.com\n\nAuth ID: 123\nUser ID: 456\nService ID: 789\nPermission: read_only\n",
 		},
+		{
+			args:       args("service-auth list --all"),
+			api:        mock.API{ListServiceAuthorizationsFn: listServiceAuthPages},
+			wantOutput: "AUTH ID  USER ID  SERVICE ID  PERMISSION\n1        456      789         read_only\n2        456      789         read_only\n",
+		},
+		{
+			args:       args("service-auth list --limit 1 --json"),
+			api:        mock.API{ListServiceAuthorizationsFn: listServiceAuthPages},
+			wantOutput: `"ID": "1"`,
+		},
 	}
 	for testcaseIdx := range scenarios {
 		testcase := &scenarios[testcaseIdx]
@@ -282,6 +293,27 @@ func listServiceAuthOK(i *fastly.ListServiceAuthorizationsInput) (*fastly.Servic
 	}, nil
 }
 
+// listServiceAuthPages returns two pages of one service authorization, with
+// the ID of each being its page number.
+func listServiceAuthPages(i *fastly.ListServiceAuthorizationsInput) (*fastly.ServiceAuthorizations, error) {
+	o := &fastly.ServiceAuthorizations{
+		Items: []*fastly.ServiceAuthorization{
+			{
+				ID: fmt.Sprint(i.PageNumber),
+				User: &fastly.SAUser{
+					ID: "456",
+				},
+				Service: &fastly.SAService{
+					ID: "789",
+				},
+				Permission: "read_only",
+			},
+		},
+	}
+	o.Info.Meta.TotalPages = 2
+	return o, nil
+}
+
 func describeServiceAuthError(*fastly.GetServiceAuthorizationInput) (*fastly.ServiceAuthorization, error) {
 	return nil, errTest
 }
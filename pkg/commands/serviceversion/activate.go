@@ -1,10 +1,14 @@
 package serviceversion
 
 import (
+	"fmt"
 	"io"
+	"time"
 
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/check"
 	"github.com/fastly/cli/pkg/cmd"
-	"github.com/fastly/cli/pkg/errors"
+	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/manifest"
 	"github.com/fastly/cli/pkg/text"
@@ -14,11 +18,19 @@ import (
 // ActivateCommand calls the Fastly API to activate a service version.
 type ActivateCommand struct {
 	cmd.Base
+	cmd.ServiceSelector
 	manifest       manifest.Data
 	Input          fastly.ActivateVersionInput
 	serviceName    cmd.OptionalServiceNameID
 	serviceVersion cmd.OptionalServiceVersion
 	autoClone      cmd.OptionalAutoClone
+
+	expectStatus     int
+	failureThreshold int
+	maxErrorRatio    float64
+	verifyInterval   time.Duration
+	verifyURL        string
+	watch            time.Duration
 }
 
 // NewActivateCommand returns a usable command registered under the parent.
@@ -39,6 +51,7 @@ func NewActivateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data)
 		Description: cmd.FlagServiceDesc,
 		Dst:         &c.serviceName.Value,
 	})
+	c.RegisterFlag(c.ServicesFlag()) // --services
 	c.RegisterFlag(cmd.StringFlagOpts{
 		Name:        cmd.FlagVersionName,
 		Description: cmd.FlagVersionDesc,
@@ -49,32 +62,114 @@ func NewActivateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data)
 		Action: c.autoClone.Set,
 		Dst:    &c.autoClone.Value,
 	})
+	c.CmdClause.Flag("expect-status", "The status code --verify-url is expected to respond with (defaults to any non-5xx status)").IntVar(&c.expectStatus)
+	c.CmdClause.Flag("failure-threshold", "Number of consecutive failed --verify-url checks that trigger a rollback").Default("3").IntVar(&c.failureThreshold)
+	c.CmdClause.Flag("max-error-ratio", "Roll back if the ratio of 5xx responses to requests (from realtime stats) exceeds this value during --watch (e.g. 0.05)").Float64Var(&c.maxErrorRatio)
+	c.CmdClause.Flag("verify-interval", "How often --verify-url is checked during --watch").Default("5s").DurationVar(&c.verifyInterval)
+	c.CmdClause.Flag("verify-url", "A URL to check once the version is activated, reactivating the previously active version if the checks fail").StringVar(&c.verifyURL)
+	c.CmdClause.Flag("watch", "How long to verify the version for after activation").Default("2m").DurationVar(&c.watch)
 	return &c
 }
 
 // Exec invokes the application logic for the command.
 func (c *ActivateCommand) Exec(_ io.Reader, out io.Writer) error {
+	if err := c.validateVerifyFlags(); err != nil {
+		return err
+	}
+	if !c.SelectingServices() {
+		return c.activate(c.manifest, c.serviceName, out)
+	}
+	if c.manifest.Flag.ServiceID != "" || c.serviceName.WasSet {
+		return fsterr.ErrInvalidServicesCombo
+	}
+	if c.verifyURL != "" {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid flag combination, --services and --verify-url"),
+			Remediation: "Verification checks a single URL, so activate and verify each service separately.",
+		}
+	}
+
+	services, err := cmd.SelectServices(c.Services, c.Globals.APIClient)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Services": c.Services,
+		})
+		return err
+	}
+	results := cmd.ForEachService(out, services, func(s cmd.SelectedService, out io.Writer) error {
+		m := c.manifest
+		m.Flag.ServiceID = s.ID
+		return c.activate(m, cmd.OptionalServiceNameID{}, out)
+	})
+	return cmd.PrintServiceSummary(out, results)
+}
+
+// validateVerifyFlags checks the flags controlling --verify-url are usable.
+func (c *ActivateCommand) validateVerifyFlags() error {
+	if c.verifyInterval <= 0 {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid --verify-interval: %s", c.verifyInterval),
+			Remediation: "Provide a positive duration (e.g. 5s).",
+		}
+	}
+	if c.failureThreshold < 1 {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid --failure-threshold: %d", c.failureThreshold),
+			Remediation: "Provide a number of consecutive failed checks of at least 1.",
+		}
+	}
+	if c.maxErrorRatio < 0 {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid --max-error-ratio: %g", c.maxErrorRatio),
+			Remediation: "Provide a ratio between 0 and 1 (e.g. 0.05).",
+		}
+	}
+	if c.maxErrorRatio != 0 && c.verifyURL == "" {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid flag combination, --max-error-ratio without --verify-url"),
+			Remediation: "The error ratio is only checked while verifying the version, so also set --verify-url.",
+		}
+	}
+	return nil
+}
+
+// activate activates the version of the service identified by the manifest
+// data or service name, verifying it if --verify-url is set.
+func (c *ActivateCommand) activate(m manifest.Data, serviceName cmd.OptionalServiceNameID, out io.Writer) error {
 	serviceID, serviceVersion, err := cmd.ServiceDetails(cmd.ServiceDetailsOpts{
 		AutoCloneFlag:      c.autoClone,
 		APIClient:          c.Globals.APIClient,
-		Manifest:           c.manifest,
+		Manifest:           m,
 		Out:                out,
-		ServiceNameFlag:    c.serviceName,
+		ServiceNameFlag:    serviceName,
 		ServiceVersionFlag: c.serviceVersion,
 		VerboseMode:        c.Globals.Flags.Verbose,
 	})
 	if err != nil {
 		c.Globals.ErrLog.AddWithContext(err, map[string]any{
 			"Service ID":      serviceID,
-			"Service Version": errors.ServiceVersion(serviceVersion),
+			"Service Version": fsterr.ServiceVersion(serviceVersion),
 		})
 		return err
 	}
 
-	c.Input.ServiceID = serviceID
-	c.Input.ServiceVersion = serviceVersion.Number
+	var previous *fastly.Version
+	if c.verifyURL != "" {
+		previous, err = activeVersion(c.Globals.APIClient, serviceID)
+		if err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Service ID": serviceID,
+			})
+			return err
+		}
+	}
+
+	input := c.Input
+	input.ServiceID = serviceID
+	input.ServiceVersion = serviceVersion.Number
 
-	ver, err := c.Globals.APIClient.ActivateVersion(&c.Input)
+	activated := time.Now()
+	ver, err := c.Globals.APIClient.ActivateVersion(&input)
 	if err != nil {
 		c.Globals.ErrLog.AddWithContext(err, map[string]any{
 			"Service ID":      serviceID,
@@ -83,6 +178,167 @@ func (c *ActivateCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
-	text.Success(out, "Activated service %s version %d", ver.ServiceID, c.Input.ServiceVersion)
+	text.Success(out, "Activated service %s version %d", ver.ServiceID, input.ServiceVersion)
+
+	if c.verifyURL == "" {
+		return nil
+	}
+
+	text.Break(out)
+	verifyErr := c.verify(serviceID, activated, out)
+	if verifyErr == nil {
+		text.Success(out, "Verified service %s version %d for %s", serviceID, input.ServiceVersion, c.watch)
+		return nil
+	}
+	c.Globals.ErrLog.AddWithContext(verifyErr, map[string]any{
+		"Service ID":      serviceID,
+		"Service Version": input.ServiceVersion,
+		"Verify URL":      c.verifyURL,
+	})
+	text.Warning(out, "Verification of service %s version %d failed: %s", serviceID, input.ServiceVersion, verifyErr)
+
+	if previous == nil || previous.Number == input.ServiceVersion {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("service %s version %d failed verification: %w", serviceID, input.ServiceVersion, verifyErr),
+			Remediation: "There was no previously active version to roll back to. Please investigate the service and activate a working version.",
+		}
+	}
+
+	_, err = c.Globals.APIClient.ActivateVersion(&fastly.ActivateVersionInput{
+		ServiceID:      serviceID,
+		ServiceVersion: previous.Number,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": previous.Number,
+		})
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("error reactivating service %s version %d: %w", serviceID, previous.Number, err),
+			Remediation: fmt.Sprintf("Service %s version %d failed verification and is still active. Please activate a working version manually (e.g. `fastly service-version activate --service-id %s --version %d`).", serviceID, input.ServiceVersion, serviceID, previous.Number),
+		}
+	}
+
+	text.Info(out, "Reactivated the previously active service %s version %d", serviceID, previous.Number)
+	return fmt.Errorf("service %s version %d failed verification and was rolled back to version %d: %w", serviceID, input.ServiceVersion, previous.Number, verifyErr)
+}
+
+// verify checks the activated version remains healthy for the watch window.
+//
+// The version must respond as expected, with fewer than --failure-threshold
+// consecutive failures, until the window elapses. Errors and unexpected
+// statuses while the new configuration propagates count as failures, rather
+// than failing the verification outright. When --max-error-ratio is set, the
+// realtime stats for the service are sampled at the same time.
+func (c *ActivateCommand) verify(serviceID string, activated time.Time, out io.Writer) error {
+	end := activated.Add(c.watch)
+	text.Info(out, "Verifying %s for %s...", c.verifyURL, c.watch)
+
+	var (
+		failures int
+		stats    errorRatio
+	)
+	ticker := time.NewTicker(c.verifyInterval)
+	defer ticker.Stop()
+
+	for time.Now().Before(end) {
+		<-ticker.C
+
+		ok, status, err := check.PingServiceURL(c.verifyURL, c.Globals.HTTPClient, c.expectStatus)
+		if err != nil || !ok || !c.expectedStatus(status) {
+			failures++
+			if c.Globals.Verbose() {
+				text.Output(out, "Check %d/%d of %s failed (status: %d, error: %v)", failures, c.failureThreshold, c.verifyURL, status, err)
+			}
+			if failures >= c.failureThreshold {
+				if err != nil {
+					return fmt.Errorf("%d consecutive checks of %s failed: %w", failures, c.verifyURL, err)
+				}
+				if ok && check.ValidStatusCode(c.expectStatus) {
+					return fmt.Errorf("%d consecutive checks of %s failed (last status: %d, expected %d)", failures, c.verifyURL, status, c.expectStatus)
+				}
+				return fmt.Errorf("%d consecutive checks of %s failed (last status: %d)", failures, c.verifyURL, status)
+			}
+		} else {
+			failures = 0
+		}
+
+		if c.maxErrorRatio > 0 {
+			if err := stats.sample(c.Globals.RTSClient, serviceID, activated); err != nil {
+				text.Warning(out, "Failed to sample realtime stats: %s", err)
+				continue
+			}
+			if ratio := stats.ratio(); ratio > c.maxErrorRatio {
+				return fmt.Errorf("error ratio %.4f exceeded --max-error-ratio %.4f (%d 5xx responses out of %d requests)", ratio, c.maxErrorRatio, stats.errors, stats.requests)
+			}
+		}
+	}
 	return nil
 }
+
+// expectedStatus reports whether the status matches --expect-status, if set.
+func (c *ActivateCommand) expectedStatus(status int) bool {
+	return !check.ValidStatusCode(c.expectStatus) || status == c.expectStatus
+}
+
+// activeVersion returns the currently active version of the service (nil if
+// no version is active).
+func activeVersion(client api.Interface, serviceID string) (*fastly.Version, error) {
+	vs, err := client.ListVersions(&fastly.ListVersionsInput{
+		ServiceID: serviceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing service versions: %w", err)
+	}
+	for _, v := range vs {
+		if v.Active {
+			return v, nil
+		}
+	}
+	return nil, nil
+}
+
+// errorRatio accumulates the realtime stats of a service since activation.
+type errorRatio struct {
+	errors    uint64
+	requests  uint64
+	timestamp uint64
+}
+
+// sample fetches the realtime stats recorded since the last sample.
+func (e *errorRatio) sample(client api.RealtimeStatsInterface, serviceID string, since time.Time) error {
+	var envelope struct {
+		Timestamp uint64 `json:"timestamp"`
+		Data      []struct {
+			Recorded   int64 `json:"recorded"`
+			Aggregated struct {
+				Requests  uint64 `json:"requests"`
+				Status5xx uint64 `json:"status_5xx"`
+			} `json:"aggregated"`
+		} `json:"data"`
+	}
+	err := client.GetRealtimeStatsJSON(&fastly.GetRealtimeStatsInput{
+		ServiceID: serviceID,
+		Timestamp: e.timestamp,
+	}, &envelope)
+	if err != nil {
+		return err
+	}
+	e.timestamp = envelope.Timestamp
+	for _, block := range envelope.Data {
+		if block.Recorded < since.Unix() {
+			continue
+		}
+		e.errors += block.Aggregated.Status5xx
+		e.requests += block.Aggregated.Requests
+	}
+	return nil
+}
+
+// ratio returns the ratio of 5xx responses to requests.
+func (e *errorRatio) ratio() float64 {
+	if e.requests == 0 {
+		return 0
+	}
+	return float64(e.errors) / float64(e.requests)
+}
@@ -0,0 +1,340 @@
+package serviceversion
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/spec"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// DiffCommand compares the configuration of two service versions.
+type DiffCommand struct {
+	cmd.Base
+	cmd.JSONOutput
+
+	exitCode    bool
+	from        cmd.OptionalServiceVersion
+	manifest    manifest.Data
+	serviceName cmd.OptionalServiceNameID
+	to          cmd.OptionalServiceVersion
+}
+
+// NewDiffCommand returns a usable command registered under the parent.
+func NewDiffCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *DiffCommand {
+	c := DiffCommand{
+		Base: cmd.Base{
+			Globals: g,
+		},
+		manifest: m,
+	}
+	c.CmdClause = parent.Command("diff", "Show the differences between two Fastly service versions")
+
+	// Required.
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        "from",
+		Description: "Number of the service version to compare from (or 'latest', 'active')",
+		Dst:         &c.from.Value,
+		Required:    true,
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        "to",
+		Description: "Number of the service version to compare to (or 'latest', 'active')",
+		Dst:         &c.to.Value,
+		Required:    true,
+	})
+
+	// Optional.
+	c.CmdClause.Flag("exit-code", "Exit with a non-zero status when the versions differ (e.g. to fail a CI job on drift)").BoolVar(&c.exitCode)
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *DiffCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	serviceID, from, err := cmd.ServiceDetails(cmd.ServiceDetailsOpts{
+		AllowActiveLocked:  true,
+		APIClient:          c.Globals.APIClient,
+		Manifest:           c.manifest,
+		Out:                out,
+		ServiceNameFlag:    c.serviceName,
+		ServiceVersionFlag: c.from,
+		VerboseMode:        c.Globals.Flags.Verbose,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": fsterr.ServiceVersion(from),
+		})
+		return err
+	}
+	to, err := c.to.Parse(serviceID, c.Globals.APIClient)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": fsterr.ServiceVersion(to),
+		})
+		return err
+	}
+
+	var specs [2]*spec.Spec
+	for i, v := range []int{from.Number, to.Number} {
+		specs[i], err = spec.Fetch(c.Globals.APIClient, serviceID, v, spec.AllKinds())
+		if err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Service ID":      serviceID,
+				"Service Version": v,
+			})
+			return err
+		}
+	}
+	changes := spec.Compare(specs[0], specs[1])
+
+	fromName, toName := fmt.Sprintf("version %d", from.Number), fmt.Sprintf("version %d", to.Number)
+
+	if c.JSONOutput.Enabled {
+		o := versionDiff{
+			ServiceID:   serviceID,
+			FromVersion: from.Number,
+			ToVersion:   to.Number,
+			Changes:     make([]resourceChange, 0, len(changes)),
+		}
+		for _, ch := range changes {
+			o.Changes = append(o.Changes, newResourceChange(ch, fromName, toName))
+		}
+		if _, err := c.WriteJSON(out, o); err != nil {
+			return err
+		}
+		return c.drift(serviceID, fromName, toName, changes)
+	}
+
+	if len(changes) == 0 {
+		text.Info(out, "No differences between service %s version %d and version %d", serviceID, from.Number, to.Number)
+		return nil
+	}
+
+	counts := make(map[spec.Action]int)
+	for _, ch := range changes {
+		counts[ch.Action]++
+		printChange(out, ch, fromName, toName)
+	}
+	text.Break(out)
+	text.Output(out, "Service %s %s => %s: %d added, %d removed, %d changed", serviceID, fromName, toName, counts[spec.ActionCreate], counts[spec.ActionDelete], counts[spec.ActionUpdate])
+	return c.drift(serviceID, fromName, toName, changes)
+}
+
+// drift returns an error when --exit-code is set and the versions differ, so
+// the command exits with a non-zero status.
+func (c *DiffCommand) drift(serviceID, fromName, toName string, changes []spec.Change) error {
+	if !c.exitCode || len(changes) == 0 {
+		return nil
+	}
+	return fmt.Errorf("service %s %s and %s differ (%d change(s))", serviceID, fromName, toName, len(changes))
+}
+
+// versionDiff is the JSON representation of the differences between two
+// service versions.
+type versionDiff struct {
+	ServiceID   string           `json:"service_id"`
+	FromVersion int              `json:"from_version"`
+	ToVersion   int              `json:"to_version"`
+	Changes     []resourceChange `json:"changes"`
+}
+
+// resourceChange is the JSON representation of a changed resource.
+type resourceChange struct {
+	Change string                 `json:"change"`
+	Kind   string                 `json:"kind"`
+	Name   string                 `json:"name"`
+	Fields map[string]fieldChange `json:"fields,omitempty"`
+}
+
+// fieldChange is the JSON representation of a changed field.
+//
+// NOTE: Multi-line values (e.g. VCL content) are represented as a unified diff.
+// Otherwise both values are always present, with an unset value being null.
+type fieldChange struct {
+	From any
+	To   any
+	Diff string
+}
+
+// MarshalJSON implements json.Marshaler.
+func (f fieldChange) MarshalJSON() ([]byte, error) {
+	if f.Diff != "" {
+		return json.Marshal(struct {
+			Diff string `json:"diff"`
+		}{f.Diff})
+	}
+	return json.Marshal(struct {
+		From any `json:"from"`
+		To   any `json:"to"`
+	}{f.From, f.To})
+}
+
+// changeNames are the names used to describe each type of change.
+var changeNames = map[spec.Action]string{
+	spec.ActionCreate: "added",
+	spec.ActionDelete: "removed",
+	spec.ActionUpdate: "changed",
+}
+
+// changeTitles describe each type of change in the text output.
+var changeTitles = map[spec.Action]string{
+	spec.ActionCreate: "Added",
+	spec.ActionDelete: "Removed",
+	spec.ActionUpdate: "Changed",
+}
+
+// changeSymbols prefix each type of change in the text output.
+var changeSymbols = map[spec.Action]string{
+	spec.ActionCreate: "+",
+	spec.ActionDelete: "-",
+	spec.ActionUpdate: "~",
+}
+
+// newResourceChange converts a change into its JSON representation.
+func newResourceChange(ch spec.Change, fromName, toName string) resourceChange {
+	rc := resourceChange{
+		Change: changeNames[ch.Action],
+		Kind:   ch.Kind.Name,
+		Name:   ch.Name,
+	}
+	if ch.Action != spec.ActionUpdate {
+		return rc
+	}
+	rc.Fields = make(map[string]fieldChange)
+	for _, f := range ch.Fields {
+		from, to := displayValue(ch.Kind, f, ch.From[f]), displayValue(ch.Kind, f, ch.To[f])
+		if multiline(from) || multiline(to) {
+			rc.Fields[f] = fieldChange{Diff: text.UnifiedDiff(fromName, toName, content(from), content(to))}
+			continue
+		}
+		rc.Fields[f] = fieldChange{From: from, To: to}
+	}
+	return rc
+}
+
+// printChange writes a human readable description of the change.
+func printChange(out io.Writer, ch spec.Change, fromName, toName string) {
+	fmt.Fprintf(out, "%s %s %s '%s'\n", changeSymbols[ch.Action], changeTitles[ch.Action], ch.Kind.Description, ch.Name)
+
+	for _, f := range ch.Fields {
+		if f == ch.Kind.Nested {
+			fmt.Fprintf(out, "    %s:\n", f)
+			for _, line := range nestedChanges(ch.Kind, ch.From, ch.To) {
+				fmt.Fprintf(out, "      %s\n", line)
+			}
+			continue
+		}
+
+		from, to := displayValue(ch.Kind, f, ch.From[f]), displayValue(ch.Kind, f, ch.To[f])
+		if multiline(from) || multiline(to) {
+			fmt.Fprintf(out, "    %s:\n", f)
+			diff := text.UnifiedDiff(fromName, toName, content(from), content(to))
+			for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+				fmt.Fprintf(out, "      %s\n", line)
+			}
+			continue
+		}
+		fmt.Fprintf(out, "    %s: %s => %s\n", f, quote(from), quote(to))
+	}
+}
+
+// nestedChanges describes the differences between the nested content (e.g.
+// dictionary items) of two versions of a resource.
+func nestedChanges(k spec.Kind, from, to spec.Resource) []string {
+	var a, b map[string]string
+	switch k.Name {
+	case spec.KindDictionary:
+		a, b = spec.Items(from), spec.Items(to)
+	case spec.KindACL:
+		a, b = aclEntries(from), aclEntries(to)
+	}
+
+	var lines []string
+	for key, v := range b {
+		if av, ok := a[key]; !ok {
+			lines = append(lines, fmt.Sprintf("+ %s: %s", key, quote(v)))
+		} else if av != v {
+			lines = append(lines, fmt.Sprintf("~ %s: %s => %s", key, quote(av), quote(v)))
+		}
+	}
+	for key, v := range a {
+		if _, ok := b[key]; !ok {
+			lines = append(lines, fmt.Sprintf("- %s: %s", key, quote(v)))
+		}
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		return lines[i][2:] < lines[j][2:]
+	})
+	return lines
+}
+
+// aclEntries returns a description of each ACL entry keyed by IP/subnet.
+func aclEntries(r spec.Resource) map[string]string {
+	m := make(map[string]string)
+	entries, _ := spec.Entries(r)
+	for key, e := range entries {
+		m[key] = fmt.Sprintf("negated=%t comment=%q", e.Negated, e.Comment)
+	}
+	return m
+}
+
+// displayValue returns the value of a field as it should be displayed, with
+// credentials redacted.
+func displayValue(k spec.Kind, field string, v any) any {
+	if v != nil && k.Secrets[field] {
+		return spec.Redacted
+	}
+	return v
+}
+
+// multiline reports whether the value is text spanning multiple lines (e.g.
+// VCL or snippet content).
+func multiline(v any) bool {
+	s, ok := v.(string)
+	return ok && strings.Contains(s, "\n")
+}
+
+// content returns the text of a multi-line value.
+func content(v any) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// quote formats a value for display, representing unset values as `-`.
+func quote(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "-"
+	case string:
+		return fmt.Sprintf("%q", v)
+	}
+	return fmt.Sprint(v)
+}
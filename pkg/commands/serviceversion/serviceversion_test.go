@@ -2,8 +2,14 @@ package serviceversion_test
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/fastly/cli/pkg/app"
 	"github.com/fastly/cli/pkg/mock"
@@ -209,6 +215,115 @@ func TestVersionActivate(t *testing.T) {
 	}
 }
 
+func TestVersionActivateVerify(t *testing.T) {
+	args := testutil.Args
+
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer healthy.Close()
+	notFound := httptest.NewServer(http.NotFoundHandler())
+	defer notFound.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	// propagating fails its first two checks, as a service does while its new
+	// configuration propagates: the connection is reset, then a 404 is returned.
+	var checks int32
+	propagating := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		switch atomic.AddInt32(&checks, 1) {
+		case 1:
+			if conn, _, err := w.(http.Hijacker).Hijack(); err == nil {
+				_ = conn.Close()
+			}
+		case 2:
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer propagating.Close()
+
+	var activated []int
+	api := mock.API{
+		ListVersionsFn: testutil.ListVersions,
+		ActivateVersionFn: func(i *fastly.ActivateVersionInput) (*fastly.Version, error) {
+			activated = append(activated, i.ServiceVersion)
+			return activateVersionOK(i)
+		},
+	}
+	erroring := mock.RealtimeStats{
+		GetRealtimeStatsJSONFn: func(_ *fastly.GetRealtimeStatsInput, dst any) error {
+			data := fmt.Sprintf(`{"timestamp": 1, "data": [{"recorded": %d, "aggregated": {"requests": 10, "status_5xx": 5}}]}`, time.Now().Unix())
+			return json.Unmarshal([]byte(data), dst)
+		},
+	}
+
+	scenarios := []struct {
+		args          []string
+		rts           mock.RealtimeStats
+		wantError     string
+		wantOutput    string
+		wantActivated []int
+	}{
+		{
+			args:      args("service-version activate --service-id 123 --version 3 --verify-url " + healthy.URL + " --verify-interval 0s"),
+			wantError: "invalid --verify-interval: 0s",
+		},
+		{
+			args:      args("service-version activate --service-id 123 --version 3 --verify-url " + healthy.URL + " --failure-threshold 0"),
+			wantError: "invalid --failure-threshold: 0",
+		},
+		{
+			args:      args("service-version activate --service-id 123 --version 3 --max-error-ratio 0.1"),
+			wantError: "invalid flag combination, --max-error-ratio without --verify-url",
+		},
+		{
+			args:          args("service-version activate --service-id 123 --version 3 --verify-url " + healthy.URL + " --watch 200ms --verify-interval 20ms"),
+			wantOutput:    "Verified service 123 version 3 for 200ms",
+			wantActivated: []int{3},
+		},
+		{
+			args:          args("service-version activate --service-id 123 --version 3 --verify-url " + failing.URL + " --watch 200ms --verify-interval 20ms"),
+			wantError:     "service 123 version 3 failed verification and was rolled back to version 1",
+			wantOutput:    "Reactivated the previously active service 123 version 1",
+			wantActivated: []int{3, 1},
+		},
+		{
+			args:          args("service-version activate --service-id 123 --version 3 --verify-url " + notFound.URL + " --expect-status 200 --watch 200ms --verify-interval 20ms"),
+			wantError:     "3 consecutive checks of " + notFound.URL + " failed (last status: 404, expected 200)",
+			wantActivated: []int{3, 1},
+		},
+		{
+			args:          args("service-version activate --service-id 123 --version 3 --verify-url " + propagating.URL + " --expect-status 200 --watch 200ms --verify-interval 20ms"),
+			wantOutput:    "Verified service 123 version 3 for 200ms",
+			wantActivated: []int{3},
+		},
+		{
+			args:          args("service-version activate --service-id 123 --version 3 --verify-url " + healthy.URL + " --max-error-ratio 0.1 --watch 1s --verify-interval 20ms"),
+			rts:           erroring,
+			wantError:     "error ratio 0.5000 exceeded --max-error-ratio 0.1000 (5 5xx responses out of 10 requests)",
+			wantActivated: []int{3, 1},
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(strings.Join(testcase.args, " "), func(t *testing.T) {
+			activated = nil
+
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.args, &stdout)
+			opts.APIClient = mock.APIClient(api)
+			opts.RTSClient = mock.RTSClient(testcase.rts)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+			testutil.AssertEqual(t, testcase.wantActivated, activated)
+		})
+	}
+}
+
 func TestVersionDeactivate(t *testing.T) {
 	args := testutil.Args
 	scenarios := []struct {
@@ -344,6 +459,115 @@ Versions: 3
 		Last edited (UTC): 2000-01-03 01:00
 `) + "\n\n"
 
+func TestVersionDiff(t *testing.T) {
+	args := testutil.Args
+
+	api := diffAPI()
+	api.ListBackendsFn = func(i *fastly.ListBackendsInput) ([]*fastly.Backend, error) {
+		bs := []*fastly.Backend{
+			{ServiceID: i.ServiceID, ServiceVersion: i.ServiceVersion, Name: "origin", Address: "old.example.com", Port: 443},
+			{ServiceID: i.ServiceID, ServiceVersion: i.ServiceVersion, Name: "legacy", Address: "legacy.example.com"},
+		}
+		if i.ServiceVersion == 3 {
+			bs[0].Address = "new.example.com"
+			bs[0].Port = 0
+			bs[1].Name = "static"
+		}
+		return bs, nil
+	}
+	api.ListVCLsFn = func(i *fastly.ListVCLsInput) ([]*fastly.VCL, error) {
+		content := "sub vcl_recv {\n  set req.http.X = \"1\";\n}\n"
+		if i.ServiceVersion == 3 {
+			content = "sub vcl_recv {\n  set req.http.X = \"2\";\n}\n"
+		}
+		return []*fastly.VCL{{ServiceID: i.ServiceID, ServiceVersion: i.ServiceVersion, Name: "main", Main: true, Content: content}}, nil
+	}
+
+	scenarios := []struct {
+		args       []string
+		api        mock.API
+		wantError  string
+		wantOutput string
+	}{
+		{
+			args:      args("service-version diff --service-id 123 --to 3"),
+			wantError: "error parsing arguments: required flag --from not provided",
+		},
+		{
+			args:       args("service-version diff --service-id 123 --from 1 --to 1"),
+			api:        api,
+			wantOutput: "No differences between service 123 version 1 and version 1",
+		},
+		{
+			args: args("service-version diff --service-id 123 --from 1 --to 3"),
+			api:  api,
+			wantOutput: `~ Changed backend 'origin'
+    address: "old.example.com" => "new.example.com"
+    port: 443 => -
++ Added backend 'static'
+~ Changed VCL 'main'
+    content:
+      --- version 1
+      +++ version 3
+      @@ -1,3 +1,3 @@
+       sub vcl_recv {
+      -  set req.http.X = "1";
+      +  set req.http.X = "2";
+       }
+- Removed backend 'legacy'
+
+Service 123 version 1 => version 3: 1 added, 1 removed, 2 changed`,
+		},
+		{
+			args:       args("service-version diff --service-id 123 --from 1 --to 3 --json"),
+			api:        api,
+			wantOutput: `"change": "removed",`,
+		},
+		{
+			args: args("service-version diff --service-id 123 --from 1 --to 3 --json"),
+			api:  api,
+			wantOutput: `"fields": {
+        "address": {
+          "from": "old.example.com",
+          "to": "new.example.com"
+        },
+        "port": {
+          "from": 443,
+          "to": null
+        }
+      }`,
+		},
+		{
+			args:       args("service-version diff --service-id 123 --from 1 --to 1 --exit-code"),
+			api:        api,
+			wantOutput: "No differences between service 123 version 1 and version 1",
+		},
+		{
+			args:       args("service-version diff --service-id 123 --from 1 --to 3 --exit-code"),
+			api:        api,
+			wantError:  "service 123 version 1 and version 3 differ (4 change(s))",
+			wantOutput: "1 added, 1 removed, 2 changed",
+		},
+		{
+			args:       args("service-version diff --service-id 123 --from 1 --to 3 --json --exit-code"),
+			api:        api,
+			wantError:  "service 123 version 1 and version 3 differ",
+			wantOutput: `"change": "removed",`,
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(strings.Join(testcase.args, " "), func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.api)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+		})
+	}
+}
+
 func updateVersionOK(i *fastly.UpdateVersionInput) (*fastly.Version, error) {
 	return &fastly.Version{
 		Number:    i.ServiceVersion,
@@ -404,3 +628,117 @@ func lockVersionOK(i *fastly.LockVersionInput) (*fastly.Version, error) {
 func lockVersionError(i *fastly.LockVersionInput) (*fastly.Version, error) {
 	return nil, testutil.Err
 }
+
+// diffAPI returns a mock API where every resource compared by
+// `service-version diff` is empty, so individual test cases only need to
+// populate what they assert.
+func diffAPI() mock.API {
+	return mock.API{
+		ListVersionsFn: testutil.ListVersions,
+		ListDomainsFn: func(*fastly.ListDomainsInput) ([]*fastly.Domain, error) {
+			return nil, nil
+		},
+		ListHealthChecksFn: func(*fastly.ListHealthChecksInput) ([]*fastly.HealthCheck, error) {
+			return nil, nil
+		},
+		ListBackendsFn: func(*fastly.ListBackendsInput) ([]*fastly.Backend, error) {
+			return nil, nil
+		},
+		ListVCLsFn: func(*fastly.ListVCLsInput) ([]*fastly.VCL, error) {
+			return nil, nil
+		},
+		ListSnippetsFn: func(*fastly.ListSnippetsInput) ([]*fastly.Snippet, error) {
+			return nil, nil
+		},
+		ListDictionariesFn: func(*fastly.ListDictionariesInput) ([]*fastly.Dictionary, error) {
+			return nil, nil
+		},
+		ListACLsFn: func(*fastly.ListACLsInput) ([]*fastly.ACL, error) {
+			return nil, nil
+		},
+		ListBlobStoragesFn: func(*fastly.ListBlobStoragesInput) ([]*fastly.BlobStorage, error) {
+			return nil, nil
+		},
+		ListBigQueriesFn: func(*fastly.ListBigQueriesInput) ([]*fastly.BigQuery, error) {
+			return nil, nil
+		},
+		ListCloudfilesFn: func(*fastly.ListCloudfilesInput) ([]*fastly.Cloudfiles, error) {
+			return nil, nil
+		},
+		ListDatadogFn: func(*fastly.ListDatadogInput) ([]*fastly.Datadog, error) {
+			return nil, nil
+		},
+		ListDigitalOceansFn: func(*fastly.ListDigitalOceansInput) ([]*fastly.DigitalOcean, error) {
+			return nil, nil
+		},
+		ListElasticsearchFn: func(*fastly.ListElasticsearchInput) ([]*fastly.Elasticsearch, error) {
+			return nil, nil
+		},
+		ListFTPsFn: func(*fastly.ListFTPsInput) ([]*fastly.FTP, error) {
+			return nil, nil
+		},
+		ListGCSsFn: func(*fastly.ListGCSsInput) ([]*fastly.GCS, error) {
+			return nil, nil
+		},
+		ListPubsubsFn: func(*fastly.ListPubsubsInput) ([]*fastly.Pubsub, error) {
+			return nil, nil
+		},
+		ListHerokusFn: func(*fastly.ListHerokusInput) ([]*fastly.Heroku, error) {
+			return nil, nil
+		},
+		ListHoneycombsFn: func(*fastly.ListHoneycombsInput) ([]*fastly.Honeycomb, error) {
+			return nil, nil
+		},
+		ListHTTPSFn: func(*fastly.ListHTTPSInput) ([]*fastly.HTTPS, error) {
+			return nil, nil
+		},
+		ListKafkasFn: func(*fastly.ListKafkasInput) ([]*fastly.Kafka, error) {
+			return nil, nil
+		},
+		ListKinesisFn: func(*fastly.ListKinesisInput) ([]*fastly.Kinesis, error) {
+			return nil, nil
+		},
+		ListLogentriesFn: func(*fastly.ListLogentriesInput) ([]*fastly.Logentries, error) {
+			return nil, nil
+		},
+		ListLogglyFn: func(*fastly.ListLogglyInput) ([]*fastly.Loggly, error) {
+			return nil, nil
+		},
+		ListLogshuttlesFn: func(*fastly.ListLogshuttlesInput) ([]*fastly.Logshuttle, error) {
+			return nil, nil
+		},
+		ListNewRelicFn: func(*fastly.ListNewRelicInput) ([]*fastly.NewRelic, error) {
+			return nil, nil
+		},
+		ListNewRelicOTLPFn: func(*fastly.ListNewRelicOTLPInput) ([]*fastly.NewRelicOTLP, error) {
+			return nil, nil
+		},
+		ListOpenstacksFn: func(*fastly.ListOpenstackInput) ([]*fastly.Openstack, error) {
+			return nil, nil
+		},
+		ListPapertrailsFn: func(*fastly.ListPapertrailsInput) ([]*fastly.Papertrail, error) {
+			return nil, nil
+		},
+		ListS3sFn: func(*fastly.ListS3sInput) ([]*fastly.S3, error) {
+			return nil, nil
+		},
+		ListScalyrsFn: func(*fastly.ListScalyrsInput) ([]*fastly.Scalyr, error) {
+			return nil, nil
+		},
+		ListSFTPsFn: func(*fastly.ListSFTPsInput) ([]*fastly.SFTP, error) {
+			return nil, nil
+		},
+		ListSplunksFn: func(*fastly.ListSplunksInput) ([]*fastly.Splunk, error) {
+			return nil, nil
+		},
+		ListSumologicsFn: func(*fastly.ListSumologicsInput) ([]*fastly.Sumologic, error) {
+			return nil, nil
+		},
+		ListSyslogsFn: func(*fastly.ListSyslogsInput) ([]*fastly.Syslog, error) {
+			return nil, nil
+		},
+		ListERLsFn: func(*fastly.ListERLsInput) ([]*fastly.ERL, error) {
+			return nil, nil
+		},
+	}
+}
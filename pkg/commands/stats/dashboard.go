@@ -0,0 +1,506 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fastly/go-fastly/v8/fastly"
+	"github.com/mitchellh/mapstructure"
+	"golang.org/x/term"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// The escape sequences used to draw the dashboard.
+const (
+	escEnterScreen = "\x1b[?1049h\x1b[?25l" // switch to the alternate screen, hide the cursor
+	escLeaveScreen = "\x1b[?25h\x1b[?1049l" // show the cursor, switch back to the main screen
+	escHome        = "\x1b[H"               // move the cursor to the top left
+	escClearLine   = "\x1b[K"               // clear the rest of the line
+	escClearScreen = "\x1b[J"               // clear the rest of the screen
+)
+
+// The layout of a service panel.
+const (
+	dashboardLabelWidth    = 13
+	dashboardValueWidth    = 12
+	dashboardMinPanelWidth = 36
+	dashboardPanelGap      = " │ "
+	dashboardMaxPOPs       = 8
+)
+
+// realtimeRetryInterval is how long to wait before fetching the stats of a
+// service again after an error.
+const realtimeRetryInterval = time.Second
+
+// sparkTicks are the characters a sparkline is drawn with, from lowest to
+// highest.
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// missLatencyBuckets are the upper bounds, in milliseconds, of the buckets the
+// miss histogram is displayed with. Slower misses are counted in a final
+// bucket.
+var missLatencyBuckets = []int{10, 20, 50, 100, 200, 500, 1000, 2000, 5000}
+
+// dashboardSample is a second of realtime stats.
+type dashboardSample struct {
+	requests  uint64
+	hits      uint64
+	miss      uint64
+	status2xx uint64
+	status4xx uint64
+	status5xx uint64
+	bytes     uint64
+	// missHistogram counts misses by their time to origin, keyed by the upper
+	// bound of the bucket in milliseconds.
+	missHistogram map[int]int
+}
+
+// decodeStats decodes a block of realtime stats, except for the miss
+// histogram.
+func decodeStats(data statsResponseData) (fastly.Stats, error) {
+	// NOTE: The keys of the miss histogram are strings in the JSON response,
+	// which mapstructure can't decode into fastly.Stats.
+	block := make(statsResponseData, len(data))
+	for k, v := range data {
+		if k != "miss_histogram" {
+			block[k] = v
+		}
+	}
+	var stats fastly.Stats
+	err := mapstructure.Decode(block, &stats)
+	return stats, err
+}
+
+// newDashboardSample decodes a second of realtime stats.
+func newDashboardSample(data statsResponseData) (dashboardSample, error) {
+	stats, err := decodeStats(data)
+	if err != nil {
+		return dashboardSample{}, err
+	}
+
+	s := dashboardSample{
+		requests:      stats.Requests,
+		hits:          stats.Hits,
+		miss:          stats.Miss,
+		status2xx:     stats.Status2xx,
+		status4xx:     stats.Status4xx,
+		status5xx:     stats.Status5xx,
+		bytes:         stats.ResponseHeaderBytes + stats.ResponseBodyBytes,
+		missHistogram: make(map[int]int),
+	}
+	if hist, ok := data["miss_histogram"].(map[string]any); ok {
+		for k, v := range hist {
+			ms, err := strconv.Atoi(k)
+			if err != nil {
+				return s, fmt.Errorf("invalid miss histogram bucket '%s': %w", k, err)
+			}
+			if n, ok := v.(float64); ok {
+				s.missHistogram[ms] += int(n)
+			}
+		}
+	}
+	return s, nil
+}
+
+// hitRatio returns the ratio of cache lookups that were hits.
+func (s dashboardSample) hitRatio() float64 {
+	if s.hits+s.miss == 0 {
+		return 0
+	}
+	return float64(s.hits) / float64(s.hits+s.miss)
+}
+
+// add returns the sum of both samples.
+func (s dashboardSample) add(o dashboardSample) dashboardSample {
+	sum := dashboardSample{
+		requests:      s.requests + o.requests,
+		hits:          s.hits + o.hits,
+		miss:          s.miss + o.miss,
+		status2xx:     s.status2xx + o.status2xx,
+		status4xx:     s.status4xx + o.status4xx,
+		status5xx:     s.status5xx + o.status5xx,
+		bytes:         s.bytes + o.bytes,
+		missHistogram: make(map[int]int),
+	}
+	for _, h := range []map[int]int{s.missHistogram, o.missHistogram} {
+		for ms, n := range h {
+			sum.missHistogram[ms] += n
+		}
+	}
+	return sum
+}
+
+// serviceDashboard holds the rolling window of stats displayed for a service.
+type serviceDashboard struct {
+	service cmd.SelectedService
+	// window is the number of seconds displayed.
+	window int
+	// samples are the stats of each second, oldest first.
+	samples []dashboardSample
+	// pops are the stats of each POP, for each of the samples.
+	pops []map[string]dashboardSample
+	// err is the last error fetching or decoding stats, cleared on success.
+	err error
+}
+
+// add appends the realtime stats to the window, dropping the oldest seconds.
+func (d *serviceDashboard) add(blocks []realtimeResponseData) error {
+	for _, block := range blocks {
+		sample, err := newDashboardSample(block.Aggregated)
+		if err != nil {
+			return err
+		}
+		pops := make(map[string]dashboardSample, len(block.Datacenter))
+		for pop, data := range block.Datacenter {
+			s, err := newDashboardSample(data)
+			if err != nil {
+				return err
+			}
+			pops[pop] = s
+		}
+		d.samples = append(d.samples, sample)
+		d.pops = append(d.pops, pops)
+	}
+	if n := len(d.samples) - d.window; n > 0 {
+		d.samples = d.samples[n:]
+		d.pops = d.pops[n:]
+	}
+	return nil
+}
+
+// series returns a value of each sample in the window.
+func (d *serviceDashboard) series(value func(dashboardSample) float64) []float64 {
+	values := make([]float64, len(d.samples))
+	for i, s := range d.samples {
+		values[i] = value(s)
+	}
+	return values
+}
+
+// render returns the lines of the service's panel, each padded to width.
+func (d *serviceDashboard) render(width int) []string {
+	lines := []string{
+		text.Bold(fit(d.service.String(), width)),
+		fit(strings.Repeat("─", width), width),
+	}
+	if d.err != nil {
+		lines = append(lines, fit("Error: "+d.err.Error(), width))
+	}
+	if len(d.samples) == 0 {
+		return append(lines, fit("Waiting for stats...", width))
+	}
+
+	last := d.samples[len(d.samples)-1]
+	sparkWidth := width - dashboardLabelWidth - dashboardValueWidth
+	cells := func(label, value, spark string) string {
+		return fit(fmt.Sprintf("%-*s%*s %s", dashboardLabelWidth, label, dashboardValueWidth-1, value, spark), width)
+	}
+	row := func(label, value string, series []float64) string {
+		return cells(label, value, sparkline(series, sparkWidth))
+	}
+	count := func(v func(dashboardSample) uint64) func(dashboardSample) float64 {
+		return func(s dashboardSample) float64 { return float64(v(s)) }
+	}
+
+	lines = append(lines,
+		row("Requests/s", strconv.FormatUint(last.requests, 10), d.series(count(func(s dashboardSample) uint64 { return s.requests }))),
+		row("Hit ratio", fmt.Sprintf("%.1f%%", last.hitRatio()*100), d.series(dashboardSample.hitRatio)),
+		row("2xx/s", strconv.FormatUint(last.status2xx, 10), d.series(count(func(s dashboardSample) uint64 { return s.status2xx }))),
+		row("4xx/s", strconv.FormatUint(last.status4xx, 10), d.series(count(func(s dashboardSample) uint64 { return s.status4xx }))),
+		row("5xx/s", strconv.FormatUint(last.status5xx, 10), d.series(count(func(s dashboardSample) uint64 { return s.status5xx }))),
+		row("Bandwidth", formatBytes(float64(last.bytes))+"/s", d.series(count(func(s dashboardSample) uint64 { return s.bytes }))),
+	)
+
+	var total dashboardSample
+	for _, s := range d.samples {
+		total = total.add(s)
+	}
+	// NOTE: The miss histogram is drawn over the latency buckets rather than
+	// over time, with the fastest misses on the left.
+	buckets := missLatency(total.missHistogram)
+	lines = append(lines,
+		cells("Miss latency", "p50 "+formatLatency(percentile(total.missHistogram, 0.5)), sparkline(buckets, len(buckets))),
+		cells("", "p95 "+formatLatency(percentile(total.missHistogram, 0.95)),
+			fmt.Sprintf("%dms…%ds+", missLatencyBuckets[0], missLatencyBuckets[len(missLatencyBuckets)-1]/1000)),
+	)
+
+	if pops := d.popTotals(); len(pops) > 0 {
+		secs := float64(len(d.samples))
+		lines = append(lines,
+			fit("", width),
+			fit(fmt.Sprintf("%-8s%10s%10s%10s", "POP", "req/s", "hit%", "5xx/s"), width),
+		)
+		for _, p := range pops {
+			lines = append(lines, fit(fmt.Sprintf("%-8s%10.1f%9.1f%%%10.1f",
+				p.name,
+				float64(p.requests)/secs,
+				p.hitRatio()*100,
+				float64(p.status5xx)/secs,
+			), width))
+		}
+	}
+	return lines
+}
+
+// popTotal is the stats of a POP summed over the window.
+type popTotal struct {
+	dashboardSample
+	name string
+}
+
+// popTotals returns the busiest POPs over the window, busiest first.
+func (d *serviceDashboard) popTotals() []popTotal {
+	totals := make(map[string]dashboardSample)
+	for _, pops := range d.pops {
+		for pop, s := range pops {
+			totals[pop] = totals[pop].add(s)
+		}
+	}
+	pops := make([]popTotal, 0, len(totals))
+	for name, s := range totals {
+		pops = append(pops, popTotal{dashboardSample: s, name: name})
+	}
+	sort.Slice(pops, func(i, j int) bool {
+		if pops[i].requests != pops[j].requests {
+			return pops[i].requests > pops[j].requests
+		}
+		return pops[i].name < pops[j].name
+	})
+	if len(pops) > dashboardMaxPOPs {
+		pops = pops[:dashboardMaxPOPs]
+	}
+	return pops
+}
+
+// renderDashboard returns the dashboard, with a panel for each service side
+// by side, fitted to the width of the terminal.
+func renderDashboard(dashboards []*serviceDashboard, window, width int, now time.Time) string {
+	panelWidth := dashboardMinPanelWidth
+	if n := len(dashboards); n > 0 {
+		gaps := utf8.RuneCountInString(dashboardPanelGap) * (n - 1)
+		if w := (width - gaps) / n; w > panelWidth {
+			panelWidth = w
+		}
+	}
+
+	panels := make([][]string, len(dashboards))
+	var height int
+	for i, d := range dashboards {
+		panels[i] = d.render(panelWidth)
+		if len(panels[i]) > height {
+			height = len(panels[i])
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Realtime stats at %s (last %ds). Press Ctrl-C to exit.%s\n\n", now.Format("15:04:05"), window, escClearLine)
+	for row := 0; row < height; row++ {
+		cells := make([]string, len(panels))
+		for i, lines := range panels {
+			if row < len(lines) {
+				cells[i] = lines[row]
+			} else {
+				cells[i] = strings.Repeat(" ", panelWidth)
+			}
+		}
+		b.WriteString(strings.TrimRight(strings.Join(cells, dashboardPanelGap), " "))
+		b.WriteString(escClearLine + "\n")
+	}
+	return b.String()
+}
+
+// realtimeUpdate is the stats received for the service at index.
+type realtimeUpdate struct {
+	index int
+	data  []realtimeResponseData
+	err   error
+}
+
+// runDashboard displays the realtime stats of the services until interrupted.
+func runDashboard(client api.RealtimeStatsInterface, services []cmd.SelectedService, window time.Duration, out io.Writer) error {
+	seconds := int(window / time.Second)
+	updates := make(chan realtimeUpdate)
+	done := make(chan struct{})
+	defer close(done)
+
+	dashboards := make([]*serviceDashboard, len(services))
+	for i, s := range services {
+		dashboards[i] = &serviceDashboard{service: s, window: seconds}
+		go pollRealtime(client, i, s.ID, updates, done)
+	}
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+
+	fmt.Fprint(out, escEnterScreen)
+	defer fmt.Fprint(out, escLeaveScreen)
+
+	draw := func() {
+		fmt.Fprint(out, escHome+renderDashboard(dashboards, seconds, terminalWidth(out), time.Now())+escClearScreen)
+	}
+	draw()
+
+	for {
+		select {
+		case <-sigs:
+			return nil
+		case u := <-updates:
+			d := dashboards[u.index]
+			d.err = u.err
+			if u.err == nil {
+				d.err = d.add(u.data)
+			}
+			draw()
+		}
+	}
+}
+
+// pollRealtime sends the realtime stats of the service as they're received,
+// until done is closed.
+func pollRealtime(client api.RealtimeStatsInterface, index int, serviceID string, updates chan<- realtimeUpdate, done <-chan struct{}) {
+	var timestamp uint64
+	for {
+		var envelope realtimeResponse
+		err := client.GetRealtimeStatsJSON(&fastly.GetRealtimeStatsInput{
+			ServiceID: serviceID,
+			Timestamp: timestamp,
+		}, &envelope)
+		if err == nil {
+			timestamp = envelope.Timestamp
+		}
+
+		select {
+		case updates <- realtimeUpdate{index: index, data: envelope.Data, err: err}:
+		case <-done:
+			return
+		}
+
+		if err != nil {
+			select {
+			case <-time.After(realtimeRetryInterval):
+			case <-done:
+				return
+			}
+		}
+	}
+}
+
+// terminalWidth returns the width of the terminal, if out is one.
+func terminalWidth(out io.Writer) int {
+	if f, ok := out.(*os.File); ok {
+		if width, _, err := term.GetSize(int(f.Fd())); err == nil && width > 0 {
+			return width
+		}
+	}
+	return text.DefaultTextWidth
+}
+
+// sparkline draws the most recent values that fit within width, scaled to
+// the highest of them. It's padded on the left while the window fills up.
+func sparkline(values []float64, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	var highest float64
+	for _, v := range values {
+		if v > highest {
+			highest = v
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" ", width-len(values)))
+	for _, v := range values {
+		i := 0
+		if highest > 0 {
+			i = int(v / highest * float64(len(sparkTicks)-1))
+		}
+		b.WriteRune(sparkTicks[i])
+	}
+	return b.String()
+}
+
+// missLatency groups the miss histogram into missLatencyBuckets, plus a final
+// bucket for slower misses.
+func missLatency(hist map[int]int) []float64 {
+	buckets := make([]float64, len(missLatencyBuckets)+1)
+	for ms, n := range hist {
+		i := sort.SearchInts(missLatencyBuckets, ms)
+		buckets[i] += float64(n)
+	}
+	return buckets
+}
+
+// percentile returns the upper bound, in milliseconds, of the histogram bucket
+// containing the percentile p (between 0 and 1), or -1 if it's empty.
+func percentile(hist map[int]int, p float64) int {
+	bounds := make([]int, 0, len(hist))
+	var total int
+	for ms, n := range hist {
+		bounds = append(bounds, ms)
+		total += n
+	}
+	if total == 0 {
+		return -1
+	}
+	sort.Ints(bounds)
+
+	var seen int
+	for _, ms := range bounds {
+		seen += hist[ms]
+		if float64(seen) >= p*float64(total) {
+			return ms
+		}
+	}
+	return bounds[len(bounds)-1]
+}
+
+// formatLatency formats a latency in milliseconds, as returned by percentile.
+func formatLatency(ms int) string {
+	switch {
+	case ms < 0:
+		return "-"
+	case ms >= 1000:
+		return fmt.Sprintf("%.1fs", float64(ms)/1000)
+	default:
+		return fmt.Sprintf("%dms", ms)
+	}
+}
+
+// formatBytes formats a number of bytes using decimal units.
+func formatBytes(n float64) string {
+	units := []string{"B", "kB", "MB", "GB", "TB"}
+	i := 0
+	for n >= 1000 && i < len(units)-1 {
+		n /= 1000
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%.0f%s", n, units[i])
+	}
+	return fmt.Sprintf("%.1f%s", n, units[i])
+}
+
+// fit pads or truncates s to width characters.
+func fit(s string, width int) string {
+	n := utf8.RuneCountInString(s)
+	if n > width {
+		return string([]rune(s)[:width])
+	}
+	return s + strings.Repeat(" ", width-n)
+}
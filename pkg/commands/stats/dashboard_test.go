@@ -0,0 +1,106 @@
+package stats
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/fastly/cli/pkg/cmd"
+)
+
+// realtimeData is two seconds of realtime stats, the second with a breakdown
+// by POP.
+const realtimeData = `[
+  {
+    "recorded": 1696000000,
+    "aggregated": {"requests": 10, "hits": 8, "miss": 2, "status_2xx": 9, "status_5xx": 1, "resp_header_bytes": 500, "resp_body_bytes": 1500, "miss_histogram": {"40": 1, "250": 1}}
+  },
+  {
+    "recorded": 1696000001,
+    "aggregated": {"requests": 20, "hits": 15, "miss": 5, "status_2xx": 18, "status_4xx": 2, "resp_header_bytes": 1000, "resp_body_bytes": 1499000, "miss_histogram": {"40": 4, "9000": 1}},
+    "datacenter": {
+      "LHR": {"requests": 15, "hits": 12, "miss": 3, "status_2xx": 15},
+      "JFK": {"requests": 5, "hits": 3, "miss": 2, "status_2xx": 3, "status_4xx": 2}
+    }
+  }
+]`
+
+// TestDashboard tests that the panels of each service are rendered side by
+// side from the realtime stats.
+func TestDashboard(t *testing.T) {
+	var data []realtimeResponseData
+	if err := json.Unmarshal([]byte(realtimeData), &data); err != nil {
+		t.Fatal(err)
+	}
+
+	shop := &serviceDashboard{service: cmd.SelectedService{ID: "123", Name: "shop"}, window: 60}
+	if err := shop.add(data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	api := &serviceDashboard{service: cmd.SelectedService{ID: "456", Name: "api"}, window: 60, err: errors.New("timeout")}
+
+	got := renderDashboard([]*serviceDashboard{shop, api}, 60, 80, time.Date(2023, 9, 29, 15, 6, 41, 0, time.UTC))
+	got = strings.ReplaceAll(got, escClearLine, "")
+
+	want := `Realtime stats at 15:06:41 (last 60s). Press Ctrl-C to exit.
+
+shop (123)                             │ api (456)
+────────────────────────────────────── │ ──────────────────────────────────────
+Requests/s            20            ▄█ │ Error: timeout
+Hit ratio          75.0%            █▇ │ Waiting for stats...
+2xx/s                 18            ▄█ │
+4xx/s                  2            ▁█ │
+5xx/s                  0            █▁ │
+Bandwidth        1.5MB/s            ▁█ │
+Miss latency    p50 40ms ▁▁█▁▁▂▁▁▁▂    │
+                p95 9.0s 10ms…5s+      │
+                                       │
+POP          req/s      hit%     5xx/s │
+LHR            7.5     80.0%       0.0 │
+JFK            2.5     60.0%       0.0 │
+`
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("dashboard mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// TestDashboardWindow tests that only the most recent seconds are kept.
+func TestDashboardWindow(t *testing.T) {
+	d := &serviceDashboard{window: 3}
+	for i := 1; i <= 5; i++ {
+		err := d.add([]realtimeResponseData{{Aggregated: statsResponseData{"requests": float64(i)}}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	got := d.series(func(s dashboardSample) float64 { return float64(s.requests) })
+	if diff := cmp.Diff([]float64{3, 4, 5}, got); diff != "" {
+		t.Errorf("window mismatch (-want +got):\n%s", diff)
+	}
+	if len(d.pops) != 3 {
+		t.Errorf("want 3 POP samples, got %d", len(d.pops))
+	}
+}
+
+// TestSparkline tests that values are scaled to the highest of them and
+// padded to the width.
+func TestSparkline(t *testing.T) {
+	for _, test := range []struct {
+		values []float64
+		width  int
+		want   string
+	}{
+		{values: []float64{0, 1, 2, 3, 4, 5, 6, 7}, width: 8, want: "▁▂▃▄▅▆▇█"},
+		{values: []float64{0, 0}, width: 4, want: "  ▁▁"},
+		{values: []float64{9, 1, 2}, width: 2, want: "▄█"},
+		{values: []float64{1}, width: 0, want: ""},
+	} {
+		if got := sparkline(test.values, test.width); got != test.want {
+			t.Errorf("sparkline(%v, %d): want %q, got %q", test.values, test.width, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,385 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fastly/go-fastly/v8/fastly"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// openMetricsContentType is the content type of the exposed metrics.
+const openMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// datacenterAll labels the stats of a service that were received without a
+// breakdown by POP.
+const datacenterAll = "all"
+
+// exporterCounter is a counter exposed for each service and POP.
+type exporterCounter struct {
+	name  string
+	help  string
+	value func(fastly.Stats) uint64
+}
+
+// exporterCounters are the counters exposed by the exporter.
+//
+// NOTE: The names follow the OpenMetrics convention of a _total suffix being
+// added to the samples of a counter.
+var exporterCounters = []exporterCounter{
+	{"fastly_requests", "Requests processed.", func(s fastly.Stats) uint64 { return s.Requests }},
+	{"fastly_hits", "Cache hits.", func(s fastly.Stats) uint64 { return s.Hits }},
+	{"fastly_misses", "Cache misses.", func(s fastly.Stats) uint64 { return s.Miss }},
+	{"fastly_passes", "Requests passed through to origin without being cached.", func(s fastly.Stats) uint64 { return s.Pass }},
+	{"fastly_synths", "Requests that returned a synthetic response.", func(s fastly.Stats) uint64 { return s.Synth }},
+	{"fastly_errors", "Cache errors.", func(s fastly.Stats) uint64 { return s.Errors }},
+	{"fastly_status_1xx", "Responses with an informational status code.", func(s fastly.Stats) uint64 { return s.Status1xx }},
+	{"fastly_status_2xx", "Responses with a success status code.", func(s fastly.Stats) uint64 { return s.Status2xx }},
+	{"fastly_status_3xx", "Responses with a redirection status code.", func(s fastly.Stats) uint64 { return s.Status3xx }},
+	{"fastly_status_4xx", "Responses with a client error status code.", func(s fastly.Stats) uint64 { return s.Status4xx }},
+	{"fastly_status_5xx", "Responses with a server error status code.", func(s fastly.Stats) uint64 { return s.Status5xx }},
+	{"fastly_request_bytes", "Bytes received from clients (headers and body).", func(s fastly.Stats) uint64 { return s.RequestHeaderBytes + s.RequestBodyBytes }},
+	{"fastly_response_bytes", "Bytes delivered to clients (headers and body).", func(s fastly.Stats) uint64 { return s.ResponseHeaderBytes + s.ResponseBodyBytes }},
+}
+
+// ExporterCommand exposes the realtime stats of services as OpenMetrics, to
+// be scraped by Prometheus.
+type ExporterCommand struct {
+	cmd.Base
+	cmd.ServiceSelector
+	manifest manifest.Data
+
+	listen      string
+	serviceIDs  []string
+	serviceName cmd.OptionalServiceNameID
+}
+
+// NewExporterCommand is the "stats exporter" subcommand.
+func NewExporterCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *ExporterCommand {
+	var c ExporterCommand
+	c.Globals = g
+	c.manifest = m
+
+	c.CmdClause = parent.Command("exporter", "Expose the realtime stats of Fastly services as OpenMetrics, for Prometheus to scrape")
+	// NOTE: The flag can be repeated to export several services, so it isn't
+	// registered with RegisterFlag.
+	c.CmdClause.Flag(cmd.FlagServiceIDName, cmd.FlagServiceIDDesc+". Repeat to export several services").Short('s').StringsVar(&c.serviceIDs)
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	c.RegisterFlag(c.ServicesFlag()) // --services
+	c.CmdClause.Flag("listen", "Address to serve the metrics on, at /metrics").Default(":9100").StringVar(&c.listen)
+
+	return &c
+}
+
+// Exec implements the command interface.
+func (c *ExporterCommand) Exec(_ io.Reader, out io.Writer) error {
+	services, err := c.selectServices(out)
+	if err != nil {
+		return err
+	}
+
+	l, err := net.Listen("tcp", c.listen)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("error listening on %s: %w", c.listen, err),
+			Remediation: "Check the --listen address is valid and not already in use.",
+		}
+	}
+
+	e := newExporter(services)
+	updates := make(chan realtimeUpdate)
+	done := make(chan struct{})
+	defer close(done)
+	for i, s := range services {
+		go pollRealtime(c.Globals.RTSClient, i, s.ID, updates, done)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", e)
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		_ = srv.Serve(l)
+	}()
+	defer srv.Close()
+
+	text.Info(out, "Exporting the realtime stats of %d service(s) at http://%s/metrics", len(services), l.Addr())
+	if c.Globals.Verbose() {
+		for _, s := range services {
+			text.Output(out, "Service: %s", s)
+		}
+	}
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+
+	for {
+		select {
+		case <-sigs:
+			return nil
+		case u := <-updates:
+			if err := e.update(u); err != nil {
+				c.Globals.ErrLog.AddWithContext(err, map[string]any{
+					"Service ID": services[u.index].ID,
+				})
+			}
+		}
+	}
+}
+
+// selectServices returns the services to export, looking up their names for
+// the service_name label.
+func (c *ExporterCommand) selectServices(out io.Writer) ([]cmd.SelectedService, error) {
+	var services []cmd.SelectedService
+	switch {
+	case c.SelectingServices():
+		if len(c.serviceIDs) > 0 || c.serviceName.WasSet {
+			return nil, fsterr.ErrInvalidServicesCombo
+		}
+		var err error
+		services, err = cmd.SelectServices(c.Services, c.Globals.APIClient)
+		if err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Services": c.Services,
+			})
+			return nil, err
+		}
+
+	case len(c.serviceIDs) > 1:
+		if c.serviceName.WasSet {
+			return nil, fsterr.RemediationError{
+				Inner:       fmt.Errorf("--service-name can't be used with more than one --service-id"),
+				Remediation: "Provide the ID of each service to export with --service-id.",
+			}
+		}
+		seen := make(map[string]bool)
+		for _, id := range c.serviceIDs {
+			if !seen[id] {
+				seen[id] = true
+				services = append(services, cmd.SelectedService{ID: id})
+			}
+		}
+
+	default:
+		if len(c.serviceIDs) == 1 {
+			c.manifest.Flag.ServiceID = c.serviceIDs[0]
+		}
+		serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
+		if err != nil {
+			return nil, err
+		}
+		if c.Globals.Verbose() {
+			cmd.DisplayServiceID(serviceID, flag, source, out)
+		}
+		services = []cmd.SelectedService{{ID: serviceID}}
+	}
+
+	for i, s := range services {
+		if s.Name != "" {
+			continue
+		}
+		service, err := c.Globals.APIClient.GetService(&fastly.GetServiceInput{ID: s.ID})
+		if err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Service ID": s.ID,
+			})
+			return nil, err
+		}
+		services[i].Name = service.Name
+	}
+	return services, nil
+}
+
+// exportedService is the stats of a service accumulated since the exporter
+// started.
+type exportedService struct {
+	service cmd.SelectedService
+	// totals are the counters of each POP, keyed by counter name.
+	totals map[string]map[string]uint64
+	// last is the most recent second of stats, across all POPs.
+	last fastly.Stats
+	// recorded is when the most recent second of stats was recorded.
+	recorded float64
+	// up is whether the stats were fetched successfully the last time.
+	up bool
+}
+
+// exporter accumulates realtime stats and serves them as OpenMetrics.
+type exporter struct {
+	mu       sync.Mutex
+	services []*exportedService
+}
+
+// newExporter returns an exporter of the services.
+func newExporter(services []cmd.SelectedService) *exporter {
+	e := &exporter{}
+	for _, s := range services {
+		e.services = append(e.services, &exportedService{
+			service: s,
+			totals:  make(map[string]map[string]uint64),
+		})
+	}
+	return e
+}
+
+// update adds the realtime stats received for a service to its counters.
+func (e *exporter) update(u realtimeUpdate) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	s := e.services[u.index]
+	s.up = u.err == nil
+	if u.err != nil {
+		return u.err
+	}
+
+	for _, block := range u.data {
+		aggregated, err := decodeStats(block.Aggregated)
+		if err != nil {
+			s.up = false
+			return err
+		}
+
+		pops := make(map[string]fastly.Stats, len(block.Datacenter))
+		for pop, data := range block.Datacenter {
+			stats, err := decodeStats(data)
+			if err != nil {
+				s.up = false
+				return err
+			}
+			pops[pop] = stats
+		}
+		// NOTE: The aggregated stats are only counted when there's no breakdown
+		// by POP, so summing the counters of a service doesn't count requests
+		// twice.
+		if len(pops) == 0 {
+			pops[datacenterAll] = aggregated
+		}
+
+		for pop, stats := range pops {
+			totals, ok := s.totals[pop]
+			if !ok {
+				totals = make(map[string]uint64, len(exporterCounters))
+				s.totals[pop] = totals
+			}
+			for _, counter := range exporterCounters {
+				totals[counter.name] += counter.value(stats)
+			}
+		}
+		s.last = aggregated
+		s.recorded = block.Recorded
+	}
+	return nil
+}
+
+// ServeHTTP implements http.Handler.
+func (e *exporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", openMetricsContentType)
+	e.write(w)
+}
+
+// write writes the metrics in the OpenMetrics text format.
+func (e *exporter) write(w io.Writer) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, counter := range exporterCounters {
+		fmt.Fprintf(w, "# TYPE %s counter\n", counter.name)
+		fmt.Fprintf(w, "# HELP %s %s\n", counter.name, counter.help)
+		for _, s := range e.services {
+			pops := make([]string, 0, len(s.totals))
+			for pop := range s.totals {
+				pops = append(pops, pop)
+			}
+			sort.Strings(pops)
+			for _, pop := range pops {
+				fmt.Fprintf(w, "%s_total%s %d\n", counter.name, s.labels(pop), s.totals[pop][counter.name])
+			}
+		}
+	}
+
+	gauges := []struct {
+		name  string
+		help  string
+		value func(*exportedService) (float64, bool)
+	}{
+		{
+			name: "fastly_up",
+			help: "Whether the realtime stats of the service were fetched successfully the last time.",
+			value: func(s *exportedService) (float64, bool) {
+				if s.up {
+					return 1, true
+				}
+				return 0, true
+			},
+		},
+		{
+			name: "fastly_hit_ratio",
+			help: "Ratio of cache lookups that were hits, over the most recent second.",
+			value: func(s *exportedService) (float64, bool) {
+				if s.last.Hits+s.last.Miss == 0 {
+					return 0, s.recorded > 0
+				}
+				return float64(s.last.Hits) / float64(s.last.Hits+s.last.Miss), true
+			},
+		},
+		{
+			name: "fastly_last_recorded_timestamp_seconds",
+			help: "When the most recent second of realtime stats was recorded.",
+			value: func(s *exportedService) (float64, bool) {
+				return s.recorded, s.recorded > 0
+			},
+		},
+	}
+	for _, gauge := range gauges {
+		fmt.Fprintf(w, "# TYPE %s gauge\n", gauge.name)
+		fmt.Fprintf(w, "# HELP %s %s\n", gauge.name, gauge.help)
+		for _, s := range e.services {
+			if v, ok := gauge.value(s); ok {
+				fmt.Fprintf(w, "%s%s %g\n", gauge.name, s.labels(""), v)
+			}
+		}
+	}
+	fmt.Fprintln(w, "# EOF")
+}
+
+// labels returns the label set of the service's samples, with the datacenter
+// label if pop isn't empty.
+func (s *exportedService) labels(pop string) string {
+	labels := fmt.Sprintf(`service_id="%s",service_name="%s"`, escapeLabel(s.service.ID), escapeLabel(s.service.Name))
+	if pop != "" {
+		labels += fmt.Sprintf(`,datacenter="%s"`, escapeLabel(pop))
+	}
+	return "{" + labels + "}"
+}
+
+// labelEscaper escapes a label value for the OpenMetrics text format.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabel escapes a label value for the OpenMetrics text format.
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
@@ -0,0 +1,141 @@
+package stats
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fastly/go-fastly/v8/fastly"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/mock"
+)
+
+// TestExporter tests that the realtime stats of each service are accumulated
+// into counters labelled by service and POP.
+func TestExporter(t *testing.T) {
+	var data []realtimeResponseData
+	if err := json.Unmarshal([]byte(realtimeData), &data); err != nil {
+		t.Fatal(err)
+	}
+
+	e := newExporter([]cmd.SelectedService{
+		{ID: "123", Name: "shop"},
+		{ID: "456", Name: `the "api"`},
+	})
+	if err := e.update(realtimeUpdate{index: 0, data: data}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// NOTE: Stats are counted again when they're received again.
+	if err := e.update(realtimeUpdate{index: 0, data: data[1:]}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := e.update(realtimeUpdate{index: 1, err: errors.New("timeout")}); err == nil {
+		t.Fatal("expected the error to be returned")
+	}
+
+	srv := httptest.NewServer(e)
+	defer srv.Close()
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if got := resp.Header.Get("Content-Type"); got != openMetricsContentType {
+		t.Errorf("want content type %q, got %q", openMetricsContentType, got)
+	}
+
+	var b strings.Builder
+	e.write(&b)
+	got := b.String()
+
+	for _, want := range []string{
+		"# TYPE fastly_requests counter\n# HELP fastly_requests Requests processed.\n" +
+			`fastly_requests_total{service_id="123",service_name="shop",datacenter="JFK"} 10` + "\n" +
+			`fastly_requests_total{service_id="123",service_name="shop",datacenter="LHR"} 30` + "\n" +
+			`fastly_requests_total{service_id="123",service_name="shop",datacenter="all"} 10` + "\n",
+		`fastly_status_4xx_total{service_id="123",service_name="shop",datacenter="JFK"} 4` + "\n",
+		`fastly_status_5xx_total{service_id="123",service_name="shop",datacenter="all"} 1` + "\n",
+		`fastly_response_bytes_total{service_id="123",service_name="shop",datacenter="all"} 2000` + "\n",
+		"# TYPE fastly_up gauge\n# HELP fastly_up Whether the realtime stats of the service were fetched successfully the last time.\n" +
+			`fastly_up{service_id="123",service_name="shop"} 1` + "\n" +
+			`fastly_up{service_id="456",service_name="the \"api\""} 0` + "\n",
+		`fastly_hit_ratio{service_id="123",service_name="shop"} 0.75` + "\n",
+		`fastly_last_recorded_timestamp_seconds{service_id="123",service_name="shop"} 1.696000001e+09` + "\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("metrics don't contain:\n%s\n\ngot:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, `fastly_hit_ratio{service_id="456"`) {
+		t.Errorf("want no hit ratio before stats are received, got:\n%s", got)
+	}
+	if !strings.HasSuffix(got, "# EOF\n") {
+		t.Errorf("want metrics to end with # EOF, got:\n%s", got)
+	}
+}
+
+// TestEscapeLabel tests that label values are escaped.
+func TestEscapeLabel(t *testing.T) {
+	got := escapeLabel("a\\b\"c\nd")
+	if diff := cmp.Diff(`a\\b\"c\nd`, got); diff != "" {
+		t.Errorf("escapeLabel mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// TestExporterSelectServices tests that the names of the services given by
+// --service-id are looked up, and that the service flags can't be combined.
+func TestExporterSelectServices(t *testing.T) {
+	api := mock.API{
+		GetServiceFn: func(i *fastly.GetServiceInput) (*fastly.Service, error) {
+			return &fastly.Service{ID: i.ID, Name: "name-" + i.ID}, nil
+		},
+	}
+
+	for _, test := range []struct {
+		c         ExporterCommand
+		want      []cmd.SelectedService
+		wantError string
+	}{
+		{
+			c: ExporterCommand{serviceIDs: []string{"123", "456", "123"}},
+			want: []cmd.SelectedService{
+				{ID: "123", Name: "name-123"},
+				{ID: "456", Name: "name-456"},
+			},
+		},
+		{
+			c:    ExporterCommand{serviceIDs: []string{"123"}},
+			want: []cmd.SelectedService{{ID: "123", Name: "name-123"}},
+		},
+		{
+			c:         ExporterCommand{serviceIDs: []string{"123"}, ServiceSelector: cmd.ServiceSelector{Services: "shop-*"}},
+			wantError: "invalid flag combination",
+		},
+		{
+			c:         ExporterCommand{serviceIDs: []string{"123", "456"}, serviceName: cmd.OptionalServiceNameID{OptionalString: cmd.OptionalString{Optional: cmd.Optional{WasSet: true}, Value: "shop"}}},
+			wantError: "--service-name can't be used with more than one --service-id",
+		},
+	} {
+		c := test.c
+		c.Globals = &global.Data{APIClient: api, ErrLog: fsterr.MockLog{}}
+		got, err := c.selectServices(io.Discard)
+		if test.wantError != "" {
+			if err == nil || !strings.Contains(err.Error(), test.wantError) {
+				t.Errorf("want error containing %q, got %v", test.wantError, err)
+			}
+		} else if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("selectServices mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
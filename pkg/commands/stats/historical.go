@@ -8,8 +8,10 @@ import (
 	"github.com/fastly/go-fastly/v8/fastly"
 
 	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
 )
 
 const statusSuccess = "success"
@@ -17,6 +19,7 @@ const statusSuccess = "success"
 // HistoricalCommand exposes the Historical Stats API.
 type HistoricalCommand struct {
 	cmd.Base
+	cmd.ServiceSelector
 	manifest manifest.Data
 
 	Input       fastly.GetStatsInput
@@ -43,19 +46,27 @@ func NewHistoricalCommand(parent cmd.Registerer, g *global.Data, m manifest.Data
 		Description: cmd.FlagServiceDesc,
 		Dst:         &c.serviceName.Value,
 	})
+	c.RegisterFlag(c.ServicesFlag()) // --services
 
 	c.CmdClause.Flag("from", "From time, accepted formats at https://fastly.dev/reference/api/metrics-stats/historical-stats").StringVar(&c.Input.From)
 	c.CmdClause.Flag("to", "To time").StringVar(&c.Input.To)
 	c.CmdClause.Flag("by", "Aggregation period (minute/hour/day)").EnumVar(&c.Input.By, "minute", "hour", "day")
 	c.CmdClause.Flag("region", "Filter by region ('stats regions' to list)").StringVar(&c.Input.Region)
 
-	c.CmdClause.Flag("format", "Output format (json)").EnumVar(&c.formatFlag, "json")
-
 	return &c
 }
 
+// SetFormat implements cmd.StructuredOutput.
+func (c *HistoricalCommand) SetFormat(format string) {
+	c.formatFlag = format
+}
+
 // Exec implements the command interface.
 func (c *HistoricalCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.SelectingServices() {
+		return c.execServices(out)
+	}
+
 	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
 	if err != nil {
 		return err
@@ -64,23 +75,90 @@ func (c *HistoricalCommand) Exec(_ io.Reader, out io.Writer) error {
 		cmd.DisplayServiceID(serviceID, flag, source, out)
 	}
 
-	c.Input.Service = serviceID
+	envelope, err := c.fetch(serviceID)
+	if err != nil {
+		return err
+	}
+	c.write(out, serviceID, envelope)
+	return nil
+}
+
+// execServices writes the stats of each service matching the --services
+// selector.
+//
+// The default output is written a service at a time, followed by a summary.
+// Structured output is combined into a single list of blocks, each with the
+// ID of its service.
+func (c *HistoricalCommand) execServices(out io.Writer) error {
+	if c.manifest.Flag.ServiceID != "" || c.serviceName.WasSet {
+		return fsterr.ErrInvalidServicesCombo
+	}
+	services, err := cmd.SelectServices(c.Services, c.Globals.APIClient)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Services": c.Services,
+		})
+		return err
+	}
+
+	if c.formatFlag == "" || c.formatFlag == text.FormatTable {
+		results := cmd.ForEachService(out, services, func(s cmd.SelectedService, out io.Writer) error {
+			envelope, err := c.fetch(s.ID)
+			if err != nil {
+				return err
+			}
+			c.write(out, s.ID, envelope)
+			return nil
+		})
+		return cmd.PrintServiceSummary(out, results)
+	}
+
+	blocks := make([][]statsResponseData, len(services))
+	results := cmd.RunServices(services, func(i int, s cmd.SelectedService) error {
+		envelope, err := c.fetch(s.ID)
+		if err != nil {
+			return err
+		}
+		for _, block := range envelope.Data {
+			if _, ok := block["service_id"]; !ok {
+				block["service_id"] = s.ID
+			}
+		}
+		blocks[i] = envelope.Data
+		return nil
+	})
+	var all []statsResponseData
+	for _, b := range blocks {
+		all = append(all, b...)
+	}
+	c.write(out, "", statsResponse{Data: all})
+	return cmd.ServicesError(results)
+}
+
+// fetch returns the historical stats of the service.
+func (c *HistoricalCommand) fetch(serviceID string) (statsResponse, error) {
+	input := c.Input
+	input.Service = serviceID
 
 	var envelope statsResponse
-	err = c.Globals.APIClient.GetStatsJSON(&c.Input, &envelope)
+	err := c.Globals.APIClient.GetStatsJSON(&input, &envelope)
 	if err != nil {
 		c.Globals.ErrLog.AddWithContext(err, map[string]any{
 			"Service ID": serviceID,
 		})
-		return err
+		return envelope, err
 	}
 
 	if envelope.Status != statusSuccess {
-		return fmt.Errorf("non-success response: %s", envelope.Msg)
+		return envelope, fmt.Errorf("non-success response: %s", envelope.Msg)
 	}
+	return envelope, nil
+}
 
+// write writes the stats in the format given by the --format flag.
+func (c *HistoricalCommand) write(out io.Writer, serviceID string, envelope statsResponse) {
 	switch c.formatFlag {
-	case "json":
+	case text.FormatJSON:
 		err := writeBlocksJSON(out, serviceID, envelope.Data)
 		if err != nil {
 			c.Globals.ErrLog.AddWithContext(err, map[string]any{
@@ -88,6 +166,14 @@ func (c *HistoricalCommand) Exec(_ io.Reader, out io.Writer) error {
 			})
 		}
 
+	case text.FormatYAML, text.FormatCSV:
+		err := text.Render(out, c.formatFlag, envelope.Data)
+		if err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Service ID": serviceID,
+			})
+		}
+
 	default:
 		writeHeader(out, envelope.Meta)
 		err := writeBlocks(out, serviceID, envelope.Data)
@@ -97,8 +183,6 @@ func (c *HistoricalCommand) Exec(_ io.Reader, out io.Writer) error {
 			})
 		}
 	}
-
-	return nil
 }
 
 func writeHeader(out io.Writer, meta statsResponseMeta) {
@@ -35,6 +35,42 @@ func TestHistorical(t *testing.T) {
 			api:        mock.API{GetStatsJSONFn: getStatsJSONOK},
 			wantOutput: historicalJSONOK,
 		},
+		{
+			args:       args("stats historical --service-id=123 --format=yaml"),
+			api:        mock.API{GetStatsJSONFn: getStatsJSONOK},
+			wantOutput: "- start_time: 0\n",
+		},
+		{
+			args:       args("stats historical --service-id=123 --format=csv"),
+			api:        mock.API{GetStatsJSONFn: getStatsJSONOK},
+			wantOutput: "start_time\n0\n",
+		},
+		{
+			args: args("stats historical --services=Ba* --format=json"),
+			api: mock.API{
+				GetStatsJSONFn: getStatsJSONOK,
+				NewListServicesPaginatorFn: func(i *fastly.ListServicesInput) fastly.PaginatorServices {
+					return &testutil.ServicesPaginator{MaxPages: 2}
+				},
+			},
+			wantOutput: "{\"service_id\":\"456\",\"start_time\":0}\n{\"service_id\":\"789\",\"start_time\":0}\n",
+		},
+		{
+			args: args("stats historical --services=Foo,Ba*"),
+			api: mock.API{
+				GetStatsJSONFn: func(i *fastly.GetStatsInput, o any) error {
+					if i.Service == "456" {
+						return errTest
+					}
+					return getStatsJSONOK(i, o)
+				},
+				NewListServicesPaginatorFn: func(i *fastly.ListServicesInput) fastly.PaginatorServices {
+					return &testutil.ServicesPaginator{MaxPages: 2}
+				},
+			},
+			wantError:  "1 of 3 services failed",
+			wantOutput: "SERVICE ID  NAME  RESULT\n123         Foo   ok\n456         Bar   failed: fixture error\n789         Baz   ok\n",
+		},
 	}
 	for testcaseIdx := range scenarios {
 		testcase := &scenarios[testcaseIdx]
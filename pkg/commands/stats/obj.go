@@ -28,4 +28,6 @@ type realtimeResponse struct {
 type realtimeResponseData struct {
 	Recorded   float64           `json:"recorded"`
 	Aggregated statsResponseData `json:"aggregated"`
+	// Datacenter is the stats of each POP, keyed by POP code.
+	Datacenter map[string]statsResponseData `json:"datacenter"`
 }
@@ -4,9 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/fastly/cli/pkg/api"
 	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/manifest"
 	"github.com/fastly/cli/pkg/text"
@@ -16,10 +18,13 @@ import (
 // RealtimeCommand exposes the Realtime Metrics API.
 type RealtimeCommand struct {
 	cmd.Base
+	cmd.ServiceSelector
 	manifest manifest.Data
 
+	dashboard   bool
 	formatFlag  string
 	serviceName cmd.OptionalServiceNameID
+	window      time.Duration
 }
 
 // NewRealtimeCommand is the "stats realtime" subcommand.
@@ -41,14 +46,35 @@ func NewRealtimeCommand(parent cmd.Registerer, g *global.Data, data manifest.Dat
 		Description: cmd.FlagServiceDesc,
 		Dst:         &c.serviceName.Value,
 	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServicesName,
+		Description: cmd.FlagServicesDesc + " (requires --dashboard)",
+		Dst:         &c.Services,
+	})
 
-	c.CmdClause.Flag("format", "Output format (json)").EnumVar(&c.formatFlag, "json")
+	c.CmdClause.Flag("dashboard", "Display a live dashboard of the stats instead of a text dump every second").BoolVar(&c.dashboard)
+	c.CmdClause.Flag("window", "Duration of stats displayed by the --dashboard sparklines").Default("1m").DurationVar(&c.window)
 
 	return &c
 }
 
+// SetFormat implements cmd.StructuredOutput.
+func (c *RealtimeCommand) SetFormat(format string) {
+	c.formatFlag = format
+}
+
 // Exec implements the command interface.
 func (c *RealtimeCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.dashboard {
+		return c.execDashboard(out)
+	}
+	if c.SelectingServices() {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("--%s requires --dashboard", cmd.FlagServicesName),
+			Remediation: "Use --dashboard to display the realtime stats of several services side by side.",
+		}
+	}
+
 	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
 	if err != nil {
 		return err
@@ -58,7 +84,13 @@ func (c *RealtimeCommand) Exec(_ io.Reader, out io.Writer) error {
 	}
 
 	switch c.formatFlag {
-	case "json":
+	case text.FormatYAML, text.FormatCSV:
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("unsupported output format for realtime stats: %s", c.formatFlag),
+			Remediation: "Realtime stats are streamed, use --format json (or table) instead.",
+		}
+
+	case text.FormatJSON:
 		if err := loopJSON(c.Globals.RTSClient, serviceID, out); err != nil {
 			c.Globals.ErrLog.AddWithContext(err, map[string]any{
 				"Service ID": serviceID,
@@ -78,6 +110,49 @@ func (c *RealtimeCommand) Exec(_ io.Reader, out io.Writer) error {
 	return nil
 }
 
+// execDashboard displays a live dashboard of the stats of the service, or of
+// each service matching the --services selector side by side.
+func (c *RealtimeCommand) execDashboard(out io.Writer) error {
+	if c.formatFlag != "" && c.formatFlag != text.FormatTable {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("unsupported output format for the realtime stats dashboard: %s", c.formatFlag),
+			Remediation: "Remove the --format flag, or remove --dashboard to stream the stats as JSON.",
+		}
+	}
+	if c.window < time.Second {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid --window: %s", c.window),
+			Remediation: "Provide a duration of at least one second (e.g. 30s or 5m).",
+		}
+	}
+
+	var services []cmd.SelectedService
+	if c.SelectingServices() {
+		if c.manifest.Flag.ServiceID != "" || c.serviceName.WasSet {
+			return fsterr.ErrInvalidServicesCombo
+		}
+		var err error
+		services, err = cmd.SelectServices(c.Services, c.Globals.APIClient)
+		if err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Services": c.Services,
+			})
+			return err
+		}
+	} else {
+		serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
+		if err != nil {
+			return err
+		}
+		if c.Globals.Verbose() {
+			cmd.DisplayServiceID(serviceID, flag, source, out)
+		}
+		services = []cmd.SelectedService{{ID: serviceID}}
+	}
+
+	return runDashboard(c.Globals.RTSClient, services, c.window, out)
+}
+
 func loopJSON(client api.RealtimeStatsInterface, service string, out io.Writer) error {
 	var timestamp uint64
 	for {
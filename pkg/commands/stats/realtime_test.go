@@ -0,0 +1,44 @@
+package stats_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestRealtimeDashboardFlags(t *testing.T) {
+	args := testutil.Args
+	scenarios := []struct {
+		args      []string
+		wantError string
+	}{
+		{
+			args:      args("stats realtime --services=Ba*"),
+			wantError: "--services requires --dashboard",
+		},
+		{
+			args:      args("stats realtime --service-id=123 --dashboard --format=json"),
+			wantError: "unsupported output format for the realtime stats dashboard: json",
+		},
+		{
+			args:      args("stats realtime --service-id=123 --dashboard --window=500ms"),
+			wantError: "invalid --window: 500ms",
+		},
+		{
+			args:      args("stats realtime --service-id=123 --services=Ba* --dashboard"),
+			wantError: "invalid flag combination",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(strings.Join(testcase.args, " "), func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.args, &stdout)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+		})
+	}
+}
@@ -21,9 +21,13 @@ func NewListCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Lis
 	c.manifest = m
 
 	// Optional.
+	c.RegisterFlagBool(c.AllFlag()) // --all
 	c.CmdClause.Flag("filter-bulk", "Optionally filter by the bulk attribute").Action(c.filterBulk.Set).BoolVar(&c.filterBulk.Value)
 	c.CmdClause.Flag("include", "Include related objects (comma-separated values)").HintOptions(include).EnumVar(&c.include, include)
+	c.RegisterFlag(c.FieldsFlag())   // --fields
+	c.RegisterFlag(c.FilterFlag())   // --filter
 	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterFlagInt(c.LimitFlag()) // --limit
 	c.CmdClause.Flag("page", "Page number of data set to fetch").IntVar(&c.pageNumber)
 	c.CmdClause.Flag("per-page", "Number of records per page").IntVar(&c.pageSize)
 
@@ -34,6 +38,7 @@ func NewListCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Lis
 type ListCommand struct {
 	cmd.Base
 	cmd.JSONOutput
+	cmd.Pagination
 
 	filterBulk cmd.OptionalBool
 	include    string
@@ -49,6 +54,9 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 	}
 
 	input := c.constructInput()
+	if c.Streaming() {
+		return c.stream(out, input)
+	}
 
 	o, err := c.Globals.APIClient.ListCustomTLSConfigurations(input)
 	if err != nil {
@@ -164,3 +172,36 @@ func (c *ListCommand) printSummary(out io.Writer, rs []*fastly.CustomTLSConfigur
 	t.Print()
 	return nil
 }
+
+// stream prints the TLS configurations a page at a time as they're fetched.
+//
+// NOTE: The pages are fetched sequentially as the number of pages isn't
+// returned by the API client.
+func (c *ListCommand) stream(out io.Writer, input *fastly.ListCustomTLSConfigurationsInput) error {
+	if input.PageSize <= 0 {
+		input.PageSize = cmd.MaxPerPage
+	}
+	start := input.PageNumber
+	if start <= 0 {
+		start = 1
+	}
+
+	w := cmd.NewPageWriter(out, &c.JSONOutput, func(rs []*fastly.CustomTLSConfiguration) {
+		if c.Globals.Verbose() {
+			c.printVerbose(out, rs)
+			return
+		}
+		_ = c.printSummary(out, rs)
+	})
+
+	page := func(n int) ([]*fastly.CustomTLSConfiguration, error) {
+		i := *input
+		i.PageNumber = start + n
+		return c.Globals.APIClient.ListCustomTLSConfigurations(&i)
+	}
+	if err := cmd.FetchNumberedPages(c.Limit, input.PageSize, page, w.Write); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	return w.Close()
+}
@@ -22,10 +22,14 @@ func NewListCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Lis
 	c.manifest = m
 
 	// Optional.
+	c.RegisterFlagBool(c.AllFlag()) // --all
 	c.CmdClause.Flag("filter-not-after", "Limit the returned certificates to those that expire prior to the specified date in UTC").StringVar(&c.filterNotAfter)
 	c.CmdClause.Flag("filter-domain", "Limit the returned certificates to those that include the specific domain").StringVar(&c.filterTLSDomainID)
 	c.CmdClause.Flag("include", "Include related objects (comma-separated values)").HintOptions("tls_activations").EnumVar(&c.include, "tls_activations")
+	c.RegisterFlag(c.FieldsFlag())   // --fields
+	c.RegisterFlag(c.FilterFlag())   // --filter
 	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterFlagInt(c.LimitFlag()) // --limit
 	c.CmdClause.Flag("page", "Page number of data set to fetch").IntVar(&c.pageNumber)
 	c.CmdClause.Flag("per-page", "Number of records per page").IntVar(&c.pageSize)
 	c.CmdClause.Flag("sort", "The order in which to list the results by creation date").StringVar(&c.sort)
@@ -37,6 +41,7 @@ func NewListCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Lis
 type ListCommand struct {
 	cmd.Base
 	cmd.JSONOutput
+	cmd.Pagination
 
 	filterNotAfter    string
 	filterTLSDomainID string
@@ -54,6 +59,9 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 	}
 
 	input := c.constructInput()
+	if c.Streaming() {
+		return c.stream(out, input)
+	}
 
 	o, err := c.Globals.APIClient.ListCustomTLSCertificates(input)
 	if err != nil {
@@ -151,3 +159,36 @@ func (c *ListCommand) printSummary(out io.Writer, rs []*fastly.CustomTLSCertific
 	t.Print()
 	return nil
 }
+
+// stream prints the certificates a page at a time as they're fetched.
+//
+// NOTE: The pages are fetched sequentially as the number of pages isn't
+// returned by the API client.
+func (c *ListCommand) stream(out io.Writer, input *fastly.ListCustomTLSCertificatesInput) error {
+	if input.PageSize <= 0 {
+		input.PageSize = cmd.MaxPerPage
+	}
+	start := input.PageNumber
+	if start <= 0 {
+		start = 1
+	}
+
+	w := cmd.NewPageWriter(out, &c.JSONOutput, func(rs []*fastly.CustomTLSCertificate) {
+		if c.Globals.Verbose() {
+			printVerbose(out, rs)
+			return
+		}
+		_ = c.printSummary(out, rs)
+	})
+
+	page := func(n int) ([]*fastly.CustomTLSCertificate, error) {
+		i := *input
+		i.PageNumber = start + n
+		return c.Globals.APIClient.ListCustomTLSCertificates(&i)
+	}
+	if err := cmd.FetchNumberedPages(c.Limit, input.PageSize, page, w.Write); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	return w.Close()
+}
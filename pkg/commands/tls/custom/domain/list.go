@@ -22,11 +22,15 @@ func NewListCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Lis
 	c.manifest = m
 
 	// Optional.
+	c.RegisterFlagBool(c.AllFlag()) // --all
 	c.CmdClause.Flag("filter-cert", "Limit the returned domains to those listed in the given TLS certificate's SAN list").StringVar(&c.filterTLSCertsID)
 	c.CmdClause.Flag("filter-in-use", "Limit the returned domains to those currently using Fastly to terminate TLS with SNI").Action(c.filterInUse.Set).BoolVar(&c.filterInUse.Value)
 	c.CmdClause.Flag("filter-subscription", "Limit the returned domains to those for a given TLS subscription").StringVar(&c.filterTLSSubsID)
 	c.CmdClause.Flag("include", "Include related objects (comma-separated values)").HintOptions("tls_activations").EnumVar(&c.include, "tls_activations")
+	c.RegisterFlag(c.FieldsFlag())   // --fields
+	c.RegisterFlag(c.FilterFlag())   // --filter
 	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterFlagInt(c.LimitFlag()) // --limit
 	c.CmdClause.Flag("page", "Page number of data set to fetch").IntVar(&c.pageNumber)
 	c.CmdClause.Flag("per-page", "Number of records per page").IntVar(&c.pageSize)
 	c.CmdClause.Flag("sort", "The order in which to list the results by creation date").StringVar(&c.sort)
@@ -38,6 +42,7 @@ func NewListCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Lis
 type ListCommand struct {
 	cmd.Base
 	cmd.JSONOutput
+	cmd.Pagination
 
 	filterInUse      cmd.OptionalBool
 	filterTLSCertsID string
@@ -56,6 +61,9 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 	}
 
 	input := c.constructInput()
+	if c.Streaming() {
+		return c.stream(out, input)
+	}
 
 	o, err := c.Globals.APIClient.ListTLSDomains(input)
 	if err != nil {
@@ -136,3 +144,36 @@ func (c *ListCommand) printSummary(out io.Writer, rs []*fastly.TLSDomain) error
 	t.Print()
 	return nil
 }
+
+// stream prints the domains a page at a time as they're fetched.
+//
+// NOTE: The pages are fetched sequentially as the number of pages isn't
+// returned by the API client.
+func (c *ListCommand) stream(out io.Writer, input *fastly.ListTLSDomainsInput) error {
+	if input.PageSize <= 0 {
+		input.PageSize = cmd.MaxPerPage
+	}
+	start := input.PageNumber
+	if start <= 0 {
+		start = 1
+	}
+
+	w := cmd.NewPageWriter(out, &c.JSONOutput, func(rs []*fastly.TLSDomain) {
+		if c.Globals.Verbose() {
+			printVerbose(out, rs)
+			return
+		}
+		_ = c.printSummary(out, rs)
+	})
+
+	page := func(n int) ([]*fastly.TLSDomain, error) {
+		i := *input
+		i.PageNumber = start + n
+		return c.Globals.APIClient.ListTLSDomains(&i)
+	}
+	if err := cmd.FetchNumberedPages(c.Limit, input.PageSize, page, w.Write); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	return w.Close()
+}
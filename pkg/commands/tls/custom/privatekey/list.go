@@ -20,8 +20,12 @@ func NewListCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Lis
 	c.manifest = m
 
 	// Optional.
+	c.RegisterFlagBool(c.AllFlag()) // --all
 	c.CmdClause.Flag("filter-in-use", "Limit the returned keys to those without any matching TLS certificates").HintOptions("false").EnumVar(&c.filterInUse, "false")
+	c.RegisterFlag(c.FieldsFlag())   // --fields
+	c.RegisterFlag(c.FilterFlag())   // --filter
 	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterFlagInt(c.LimitFlag()) // --limit
 	c.CmdClause.Flag("page", "Page number of data set to fetch").IntVar(&c.pageNumber)
 	c.CmdClause.Flag("per-page", "Number of records per page").IntVar(&c.pageSize)
 
@@ -32,6 +36,7 @@ func NewListCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Lis
 type ListCommand struct {
 	cmd.Base
 	cmd.JSONOutput
+	cmd.Pagination
 
 	filterInUse string
 	manifest    manifest.Data
@@ -46,6 +51,9 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 	}
 
 	input := c.constructInput()
+	if c.Streaming() {
+		return c.stream(out, input)
+	}
 
 	o, err := c.Globals.APIClient.ListPrivateKeys(input)
 	if err != nil {
@@ -119,3 +127,36 @@ func (c *ListCommand) printSummary(out io.Writer, rs []*fastly.PrivateKey) error
 	t.Print()
 	return nil
 }
+
+// stream prints the private keys a page at a time as they're fetched.
+//
+// NOTE: The pages are fetched sequentially as the number of pages isn't
+// returned by the API client.
+func (c *ListCommand) stream(out io.Writer, input *fastly.ListPrivateKeysInput) error {
+	if input.PageSize <= 0 {
+		input.PageSize = cmd.MaxPerPage
+	}
+	start := input.PageNumber
+	if start <= 0 {
+		start = 1
+	}
+
+	w := cmd.NewPageWriter(out, &c.JSONOutput, func(rs []*fastly.PrivateKey) {
+		if c.Globals.Verbose() {
+			printVerbose(out, rs)
+			return
+		}
+		_ = c.printSummary(out, rs)
+	})
+
+	page := func(n int) ([]*fastly.PrivateKey, error) {
+		i := *input
+		i.PageNumber = start + n
+		return c.Globals.APIClient.ListPrivateKeys(&i)
+	}
+	if err := cmd.FetchNumberedPages(c.Limit, input.PageSize, page, w.Write); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	return w.Close()
+}
@@ -191,6 +191,54 @@ func TestList(t *testing.T) {
 			Args:       args("tls-platform list --verbose"),
 			WantOutput: "\nID: " + mockResponseID + "\nCreated at: 2021-06-15 23:00:00 +0000 UTC\nUpdated at: 2021-06-15 23:00:00 +0000 UTC\nReplace: true\n",
 		},
+		{
+			Name: "validate --all fetches pages until a short page",
+			API: mock.API{
+				ListBulkCertificatesFn: func(i *fastly.ListBulkCertificatesInput) ([]*fastly.BulkCertificate, error) {
+					// Two full pages of two certificates, then a page of one.
+					n := 2
+					if i.PageNumber == 3 {
+						n = 1
+					}
+					var cs []*fastly.BulkCertificate
+					for j := 0; j < n; j++ {
+						cs = append(cs, &fastly.BulkCertificate{ID: fmt.Sprintf("%d-%d", i.PageNumber, j)})
+					}
+					return cs, nil
+				},
+			},
+			Args: args("tls-platform list --all --per-page 2 --json --fields ID"),
+			WantOutput: `[
+  {
+    "ID": "1-0"
+  },
+  {
+    "ID": "1-1"
+  },
+  {
+    "ID": "2-0"
+  },
+  {
+    "ID": "2-1"
+  },
+  {
+    "ID": "3-0"
+  }
+]`,
+		},
+		{
+			Name: "validate --limit stops fetching pages",
+			API: mock.API{
+				ListBulkCertificatesFn: func(i *fastly.ListBulkCertificatesInput) ([]*fastly.BulkCertificate, error) {
+					if i.PageNumber > 1 {
+						return nil, testutil.Err
+					}
+					return []*fastly.BulkCertificate{{ID: "a"}, {ID: "b"}}, nil
+				},
+			},
+			Args:       args("tls-platform list --limit 1 --per-page 2"),
+			WantOutput: "ID  REPLACE  NOT BEFORE  NOT AFTER  CREATED\na   false    <nil>       <nil>      <nil>\n",
+		},
 	}
 
 	for testcaseIdx := range scenarios {
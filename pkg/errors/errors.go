@@ -11,10 +11,6 @@ var ErrSignalInterrupt = fmt.Errorf("a SIGINT was received")
 // ErrSignalKilled means a SIGTERM was received.
 var ErrSignalKilled = fmt.Errorf("a SIGTERM was received")
 
-// ErrViceroyRestart means the viceroy binary needs to be restarted due to a
-// file modification noticed while running `compute serve --watch`.
-var ErrViceroyRestart = fmt.Errorf("a RESTART was initiated")
-
 // ErrIncompatibleServeFlags means no --skip-build can't be used with --watch
 // because it defeats the purpose of --watch which is designed to restart
 // Viceroy whenever changes are detected (those changes would not be seen if we
@@ -114,6 +110,21 @@ var ErrInvalidVerboseJSONCombo = RemediationError{
 	Remediation: "Use either --verbose or --json, not both.",
 }
 
+// ErrInvalidServicesCombo means the user provided both a --services flag and
+// a --service-id or --service-name flag which are mutually exclusive
+// behaviours.
+var ErrInvalidServicesCombo = RemediationError{
+	Inner:       fmt.Errorf("invalid flag combination, --services and --service-id/--service-name"),
+	Remediation: "Use either --services or --service-id/--service-name, not both.",
+}
+
+// ErrInvalidCanaryPlanCombo means the user provided both a --canary and a
+// --plan flag which are mutually exclusive behaviours.
+var ErrInvalidCanaryPlanCombo = RemediationError{
+	Inner:       fmt.Errorf("invalid flag combination, --canary and --plan"),
+	Remediation: "Use either --canary or --plan, not both.",
+}
+
 // ErrInvalidDeleteAllJSONKeyCombo means the user provided both a --all and
 // --json flag which are mutually exclusive behaviours.
 var ErrInvalidDeleteAllJSONKeyCombo = RemediationError{
@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
@@ -34,7 +35,13 @@ type Streaming struct {
 	// Output is where to write output (e.g. stdout)
 	Output io.Writer
 	// Process is the process to terminal if signal received.
+	//
+	// NOTE: It's set by Exec whilst Signal may be called from another
+	// goroutine, so both access it while holding mu.
 	Process *os.Process
+	// pending is a signal received before the process started, which is sent
+	// to it once it has.
+	pending os.Signal
 	// SignalCh is a channel handling signal events.
 	SignalCh chan os.Signal
 	// Spinner is a specific spinner instance.
@@ -45,6 +52,8 @@ type Streaming struct {
 	Timeout time.Duration
 	// Verbose outputs additional information.
 	Verbose bool
+
+	mu sync.Mutex
 }
 
 // MonitorSignals spawns a goroutine that configures signal handling so that
@@ -125,7 +134,13 @@ func (s *Streaming) Exec() error {
 	// Store off os.Process so it can be killed by signal listener.
 	//
 	// NOTE: cmd.Process is nil until exec.Start() returns successfully.
+	s.mu.Lock()
 	s.Process = cmd.Process
+	pending := s.pending
+	s.mu.Unlock()
+	if pending != nil {
+		_ = cmd.Process.Signal(pending)
+	}
 
 	if err := cmd.Wait(); err != nil {
 		text.Output(output, divider)
@@ -158,14 +173,17 @@ func (s *Streaming) Exec() error {
 }
 
 // Signal enables spawned subprocess to accept given signal.
+//
+// If the subprocess hasn't started yet, the signal is sent once it has, so
+// stopping a process that's starting doesn't leave it running.
 func (s *Streaming) Signal(sig os.Signal) error {
-	if s.Process != nil {
-		err := s.Process.Signal(sig)
-		if err != nil {
-			return err
-		}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Process == nil {
+		s.pending = sig
+		return nil
 	}
-	return nil
+	return s.Process.Signal(sig)
 }
 
 // CommandOpts are arguments for executing a streaming command.
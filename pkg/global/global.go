@@ -123,6 +123,7 @@ type Flags struct {
 	AcceptDefaults bool
 	AutoYes        bool
 	Endpoint       string
+	Format         string
 	NonInteractive bool
 	Profile        string
 	Quiet          bool
@@ -16,6 +16,8 @@ import (
 type Data struct {
 	File File
 	Flag Flag
+
+	env string // the environment applied by ApplyEnv().
 }
 
 // Authors yields an Authors.
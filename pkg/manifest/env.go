@@ -0,0 +1,231 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	toml "github.com/pelletier/go-toml"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+)
+
+// validEnv matches the environment names accepted by the --env flag.
+var validEnv = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// EnvFilename returns the name of the manifest overlay file for the given
+// environment (e.g. fastly.stage.toml), or Filename if env is empty.
+func EnvFilename(env string) string {
+	if env == "" {
+		return Filename
+	}
+	return fmt.Sprintf("fastly.%s.toml", env)
+}
+
+// ApplyEnv merges the manifest overlay for the environment given by the --env
+// flag (see EnvFilename) into the manifest file data. It does nothing if the
+// flag isn't set or the overlay has already been applied.
+//
+// The overlay only needs to contain the settings that differ from the
+// fastly.toml:
+//
+//   - service_id is always taken from the overlay, so an environment never
+//     deploys to the service of another environment.
+//   - [scripts] fields replace those of the fastly.toml, except env_vars which
+//     are merged by variable name.
+//   - [setup] resources replace the resource of the same name.
+//   - [local_server] backends and stores replace those of the same name.
+func (d *Data) ApplyEnv() error {
+	env := d.Flag.Env
+	if env == "" || d.env == env {
+		return nil
+	}
+	if !validEnv.MatchString(env) {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid environment name '%s'", env),
+			Remediation: "Environment names may only contain letters, numbers, hyphens and underscores (e.g. --env stage).",
+		}
+	}
+
+	overlay, err := readEnv(env)
+	if err != nil {
+		return err
+	}
+
+	d.File.ServiceID = overlay.ServiceID
+	d.File.Scripts = mergeScripts(d.File.Scripts, overlay.Scripts)
+	d.File.Setup = Setup{
+		Backends:     mergeResources(d.File.Setup.Backends, overlay.Setup.Backends),
+		ConfigStores: mergeResources(d.File.Setup.ConfigStores, overlay.Setup.ConfigStores),
+		Loggers:      mergeResources(d.File.Setup.Loggers, overlay.Setup.Loggers),
+		ObjectStores: mergeResources(d.File.Setup.ObjectStores, overlay.Setup.ObjectStores),
+		KVStores:     mergeResources(d.File.Setup.KVStores, overlay.Setup.KVStores),
+		SecretStores: mergeResources(d.File.Setup.SecretStores, overlay.Setup.SecretStores),
+	}
+	d.File.LocalServer = mergeLocalServer(d.File.LocalServer, overlay.LocalServer)
+	d.env = env
+	return nil
+}
+
+// readEnv reads the manifest overlay for the environment.
+func readEnv(env string) (File, error) {
+	var overlay File
+	fpath := EnvFilename(env)
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable.
+	// Disabling as the environment name is validated by Data.ApplyEnv().
+	/* #nosec */
+	tree, err := toml.LoadFile(fpath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return overlay, fsterr.RemediationError{
+				Inner:       fmt.Errorf("error reading %s: %w", fpath, err),
+				Remediation: fmt.Sprintf("Create a %s manifest containing the settings for the '%s' environment that differ from the %s. If it doesn't set a service_id, a new service is created on the first deploy and its ID saved to the file.", fpath, env, Filename),
+			}
+		}
+		return overlay, fmt.Errorf("error reading %s: %w", fpath, err)
+	}
+	if err := tree.Unmarshal(&overlay); err != nil {
+		return overlay, fmt.Errorf("error parsing %s: %w", fpath, err)
+	}
+	return overlay, nil
+}
+
+// EnvLocalServer returns the [local_server] section of the manifest overlay
+// for the environment (rather than the section merged by Data.ApplyEnv).
+func EnvLocalServer(env string) (LocalServer, error) {
+	overlay, err := readEnv(env)
+	return overlay.LocalServer, err
+}
+
+// WriteEnvLocalServer replaces the [local_server] section of the manifest
+// overlay for the environment, leaving the rest of the file as is.
+func WriteEnvLocalServer(env string, ls LocalServer) error {
+	fpath := EnvFilename(env)
+
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable.
+	// Disabling as the environment name is validated by Data.ApplyEnv().
+	/* #nosec */
+	tree, err := toml.LoadFile(fpath)
+	if err != nil {
+		return err
+	}
+	b, err := toml.Marshal(ls)
+	if err != nil {
+		return err
+	}
+	section, err := toml.LoadBytes(b)
+	if err != nil {
+		return err
+	}
+	tree.Set("local_server", section)
+
+	s, err := tree.ToTomlString()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(fpath, []byte(s), FilePermissions)
+}
+
+// Env returns the environment whose manifest overlay has been applied (or an
+// empty string if none has).
+func (d *Data) Env() string {
+	return d.env
+}
+
+// WriteEnvServiceID sets the service_id in the manifest overlay for the
+// environment, leaving the rest of the file as is.
+func WriteEnvServiceID(env, serviceID string) error {
+	fpath := EnvFilename(env)
+
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable.
+	// Disabling as the environment name is validated by Data.ApplyEnv().
+	/* #nosec */
+	tree, err := toml.LoadFile(fpath)
+	if err != nil {
+		return err
+	}
+	tree.Set("service_id", serviceID)
+
+	s, err := tree.ToTomlString()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(fpath, []byte(s), FilePermissions)
+}
+
+// mergeLocalServer layers the overlay [local_server] on top of the base.
+func mergeLocalServer(base, overlay LocalServer) LocalServer {
+	base.Backends = mergeResources(base.Backends, overlay.Backends)
+	base.ConfigStores = mergeResources(base.ConfigStores, overlay.ConfigStores)
+	base.KVStores = mergeResources(base.KVStores, overlay.KVStores)
+	base.SecretStores = mergeResources(base.SecretStores, overlay.SecretStores)
+	if overlay.ViceroyVersion != "" {
+		base.ViceroyVersion = overlay.ViceroyVersion
+	}
+	return base
+}
+
+// mergeScripts layers the overlay [scripts] on top of the base.
+func mergeScripts(base, overlay Scripts) Scripts {
+	if overlay.Build != "" {
+		base.Build = overlay.Build
+	}
+	if overlay.PostBuild != "" {
+		base.PostBuild = overlay.PostBuild
+	}
+	if overlay.PostInit != "" {
+		base.PostInit = overlay.PostInit
+	}
+	if overlay.Test != "" {
+		base.Test = overlay.Test
+	}
+	base.EnvVars = mergeEnvVars(base.EnvVars, overlay.EnvVars)
+	return base
+}
+
+// mergeEnvVars merges two lists of KEY=VALUE pairs, with the values of the
+// overlay replacing any of the same name in the base.
+func mergeEnvVars(base, overlay []string) []string {
+	if len(overlay) == 0 {
+		return base
+	}
+	key := func(v string) string {
+		return strings.SplitN(v, "=", 2)[0]
+	}
+	index := make(map[string]int, len(base))
+	merged := make([]string, 0, len(base)+len(overlay))
+	for _, v := range base {
+		index[key(v)] = len(merged)
+		merged = append(merged, v)
+	}
+	for _, v := range overlay {
+		if i, ok := index[key(v)]; ok {
+			merged[i] = v
+			continue
+		}
+		index[key(v)] = len(merged)
+		merged = append(merged, v)
+	}
+	return merged
+}
+
+// mergeResources merges two sets of [setup] resources, with the resources of
+// the overlay replacing any of the same name in the base.
+func mergeResources[T any](base, overlay map[string]T) map[string]T {
+	if len(overlay) == 0 {
+		return base
+	}
+	merged := make(map[string]T, len(base)+len(overlay))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overlay {
+		merged[k] = v
+	}
+	return merged
+}
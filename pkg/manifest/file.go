@@ -196,4 +196,6 @@ type Scripts struct {
 	PostBuild string `toml:"post_build,omitempty"`
 	// PostInit is executed after the init step.
 	PostInit string `toml:"post_init,omitempty"`
+	// Test is a custom test script, run by `compute test`.
+	Test string `toml:"test,omitempty"`
 }
@@ -7,4 +7,7 @@ type Flag struct {
 	Description string
 	Authors     []string
 	ServiceID   string
+	// Env is the environment whose manifest overlay (e.g. fastly.stage.toml)
+	// is merged into the manifest (see Data.ApplyEnv).
+	Env string
 }
@@ -298,3 +298,148 @@ func TestManifestPersistsLocalServerSection(t *testing.T) {
 		t.Fatalf("testing section between original and updated fastly.toml do not match (-want +got):\n%s", diff)
 	}
 }
+
+func TestDataApplyEnv(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(pwd)
+
+	overlay := `service_id = "stage-123"
+
+[scripts]
+build = "make build-stage"
+env_vars = ["API_HOST=stage.example.com", "DEBUG=1"]
+
+[setup.backends.origin]
+address = "stage.example.com"
+port = 443
+
+[local_server.backends.origin]
+url = "https://stage.example.com"
+`
+	if err := os.WriteFile("fastly.stage.toml", []byte(overlay), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	base := func() manifest.Data {
+		return manifest.Data{
+			File: manifest.File{
+				Name:      "app",
+				ServiceID: "prod-456",
+				Scripts: manifest.Scripts{
+					Build:     "make build",
+					PostBuild: "echo done",
+					EnvVars:   []string{"API_HOST=www.example.com", "LOG_LEVEL=info"},
+				},
+				Setup: manifest.Setup{
+					Backends: map[string]*manifest.SetupBackend{
+						"origin": {Address: "www.example.com", Port: 443},
+						"assets": {Address: "assets.example.com", Port: 443},
+					},
+				},
+				LocalServer: manifest.LocalServer{
+					Backends: map[string]manifest.LocalBackend{
+						"origin": {URL: "https://www.example.com"},
+						"assets": {URL: "https://assets.example.com"},
+					},
+				},
+			},
+		}
+	}
+
+	d := base()
+	d.Flag.Env = "stage"
+	if err := d.ApplyEnv(); err != nil {
+		t.Fatal(err)
+	}
+	want := manifest.File{
+		Name:      "app",
+		ServiceID: "stage-123",
+		Scripts: manifest.Scripts{
+			Build:     "make build-stage",
+			PostBuild: "echo done",
+			EnvVars:   []string{"API_HOST=stage.example.com", "LOG_LEVEL=info", "DEBUG=1"},
+		},
+		Setup: manifest.Setup{
+			Backends: map[string]*manifest.SetupBackend{
+				"origin": {Address: "stage.example.com", Port: 443},
+				"assets": {Address: "assets.example.com", Port: 443},
+			},
+		},
+		LocalServer: manifest.LocalServer{
+			Backends: map[string]manifest.LocalBackend{
+				"origin": {URL: "https://stage.example.com"},
+				"assets": {URL: "https://assets.example.com"},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, d.File, cmp.AllowUnexported(manifest.File{})); diff != "" {
+		t.Fatalf("unexpected manifest (-want +got):\n%s", diff)
+	}
+	if d.Env() != "stage" {
+		t.Fatalf("want env 'stage', got '%s'", d.Env())
+	}
+
+	// No environment leaves the manifest as is.
+	d = base()
+	if err := d.ApplyEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if d.File.ServiceID != "prod-456" || d.Env() != "" {
+		t.Fatalf("unexpected manifest: %+v", d.File)
+	}
+
+	// A missing overlay is an error rather than silently using the fastly.toml.
+	d = base()
+	d.Flag.Env = "prod"
+	testutil.AssertRemediationErrorContains(t, d.ApplyEnv(), "Create a fastly.prod.toml manifest")
+
+	d.Flag.Env = "../stage"
+	testutil.AssertErrorContains(t, d.ApplyEnv(), "invalid environment name '../stage'")
+
+	// The Service ID is saved to the overlay, preserving its other settings.
+	if err := manifest.WriteEnvServiceID("stage", "stage-789"); err != nil {
+		t.Fatal(err)
+	}
+	d = base()
+	d.Flag.Env = "stage"
+	if err := d.ApplyEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if d.File.ServiceID != "stage-789" || d.File.Scripts.Build != "make build-stage" {
+		t.Fatalf("unexpected manifest: %+v", d.File)
+	}
+
+	// The [local_server] is saved to the overlay, preserving its other
+	// settings, and read back without the sections of the fastly.toml.
+	ls, err := manifest.EnvLocalServer("stage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ls.ConfigStores = map[string]manifest.LocalConfigStore{
+		"settings": {File: "local-stores/config/settings.json", Format: "json"},
+	}
+	if err := manifest.WriteEnvLocalServer("stage", ls); err != nil {
+		t.Fatal(err)
+	}
+	got, err := manifest.EnvLocalServer("stage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(ls, got); diff != "" {
+		t.Fatalf("unexpected [local_server] (-want +got):\n%s", diff)
+	}
+	d = base()
+	d.Flag.Env = "stage"
+	if err := d.ApplyEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if d.File.ServiceID != "stage-789" || len(d.File.LocalServer.ConfigStores) != 1 || d.File.LocalServer.Backends["origin"].URL != "https://stage.example.com" {
+		t.Fatalf("unexpected manifest: %+v", d.File)
+	}
+}
@@ -14,6 +14,14 @@ func APIClient(a API) func(string, string) (api.Interface, error) {
 	}
 }
 
+// RTSClient takes a mock.RealtimeStats and returns an app.RTSClientFactory
+// that uses that mock, ignoring the token. It should only be used for tests.
+func RTSClient(r RealtimeStats) func(string) (api.RealtimeStatsInterface, error) {
+	return func(token string) (api.RealtimeStatsInterface, error) {
+		return r, nil
+	}
+}
+
 type mockHTTPClient struct {
 	// index keeps track of which response/error to return
 	index int
@@ -0,0 +1,21 @@
+package mock
+
+import (
+	"github.com/fastly/go-fastly/v8/fastly"
+
+	"github.com/fastly/cli/pkg/api"
+)
+
+// RealtimeStats is a mock implementation of api.RealtimeStatsInterface that's
+// used for testing.
+type RealtimeStats struct {
+	GetRealtimeStatsJSONFn func(i *fastly.GetRealtimeStatsInput, dst any) error
+}
+
+// GetRealtimeStatsJSON implements api.RealtimeStatsInterface.
+func (m RealtimeStats) GetRealtimeStatsJSON(i *fastly.GetRealtimeStatsInput, dst any) error {
+	return m.GetRealtimeStatsJSONFn(i, dst)
+}
+
+// Ensure that RealtimeStats satisfies api.RealtimeStatsInterface.
+var _ api.RealtimeStatsInterface = RealtimeStats{}
@@ -0,0 +1,376 @@
+package spec
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/undo"
+)
+
+// Action is the type of change made to a resource.
+type Action string
+
+const (
+	// ActionCreate indicates a resource is to be created.
+	ActionCreate Action = "create"
+	// ActionUpdate indicates a resource is to be updated.
+	ActionUpdate Action = "update"
+	// ActionDelete indicates a resource is to be deleted.
+	ActionDelete Action = "delete"
+)
+
+// Change describes a difference between two versions of a resource.
+type Change struct {
+	Action Action
+	Kind   Kind
+	Name   string
+	// From is the current resource (nil when the resource is to be created).
+	From Resource
+	// To is the desired resource (nil when the resource is to be deleted).
+	To Resource
+	// Fields are the names of the changed fields (only set for updates).
+	Fields []string
+}
+
+// String returns a human readable summary of the change.
+func (c Change) String() string {
+	return fmt.Sprintf("%s %s '%s'", c.Action, c.Kind.Description, c.Name)
+}
+
+// Live reports whether the change is to the versionless content of an
+// existing resource (e.g. the items of a dictionary), which takes effect
+// immediately on every version of the service, including the active version.
+func (c Change) Live() bool {
+	if c.Action != ActionUpdate || c.Kind.Nested == "" {
+		return false
+	}
+	for _, f := range c.Fields {
+		if f == c.Kind.Nested {
+			return true
+		}
+	}
+	return false
+}
+
+// Fetch returns a spec describing the given kinds of resources configured on
+// the service version.
+func Fetch(c api.Interface, serviceID string, version int, kinds []Kind) (*Spec, error) {
+	s := &Spec{}
+	for _, k := range kinds {
+		rs, err := k.List(c, serviceID, version)
+		if err != nil {
+			return nil, fmt.Errorf("error listing %s resources: %w", k.Description, err)
+		}
+		for i, r := range rs {
+			if k.Nested != "" {
+				if err := fetchNested(c, serviceID, k, r); err != nil {
+					return nil, err
+				}
+			}
+			rs[i] = r.only(k)
+		}
+		s.set(k, rs)
+	}
+	return s, nil
+}
+
+// Diff returns the changes required to turn the `from` spec into the `to`
+// spec. Only the sections managed by the `to` spec are compared, and only the
+// fields set in the `to` spec are considered.
+//
+// Creations and updates are ordered by section, while deletions follow them
+// in reverse section order, so dependent resources are handled correctly.
+func Diff(from, to *Spec) []Change {
+	return diff(from, to, false)
+}
+
+// Compare returns the differences between two complete specs, such as those
+// fetched from two versions of a service. Unlike Diff, a field set in the
+// `from` spec but missing from the `to` spec is considered changed.
+func Compare(from, to *Spec) []Change {
+	return diff(from, to, true)
+}
+
+// diff implements Diff and Compare.
+func diff(from, to *Spec, exhaustive bool) []Change {
+	var (
+		changes []Change
+		deletes [][]Change
+	)
+	for _, section := range to.sections(exhaustive) {
+		k := section.Kind
+		current := make(map[string]Resource)
+		for _, r := range from.get(k) {
+			current[r.Name()] = r
+		}
+
+		for _, r := range section.Resources {
+			name := r.Name()
+			cr, ok := current[name]
+			if !ok {
+				changes = append(changes, Change{Action: ActionCreate, Kind: k, Name: name, To: r})
+				continue
+			}
+			if fields := changedFields(k, cr, r, exhaustive); len(fields) > 0 {
+				changes = append(changes, Change{Action: ActionUpdate, Kind: k, Name: name, From: cr, To: r, Fields: fields})
+			}
+			delete(current, name)
+		}
+
+		var sectionDeletes []Change
+		for name, r := range current {
+			sectionDeletes = append(sectionDeletes, Change{Action: ActionDelete, Kind: k, Name: name, From: r})
+		}
+		sort.Slice(sectionDeletes, func(i, j int) bool {
+			return sectionDeletes[i].Name < sectionDeletes[j].Name
+		})
+		deletes = append(deletes, sectionDeletes)
+	}
+
+	for i := len(deletes) - 1; i >= 0; i-- {
+		changes = append(changes, deletes[i]...)
+	}
+	return changes
+}
+
+// Apply makes the changes to the service version.
+//
+// A compensating action is pushed onto the undo stack for every change that
+// succeeds, so unwinding the stack restores the original configuration.
+func Apply(c api.Interface, serviceID string, version int, changes []Change, stack undo.Stacker) error {
+	for _, ch := range changes {
+		if err := apply(c, serviceID, version, ch, stack); err != nil {
+			return fmt.Errorf("error attempting to %s: %w", ch, err)
+		}
+	}
+	return nil
+}
+
+// apply makes a single change to the service version.
+func apply(c api.Interface, serviceID string, version int, ch Change, stack undo.Stacker) error {
+	k, name := ch.Kind, ch.Name
+
+	switch ch.Action {
+	case ActionCreate:
+		if err := k.Create(c, serviceID, version, ch.To.without(k.Nested)); err != nil {
+			return err
+		}
+		stack.Push(func() error {
+			return k.Delete(c, serviceID, version, name)
+		})
+		if _, ok := ch.To[k.Nested]; ok && k.Sync != nil {
+			return k.Sync(c, serviceID, version, name, nil, ch.To, stack)
+		}
+
+	case ActionUpdate:
+		var (
+			to, from Resource = Resource{}, Resource{}
+			nested   bool
+		)
+		for _, f := range ch.Fields {
+			if f == k.Nested {
+				nested = true
+				continue
+			}
+			to[f] = ch.To[f]
+			if v, ok := ch.From[f]; ok {
+				from[f] = v
+			} else {
+				from[f] = reflect.Zero(reflect.TypeOf(ch.To[f])).Interface()
+			}
+		}
+		if len(to) > 0 {
+			if err := k.Update(c, serviceID, version, name, to); err != nil {
+				return err
+			}
+			stack.Push(func() error {
+				return k.Update(c, serviceID, version, name, from)
+			})
+		}
+		if nested && k.Sync != nil {
+			if err := k.Sync(c, serviceID, version, name, ch.From, ch.To, stack); err != nil {
+				return err
+			}
+		}
+
+	case ActionDelete:
+		if err := k.Delete(c, serviceID, version, name); err != nil {
+			return err
+		}
+		stack.Push(func() error {
+			if err := k.Create(c, serviceID, version, ch.From.without(k.Nested)); err != nil {
+				return err
+			}
+			if k.Sync != nil {
+				// NOTE: An undo isn't itself undone, so its stack is discarded.
+				return k.Sync(c, serviceID, version, name, nil, ch.From, undo.NewStack())
+			}
+			return nil
+		})
+	}
+
+	return nil
+}
+
+// changedFields returns the sorted names of the fields set on the desired
+// resource that differ from the current resource. When exhaustive, the fields
+// only set on the current resource are also compared.
+func changedFields(k Kind, current, desired Resource, exhaustive bool) []string {
+	names := make(map[string]bool)
+	for f := range desired {
+		names[f] = true
+	}
+	if exhaustive {
+		for f := range current {
+			names[f] = true
+		}
+	}
+
+	var fields []string
+	for f := range names {
+		if f == k.Nested {
+			if !nestedEqual(k, current, desired) {
+				fields = append(fields, f)
+			}
+			continue
+		}
+		if !Equal(current[f], desired[f]) {
+			fields = append(fields, f)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// nestedEqual reports whether the nested content of both resources match.
+func nestedEqual(k Kind, a, b Resource) bool {
+	switch k.Name {
+	case KindDictionary:
+		return reflect.DeepEqual(Items(a), Items(b))
+	case KindACL:
+		ea, errA := Entries(a)
+		eb, errB := Entries(b)
+		if errA != nil || errB != nil || len(ea) != len(eb) {
+			return false
+		}
+		for key, e := range ea {
+			if o, ok := eb[key]; !ok || o.Comment != e.Comment || o.Negated != e.Negated {
+				return false
+			}
+		}
+		return true
+	}
+	return true
+}
+
+// Equal reports whether two field values are equivalent.
+//
+// Values are compared by their textual representation as documents decode
+// numbers differently (e.g. JSON decodes all numbers as float64). A missing
+// value is equivalent to the zero value of its type.
+func Equal(a, b any) bool {
+	return textual(a) == textual(b)
+}
+
+// textual returns the textual representation of a field value.
+func textual(v any) string {
+	if v == nil {
+		return ""
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return ""
+		}
+		rv = rv.Elem()
+	}
+	if rv.IsZero() {
+		return ""
+	}
+	return fmt.Sprint(rv.Interface())
+}
+
+// get returns the resources of the given kind.
+func (s *Spec) get(k Kind) []Resource {
+	switch k.Name {
+	case KindDomain:
+		return s.Domains
+	case KindHealthCheck:
+		return s.HealthChecks
+	case KindBackend:
+		return s.Backends
+	case KindDictionary:
+		return s.Dictionaries
+	case KindACL:
+		return s.ACLs
+	case KindVCL:
+		return s.VCLs
+	case KindSnippet:
+		return s.Snippets
+	case KindRateLimiter:
+		return s.RateLimiters
+	}
+	for _, p := range LoggingProviders() {
+		if k.Name == LoggingKind(p) && s.Logging != nil {
+			return s.Logging[p]
+		}
+	}
+	return nil
+}
+
+// set assigns the resources of the given kind.
+func (s *Spec) set(k Kind, rs []Resource) {
+	switch k.Name {
+	case KindDomain:
+		s.Domains = rs
+	case KindHealthCheck:
+		s.HealthChecks = rs
+	case KindBackend:
+		s.Backends = rs
+	case KindDictionary:
+		s.Dictionaries = rs
+	case KindACL:
+		s.ACLs = rs
+	case KindVCL:
+		s.VCLs = rs
+	case KindSnippet:
+		s.Snippets = rs
+	case KindRateLimiter:
+		s.RateLimiters = rs
+	default:
+		for _, p := range LoggingProviders() {
+			if k.Name == LoggingKind(p) {
+				if s.Logging == nil {
+					s.Logging = make(map[string][]Resource)
+				}
+				if len(rs) > 0 {
+					s.Logging[p] = rs
+				}
+			}
+		}
+	}
+}
+
+// only returns a copy of the resource restricted to the fields of the kind.
+func (r Resource) only(k Kind) Resource {
+	o := Resource{}
+	for f, v := range r {
+		if k.Fields[f] || f == k.Nested {
+			o[f] = v
+		}
+	}
+	return o
+}
+
+// without returns a copy of the resource without the given field.
+func (r Resource) without(field string) Resource {
+	o := Resource{}
+	for f, v := range r {
+		if f != field {
+			o[f] = v
+		}
+	}
+	return o
+}
@@ -0,0 +1,3 @@
+// Package spec contains abstractions for describing the configuration of a
+// Fastly service version as a single structured document.
+package spec
@@ -0,0 +1,351 @@
+package spec
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/undo"
+)
+
+// The following are the names of the non-logging resource kinds.
+const (
+	KindACL         = "acl"
+	KindBackend     = "backend"
+	KindDictionary  = "dictionary"
+	KindDomain      = "domain"
+	KindHealthCheck = "healthcheck"
+	KindRateLimiter = "ratelimiter"
+	KindSnippet     = "snippet"
+	KindVCL         = "vcl"
+)
+
+// Kind describes how to manage a type of service version resource.
+type Kind struct {
+	// Name identifies the kind (e.g. `backend` or `logging/syslog`).
+	Name string
+	// Description is a human readable name for the kind.
+	Description string
+	// Fields is the set of API field names accepted when creating the resource.
+	Fields map[string]bool
+	// Nested is the name of the field holding versionless content that is
+	// managed separately from the resource itself (e.g. dictionary items).
+	Nested string
+	// Secrets is the set of API field names whose values are credentials
+	// (e.g. passwords, or collector URLs that embed a token).
+	Secrets map[string]bool
+
+	// List returns all resources of this kind for the service version.
+	List func(c api.Interface, serviceID string, version int) ([]Resource, error)
+	// Create creates the resource.
+	Create func(c api.Interface, serviceID string, version int, r Resource) error
+	// Update updates the named resource with the given fields.
+	Update func(c api.Interface, serviceID string, version int, name string, r Resource) error
+	// Delete deletes the named resource.
+	Delete func(c api.Interface, serviceID string, version int, name string) error
+	// Sync reconciles the nested content of the named resource, pushing a
+	// compensating action onto the undo stack for each change that succeeds.
+	Sync func(c api.Interface, serviceID string, version int, name string, from, to Resource, stack undo.Stacker) error
+}
+
+// metadata are the API response fields that describe the resource rather
+// than configure it, and so are never part of a spec.
+var metadata = map[string]bool{
+	"created_at": true,
+	"deleted_at": true,
+	"service_id": true,
+	"updated_at": true,
+	"version":    true,
+}
+
+// Kinds is the registry of every resource kind a spec can describe.
+var Kinds = map[string]Kind{}
+
+// loggingPrefix namespaces the logging provider kinds.
+const loggingPrefix = "logging/"
+
+// LoggingKind returns the kind name for the given logging provider.
+func LoggingKind(provider string) string {
+	return loggingPrefix + provider
+}
+
+// LoggingProviders returns the sorted names of all logging providers.
+func LoggingProviders() []string {
+	var providers []string
+	for k := range Kinds {
+		if p, ok := strings.CutPrefix(k, loggingPrefix); ok {
+			providers = append(providers, p)
+		}
+	}
+	sort.Strings(providers)
+	return providers
+}
+
+// AllKinds returns every kind in the order their sections are created.
+func AllKinds() []Kind {
+	kinds := make([]Kind, 0, len(Kinds))
+	for _, name := range []string{KindDomain, KindHealthCheck, KindBackend, KindDictionary, KindACL, KindVCL, KindSnippet, KindRateLimiter} {
+		kinds = append(kinds, Kinds[name])
+	}
+	for _, p := range LoggingProviders() {
+		kinds = append(kinds, Kinds[LoggingKind(p)])
+	}
+	return kinds
+}
+
+func init() {
+	register := func(k Kind) {
+		Kinds[k.Name] = k
+	}
+
+	register(newKind(KindDomain, "domain", api.Interface.ListDomains, api.Interface.CreateDomain, api.Interface.UpdateDomain, api.Interface.DeleteDomain))
+	register(newKind(KindHealthCheck, "healthcheck", api.Interface.ListHealthChecks, api.Interface.CreateHealthCheck, api.Interface.UpdateHealthCheck, api.Interface.DeleteHealthCheck))
+	register(newKind(KindBackend, "backend", api.Interface.ListBackends, api.Interface.CreateBackend, api.Interface.UpdateBackend, api.Interface.DeleteBackend))
+	register(newKind(KindVCL, "VCL", api.Interface.ListVCLs, api.Interface.CreateVCL, api.Interface.UpdateVCL, api.Interface.DeleteVCL))
+	register(newKind(KindSnippet, "snippet", api.Interface.ListSnippets, api.Interface.CreateSnippet, api.Interface.UpdateSnippet, api.Interface.DeleteSnippet))
+
+	dictionary := newKind(KindDictionary, "dictionary", api.Interface.ListDictionaries, api.Interface.CreateDictionary, api.Interface.UpdateDictionary, api.Interface.DeleteDictionary)
+	dictionary.Nested = "items"
+	dictionary.Sync = syncDictionaryItems
+	register(dictionary)
+
+	acl := newKind(KindACL, "ACL", api.Interface.ListACLs, api.Interface.CreateACL, api.Interface.UpdateACL, api.Interface.DeleteACL)
+	acl.Nested = "entries"
+	acl.Sync = syncACLEntries
+	register(acl)
+
+	register(rateLimiterKind())
+
+	// NOTE: Besides the credential fields common to every kind, some
+	// providers hold credentials in fields that are harmless elsewhere (e.g.
+	// the collector URLs of Heroku, Logshuttle and Sumologic embed a token).
+	logging := func(provider, desc string, k Kind, secrets ...string) {
+		k.Name = LoggingKind(provider)
+		k.Description = desc + " logging endpoint"
+		for _, field := range secrets {
+			k.Secrets[field] = true
+		}
+		register(k)
+	}
+	logging("azureblob", "Azure Blob Storage", newKind("", "", api.Interface.ListBlobStorages, api.Interface.CreateBlobStorage, api.Interface.UpdateBlobStorage, api.Interface.DeleteBlobStorage))
+	logging("bigquery", "BigQuery", newKind("", "", api.Interface.ListBigQueries, api.Interface.CreateBigQuery, api.Interface.UpdateBigQuery, api.Interface.DeleteBigQuery))
+	logging("cloudfiles", "Cloudfiles", newKind("", "", api.Interface.ListCloudfiles, api.Interface.CreateCloudfiles, api.Interface.UpdateCloudfiles, api.Interface.DeleteCloudfiles))
+	logging("datadog", "Datadog", newKind("", "", api.Interface.ListDatadog, api.Interface.CreateDatadog, api.Interface.UpdateDatadog, api.Interface.DeleteDatadog))
+	logging("digitalocean", "DigitalOcean Spaces", newKind("", "", api.Interface.ListDigitalOceans, api.Interface.CreateDigitalOcean, api.Interface.UpdateDigitalOcean, api.Interface.DeleteDigitalOcean))
+	logging("elasticsearch", "Elasticsearch", newKind("", "", api.Interface.ListElasticsearch, api.Interface.CreateElasticsearch, api.Interface.UpdateElasticsearch, api.Interface.DeleteElasticsearch))
+	logging("ftp", "FTP", newKind("", "", api.Interface.ListFTPs, api.Interface.CreateFTP, api.Interface.UpdateFTP, api.Interface.DeleteFTP))
+	logging("gcs", "GCS", newKind("", "", api.Interface.ListGCSs, api.Interface.CreateGCS, api.Interface.UpdateGCS, api.Interface.DeleteGCS))
+	logging("googlepubsub", "Google Cloud Pub/Sub", newKind("", "", api.Interface.ListPubsubs, api.Interface.CreatePubsub, api.Interface.UpdatePubsub, api.Interface.DeletePubsub))
+	logging("heroku", "Heroku", newKind("", "", api.Interface.ListHerokus, api.Interface.CreateHeroku, api.Interface.UpdateHeroku, api.Interface.DeleteHeroku), "url")
+	logging("honeycomb", "Honeycomb", newKind("", "", api.Interface.ListHoneycombs, api.Interface.CreateHoneycomb, api.Interface.UpdateHoneycomb, api.Interface.DeleteHoneycomb))
+	logging("https", "HTTPS", newKind("", "", api.Interface.ListHTTPS, api.Interface.CreateHTTPS, api.Interface.UpdateHTTPS, api.Interface.DeleteHTTPS), "header_value")
+	logging("kafka", "Kafka", newKind("", "", api.Interface.ListKafkas, api.Interface.CreateKafka, api.Interface.UpdateKafka, api.Interface.DeleteKafka))
+	logging("kinesis", "Amazon Kinesis", newKind("", "", api.Interface.ListKinesis, api.Interface.CreateKinesis, api.Interface.UpdateKinesis, api.Interface.DeleteKinesis))
+	logging("logentries", "Logentries", newKind("", "", api.Interface.ListLogentries, api.Interface.CreateLogentries, api.Interface.UpdateLogentries, api.Interface.DeleteLogentries))
+	logging("loggly", "Loggly", newKind("", "", api.Interface.ListLoggly, api.Interface.CreateLoggly, api.Interface.UpdateLoggly, api.Interface.DeleteLoggly))
+	logging("logshuttle", "Logshuttle", newKind("", "", api.Interface.ListLogshuttles, api.Interface.CreateLogshuttle, api.Interface.UpdateLogshuttle, api.Interface.DeleteLogshuttle), "url")
+	logging("newrelic", "New Relic", newKind("", "", api.Interface.ListNewRelic, api.Interface.CreateNewRelic, api.Interface.UpdateNewRelic, api.Interface.DeleteNewRelic))
+	logging("newrelicotlp", "New Relic OTLP", newKind("", "", api.Interface.ListNewRelicOTLP, api.Interface.CreateNewRelicOTLP, api.Interface.UpdateNewRelicOTLP, api.Interface.DeleteNewRelicOTLP))
+	logging("openstack", "OpenStack", newKind("", "", api.Interface.ListOpenstack, api.Interface.CreateOpenstack, api.Interface.UpdateOpenstack, api.Interface.DeleteOpenstack))
+	logging("papertrail", "Papertrail", newKind("", "", api.Interface.ListPapertrails, api.Interface.CreatePapertrail, api.Interface.UpdatePapertrail, api.Interface.DeletePapertrail))
+	logging("s3", "S3", newKind("", "", api.Interface.ListS3s, api.Interface.CreateS3, api.Interface.UpdateS3, api.Interface.DeleteS3))
+	logging("scalyr", "Scalyr", newKind("", "", api.Interface.ListScalyrs, api.Interface.CreateScalyr, api.Interface.UpdateScalyr, api.Interface.DeleteScalyr))
+	logging("sftp", "SFTP", newKind("", "", api.Interface.ListSFTPs, api.Interface.CreateSFTP, api.Interface.UpdateSFTP, api.Interface.DeleteSFTP))
+	logging("splunk", "Splunk", newKind("", "", api.Interface.ListSplunks, api.Interface.CreateSplunk, api.Interface.UpdateSplunk, api.Interface.DeleteSplunk))
+	logging("sumologic", "Sumologic", newKind("", "", api.Interface.ListSumologics, api.Interface.CreateSumologic, api.Interface.UpdateSumologic, api.Interface.DeleteSumologic), "url")
+	logging("syslog", "Syslog", newKind("", "", api.Interface.ListSyslogs, api.Interface.CreateSyslog, api.Interface.UpdateSyslog, api.Interface.DeleteSyslog))
+}
+
+// newKind constructs a Kind from the go-fastly CRUD methods of a resource.
+//
+// NOTE: The go-fastly input structs all follow the same conventions, which
+// allows the service ID, service version and resource name to be assigned
+// by field name, while the remaining fields are decoded from the resource
+// using their `url` struct tags (i.e. the API field names).
+func newKind[T, L, C, U, D any](
+	name, description string,
+	list func(api.Interface, *L) ([]*T, error),
+	create func(api.Interface, *C) (*T, error),
+	update func(api.Interface, *U) (*T, error),
+	remove func(api.Interface, *D) error,
+) Kind {
+	fields := fieldNames(new(C))
+	return Kind{
+		Name:        name,
+		Description: description,
+		Fields:      fields,
+		Secrets:     secretFields(fields),
+		List: func(c api.Interface, serviceID string, version int) ([]Resource, error) {
+			var i L
+			setVersion(&i, serviceID, version)
+			items, err := list(c, &i)
+			if err != nil {
+				return nil, err
+			}
+			rs := make([]Resource, 0, len(items))
+			for _, item := range items {
+				rs = append(rs, toResource(item))
+			}
+			return rs, nil
+		},
+		Create: func(c api.Interface, serviceID string, version int, r Resource) error {
+			var i C
+			if err := decode(r, &i); err != nil {
+				return err
+			}
+			setVersion(&i, serviceID, version)
+			_, err := create(c, &i)
+			return err
+		},
+		Update: func(c api.Interface, serviceID string, version int, name string, r Resource) error {
+			var i U
+			if err := decode(r, &i); err != nil {
+				return err
+			}
+			setVersion(&i, serviceID, version)
+			setField(&i, "Name", name)
+			_, err := update(c, &i)
+			return err
+		},
+		Delete: func(c api.Interface, serviceID string, version int, name string) error {
+			var i D
+			setVersion(&i, serviceID, version)
+			setField(&i, "Name", name)
+			return remove(c, &i)
+		},
+	}
+}
+
+// setVersion assigns the service ID and version to a go-fastly input struct.
+func setVersion(input any, serviceID string, version int) {
+	setField(input, "ServiceID", serviceID)
+	setField(input, "ServiceVersion", version)
+}
+
+// setField assigns the value to the named field of the struct pointer.
+func setField(ptr any, field string, value any) {
+	f := reflect.ValueOf(ptr).Elem().FieldByName(field)
+	v := reflect.ValueOf(value)
+	if f.IsValid() && f.CanSet() && v.Type().AssignableTo(f.Type()) {
+		f.Set(v)
+	}
+}
+
+// fieldNames returns the API field names of a go-fastly input struct.
+func fieldNames(input any) map[string]bool {
+	fields := make(map[string]bool)
+	t := reflect.TypeOf(input).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("url"), ",")[0]
+		if tag != "" && tag != "-" {
+			fields[tag] = true
+		}
+	}
+	return fields
+}
+
+// credentials are the API field names that hold credentials whichever kind of
+// resource they belong to.
+var credentials = []string{
+	"access_key",
+	"password",
+	"sas_token",
+	"secret_key",
+	"ssl_client_key",
+	"tls_client_key",
+	"token",
+}
+
+// secretFields returns the credential fields among the given fields.
+func secretFields(fields map[string]bool) map[string]bool {
+	secrets := make(map[string]bool)
+	for _, field := range credentials {
+		if fields[field] {
+			secrets[field] = true
+		}
+	}
+	return secrets
+}
+
+// decode assigns the resource fields to a go-fastly input struct.
+func decode(r Resource, input any) error {
+	d, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		ErrorUnused:      true,
+		Result:           input,
+		TagName:          "url",
+		WeaklyTypedInput: true,
+	})
+	if err != nil {
+		return err
+	}
+	if err := d.Decode(map[string]any(r)); err != nil {
+		return fmt.Errorf("error decoding '%s': %w", r.Name(), err)
+	}
+	return nil
+}
+
+// toResource converts a go-fastly response struct into a Resource.
+//
+// Metadata and zero values are omitted, while nested structures (e.g. a rate
+// limiter response) are converted into maps.
+func toResource(v any) Resource {
+	r := Resource{}
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		tag := strings.Split(rt.Field(i).Tag.Get("mapstructure"), ",")[0]
+		if tag == "" || tag == "-" || metadata[tag] {
+			continue
+		}
+		if fv, ok := plain(rv.Field(i)); ok {
+			r[tag] = fv
+		}
+	}
+	return r
+}
+
+// plain converts a reflected value into a built-in type, reporting false if
+// the value is nil, a zero value or not representable in a spec.
+func plain(v reflect.Value) (any, bool) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+	if v.IsZero() {
+		return nil, false
+	}
+	if _, ok := v.Interface().(time.Time); ok {
+		return nil, false
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.String:
+		return v.String(), true
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return nil, false
+		}
+		s := make([]string, v.Len())
+		for i := range s {
+			s[i] = v.Index(i).String()
+		}
+		return s, true
+	case reflect.Struct:
+		return map[string]any(toResource(v.Interface())), true
+	}
+	return nil, false
+}
@@ -0,0 +1,408 @@
+package spec
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/fastly/go-fastly/v8/fastly"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/undo"
+)
+
+// Items returns the dictionary items of a dictionary resource.
+func Items(r Resource) map[string]string {
+	items := make(map[string]string)
+	if m, ok := r["items"].(map[string]any); ok {
+		for k, v := range m {
+			items[k] = fmt.Sprint(v)
+		}
+	}
+	if m, ok := r["items"].(map[string]string); ok {
+		for k, v := range m {
+			items[k] = v
+		}
+	}
+	return items
+}
+
+// ACLEntry is a single entry of an ACL resource.
+type ACLEntry struct {
+	Comment string
+	IP      string
+	Negated bool
+	Subnet  *int
+}
+
+// Key uniquely identifies the entry within an ACL.
+func (e ACLEntry) Key() string {
+	if e.Subnet != nil {
+		return fmt.Sprintf("%s/%d", e.IP, *e.Subnet)
+	}
+	return e.IP
+}
+
+// Resource converts the entry into its spec representation.
+func (e ACLEntry) Resource() Resource {
+	r := Resource{"ip": e.IP}
+	if e.Subnet != nil {
+		r["subnet"] = *e.Subnet
+	}
+	if e.Negated {
+		r["negated"] = true
+	}
+	if e.Comment != "" {
+		r["comment"] = e.Comment
+	}
+	return r
+}
+
+// Entries returns the ACL entries of an ACL resource keyed by ACLEntry.Key.
+func Entries(r Resource) (map[string]ACLEntry, error) {
+	entries := make(map[string]ACLEntry)
+
+	var raw []map[string]any
+	switch v := r["entries"].(type) {
+	case nil:
+	case []map[string]any:
+		raw = v
+	case []Resource:
+		for _, e := range v {
+			raw = append(raw, e)
+		}
+	case []any:
+		for _, e := range v {
+			m, ok := e.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("ACL '%s' has an invalid entry: %v", r.Name(), e)
+			}
+			raw = append(raw, m)
+		}
+	default:
+		return nil, fmt.Errorf("ACL '%s' has invalid entries: %v", r.Name(), v)
+	}
+
+	for _, m := range raw {
+		var e ACLEntry
+		e.IP = fmt.Sprint(m["ip"])
+		if v, ok := m["subnet"]; ok {
+			i, err := strconv.Atoi(fmt.Sprint(v))
+			if err != nil {
+				return nil, fmt.Errorf("ACL '%s' has an invalid subnet for '%s': %w", r.Name(), e.IP, err)
+			}
+			e.Subnet = &i
+		}
+		if v, ok := m["negated"]; ok {
+			e.Negated = fmt.Sprint(v) == "true" || fmt.Sprint(v) == "1"
+		}
+		if v, ok := m["comment"]; ok {
+			e.Comment = fmt.Sprint(v)
+		}
+		entries[e.Key()] = e
+	}
+	return entries, nil
+}
+
+// fetchNested populates the nested content of a resource listed from the API.
+func fetchNested(c api.Interface, serviceID string, k Kind, r Resource) error {
+	id := fmt.Sprint(r["id"])
+
+	switch k.Name {
+	case KindDictionary:
+		if isWriteOnly(r) {
+			return nil
+		}
+		items := make(map[string]any)
+		p := c.NewListDictionaryItemsPaginator(&fastly.ListDictionaryItemsInput{
+			DictionaryID: id,
+			ServiceID:    serviceID,
+		})
+		for p.HasNext() {
+			data, err := p.GetNext()
+			if err != nil {
+				return fmt.Errorf("error listing items for dictionary '%s': %w", r.Name(), err)
+			}
+			for _, item := range data {
+				items[item.ItemKey] = item.ItemValue
+			}
+		}
+		r[k.Nested] = items
+
+	case KindACL:
+		var acl []ACLEntry
+		p := c.NewListACLEntriesPaginator(&fastly.ListACLEntriesInput{
+			ACLID:     id,
+			ServiceID: serviceID,
+		})
+		for p.HasNext() {
+			data, err := p.GetNext()
+			if err != nil {
+				return fmt.Errorf("error listing entries for ACL '%s': %w", r.Name(), err)
+			}
+			for _, e := range data {
+				acl = append(acl, ACLEntry{
+					Comment: e.Comment,
+					IP:      e.IP,
+					Negated: e.Negated,
+					Subnet:  e.Subnet,
+				})
+			}
+		}
+		sort.Slice(acl, func(i, j int) bool {
+			return acl[i].Key() < acl[j].Key()
+		})
+		entries := make([]any, 0, len(acl))
+		for _, e := range acl {
+			entries = append(entries, map[string]any(e.Resource()))
+		}
+		r[k.Nested] = entries
+	}
+	return nil
+}
+
+// syncDictionaryItems modifies the items of the named dictionary so they
+// match those of the `to` resource.
+//
+// The items of a write-only dictionary can't be listed, so they're unknown.
+// Instead the desired items are upserted, and any other items are left in
+// place.
+//
+// The inverse of each batch of modifications is pushed onto the undo stack
+// once the batch succeeds, so a partly synced dictionary can be restored.
+// NOTE: Upserted items can't be restored as their previous values are
+// unknown.
+func syncDictionaryItems(c api.Interface, serviceID string, version int, name string, from, to Resource, stack undo.Stacker) error {
+	current, desired := Items(from), Items(to)
+	writeOnly := isWriteOnly(from) || isWriteOnly(to)
+
+	// The inverse of each operation is held at the same index (or nil when
+	// the operation can't be undone).
+	var ops, inverse []*fastly.BatchDictionaryItem
+	for k, v := range desired {
+		cv, ok := current[k]
+		switch {
+		case writeOnly:
+			ops = append(ops, &fastly.BatchDictionaryItem{Operation: fastly.UpsertBatchOperation, ItemKey: k, ItemValue: v})
+			inverse = append(inverse, nil)
+		case !ok:
+			ops = append(ops, &fastly.BatchDictionaryItem{Operation: fastly.CreateBatchOperation, ItemKey: k, ItemValue: v})
+			inverse = append(inverse, &fastly.BatchDictionaryItem{Operation: fastly.DeleteBatchOperation, ItemKey: k})
+		case cv != v:
+			ops = append(ops, &fastly.BatchDictionaryItem{Operation: fastly.UpdateBatchOperation, ItemKey: k, ItemValue: v})
+			inverse = append(inverse, &fastly.BatchDictionaryItem{Operation: fastly.UpdateBatchOperation, ItemKey: k, ItemValue: cv})
+		}
+	}
+	if !writeOnly {
+		for k, cv := range current {
+			if _, ok := desired[k]; !ok {
+				ops = append(ops, &fastly.BatchDictionaryItem{Operation: fastly.DeleteBatchOperation, ItemKey: k})
+				inverse = append(inverse, &fastly.BatchDictionaryItem{Operation: fastly.CreateBatchOperation, ItemKey: k, ItemValue: cv})
+			}
+		}
+	}
+	if len(ops) == 0 {
+		return nil
+	}
+
+	d, err := c.GetDictionary(&fastly.GetDictionaryInput{
+		Name:           name,
+		ServiceID:      serviceID,
+		ServiceVersion: version,
+	})
+	if err != nil {
+		return fmt.Errorf("error getting dictionary '%s': %w", name, err)
+	}
+
+	for len(ops) > 0 {
+		n := len(ops)
+		if n > fastly.BatchModifyMaximumOperations {
+			n = fastly.BatchModifyMaximumOperations
+		}
+		err := c.BatchModifyDictionaryItems(&fastly.BatchModifyDictionaryItemsInput{
+			DictionaryID: d.ID,
+			Items:        ops[:n],
+			ServiceID:    serviceID,
+		})
+		if err != nil {
+			return fmt.Errorf("error modifying items for dictionary '%s': %w", name, err)
+		}
+
+		var undoOps []*fastly.BatchDictionaryItem
+		for _, op := range inverse[:n] {
+			if op != nil {
+				undoOps = append(undoOps, op)
+			}
+		}
+		if len(undoOps) > 0 {
+			stack.Push(func() error {
+				return c.BatchModifyDictionaryItems(&fastly.BatchModifyDictionaryItemsInput{
+					DictionaryID: d.ID,
+					Items:        undoOps,
+					ServiceID:    serviceID,
+				})
+			})
+		}
+
+		ops, inverse = ops[n:], inverse[n:]
+	}
+	return nil
+}
+
+// isWriteOnly reports whether the resource is a write-only dictionary.
+func isWriteOnly(r Resource) bool {
+	return fmt.Sprint(r["write_only"]) == "true"
+}
+
+// syncACLEntries modifies the entries of the named ACL so they match those
+// of the `to` resource.
+//
+// The inverse of each batch of modifications is pushed onto the undo stack
+// once the batch succeeds, so a partly synced ACL can be restored.
+func syncACLEntries(c api.Interface, serviceID string, version int, name string, from, to Resource, stack undo.Stacker) error {
+	current, err := Entries(from)
+	if err != nil {
+		return err
+	}
+	desired, err := Entries(to)
+	if err != nil {
+		return err
+	}
+
+	var changed bool
+	for k, e := range desired {
+		if ce, ok := current[k]; !ok || ce.Comment != e.Comment || ce.Negated != e.Negated {
+			changed = true
+		}
+	}
+	for k := range current {
+		if _, ok := desired[k]; !ok {
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	a, err := c.GetACL(&fastly.GetACLInput{
+		Name:           name,
+		ServiceID:      serviceID,
+		ServiceVersion: version,
+	})
+	if err != nil {
+		return fmt.Errorf("error getting ACL '%s': %w", name, err)
+	}
+
+	// The entry IDs are required to update or delete entries.
+	ids, err := aclEntryIDs(c, serviceID, a.ID, name)
+	if err != nil {
+		return err
+	}
+
+	// The inverse of each operation is held at the same index. The inverse of
+	// a create has no ID, as the entry's ID is only known once it's created.
+	var ops, inverse []*fastly.BatchACLEntry
+	for k, e := range desired {
+		op := &fastly.BatchACLEntry{
+			Comment: fastly.String(e.Comment),
+			IP:      fastly.String(e.IP),
+			Negated: fastly.CBool(e.Negated),
+			Subnet:  e.Subnet,
+		}
+		id, ok := ids[k]
+		switch {
+		case !ok:
+			op.Operation = fastly.CreateBatchOperation
+			inverse = append(inverse, &fastly.BatchACLEntry{Operation: fastly.DeleteBatchOperation, IP: fastly.String(e.IP), Subnet: e.Subnet})
+		case current[k].Comment != e.Comment || current[k].Negated != e.Negated:
+			op.Operation = fastly.UpdateBatchOperation
+			op.ID = fastly.String(id)
+			ce := current[k]
+			inverse = append(inverse, &fastly.BatchACLEntry{Operation: fastly.UpdateBatchOperation, ID: fastly.String(id), Comment: fastly.String(ce.Comment), IP: fastly.String(ce.IP), Negated: fastly.CBool(ce.Negated), Subnet: ce.Subnet})
+		default:
+			continue
+		}
+		ops = append(ops, op)
+	}
+	for k, id := range ids {
+		if _, ok := desired[k]; !ok {
+			ops = append(ops, &fastly.BatchACLEntry{Operation: fastly.DeleteBatchOperation, ID: fastly.String(id)})
+			ce := current[k]
+			inverse = append(inverse, &fastly.BatchACLEntry{Operation: fastly.CreateBatchOperation, Comment: fastly.String(ce.Comment), IP: fastly.String(ce.IP), Negated: fastly.CBool(ce.Negated), Subnet: ce.Subnet})
+		}
+	}
+
+	for len(ops) > 0 {
+		n := len(ops)
+		if n > fastly.BatchModifyMaximumOperations {
+			n = fastly.BatchModifyMaximumOperations
+		}
+		err := c.BatchModifyACLEntries(&fastly.BatchModifyACLEntriesInput{
+			ACLID:     a.ID,
+			Entries:   ops[:n],
+			ServiceID: serviceID,
+		})
+		if err != nil {
+			return fmt.Errorf("error modifying entries for ACL '%s': %w", name, err)
+		}
+
+		undoOps := inverse[:n]
+		stack.Push(func() error {
+			return undoACLEntries(c, serviceID, a.ID, name, undoOps)
+		})
+
+		ops, inverse = ops[n:], inverse[n:]
+	}
+	return nil
+}
+
+// undoACLEntries applies the inverse of a batch of ACL entry modifications,
+// looking up the IDs of the entries that the batch created.
+func undoACLEntries(c api.Interface, serviceID, aclID, name string, ops []*fastly.BatchACLEntry) error {
+	var ids map[string]string
+	for _, op := range ops {
+		if op.Operation != fastly.DeleteBatchOperation || op.ID != nil {
+			continue
+		}
+		if ids == nil {
+			var err error
+			if ids, err = aclEntryIDs(c, serviceID, aclID, name); err != nil {
+				return err
+			}
+		}
+		id, ok := ids[ACLEntry{IP: *op.IP, Subnet: op.Subnet}.Key()]
+		if !ok {
+			return fmt.Errorf("error undoing changes to ACL '%s': entry '%s' not found", name, *op.IP)
+		}
+		op.ID = fastly.String(id)
+		op.IP, op.Subnet = nil, nil
+	}
+	err := c.BatchModifyACLEntries(&fastly.BatchModifyACLEntriesInput{
+		ACLID:     aclID,
+		Entries:   ops,
+		ServiceID: serviceID,
+	})
+	if err != nil {
+		return fmt.Errorf("error modifying entries for ACL '%s': %w", name, err)
+	}
+	return nil
+}
+
+// aclEntryIDs returns the IDs of the ACL's entries keyed by ACLEntry.Key.
+func aclEntryIDs(c api.Interface, serviceID, aclID, name string) (map[string]string, error) {
+	ids := make(map[string]string)
+	p := c.NewListACLEntriesPaginator(&fastly.ListACLEntriesInput{
+		ACLID:     aclID,
+		ServiceID: serviceID,
+	})
+	for p.HasNext() {
+		data, err := p.GetNext()
+		if err != nil {
+			return nil, fmt.Errorf("error listing entries for ACL '%s': %w", name, err)
+		}
+		for _, e := range data {
+			ids[ACLEntry{IP: e.IP, Subnet: e.Subnet}.Key()] = e.ID
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,55 @@
+package spec
+
+import (
+	"fmt"
+
+	"github.com/fastly/go-fastly/v8/fastly"
+
+	"github.com/fastly/cli/pkg/api"
+)
+
+// rateLimiterKind returns the kind describing rate limiters.
+//
+// NOTE: Unlike other resources, rate limiters are updated and deleted by ID
+// rather than by service version and name, so the ID is looked up first.
+func rateLimiterKind() Kind {
+	k := newKind(KindRateLimiter, "rate limiter", api.Interface.ListERLs, api.Interface.CreateERL, api.Interface.UpdateERL, api.Interface.DeleteERL)
+	k.Update = func(c api.Interface, serviceID string, version int, name string, r Resource) error {
+		id, err := rateLimiterID(c, serviceID, version, name)
+		if err != nil {
+			return err
+		}
+		var i fastly.UpdateERLInput
+		if err := decode(r, &i); err != nil {
+			return err
+		}
+		i.ERLID = id
+		_, err = c.UpdateERL(&i)
+		return err
+	}
+	k.Delete = func(c api.Interface, serviceID string, version int, name string) error {
+		id, err := rateLimiterID(c, serviceID, version, name)
+		if err != nil {
+			return err
+		}
+		return c.DeleteERL(&fastly.DeleteERLInput{ERLID: id})
+	}
+	return k
+}
+
+// rateLimiterID returns the ID of the named rate limiter.
+func rateLimiterID(c api.Interface, serviceID string, version int, name string) (string, error) {
+	erls, err := c.ListERLs(&fastly.ListERLsInput{
+		ServiceID:      serviceID,
+		ServiceVersion: version,
+	})
+	if err != nil {
+		return "", err
+	}
+	for _, erl := range erls {
+		if erl.Name == name {
+			return erl.ID, nil
+		}
+	}
+	return "", fmt.Errorf("rate limiter '%s' not found", name)
+}
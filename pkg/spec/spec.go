@@ -0,0 +1,276 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	toml "github.com/pelletier/go-toml"
+)
+
+// Resource describes a single service version resource (e.g. a backend).
+//
+// The keys are the field names used by the Fastly API (e.g. `address`,
+// `port`, `use_ssl`) and every resource is identified by its `name` key.
+type Resource map[string]any
+
+// Name returns the name of the resource.
+func (r Resource) Name() string {
+	if v, ok := r["name"]; ok {
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+// Spec describes the configuration of a Fastly service version.
+//
+// NOTE: A nil section means the section was omitted from the document and so
+// should be considered unmanaged (i.e. left untouched) by consumers.
+type Spec struct {
+	Domains      []Resource            `json:"domains,omitempty" toml:"domains,omitempty"`
+	HealthChecks []Resource            `json:"healthchecks,omitempty" toml:"healthchecks,omitempty"`
+	Backends     []Resource            `json:"backends,omitempty" toml:"backends,omitempty"`
+	Dictionaries []Resource            `json:"dictionaries,omitempty" toml:"dictionaries,omitempty"`
+	ACLs         []Resource            `json:"acls,omitempty" toml:"acls,omitempty"`
+	VCLs         []Resource            `json:"vcls,omitempty" toml:"vcls,omitempty"`
+	Snippets     []Resource            `json:"snippets,omitempty" toml:"snippets,omitempty"`
+	RateLimiters []Resource            `json:"rate_limiters,omitempty" toml:"rate_limiters,omitempty"`
+	Logging      map[string][]Resource `json:"logging,omitempty" toml:"logging,omitempty"`
+}
+
+// Section is a group of resources of the same kind.
+type Section struct {
+	Kind      Kind
+	Resources []Resource
+}
+
+// Sections returns the managed sections of the spec in the order they should
+// be created. Deletion should happen in the reverse order.
+//
+// NOTE: Health checks come before backends as backends reference them, and
+// rate limiters come after the dictionaries they reference.
+//
+// Only the logging providers listed in the spec are managed, so declaring the
+// endpoints of one provider leaves the endpoints of every other provider
+// untouched. A provider listed without any endpoints (e.g. `s3 = []` within a
+// `[logging]` table) has all of its endpoints deleted.
+func (s *Spec) Sections() []Section {
+	return s.sections(false)
+}
+
+// sections implements Sections. When allLogging is set, every logging provider
+// is managed if the spec has a logging table, as when comparing two complete
+// specs fetched from the API (which omit providers without endpoints).
+func (s *Spec) sections(allLogging bool) []Section {
+	var sections []Section
+	add := func(k string, rs []Resource) {
+		if rs != nil {
+			sections = append(sections, Section{Kind: Kinds[k], Resources: rs})
+		}
+	}
+	add(KindDomain, s.Domains)
+	add(KindHealthCheck, s.HealthChecks)
+	add(KindBackend, s.Backends)
+	add(KindDictionary, s.Dictionaries)
+	add(KindACL, s.ACLs)
+	add(KindVCL, s.VCLs)
+	add(KindSnippet, s.Snippets)
+	add(KindRateLimiter, s.RateLimiters)
+	if s.Logging != nil {
+		for _, p := range LoggingProviders() {
+			rs, ok := s.Logging[p]
+			if !ok && !allLogging {
+				continue
+			}
+			if rs == nil {
+				rs = []Resource{}
+			}
+			sections = append(sections, Section{Kind: Kinds[LoggingKind(p)], Resources: rs})
+		}
+	}
+	return sections
+}
+
+// Validate checks the spec for unknown logging providers, unknown fields,
+// redacted values and resources that are missing a name or are declared more
+// than once.
+func (s *Spec) Validate() error {
+	for p := range s.Logging {
+		if _, ok := Kinds[LoggingKind(p)]; !ok {
+			return fmt.Errorf("unrecognised logging provider '%s' (valid providers: %s)", p, strings.Join(LoggingProviders(), ", "))
+		}
+	}
+	for _, section := range s.Sections() {
+		seen := make(map[string]bool)
+		for _, r := range section.Resources {
+			name := r.Name()
+			if name == "" {
+				return fmt.Errorf("%s is missing a name", section.Kind.Description)
+			}
+			if seen[name] {
+				return fmt.Errorf("%s '%s' is declared more than once", section.Kind.Description, name)
+			}
+			seen[name] = true
+			for field := range r {
+				if !section.Kind.Fields[field] && field != section.Kind.Nested {
+					return fmt.Errorf("%s '%s' has an unrecognised field '%s'", section.Kind.Description, name, field)
+				}
+				if r[field] == Redacted {
+					return fmt.Errorf("%s '%s' has a redacted value for field '%s' (replace it with the actual value)", section.Kind.Description, name, field)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Format is a supported spec document format.
+type Format string
+
+const (
+	// FormatTOML is the TOML document format.
+	FormatTOML Format = "toml"
+	// FormatJSON is the JSON document format.
+	FormatJSON Format = "json"
+)
+
+// Formats is a list of supported document formats.
+var Formats = []string{string(FormatTOML), string(FormatJSON)}
+
+// FormatFromPath infers the document format from the file extension.
+func FormatFromPath(path string) Format {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return FormatJSON
+	}
+	return FormatTOML
+}
+
+// Read parses the spec file at the given path.
+func Read(path string) (*Spec, error) {
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	//
+	// Disabling as we require a user to configure their own environment.
+	/* #nosec */
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading spec file: %w", err)
+	}
+	return Parse(data, FormatFromPath(path))
+}
+
+// Parse decodes the given spec document.
+func Parse(data []byte, format Format) (*Spec, error) {
+	var (
+		s   Spec
+		err error
+	)
+	switch format {
+	case FormatJSON:
+		err = json.Unmarshal(data, &s)
+	default:
+		err = unmarshalTOML(data, &s)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing spec (%s): %w", format, err)
+	}
+	if err := s.Validate(); err != nil {
+		return nil, fmt.Errorf("error validating spec: %w", err)
+	}
+	return &s, nil
+}
+
+// unmarshalTOML decodes a TOML spec document.
+//
+// NOTE: go-toml can't decode an empty array into a slice of tables, so the
+// logging providers listed without any endpoints (e.g. `s3 = []`) are removed
+// from the document before decoding, then added back as managed providers.
+func unmarshalTOML(data []byte, s *Spec) error {
+	tree, err := toml.LoadBytes(data)
+	if err != nil {
+		return err
+	}
+	var empty []string
+	if logging, ok := tree.Get("logging").(*toml.Tree); ok {
+		for _, p := range logging.Keys() {
+			if v, ok := logging.Get(p).([]any); ok && len(v) == 0 {
+				empty = append(empty, p)
+				if err := logging.Delete(p); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if err := tree.Unmarshal(s); err != nil {
+		return err
+	}
+	for _, p := range empty {
+		if s.Logging == nil {
+			s.Logging = make(map[string][]Resource)
+		}
+		s.Logging[p] = []Resource{}
+	}
+	return nil
+}
+
+// Write encodes the spec to the given writer.
+func (s *Spec) Write(w io.Writer, format Format) error {
+	s.sort()
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(s)
+	default:
+		var buf bytes.Buffer
+		enc := toml.NewEncoder(&buf).Order(toml.OrderAlphabetical)
+		if err := enc.Encode(s); err != nil {
+			return err
+		}
+		_, err := buf.WriteTo(w)
+		return err
+	}
+}
+
+// sort orders every section by resource name so documents are stable.
+func (s *Spec) sort() {
+	byName := func(rs []Resource) {
+		sort.SliceStable(rs, func(i, j int) bool {
+			return rs[i].Name() < rs[j].Name()
+		})
+	}
+	byName(s.Domains)
+	byName(s.HealthChecks)
+	byName(s.Backends)
+	byName(s.Dictionaries)
+	byName(s.ACLs)
+	byName(s.VCLs)
+	byName(s.Snippets)
+	byName(s.RateLimiters)
+	for _, rs := range s.Logging {
+		byName(rs)
+	}
+}
+
+// Redacted replaces the value of secret fields when a spec is redacted.
+const Redacted = "<redacted>"
+
+// Redact replaces the value of every secret field of each resource (e.g.
+// logging credentials), as defined by its kind, so the spec can be safely
+// shared.
+func (s *Spec) Redact() {
+	for _, section := range s.Sections() {
+		for _, r := range section.Resources {
+			for field := range r {
+				if section.Kind.Secrets[field] {
+					r[field] = Redacted
+				}
+			}
+		}
+	}
+}
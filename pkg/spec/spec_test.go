@@ -0,0 +1,339 @@
+package spec_test
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/fastly/go-fastly/v8/fastly"
+
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/spec"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/cli/pkg/undo"
+)
+
+const specTOML = `
+[[backends]]
+name = "origin"
+address = "example.com"
+port = 443
+use_ssl = true
+
+[[dictionaries]]
+name = "settings"
+[dictionaries.items]
+foo = "bar"
+
+[[acls]]
+name = "blocklist"
+[[acls.entries]]
+ip = "192.0.2.0"
+subnet = 24
+
+[[logging.syslog]]
+name = "audit"
+address = "syslog.example.com"
+`
+
+const specJSON = `{
+  "backends": [{"name": "origin", "address": "example.com", "port": 443, "use_ssl": true}],
+  "dictionaries": [{"name": "settings", "items": {"foo": "bar"}}],
+  "acls": [{"name": "blocklist", "entries": [{"ip": "192.0.2.0", "subnet": 24}]}],
+  "logging": {"syslog": [{"name": "audit", "address": "syslog.example.com"}]}
+}`
+
+func TestParse(t *testing.T) {
+	for _, format := range []spec.Format{spec.FormatTOML, spec.FormatJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			data := specTOML
+			if format == spec.FormatJSON {
+				data = specJSON
+			}
+			s, err := spec.Parse([]byte(data), format)
+			testutil.AssertNoError(t, err)
+
+			testutil.AssertEqual(t, 1, len(s.Backends))
+			testutil.AssertString(t, "origin", s.Backends[0].Name())
+			testutil.AssertBool(t, true, spec.Equal(443, s.Backends[0]["port"]))
+			testutil.AssertEqual(t, map[string]string{"foo": "bar"}, spec.Items(s.Dictionaries[0]))
+
+			entries, err := spec.Entries(s.ACLs[0])
+			testutil.AssertNoError(t, err)
+			if _, ok := entries["192.0.2.0/24"]; !ok {
+				t.Fatalf("expected ACL entry 192.0.2.0/24, got: %#v", entries)
+			}
+
+			testutil.AssertEqual(t, 1, len(s.Logging["syslog"]))
+			testutil.AssertBool(t, true, s.Domains == nil)
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	scenarios := []struct {
+		name      string
+		data      string
+		wantError string
+	}{
+		{
+			name:      "missing name",
+			data:      "[[backends]]\naddress = \"example.com\"\n",
+			wantError: "backend is missing a name",
+		},
+		{
+			name:      "duplicate name",
+			data:      "[[domains]]\nname = \"a\"\n[[domains]]\nname = \"a\"\n",
+			wantError: "domain 'a' is declared more than once",
+		},
+		{
+			name:      "unknown field",
+			data:      "[[backends]]\nname = \"origin\"\nadress = \"example.com\"\n",
+			wantError: "backend 'origin' has an unrecognised field 'adress'",
+		},
+		{
+			name:      "unknown provider",
+			data:      "[[logging.carrierpigeon]]\nname = \"coo\"\n",
+			wantError: "unrecognised logging provider 'carrierpigeon'",
+		},
+		{
+			name:      "redacted value",
+			data:      "[[logging.syslog]]\nname = \"audit\"\ntoken = \"<redacted>\"\n",
+			wantError: "logging endpoint 'audit' has a redacted value for field 'token'",
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			_, err := spec.Parse([]byte(testcase.data), spec.FormatTOML)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	s, err := spec.Parse([]byte(specTOML), spec.FormatTOML)
+	testutil.AssertNoError(t, err)
+
+	for _, format := range []spec.Format{spec.FormatTOML, spec.FormatJSON} {
+		var buf bytes.Buffer
+		testutil.AssertNoError(t, s.Write(&buf, format))
+
+		// The document should round trip.
+		rs, err := spec.Parse(buf.Bytes(), format)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 0, len(spec.Diff(s, rs)))
+		testutil.AssertEqual(t, 0, len(spec.Diff(rs, s)))
+	}
+}
+
+func TestDiff(t *testing.T) {
+	current := &spec.Spec{
+		HealthChecks: []spec.Resource{{"name": "check", "path": "/health"}},
+		Backends: []spec.Resource{
+			{"name": "origin", "address": "old.example.com", "port": 443},
+			{"name": "legacy", "address": "legacy.example.com"},
+		},
+	}
+	desired := &spec.Spec{
+		HealthChecks: []spec.Resource{},
+		Backends: []spec.Resource{
+			{"name": "origin", "address": "new.example.com", "port": float64(443)},
+			{"name": "extra", "address": "extra.example.com"},
+		},
+	}
+
+	var got []string
+	for _, ch := range spec.Diff(current, desired) {
+		got = append(got, ch.String()+" "+strings.Join(ch.Fields, ","))
+	}
+	want := []string{
+		"update backend 'origin' address",
+		"create backend 'extra' ",
+		"delete backend 'legacy' ",
+		"delete healthcheck 'check' ",
+	}
+	testutil.AssertEqual(t, want, got)
+}
+
+func TestDiffLogging(t *testing.T) {
+	current := &spec.Spec{Logging: map[string][]spec.Resource{
+		"s3":     {{"name": "archive", "bucket_name": "logs"}},
+		"syslog": {{"name": "audit", "address": "old.example.com"}},
+		"splunk": {{"name": "events", "url": "https://splunk.example.com"}},
+	}}
+
+	for _, format := range []spec.Format{spec.FormatTOML, spec.FormatJSON} {
+		data := "[[logging.syslog]]\nname = \"audit\"\naddress = \"new.example.com\"\n[logging]\ns3 = []\n"
+		if format == spec.FormatJSON {
+			data = `{"logging": {"syslog": [{"name": "audit", "address": "new.example.com"}], "s3": []}}`
+		}
+		desired, err := spec.Parse([]byte(data), format)
+		testutil.AssertNoError(t, err)
+
+		// Only the listed providers are managed, so the splunk endpoint is left
+		// untouched while the s3 endpoints are all deleted.
+		var got []string
+		for _, ch := range spec.Diff(current, desired) {
+			got = append(got, ch.String())
+		}
+		want := []string{
+			"update Syslog logging endpoint 'audit'",
+			"delete S3 logging endpoint 'archive'",
+		}
+		testutil.AssertEqual(t, want, got)
+	}
+}
+
+func TestChangeLive(t *testing.T) {
+	current := &spec.Spec{
+		Dictionaries: []spec.Resource{
+			{"name": "settings", "items": map[string]any{"foo": "bar"}},
+			{"name": "flags", "items": map[string]any{"beta": "on"}, "write_only": false},
+		},
+	}
+	desired := &spec.Spec{
+		Dictionaries: []spec.Resource{
+			{"name": "settings", "items": map[string]any{"foo": "baz"}},
+			{"name": "flags", "items": map[string]any{"beta": "on"}, "write_only": true},
+			{"name": "extra", "items": map[string]any{"a": "b"}},
+		},
+	}
+
+	live := make(map[string]bool)
+	for _, ch := range spec.Diff(current, desired) {
+		live[ch.Name] = ch.Live()
+	}
+	// Only the items of an existing dictionary are changed on every version.
+	testutil.AssertEqual(t, map[string]bool{"settings": true, "flags": false, "extra": false}, live)
+}
+
+func TestApplyUndo(t *testing.T) {
+	var calls []string
+	api := mock.API{
+		CreateBackendFn: func(i *fastly.CreateBackendInput) (*fastly.Backend, error) {
+			calls = append(calls, "create "+*i.Name)
+			return &fastly.Backend{Name: *i.Name}, nil
+		},
+		UpdateBackendFn: func(i *fastly.UpdateBackendInput) (*fastly.Backend, error) {
+			calls = append(calls, "update "+i.Name+" "+*i.Address)
+			return &fastly.Backend{Name: i.Name}, nil
+		},
+		DeleteBackendFn: func(i *fastly.DeleteBackendInput) error {
+			calls = append(calls, "delete "+i.Name)
+			if i.Name == "legacy" {
+				return testutil.Err
+			}
+			return nil
+		},
+	}
+
+	changes := spec.Diff(
+		&spec.Spec{Backends: []spec.Resource{
+			{"name": "origin", "address": "old.example.com"},
+			{"name": "legacy", "address": "legacy.example.com"},
+		}},
+		&spec.Spec{Backends: []spec.Resource{
+			{"name": "origin", "address": "new.example.com"},
+			{"name": "extra", "address": "extra.example.com"},
+		}},
+	)
+
+	var out bytes.Buffer
+	stack := undo.NewStack()
+	err := spec.Apply(api, "123", 1, changes, stack)
+	testutil.AssertErrorContains(t, err, "error attempting to delete backend 'legacy'")
+	stack.RunIfError(&out, err)
+
+	want := []string{
+		"update origin new.example.com",
+		"create extra",
+		"delete legacy",
+		// undo
+		"delete extra",
+		"update origin old.example.com",
+	}
+	testutil.AssertEqual(t, want, calls)
+}
+
+func TestApplyDictionaryItems(t *testing.T) {
+	var batches [][]string
+	api := mock.API{
+		GetDictionaryFn: func(i *fastly.GetDictionaryInput) (*fastly.Dictionary, error) {
+			return &fastly.Dictionary{ID: "abc", Name: i.Name}, nil
+		},
+		BatchModifyDictionaryItemsFn: func(i *fastly.BatchModifyDictionaryItemsInput) error {
+			var ops []string
+			for _, item := range i.Items {
+				ops = append(ops, fmt.Sprintf("%s %s", item.Operation, item.ItemKey))
+			}
+			sort.Strings(ops)
+			batches = append(batches, ops)
+			if len(batches) == 2 {
+				return testutil.Err
+			}
+			return nil
+		},
+	}
+
+	// The items of a write-only dictionary are unknown, so they're upserted.
+	changes := spec.Diff(
+		&spec.Spec{Dictionaries: []spec.Resource{{"name": "flags", "write_only": true}}},
+		&spec.Spec{Dictionaries: []spec.Resource{{"name": "flags", "write_only": true, "items": map[string]any{"beta": "on"}}}},
+	)
+	err := spec.Apply(api, "123", 1, changes, undo.NewStack())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, [][]string{{"upsert beta"}}, batches)
+
+	// When a later batch fails the earlier batches are undone.
+	batches = nil
+	items := make(map[string]any)
+	for i := 0; i <= fastly.BatchModifyMaximumOperations; i++ {
+		items[fmt.Sprintf("key%04d", i)] = "value"
+	}
+	changes = spec.Diff(
+		&spec.Spec{Dictionaries: []spec.Resource{{"name": "settings", "items": map[string]any{}}}},
+		&spec.Spec{Dictionaries: []spec.Resource{{"name": "settings", "items": items}}},
+	)
+	var out bytes.Buffer
+	stack := undo.NewStack()
+	err = spec.Apply(api, "123", 1, changes, stack)
+	testutil.AssertErrorContains(t, err, "error modifying items for dictionary 'settings'")
+	stack.RunIfError(&out, err)
+
+	testutil.AssertEqual(t, 3, len(batches))
+	testutil.AssertEqual(t, fastly.BatchModifyMaximumOperations, len(batches[2]))
+	for i, op := range batches[2] {
+		testutil.AssertEqual(t, strings.Replace(batches[0][i], "create", "delete", 1), op)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	s := &spec.Spec{
+		Backends: []spec.Resource{{"name": "origin", "address": "example.com", "ssl_client_key": "key"}},
+		Logging: map[string][]spec.Resource{
+			"s3":     {{"name": "archive", "bucket_name": "logs", "access_key": "id", "secret_key": "secret"}},
+			"https":  {{"name": "collector", "url": "https://logs.example.com", "header_value": "Bearer secret"}},
+			"heroku": {{"name": "app", "url": "https://1.us.logplex.io/logs", "token": "secret"}},
+		},
+	}
+	s.Redact()
+
+	testutil.AssertEqual(t, spec.Resource{"name": "origin", "address": "example.com", "ssl_client_key": spec.Redacted}, s.Backends[0])
+	testutil.AssertEqual(t, spec.Resource{"name": "archive", "bucket_name": "logs", "access_key": spec.Redacted, "secret_key": spec.Redacted}, s.Logging["s3"][0])
+	testutil.AssertEqual(t, spec.Resource{"name": "collector", "url": "https://logs.example.com", "header_value": spec.Redacted}, s.Logging["https"][0])
+	testutil.AssertEqual(t, spec.Resource{"name": "app", "url": spec.Redacted, "token": spec.Redacted}, s.Logging["heroku"][0])
+}
+
+func TestCompare(t *testing.T) {
+	from := &spec.Spec{Backends: []spec.Resource{{"name": "origin", "address": "example.com", "comment": "old"}}}
+	to := &spec.Spec{Backends: []spec.Resource{{"name": "origin", "address": "example.com"}}}
+
+	// Diff only considers the fields set on the desired resource.
+	testutil.AssertEqual(t, 0, len(spec.Diff(from, to)))
+
+	changes := spec.Compare(from, to)
+	testutil.AssertEqual(t, 1, len(changes))
+	testutil.AssertEqual(t, []string{"comment"}, changes[0].Fields)
+}
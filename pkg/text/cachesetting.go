@@ -0,0 +1,22 @@
+package text
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/fastly/go-fastly/v8/fastly"
+	"github.com/segmentio/textio"
+)
+
+// PrintCacheSetting pretty prints a fastly.CacheSetting structure in verbose
+// format to a given io.Writer. Consumers can provide a prefix string which
+// will be used as a prefix to each line, useful for indentation.
+func PrintCacheSetting(out io.Writer, prefix string, c *fastly.CacheSetting) {
+	out = textio.NewPrefixWriter(out, prefix)
+
+	fmt.Fprintf(out, "Name: %s\n", c.Name)
+	fmt.Fprintf(out, "Action: %s\n", c.Action)
+	fmt.Fprintf(out, "TTL: %d\n", c.TTL)
+	fmt.Fprintf(out, "Stale TTL: %d\n", c.StaleTTL)
+	fmt.Fprintf(out, "Cache condition: %s\n", c.CacheCondition)
+}
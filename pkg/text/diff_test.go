@@ -0,0 +1,44 @@
+package text_test
+
+import (
+	"testing"
+
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/cli/pkg/text"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	for _, testcase := range []struct {
+		name       string
+		a, b       string
+		wantOutput string
+	}{
+		{
+			name: "equal",
+			a:    "a\nb\n",
+			b:    "a\nb\n",
+		},
+		{
+			name:       "added to empty",
+			a:          "",
+			b:          "a\nb\n",
+			wantOutput: "--- v1\n+++ v2\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name:       "changed line",
+			a:          "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			b:          "1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+			wantOutput: "--- v1\n+++ v2\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name:       "separate hunks",
+			a:          "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			b:          "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n",
+			wantOutput: "--- v1\n+++ v2\n@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n@@ -9,4 +9,3 @@\n 9\n 10\n 11\n-12\n",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			testutil.AssertString(t, testcase.wantOutput, text.UnifiedDiff("v1", "v2", testcase.a, testcase.b))
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package text
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/fastly/go-fastly/v8/fastly"
+	"github.com/segmentio/textio"
+)
+
+// PrintDirector pretty prints a fastly.Director structure in verbose
+// format to a given io.Writer. Consumers can provide a prefix string which
+// will be used as a prefix to each line, useful for indentation.
+func PrintDirector(out io.Writer, prefix string, d *fastly.Director) {
+	out = textio.NewPrefixWriter(out, prefix)
+
+	fmt.Fprintf(out, "Name: %s\n", d.Name)
+	fmt.Fprintf(out, "Comment: %s\n", d.Comment)
+	fmt.Fprintf(out, "Type: %s\n", DirectorTypeName(d.Type))
+	fmt.Fprintf(out, "Backends: %s\n", strings.Join(d.Backends, ", "))
+	fmt.Fprintf(out, "Quorum: %d\n", d.Quorum)
+	fmt.Fprintf(out, "Retries: %d\n", d.Retries)
+	fmt.Fprintf(out, "Capacity: %d\n", d.Capacity)
+	fmt.Fprintf(out, "Shield: %s\n", d.Shield)
+}
+
+// DirectorTypeName returns the name of a director type as accepted by the
+// --type flag of the director commands (e.g. random).
+func DirectorTypeName(t fastly.DirectorType) string {
+	switch t {
+	case fastly.DirectorTypeRandom:
+		return "random"
+	case fastly.DirectorTypeRoundRobin:
+		return "round-robin"
+	case fastly.DirectorTypeHash:
+		return "hash"
+	case fastly.DirectorTypeClient:
+		return "client"
+	}
+	return strconv.Itoa(int(t))
+}
@@ -0,0 +1,366 @@
+package text
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"gopkg.in/yaml.v2"
+)
+
+// The output formats accepted by the global --format flag.
+const (
+	FormatCSV   = "csv"
+	FormatJSON  = "json"
+	FormatTable = "table"
+	FormatYAML  = "yaml"
+)
+
+// Formats is a list of the output formats accepted by the --format flag.
+var Formats = []string{FormatJSON, FormatYAML, FormatCSV, FormatTable}
+
+// Render writes the value to out in the given format.
+//
+// The value is first encoded as JSON, so the field names (and order) of the
+// YAML, CSV and table formats match the JSON output. The CSV and table formats
+// write a row for each element of a list (or a single row for any other
+// value), with nested objects and lists represented as compact JSON.
+func Render(out io.Writer, format string, value any) error {
+	if format == FormatJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(value)
+	}
+
+	v, err := Decode(value)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case FormatYAML:
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(b)
+		return err
+	case FormatCSV:
+		header, rows, err := records(v)
+		if err != nil || len(header) == 0 {
+			return err
+		}
+		w := csv.NewWriter(out)
+		if err := w.Write(header); err != nil {
+			return err
+		}
+		if err := w.WriteAll(rows); err != nil {
+			return err
+		}
+		return w.Error()
+	case FormatTable:
+		header, rows, err := records(v)
+		if err != nil || len(header) == 0 {
+			return err
+		}
+		t := NewTable(out)
+		t.AddHeader(toAny(header, nil)...)
+		for _, row := range rows {
+			t.AddLine(toAny(row, nil)...)
+		}
+		t.Print()
+		return nil
+	}
+	return fmt.Errorf("unsupported output format: %s", format)
+}
+
+// Object is a JSON object that preserves the order of its fields.
+type Object yaml.MapSlice
+
+// MarshalYAML implements yaml.Marshaler.
+func (o Object) MarshalYAML() (any, error) {
+	return yaml.MapSlice(o), nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (o Object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, item := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(item.Key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(item.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Decode encodes the value as JSON and decodes it again, representing objects
+// as an Object so the order of their fields is preserved.
+//
+// The decoded value is an Object, []any, string, int64, float64, bool or nil.
+func Decode(value any) (any, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	return decodeOrdered(dec)
+}
+
+// decodeOrdered decodes the next JSON value from dec.
+func decodeOrdered(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '[' {
+			l := []any{}
+			for dec.More() {
+				v, err := decodeOrdered(dec)
+				if err != nil {
+					return nil, err
+				}
+				l = append(l, v)
+			}
+			_, err := dec.Token()
+			return l, err
+		}
+		o := Object{}
+		for dec.More() {
+			k, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			v, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			o = append(o, yaml.MapItem{Key: k, Value: v})
+		}
+		_, err := dec.Token()
+		return o, err
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i, nil
+		}
+		return t.Float64()
+	}
+	return tok, nil
+}
+
+// records flattens a value into rows of cells, returning the field names of
+// every row as the header.
+func records(v any) (header []string, rows [][]string, err error) {
+	l, ok := v.([]any)
+	if !ok {
+		l = []any{v}
+	}
+
+	index := make(map[string]int)
+	cells := make([]map[string]string, 0, len(l))
+	for _, e := range l {
+		o, ok := e.(Object)
+		if !ok {
+			o = Object{{Key: "value", Value: e}}
+		}
+		m := make(map[string]string, len(o))
+		for _, item := range o {
+			k := fmt.Sprint(item.Key)
+			if _, ok := index[k]; !ok {
+				index[k] = len(header)
+				header = append(header, k)
+			}
+			if m[k], err = cell(item.Value); err != nil {
+				return nil, nil, err
+			}
+		}
+		cells = append(cells, m)
+	}
+
+	for _, m := range cells {
+		row := make([]string, len(header))
+		for k, v := range m {
+			row[index[k]] = v
+		}
+		rows = append(rows, row)
+	}
+	return header, rows, nil
+}
+
+// cell returns the text representation of a decoded value.
+func cell(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case Object, []any:
+		b, err := json.Marshal(v)
+		return string(b), err
+	}
+	return fmt.Sprint(v), nil
+}
+
+// toAny converts the strings to a list of values for a Table, applying fn to
+// each string if set.
+func toAny(s []string, fn func(string) string) []any {
+	l := make([]any, len(s))
+	for i, v := range s {
+		if fn != nil {
+			v = fn(v)
+		}
+		l[i] = v
+	}
+	return l
+}
+
+// ListRenderer renders a list one page at a time, so long lists can be
+// written as they are fetched. The output is equivalent to rendering the
+// whole list with Render, except that the CSV and table formats use the
+// fields of the first item as the header and tables are aligned per page.
+type ListRenderer struct {
+	format string
+	header []string
+	items  int
+	out    io.Writer
+}
+
+// NewListRenderer returns a ListRenderer that writes to out in the given
+// format.
+func NewListRenderer(out io.Writer, format string) *ListRenderer {
+	return &ListRenderer{
+		format: format,
+		out:    out,
+	}
+}
+
+// Render writes the items of the next page.
+func (r *ListRenderer) Render(page any) error {
+	v, err := Decode(page)
+	if err != nil {
+		return err
+	}
+	l, ok := v.([]any)
+	if !ok {
+		l = []any{v}
+	}
+	if len(l) == 0 {
+		return nil
+	}
+	defer func() {
+		r.items += len(l)
+	}()
+
+	switch r.format {
+	case FormatJSON:
+		for i, item := range l {
+			b, err := json.MarshalIndent(item, "  ", "  ")
+			if err != nil {
+				return err
+			}
+			sep := ",\n  "
+			if r.items == 0 && i == 0 {
+				sep = "[\n  "
+			}
+			if _, err := fmt.Fprintf(r.out, "%s%s", sep, b); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatYAML:
+		b, err := yaml.Marshal(l)
+		if err != nil {
+			return err
+		}
+		_, err = r.out.Write(b)
+		return err
+	case FormatCSV, FormatTable:
+		header, rows, err := records(l)
+		if err != nil {
+			return err
+		}
+		if r.header == nil {
+			r.header = header
+		}
+		rows = reorder(r.header, header, rows)
+		if r.format == FormatCSV {
+			w := csv.NewWriter(r.out)
+			if r.items == 0 {
+				if err := w.Write(r.header); err != nil {
+					return err
+				}
+			}
+			if err := w.WriteAll(rows); err != nil {
+				return err
+			}
+			return w.Error()
+		}
+		t := NewTable(r.out)
+		if r.items == 0 {
+			t.AddHeader(toAny(r.header, nil)...)
+		}
+		for _, row := range rows {
+			t.AddLine(toAny(row, nil)...)
+		}
+		t.Print()
+		return nil
+	}
+	return fmt.Errorf("unsupported output format: %s", r.format)
+}
+
+// Close completes the output once every page has been rendered.
+func (r *ListRenderer) Close() error {
+	var err error
+	switch r.format {
+	case FormatJSON:
+		if r.items == 0 {
+			_, err = fmt.Fprintln(r.out, "[]")
+		} else {
+			_, err = fmt.Fprintln(r.out, "\n]")
+		}
+	case FormatYAML:
+		if r.items == 0 {
+			_, err = fmt.Fprintln(r.out, "[]")
+		}
+	}
+	return err
+}
+
+// reorder arranges the cells of rows (with the given header) in the order of
+// the fields in want, dropping any other fields.
+func reorder(want, header []string, rows [][]string) [][]string {
+	index := make(map[string]int, len(header))
+	for i, k := range header {
+		index[k] = i
+	}
+	for i, row := range rows {
+		r := make([]string, len(want))
+		for j, k := range want {
+			if n, ok := index[k]; ok {
+				r[j] = row[n]
+			}
+		}
+		rows[i] = r
+	}
+	return rows
+}
@@ -0,0 +1,94 @@
+package text_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/cli/pkg/text"
+)
+
+type formatFixture struct {
+	Name    string
+	Port    int
+	Weight  float64
+	Shield  *string
+	Tags    []string
+	Enabled bool
+}
+
+func TestRender(t *testing.T) {
+	value := []formatFixture{
+		{Name: "origin", Port: 443, Weight: 0.5, Tags: []string{"a", "b"}, Enabled: true},
+		{Name: "fallback, eu", Port: 80, Weight: 100},
+	}
+	for _, testcase := range []struct {
+		format     string
+		value      any
+		wantOutput string
+	}{
+		{
+			format: text.FormatJSON,
+			value:  value[1],
+			wantOutput: `{
+  "Name": "fallback, eu",
+  "Port": 80,
+  "Weight": 100,
+  "Shield": null,
+  "Tags": null,
+  "Enabled": false
+}
+`,
+		},
+		{
+			format: text.FormatYAML,
+			value:  value,
+			wantOutput: `- Name: origin
+  Port: 443
+  Weight: 0.5
+  Shield: null
+  Tags:
+  - a
+  - b
+  Enabled: true
+- Name: fallback, eu
+  Port: 80
+  Weight: 100
+  Shield: null
+  Tags: null
+  Enabled: false
+`,
+		},
+		{
+			format: text.FormatCSV,
+			value:  value,
+			wantOutput: `Name,Port,Weight,Shield,Tags,Enabled
+origin,443,0.5,,"[""a"",""b""]",true
+"fallback, eu",80,100,,,false
+`,
+		},
+		{
+			format: text.FormatCSV,
+			value:  []string{"a", "b"},
+			wantOutput: `value
+a
+b
+`,
+		},
+		{
+			format: text.FormatTable,
+			value:  value[0],
+			wantOutput: `Name    Port  Weight  Shield  Tags       Enabled
+origin  443   0.5             ["a","b"]  true
+`,
+		},
+	} {
+		t.Run(testcase.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := text.Render(&buf, testcase.format, testcase.value); err != nil {
+				t.Fatal(err)
+			}
+			testutil.AssertString(t, testcase.wantOutput, buf.String())
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package text
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/fastly/go-fastly/v8/fastly"
+	"github.com/segmentio/textio"
+)
+
+// PrintGzip pretty prints a fastly.Gzip structure in verbose
+// format to a given io.Writer. Consumers can provide a prefix string which
+// will be used as a prefix to each line, useful for indentation.
+func PrintGzip(out io.Writer, prefix string, g *fastly.Gzip) {
+	out = textio.NewPrefixWriter(out, prefix)
+
+	fmt.Fprintf(out, "Name: %s\n", g.Name)
+	fmt.Fprintf(out, "Content types: %s\n", g.ContentTypes)
+	fmt.Fprintf(out, "Extensions: %s\n", g.Extensions)
+	fmt.Fprintf(out, "Cache condition: %s\n", g.CacheCondition)
+}
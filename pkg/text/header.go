@@ -0,0 +1,29 @@
+package text
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/fastly/go-fastly/v8/fastly"
+	"github.com/segmentio/textio"
+)
+
+// PrintHeader pretty prints a fastly.Header structure in verbose
+// format to a given io.Writer. Consumers can provide a prefix string which
+// will be used as a prefix to each line, useful for indentation.
+func PrintHeader(out io.Writer, prefix string, h *fastly.Header) {
+	out = textio.NewPrefixWriter(out, prefix)
+
+	fmt.Fprintf(out, "Name: %s\n", h.Name)
+	fmt.Fprintf(out, "Type: %s\n", h.Type)
+	fmt.Fprintf(out, "Action: %s\n", h.Action)
+	fmt.Fprintf(out, "Destination: %s\n", h.Destination)
+	fmt.Fprintf(out, "Source: %s\n", h.Source)
+	fmt.Fprintf(out, "Regex: %s\n", h.Regex)
+	fmt.Fprintf(out, "Substitution: %s\n", h.Substitution)
+	fmt.Fprintf(out, "Ignore if set: %t\n", h.IgnoreIfSet)
+	fmt.Fprintf(out, "Priority: %d\n", h.Priority)
+	fmt.Fprintf(out, "Request condition: %s\n", h.RequestCondition)
+	fmt.Fprintf(out, "Cache condition: %s\n", h.CacheCondition)
+	fmt.Fprintf(out, "Response condition: %s\n", h.ResponseCondition)
+}
@@ -0,0 +1,29 @@
+package text
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/fastly/go-fastly/v8/fastly"
+	"github.com/segmentio/textio"
+)
+
+// PrintRequestSetting pretty prints a fastly.RequestSetting structure in verbose
+// format to a given io.Writer. Consumers can provide a prefix string which
+// will be used as a prefix to each line, useful for indentation.
+func PrintRequestSetting(out io.Writer, prefix string, r *fastly.RequestSetting) {
+	out = textio.NewPrefixWriter(out, prefix)
+
+	fmt.Fprintf(out, "Name: %s\n", r.Name)
+	fmt.Fprintf(out, "Action: %s\n", r.Action)
+	fmt.Fprintf(out, "Default host: %s\n", r.DefaultHost)
+	fmt.Fprintf(out, "Force miss: %t\n", r.ForceMiss)
+	fmt.Fprintf(out, "Force SSL: %t\n", r.ForceSSL)
+	fmt.Fprintf(out, "Bypass busy wait: %t\n", r.BypassBusyWait)
+	fmt.Fprintf(out, "Geo headers: %t\n", r.GeoHeaders)
+	fmt.Fprintf(out, "Hash keys: %s\n", r.HashKeys)
+	fmt.Fprintf(out, "Max stale age: %d\n", r.MaxStaleAge)
+	fmt.Fprintf(out, "Timer support: %t\n", r.TimerSupport)
+	fmt.Fprintf(out, "X-Forwarded-For: %s\n", r.XForwardedFor)
+	fmt.Fprintf(out, "Request condition: %s\n", r.RequestCondition)
+}
@@ -0,0 +1,24 @@
+package text
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/fastly/go-fastly/v8/fastly"
+	"github.com/segmentio/textio"
+)
+
+// PrintResponseObject pretty prints a fastly.ResponseObject structure in verbose
+// format to a given io.Writer. Consumers can provide a prefix string which
+// will be used as a prefix to each line, useful for indentation.
+func PrintResponseObject(out io.Writer, prefix string, r *fastly.ResponseObject) {
+	out = textio.NewPrefixWriter(out, prefix)
+
+	fmt.Fprintf(out, "Name: %s\n", r.Name)
+	fmt.Fprintf(out, "Status: %d\n", r.Status)
+	fmt.Fprintf(out, "Response: %s\n", r.Response)
+	fmt.Fprintf(out, "Content type: %s\n", r.ContentType)
+	fmt.Fprintf(out, "Content: %s\n", r.Content)
+	fmt.Fprintf(out, "Request condition: %s\n", r.RequestCondition)
+	fmt.Fprintf(out, "Cache condition: %s\n", r.CacheCondition)
+}
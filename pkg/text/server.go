@@ -0,0 +1,26 @@
+package text
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/fastly/go-fastly/v8/fastly"
+	"github.com/segmentio/textio"
+)
+
+// PrintServer pretty prints a fastly.Server structure in verbose format to a
+// given io.Writer. Consumers can provide a prefix string which will be used
+// as a prefix to each line, useful for indentation.
+func PrintServer(out io.Writer, prefix string, s *fastly.Server) {
+	out = textio.NewPrefixWriter(out, prefix)
+
+	fmt.Fprintf(out, "ID: %s\n", s.ID)
+	fmt.Fprintf(out, "Pool ID: %s\n", s.PoolID)
+	fmt.Fprintf(out, "Address: %s\n", s.Address)
+	fmt.Fprintf(out, "Port: %d\n", s.Port)
+	fmt.Fprintf(out, "Weight: %d\n", s.Weight)
+	fmt.Fprintf(out, "Max connections: %d\n", s.MaxConn)
+	fmt.Fprintf(out, "Override host: %s\n", s.OverrideHost)
+	fmt.Fprintf(out, "Comment: %s\n", s.Comment)
+	fmt.Fprintf(out, "Disabled: %t\n", s.Disabled)
+}